@@ -0,0 +1,158 @@
+// clustereval 在带人工标注的新闻数据集上评估事件聚类效果，不连接数据库
+//
+// 用法：
+//
+//	go run ./cmd/clustereval -data data/labeled.json -label gold_event
+//	go run ./cmd/clustereval -data data/labeled.json -config a.yaml -compare b.yaml
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+)
+
+func main() {
+	dataPath := flag.String("data", "", "标注数据集路径（data/new.json 或 converted_news_data.json 格式）")
+	labelField := flag.String("label", "gold_event", "标注事件所在的字段")
+	configPath := flag.String("config", "internal/config/config.yaml", "聚类配置文件")
+	comparePath := flag.String("compare", "", "用于对比的另一份聚类配置文件")
+	asJSON := flag.Bool("json", false, "以 JSON 格式输出")
+	flag.Parse()
+
+	if *dataPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dataset, err := services.LoadLabeledNews(*dataPath, *labelField)
+	if err != nil {
+		log.Fatalf("Failed to load dataset: %v", err)
+	}
+
+	paths := []string{*configPath}
+	if *comparePath != "" {
+		paths = append(paths, *comparePath)
+	}
+
+	evaluations := make([]services.ClusteringEvaluation, 0, len(paths))
+	for _, path := range paths {
+		cfg, err := config.LoadClusteringConfig(path)
+		if err != nil {
+			log.Fatalf("Failed to load clustering config %s: %v", path, err)
+		}
+		evaluations = append(evaluations, services.EvaluateClustering(path, dataset, cfg))
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]interface{}{
+			"dataset":     *dataPath,
+			"documents":   len(dataset.News),
+			"skipped":     dataset.Skipped,
+			"evaluations": evaluations,
+		}); err != nil {
+			log.Fatalf("Failed to encode result: %v", err)
+		}
+		return
+	}
+
+	fmt.Printf("数据集: %s（%d 条已标注新闻，跳过 %d 条无标注）\n\n", *dataPath, len(dataset.News), dataset.Skipped)
+	printReport(evaluations)
+}
+
+// printReport 按列并排输出各配置的指标，两份配置时追加差值列
+func printReport(evaluations []services.ClusteringEvaluation) {
+	header := []string{"指标"}
+	for _, evaluation := range evaluations {
+		header = append(header, evaluation.Name)
+	}
+	diff := len(evaluations) == 2
+	if diff {
+		header = append(header, "差值")
+	}
+
+	rows := [][]string{header}
+	addRow := func(name string, value func(m nlp.ClusterMetrics) float64, format string) {
+		row := []string{name}
+		for _, evaluation := range evaluations {
+			row = append(row, fmt.Sprintf(format, value(evaluation.Metrics)))
+		}
+		if diff {
+			delta := value(evaluations[1].Metrics) - value(evaluations[0].Metrics)
+			row = append(row, fmt.Sprintf("%+"+strings.TrimPrefix(format, "%"), delta))
+		}
+		rows = append(rows, row)
+	}
+
+	addRow("纯度 purity", func(m nlp.ClusterMetrics) float64 { return m.Purity }, "%.4f")
+	addRow("逆纯度 inverse purity", func(m nlp.ClusterMetrics) float64 { return m.InversePurity }, "%.4f")
+	addRow("NMI", func(m nlp.ClusterMetrics) float64 { return m.NMI }, "%.4f")
+	addRow("pair precision", func(m nlp.ClusterMetrics) float64 { return m.PairPrecision }, "%.4f")
+	addRow("pair recall", func(m nlp.ClusterMetrics) float64 { return m.PairRecall }, "%.4f")
+	addRow("pair F1", func(m nlp.ClusterMetrics) float64 { return m.PairF1 }, "%.4f")
+	addRow("簇数", func(m nlp.ClusterMetrics) float64 { return float64(m.Clusters) }, "%.0f")
+	addRow("标注簇数", func(m nlp.ClusterMetrics) float64 { return float64(m.GoldClusters) }, "%.0f")
+	addRow("单条簇数", func(m nlp.ClusterMetrics) float64 { return float64(m.Sizes.Singletons) }, "%.0f")
+	addRow("最大簇", func(m nlp.ClusterMetrics) float64 { return float64(m.Sizes.Max) }, "%.0f")
+	addRow("平均簇大小", func(m nlp.ClusterMetrics) float64 { return m.Sizes.Mean }, "%.2f")
+	addRow("簇大小中位数", func(m nlp.ClusterMetrics) float64 { return m.Sizes.Median }, "%.1f")
+	for i, bucket := range evaluations[0].Metrics.Sizes.Buckets {
+		index := i
+		addRow("大小 "+bucket.Label, func(m nlp.ClusterMetrics) float64 { return float64(m.Sizes.Buckets[index].Count) }, "%.0f")
+	}
+
+	durations := []string{"耗时"}
+	for _, evaluation := range evaluations {
+		durations = append(durations, evaluation.Duration)
+	}
+	rows = append(rows, durations)
+
+	printTable(rows)
+}
+
+// printTable 按显示宽度对齐输出表格，中文字符按两个宽度计算
+func printTable(rows [][]string) {
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := displayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		fmt.Println(line.String())
+	}
+}
+
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		if r > 0x2E80 {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/EasyPeek/EasyPeek-backend/internal/api"
+	"github.com/EasyPeek/EasyPeek-backend/internal/cache"
 	"github.com/EasyPeek/EasyPeek-backend/internal/config"
 	"github.com/EasyPeek/EasyPeek-backend/internal/database"
 	"github.com/EasyPeek/EasyPeek-backend/internal/models"
@@ -29,12 +30,47 @@ func main() {
 	}
 	defer database.CloseDatabase()
 
+	// initialize redis, view counts fall back to direct database updates without it
+	if err := cache.Initialize(cfg); err != nil {
+		log.Printf("Warning: Failed to initialize redis: %v", err)
+	}
+	defer cache.CloseCache()
+
 	// execute database migration
 	if err := database.Migrate(
 		&models.User{},
 		&models.Event{},
 		&models.RSSSource{},
 		&models.News{}, // 统一的新闻模型，支持手动创建和RSS抓取
+		&models.Comment{},
+		&models.CommentEdit{},
+		&models.SensitiveWord{},
+		&models.ModerationRecord{},
+		&models.KeywordGroup{},
+		&models.StopWord{},
+		&models.TaxonomyChange{},
+		&models.EventRedirect{},
+		&models.EventOperation{},
+		&models.EventMilestone{},
+		&models.EventStatusHistory{},
+		&models.EventGenerationPlan{},
+		&models.Job{},
+		&models.EventRelation{},
+		&models.Entity{},
+		&models.EntityAlias{},
+		&models.NewsEntity{},
+		&models.EventEntity{},
+		&models.EventSuggestionFeedback{},
+		&models.CategoryModel{},
+		&models.Category{},
+		&models.CategoryAlias{},
+		&models.NewsCategory{},
+		&models.EventCategory{},
+		&models.Tag{},
+		&models.TagAlias{},
+		&models.NewsTag{},
+		&models.EventTag{},
+		&models.SearchQueryLog{},
 	); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
@@ -46,6 +82,17 @@ func main() {
 
 	}
 
+	// 执行实例已退出、心跳超时的后台任务标记为失败
+	if err := services.NewJobService().RecoverInterruptedJobs(); err != nil {
+		log.Printf("Warning: Failed to recover interrupted jobs: %v", err)
+	}
+
+	// 预先建立搜索建议索引，之后按间隔在后台重建
+	go services.NewSearchService().WarmSuggestIndex()
+
+	// 为升级前已有、还没有全文索引的新闻和事件建立索引
+	go services.NewSearchService().BackfillSearchIndex()
+
 	// initialize RSS scheduler
 	rssScheduler := scheduler.NewRSSScheduler()
 	if err := rssScheduler.Start(); err != nil {
@@ -73,6 +120,7 @@ func main() {
 
 		log.Println("Shutting down server...")
 		rssScheduler.Stop()
+		services.StopJobs(10 * time.Second)
 
 		if err := server.Close(); err != nil {
 			log.Printf("Server shutdown error: %v", err)
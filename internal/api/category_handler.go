@@ -0,0 +1,206 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService *services.CategoryService
+}
+
+func NewCategoryHandler() *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: services.NewCategoryService(),
+	}
+}
+
+// GetCategories 获取分类列表
+// @Summary 获取分类列表
+// @Description 返回启用的分类及其关联的新闻数和事件数，默认按层级返回树；display_name 为 lang 指定语言的名称
+// @Tags categories
+// @Produce json
+// @Param lang query string false "显示名称的语言，如 en"
+// @Param flat query bool false "按排序返回平铺列表"
+// @Success 200 {object} utils.Response{data=[]models.CategoryResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/categories [get]
+func (h *CategoryHandler) GetCategories(c *gin.Context) {
+	var query models.CategoryQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	categories, err := h.categoryService.GetCategories(&query, false)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, categories)
+}
+
+// GetAdminCategories 获取分类列表（管理员）
+// @Summary 获取分类列表（管理员）
+// @Description 与公开接口相同，可以包含停用的分类
+// @Tags categories
+// @Security BearerAuth
+// @Produce json
+// @Param lang query string false "显示名称的语言，如 en"
+// @Param flat query bool false "按排序返回平铺列表"
+// @Param include_inactive query bool false "包含停用的分类"
+// @Success 200 {object} utils.Response{data=[]models.CategoryResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/categories [get]
+func (h *CategoryHandler) GetAdminCategories(c *gin.Context) {
+	var query models.CategoryQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	categories, err := h.categoryService.GetCategories(&query, true)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, categories)
+}
+
+// CreateCategory 创建分类
+// @Summary 创建分类
+// @Description 创建分类，名称、slug、各语言名称和别名都用于识别新闻和事件的分类字符串，不能与其他分类重复；创建后重新关联全部新闻和事件
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param category body models.CreateCategoryRequest true "分类信息"
+// @Success 201 {object} utils.Response{data=models.CategoryResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req models.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(&req)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Code:    201,
+		Message: "Category created successfully",
+		Data:    category,
+	})
+}
+
+// UpdateCategory 更新分类
+// @Summary 更新分类
+// @Description names 和 aliases 传入时整体替换，parent_id 为 0 表示移到顶层；名称或别名变化后重新关联全部新闻和事件
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "分类ID"
+// @Param category body models.UpdateCategoryRequest true "更新内容"
+// @Success 200 {object} utils.Response{data=models.CategoryResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	var req models.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(uint(id), &req)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	utils.Success(c, category)
+}
+
+// DeleteCategory 删除分类
+// @Summary 删除分类
+// @Description 删除分类及其与新闻和事件的关联，有子分类时不能删除；新闻和事件保留原有的分类字符串
+// @Tags categories
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "分类ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(uint(id)); err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Category deleted successfully"})
+}
+
+// MigrateCategories 从分类字符串迁移分类
+// @Summary 从分类字符串迁移分类
+// @Description 为事件、RSS 源和新闻中无法识别的分类字符串新建分类（RSS 条目的多个分类除外），然后重新关联全部新闻和事件
+// @Tags categories
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.MigrateCategoriesResult}
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/categories/migrate [post]
+func (h *CategoryHandler) MigrateCategories(c *gin.Context) {
+	result, err := h.categoryService.MigrateLegacyCategories()
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// respondCategoryError 将分类服务的错误映射为HTTP响应
+func respondCategoryError(c *gin.Context, err error) {
+	switch msg := err.Error(); {
+	case msg == "category not found":
+		utils.NotFound(c, msg)
+	case msg == "parent category not found", msg == "invalid parent category", msg == "invalid category slug",
+		msg == "category slug already exists", msg == "category has subcategories",
+		strings.HasPrefix(msg, "category name or alias already in use"):
+		utils.BadRequest(c, msg)
+	default:
+		utils.InternalServerError(c, msg)
+	}
+}
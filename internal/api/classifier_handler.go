@@ -0,0 +1,93 @@
+package api
+
+import (
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ClassifierHandler struct {
+	classifierService *services.CategoryClassifierService
+	jobService        *services.JobService
+}
+
+func NewClassifierHandler() *ClassifierHandler {
+	return &ClassifierHandler{
+		classifierService: services.NewCategoryClassifierService(),
+		jobService:        services.NewJobService(),
+	}
+}
+
+// GetClassifier 获取当前分类模型
+// @Summary 获取当前分类模型
+// @Description 返回最新训练的新闻分类模型在留出集上的准确率、各类别的精确率和召回率，以及混淆矩阵（行为新闻原有的分类，列为预测的分类）
+// @Tags classifier
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.CategoryModelResponse}
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/classifier [get]
+func (h *ClassifierHandler) GetClassifier(c *gin.Context) {
+	model, err := h.classifierService.GetLatestModel()
+	if err != nil {
+		respondClassifierError(c, err)
+		return
+	}
+
+	utils.Success(c, model)
+}
+
+// TrainClassifier 重新训练分类模型
+// @Summary 重新训练分类模型
+// @Description 提交 classifier_training 后台任务：用已有分类的新闻训练朴素贝叶斯分类模型，按新闻 ID 留出一部分新闻评估；只使用 RSS 源默认分类的新闻不参与训练。立即返回任务记录，任务结果为新模型的评估结果。训练完成后新抓取的新闻立即使用新模型，已有新闻可提交 news_classification 任务重新预测
+// @Tags classifier
+// @Security BearerAuth
+// @Produce json
+// @Success 202 {object} utils.Response{data=models.JobResponse}
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/classifier/train [post]
+func (h *ClassifierHandler) TrainClassifier(c *gin.Context) {
+	submitJob(c, h.jobService, &models.SubmitJobRequest{Type: models.JobTypeClassifierTraining})
+}
+
+// PredictCategory 预测新闻分类
+// @Summary 预测新闻分类
+// @Description 用当前分类模型预测一段新闻文本的分类，返回概率最高的几个类别
+// @Tags classifier
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.PredictCategoryRequest true "新闻标题和正文"
+// @Success 200 {object} utils.Response{data=models.CategoryPredictionResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/classifier/predict [post]
+func (h *ClassifierHandler) PredictCategory(c *gin.Context) {
+	var req models.PredictCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	prediction, err := h.classifierService.Predict(req.Title, req.Content)
+	if err != nil {
+		respondClassifierError(c, err)
+		return
+	}
+
+	utils.Success(c, prediction)
+}
+
+// respondClassifierError 将分类服务的错误映射为HTTP响应
+func respondClassifierError(c *gin.Context, err error) {
+	switch msg := err.Error(); msg {
+	case "classifier not trained":
+		utils.NotFound(c, msg)
+	default:
+		utils.InternalServerError(c, msg)
+	}
+}
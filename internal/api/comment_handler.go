@@ -0,0 +1,305 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService *services.CommentService
+}
+
+func NewCommentHandler() *CommentHandler {
+	return &CommentHandler{
+		commentService: services.NewCommentService(),
+	}
+}
+
+// GetEventComments 获取事件评论列表
+// @Summary 获取事件评论列表
+// @Description 获取事件的评论，顶层评论分页，回复随父评论返回
+// @Tags comments
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param sort_by query string false "排序方式" Enums(newest, top)
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response{data=models.CommentListResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/events/{id}/comments [get]
+func (h *CommentHandler) GetEventComments(c *gin.Context) {
+	h.getComments(c, models.CommentTargetEvent)
+}
+
+// CreateEventComment 发表事件评论
+// @Summary 发表事件评论
+// @Description 为事件发表评论或回复已有评论
+// @Tags comments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param comment body models.CreateCommentRequest true "评论内容"
+// @Success 201 {object} utils.Response{data=models.CommentResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/events/{id}/comments [post]
+func (h *CommentHandler) CreateEventComment(c *gin.Context) {
+	h.createComment(c, models.CommentTargetEvent)
+}
+
+// GetNewsComments 获取新闻评论列表
+// @Summary 获取新闻评论列表
+// @Description 获取新闻的评论，顶层评论分页，回复随父评论返回
+// @Tags comments
+// @Produce json
+// @Param id path int true "新闻ID"
+// @Param sort_by query string false "排序方式" Enums(newest, top)
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response{data=models.CommentListResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/news/{id}/comments [get]
+func (h *CommentHandler) GetNewsComments(c *gin.Context) {
+	h.getComments(c, models.CommentTargetNews)
+}
+
+// CreateNewsComment 发表新闻评论
+// @Summary 发表新闻评论
+// @Description 为新闻发表评论或回复已有评论
+// @Tags comments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "新闻ID"
+// @Param comment body models.CreateCommentRequest true "评论内容"
+// @Success 201 {object} utils.Response{data=models.CommentResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/news/{id}/comments [post]
+func (h *CommentHandler) CreateNewsComment(c *gin.Context) {
+	h.createComment(c, models.CommentTargetNews)
+}
+
+// UpdateComment 编辑评论
+// @Summary 编辑评论
+// @Description 评论作者或管理员编辑评论，编辑前的内容会记录到编辑历史
+// @Tags comments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "评论ID"
+// @Param comment body models.UpdateCommentRequest true "新的评论内容"
+// @Success 200 {object} utils.Response{data=models.CommentResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/comments/{id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid comment ID")
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, isAdmin, ok := currentUser(c)
+	if !ok {
+		utils.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(uint(id), userID, isAdmin, &req)
+	if err != nil {
+		respondCommentError(c, err, "Failed to update comment")
+		return
+	}
+
+	utils.Success(c, comment)
+}
+
+// DeleteComment 删除评论
+// @Summary 删除评论
+// @Description 评论作者或管理员删除评论（软删除），顶层评论的回复会一并删除
+// @Tags comments
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid comment ID")
+		return
+	}
+
+	userID, isAdmin, ok := currentUser(c)
+	if !ok {
+		utils.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.commentService.DeleteComment(uint(id), userID, isAdmin); err != nil {
+		respondCommentError(c, err, "Failed to delete comment")
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Comment deleted successfully"})
+}
+
+// GetCommentHistory 获取评论编辑历史
+// @Summary 获取评论编辑历史
+// @Description 获取评论的历次编辑前内容，按时间倒序；待审核和被拒绝的评论只有作者和管理员可以查看（需带上token）
+// @Tags comments
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} utils.Response{data=[]models.CommentEdit}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/comments/{id}/history [get]
+func (h *CommentHandler) GetCommentHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid comment ID")
+		return
+	}
+
+	viewerID, isAdmin, _ := optionalUser(c)
+	history, err := h.commentService.GetCommentHistory(uint(id), viewerID, isAdmin)
+	if err != nil {
+		respondCommentError(c, err, "Failed to get comment history")
+		return
+	}
+
+	utils.Success(c, history)
+}
+
+func (h *CommentHandler) getComments(c *gin.Context, targetType models.CommentTargetType) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID")
+		return
+	}
+
+	var query models.CommentQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	comments, err := h.commentService.GetComments(targetType, uint(id), &query)
+	if err != nil {
+		respondCommentError(c, err, "Failed to get comments")
+		return
+	}
+
+	utils.Success(c, comments)
+}
+
+func (h *CommentHandler) createComment(c *gin.Context, targetType models.CommentTargetType) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID")
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, ok := currentUser(c)
+	if !ok {
+		utils.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(targetType, uint(id), userID, &req)
+	if err != nil {
+		respondCommentError(c, err, "Failed to create comment")
+		return
+	}
+
+	message := "Comment created successfully"
+	if comment.Status == models.CommentStatusPending {
+		message = "Comment submitted and pending review"
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Code:    201,
+		Message: message,
+		Data:    comment,
+	})
+}
+
+// currentUser 从上下文中获取当前用户ID以及是否为管理员
+func currentUser(c *gin.Context) (uint, bool, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0, false, false
+	}
+	id, ok := userID.(uint)
+	if !ok {
+		return 0, false, false
+	}
+
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return id, roleStr == "admin" || roleStr == "system", true
+}
+
+// optionalUser 公开接口不经过认证中间件，带有有效token时识别当前用户，否则返回 ok=false
+func optionalUser(c *gin.Context) (uint, bool, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return 0, false, false
+	}
+	claims, err := utils.ParseToken(authHeader)
+	if err != nil {
+		return 0, false, false
+	}
+	return claims.UserID, claims.Role == "admin" || claims.Role == "system", true
+}
+
+// respondCommentError 将评论服务的错误映射为HTTP响应
+func respondCommentError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "event not found", "news not found", "comment not found", "parent comment not found":
+		utils.NotFound(c, err.Error())
+	case "permission denied":
+		utils.Forbidden(c, "You can only modify your own comments")
+	case "comment content cannot be empty", "parent comment does not belong to this target", "parent comment is not published",
+		"invalid comment target type",
+		services.ErrContentBlocked.Error():
+		utils.BadRequest(c, err.Error())
+	default:
+		utils.InternalServerError(c, fallback)
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type EntityHandler struct {
+	entityService *services.EntityService
+}
+
+func NewEntityHandler() *EntityHandler {
+	return &EntityHandler{
+		entityService: services.NewEntityService(),
+	}
+}
+
+// GetEntity 获取实体详情
+// @Summary 获取实体详情
+// @Description 返回人物、机构或地点的名称和别名，以及提到它的全部事件和分页的相关新闻
+// @Tags entities
+// @Produce json
+// @Param id path int true "实体ID"
+// @Param page query int false "新闻页码" default(1)
+// @Param limit query int false "每页新闻数量" default(20)
+// @Success 200 {object} utils.Response{data=models.EntityDetailResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/entities/{id} [get]
+func (h *EntityHandler) GetEntity(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid entity ID")
+		return
+	}
+
+	var query models.EntityDetailQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	detail, err := h.entityService.GetEntity(uint(id), &query)
+	if err != nil {
+		if err.Error() == "entity not found" {
+			utils.NotFound(c, "Entity not found")
+			return
+		}
+		utils.InternalServerError(c, "Failed to get entity")
+		return
+	}
+
+	utils.Success(c, detail)
+}
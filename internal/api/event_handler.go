@@ -1,8 +1,10 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/EasyPeek/EasyPeek-backend/internal/models"
 	"github.com/EasyPeek/EasyPeek-backend/internal/services"
@@ -11,26 +13,35 @@ import (
 )
 
 type EventHandler struct {
-	eventService *services.EventService
-	newsService  *services.NewsService
+	eventService   *services.EventService
+	newsService    *services.NewsService
+	commentService *services.CommentService
+	jobService     *services.JobService
 }
 
 func NewEventHandler() *EventHandler {
 	return &EventHandler{
-		eventService: services.NewEventService(),
-		newsService:  services.NewNewsService(),
+		eventService:   services.NewEventService(),
+		newsService:    services.NewNewsService(),
+		commentService: services.NewCommentService(),
+		jobService:     services.NewJobService(),
 	}
 }
 
 // GetEvents 获取事件列表
 // @Summary 获取事件列表
-// @Description 获取事件列表，支持分页、状态筛选、分类筛选、搜索和排序
+// @Description 获取事件列表，支持分页、状态筛选、分类筛选、搜索、地理范围筛选和排序
 // @Tags events
 // @Produce json
-// @Param status query string false "事件状态" Enums(进行中, 已结束)
-// @Param category query string false "事件分类"
-// @Param search query string false "搜索关键词"
-// @Param sort_by query string false "排序方式" Enums(time, hotness, views)
+// @Param status query string false "事件状态代码（也接受中文名称）" Enums(upcoming, ongoing, cooling, ended, archived)
+// @Param category query string false "事件分类，可以是分类的 slug、名称或别名，包含子分类"
+// @Param search query string false "全文搜索关键词，支持与 /api/v1/search 相同的语法，结果带有高亮片段，未指定 sort_by 时按相关度排序"
+// @Param sort_by query string false "排序方式，distance 需要同时指定 near" Enums(time, hotness, views, distance)
+// @Param near query string false "按距离筛选的中心点，格式 lat,lon"
+// @Param radius query number false "near 的半径（公里）" default(50)
+// @Param bbox query string false "按范围筛选，格式 minLon,minLat,maxLon,maxLat"
+// @Param entity_id query int false "只返回提到该实体的事件"
+// @Param facets query bool false "返回筛选结果中出现最多的人物、机构和地点"
 // @Param page query int false "页码" default(1)
 // @Param limit query int false "每页数量" default(10)
 // @Success 200 {object} utils.Response{data=models.EventListResponse}
@@ -49,7 +60,13 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 	// 获取事件列表
 	events, err := h.eventService.GetEvents(&query)
 	if err != nil {
-		utils.InternalServerError(c, "Failed to get events")
+		switch msg := err.Error(); msg {
+		case "invalid near parameter", "invalid radius parameter", "invalid bbox parameter",
+			"sort by distance requires near parameter":
+			utils.BadRequest(c, msg)
+		default:
+			utils.InternalServerError(c, "Failed to get events")
+		}
 		return
 	}
 
@@ -58,7 +75,7 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 
 // GetEvent 根据ID获取事件
 // @Summary 根据ID获取事件
-// @Description 根据ID获取单个事件详情（会增加浏览量并更新热度）
+// @Description 根据ID获取单个事件详情（会记录浏览量，同一访客短时间内重复浏览只计一次）
 // @Tags events
 // @Produce json
 // @Param id path int true "事件ID"
@@ -75,7 +92,7 @@ func (h *EventHandler) GetEvent(c *gin.Context) {
 		return
 	}
 
-	event, err := h.eventService.ViewEvent(uint(id))
+	event, err := h.eventService.ViewEvent(uint(id), viewerKey(c))
 	if err != nil {
 		if err.Error() == "event not found" {
 			utils.NotFound(c, "Event not found")
@@ -134,6 +151,7 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 // @Success 200 {object} utils.Response{data=models.EventResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Security BearerAuth
 // @Router /api/v1/events/{id} [put]
@@ -157,6 +175,14 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 			utils.NotFound(c, "Event not found")
 			return
 		}
+		if err.Error() == "invalid event status" {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid status transition") {
+			utils.Error(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.InternalServerError(c, "Failed to update event")
 		return
 	}
@@ -202,7 +228,7 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 // @Description 根据状态获取事件列表
 // @Tags events
 // @Produce json
-// @Param status path string true "事件状态" Enums(进行中, 已结束)
+// @Param status path string true "事件状态代码（也接受中文名称）" Enums(upcoming, ongoing, cooling, ended, archived)
 // @Success 200 {object} utils.Response{data=[]models.EventResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -211,7 +237,7 @@ func (h *EventHandler) GetEventsByStatus(c *gin.Context) {
 	status := c.Param("status")
 
 	// 验证状态值
-	if status != "进行中" && status != "已结束" {
+	if _, ok := models.NormalizeEventStatus(status); !ok {
 		utils.BadRequest(c, "Invalid status")
 		return
 	}
@@ -253,7 +279,7 @@ func (h *EventHandler) GetHotEvents(c *gin.Context) {
 
 // GetEventCategories 获取事件分类列表
 // @Summary 获取事件分类列表
-// @Description 获取所有可用的事件分类
+// @Description 返回关联了事件的启用分类名称，按分类的排序顺序排列；完整的分类信息见 /api/v1/categories
 // @Tags events
 // @Produce json
 // @Success 200 {object} utils.Response{data=[]string}
@@ -271,7 +297,7 @@ func (h *EventHandler) GetEventCategories(c *gin.Context) {
 
 // IncrementViewCount 增加事件浏览次数
 // @Summary 增加事件浏览次数
-// @Description 记录事件被查看，增加浏览次数
+// @Description 记录事件被查看，同一访客短时间内重复浏览只计一次
 // @Tags events
 // @Produce json
 // @Param id path int true "事件ID"
@@ -288,8 +314,12 @@ func (h *EventHandler) IncrementViewCount(c *gin.Context) {
 		return
 	}
 
-	err = h.eventService.IncrementViewCount(uint(id))
+	err = h.eventService.IncrementViewCount(uint(id), viewerKey(c))
 	if err != nil {
+		if err.Error() == "event not found" {
+			utils.NotFound(c, "Event not found")
+			return
+		}
 		utils.InternalServerError(c, "Failed to increment view count")
 		return
 	}
@@ -302,7 +332,7 @@ func (h *EventHandler) IncrementViewCount(c *gin.Context) {
 // @Description 根据分类获取事件列表，支持分页和排序
 // @Tags events
 // @Produce json
-// @Param category path string true "事件分类"
+// @Param category path string true "事件分类，可以是分类的 slug、名称或别名，包含子分类"
 // @Param page query int false "页码" default(1)
 // @Param limit query int false "每页数量" default(10)
 // @Param sort_by query string false "排序方式" Enums(time, hotness, views)
@@ -334,7 +364,7 @@ func (h *EventHandler) GetEventsByCategory(c *gin.Context) {
 
 // GetPopularTags 获取热门标签
 // @Summary 获取热门标签
-// @Description 获取使用频率最高的标签列表
+// @Description 按关联的事件数返回最常用的标签，同义词计入同一个标签；category 为带有该标签的事件中最常见的分类
 // @Tags events
 // @Produce json
 // @Param limit query int false "返回标签数量" default(50)
@@ -400,7 +430,7 @@ func (h *EventHandler) GetTrendingEvents(c *gin.Context) {
 
 // UpdateEventTags 更新事件标签
 // @Summary 更新事件标签
-// @Description 管理员更新事件标签，支持替换、添加、删除操作
+// @Description 管理员更新事件标签，支持替换、添加、删除操作；标签按名称和同义词识别并保存为规范名称，移除同义词会移除对应的标签
 // @Tags events
 // @Security BearerAuth
 // @Accept json
@@ -593,14 +623,17 @@ func (h *EventHandler) ShareEvent(c *gin.Context) {
 
 // AddComment 添加评论
 // @Summary 添加评论
-// @Description 为事件添加评论，增加评论计数
+// @Description 为事件添加评论（兼容旧接口，等同于 POST /api/v1/events/{id}/comments）
 // @Tags events
 // @Security BearerAuth
+// @Accept json
 // @Produce json
 // @Param id path int true "事件ID"
-// @Success 200 {object} utils.Response
+// @Param comment body models.CreateCommentRequest true "评论内容"
+// @Success 200 {object} utils.Response{data=models.CommentResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /api/v1/events/{id}/comment [post]
 func (h *EventHandler) AddComment(c *gin.Context) {
@@ -611,15 +644,25 @@ func (h *EventHandler) AddComment(c *gin.Context) {
 		return
 	}
 
-	// 这里简化处理，只增加评论计数
-	// 实际应用中应该保存评论内容到评论表
-	err = h.eventService.IncrementCommentCount(uint(id))
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	userID, _, ok := currentUser(c)
+	if !ok {
+		utils.Unauthorized(c, "User not found")
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(models.CommentTargetEvent, uint(id), userID, &req)
 	if err != nil {
-		utils.InternalServerError(c, "Failed to add comment")
+		respondCommentError(c, err, "Failed to add comment")
 		return
 	}
 
-	utils.Success(c, gin.H{"message": "Comment added successfully"})
+	utils.Success(c, comment)
 }
 
 // GetEventStats 获取事件统计信息
@@ -656,25 +699,156 @@ func (h *EventHandler) GetEventStats(c *gin.Context) {
 
 // GenerateEventsFromNews 从新闻自动生成事件
 // @Summary 从新闻自动生成事件
-// @Description 基于现有新闻数据自动生成事件，会自动聚类相似新闻并建立关联
+// @Description 基于现有新闻数据自动生成事件，会自动聚类相似新闻并建立关联。
+// @Description mode=incremental 时只处理未关联事件的新闻，优先归入开放中的事件，其余聚类为新事件
+// @Description dry_run=true 时只返回提案（标题、新闻、标签、时间跨度、归入的已有事件），不修改事件和新闻，审核后通过 /events/generate/commit 提交
+// @Description async=true 时提交为后台任务并返回任务记录，通过 /admin/jobs/{id} 查询进度和结果
 // @Tags events
 // @Security BearerAuth
 // @Produce json
+// @Param mode query string false "生成模式" Enums(full, incremental) default(full)
+// @Param dry_run query bool false "只预演不写入" default(false)
+// @Param async query bool false "作为后台任务执行" default(false)
 // @Success 200 {object} utils.Response{data=services.EventGenerationResult}
+// @Success 201 {object} utils.Response{data=models.EventGenerationPreview}
+// @Success 202 {object} utils.Response{data=models.JobResponse}
+// @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /api/v1/events/generate [post]
 func (h *EventHandler) GenerateEventsFromNews(c *gin.Context) {
+	mode := c.DefaultQuery("mode", "full")
+	if mode != "full" && mode != "incremental" {
+		utils.BadRequest(c, "Invalid mode, must be full or incremental")
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid dry_run parameter")
+		return
+	}
+	async, err := strconv.ParseBool(c.DefaultQuery("async", "false"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid async parameter")
+		return
+	}
+	if async {
+		submitJob(c, h.jobService, &models.SubmitJobRequest{
+			Type:   models.JobTypeEventGeneration,
+			Mode:   mode,
+			DryRun: dryRun,
+		})
+		return
+	}
+
+	if dryRun {
+		userID, _, _ := currentUser(c)
+		preview, err := h.eventService.PreviewEventGeneration(mode, userID)
+		if err != nil {
+			respondEventGenerationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, utils.Response{
+			Code:    201,
+			Message: "Event generation plan created",
+			Data:    preview,
+		})
+		return
+	}
+
 	// 调用事件生成服务
-	result, err := h.eventService.GenerateEventsFromNews()
+	var result *services.EventGenerationResult
+	if mode == "full" {
+		result, err = h.eventService.GenerateEventsFromNews()
+	} else {
+		result, err = h.eventService.GenerateEventsIncrementally()
+	}
 	if err != nil {
-		utils.InternalServerError(c, err.Error())
+		respondEventGenerationError(c, err)
 		return
 	}
 
 	utils.Success(c, result)
 }
 
+// GetEventGenerationPlan 获取事件生成计划
+// @Summary 获取事件生成计划
+// @Description 获取预演生成的提案及各提案的提交情况
+// @Tags events
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "计划ID"
+// @Success 200 {object} utils.Response{data=models.EventGenerationPreview}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/events/generate/plans/{id} [get]
+func (h *EventHandler) GetEventGenerationPlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid plan ID")
+		return
+	}
+
+	preview, err := h.eventService.GetEventGenerationPlan(uint(id))
+	if err != nil {
+		respondEventGenerationError(c, err)
+		return
+	}
+
+	utils.Success(c, preview)
+}
+
+// CommitEventProposals 提交事件生成提案
+// @Summary 提交事件生成提案
+// @Description 提交预演生成计划中选中的提案。预演后新闻归属发生变化、新闻被删除或要归入的事件已不存在的提案会被跳过并返回原因
+// @Tags events
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CommitEventProposalsRequest true "计划ID和选中的提案"
+// @Success 200 {object} utils.Response{data=models.CommitEventProposalsResult}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/events/generate/commit [post]
+func (h *EventHandler) CommitEventProposals(c *gin.Context) {
+	var req models.CommitEventProposalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.eventService.CommitEventProposals(&req)
+	if err != nil {
+		respondEventGenerationError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// respondEventGenerationError 将事件生成相关错误映射为HTTP响应
+func respondEventGenerationError(c *gin.Context, err error) {
+	switch msg := err.Error(); {
+	case errors.Is(err, services.ErrEventGenerationRunning):
+		utils.Error(c, http.StatusConflict, msg)
+	case msg == "generation plan not found":
+		utils.NotFound(c, msg)
+	case msg == "generation plan expired":
+		utils.Error(c, http.StatusGone, msg)
+	case msg == "invalid generation mode":
+		utils.BadRequest(c, msg)
+	default:
+		utils.InternalServerError(c, msg)
+	}
+}
+
 // GetNewsByEventID 根据事件ID获取相关新闻
 // @Summary 根据事件ID获取相关新闻
 // @Description 获取指定事件相关联的所有新闻列表
@@ -720,3 +894,127 @@ func (h *EventHandler) GetNewsByEventID(c *gin.Context) {
 
 	utils.Success(c, newsResponses)
 }
+
+// GetEventTimeline 获取事件时间线
+// @Summary 获取事件时间线
+// @Description 按天或小时将事件当前关联的新闻和编辑添加的里程碑组织为时间线，标记首条报道和最新进展，并统计各时段的来源多样性
+// @Tags events
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param granularity query string false "分组粒度" Enums(day, hour) default(day)
+// @Param order query string false "时间顺序" Enums(asc, desc) default(asc)
+// @Success 200 {object} utils.Response{data=models.EventTimelineResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/events/{id}/timeline [get]
+func (h *EventHandler) GetEventTimeline(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	var query models.EventTimelineQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	timeline, err := h.eventService.GetEventTimeline(uint(id), &query)
+	if err != nil {
+		if err.Error() == "event not found" {
+			utils.NotFound(c, "Event not found")
+			return
+		}
+		utils.InternalServerError(c, "Failed to get event timeline")
+		return
+	}
+
+	utils.Success(c, timeline)
+}
+
+// GetEventStatuses 获取事件状态列表
+// @Summary 获取事件状态列表
+// @Description 按生命周期顺序返回全部事件状态代码、中英文名称和允许直接转换到的状态
+// @Tags events
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.EventStatusInfo}
+// @Router /api/v1/events/statuses [get]
+func (h *EventHandler) GetEventStatuses(c *gin.Context) {
+	utils.Success(c, models.EventStatusInfos())
+}
+
+// GetEventStatusHistory 获取事件状态变化记录
+// @Summary 获取事件状态变化记录
+// @Description 按时间倒序返回事件的状态转换记录，包括定时任务、新闻变化和管理员修改
+// @Tags events
+// @Produce json
+// @Param id path int true "事件ID"
+// @Success 200 {object} utils.Response{data=[]models.EventStatusHistory}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/events/{id}/status-history [get]
+func (h *EventHandler) GetEventStatusHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	history, err := h.eventService.GetEventStatusHistory(uint(id))
+	if err != nil {
+		if err.Error() == "event not found" {
+			utils.NotFound(c, "Event not found")
+			return
+		}
+		utils.InternalServerError(c, "Failed to get event status history")
+		return
+	}
+
+	utils.Success(c, history)
+}
+
+// GetRelatedEvents 获取相关事件
+// @Summary 获取相关事件
+// @Description 返回与事件有关系（后续进展、起因、同一系列、相关）的事件，按关系置信度和类型权重排序；label 是从当前事件看相关事件的关系名称
+// @Tags events
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param type query string false "关系类型" Enums(follow-up-of, caused-by, related-to, part-of-series)
+// @Param min_confidence query number false "最低置信度"
+// @Param limit query int false "返回数量" default(10)
+// @Success 200 {object} utils.Response{data=[]models.RelatedEventResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/events/{id}/related [get]
+func (h *EventHandler) GetRelatedEvents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	var query models.RelatedEventQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	related, err := h.eventService.GetRelatedEvents(uint(id), &query)
+	if err != nil {
+		switch err.Error() {
+		case "event not found":
+			utils.NotFound(c, "Event not found")
+		case "invalid relation type":
+			utils.BadRequest(c, "Invalid relation type")
+		default:
+			utils.InternalServerError(c, "Failed to get related events")
+		}
+		return
+	}
+
+	utils.Success(c, related)
+}
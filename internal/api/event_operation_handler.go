@@ -0,0 +1,561 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type EventOperationHandler struct {
+	eventService *services.EventService
+	jobService   *services.JobService
+}
+
+func NewEventOperationHandler() *EventOperationHandler {
+	return &EventOperationHandler{
+		eventService: services.NewEventService(),
+		jobService:   services.NewJobService(),
+	}
+}
+
+// MergeEvents 合并事件
+// @Summary 合并事件
+// @Description 将多个事件合并到目标事件：新闻和评论移到目标事件，计数累加，标签和相关链接取并集，被合并事件的ID重定向到目标事件
+// @Tags event-operations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.MergeEventsRequest true "合并信息"
+// @Success 200 {object} utils.Response{data=models.EventOperationResult}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/merge [post]
+func (h *EventOperationHandler) MergeEvents(c *gin.Context) {
+	var req models.MergeEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	result, err := h.eventService.MergeEvents(&req, userID)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// SplitEvent 拆分事件
+// @Summary 拆分事件
+// @Description 将事件中的部分新闻移到新事件，未指定标题时使用最早一条新闻的标题
+// @Tags event-operations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param request body models.SplitEventRequest true "拆分信息"
+// @Success 201 {object} utils.Response{data=models.EventOperationResult}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/{id}/split [post]
+func (h *EventOperationHandler) SplitEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	var req models.SplitEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	result, err := h.eventService.SplitEvent(uint(id), &req, userID)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Code:    201,
+		Message: "Event split successfully",
+		Data:    result,
+	})
+}
+
+// GetOperations 获取事件合并/拆分记录
+// @Summary 获取事件合并/拆分记录
+// @Tags event-operations
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "操作类型" Enums(merge, split)
+// @Param event_id query int false "涉及的事件ID"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.PageResponse{data=[]models.EventOperation}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/operations [get]
+func (h *EventOperationHandler) GetOperations(c *gin.Context) {
+	var query models.EventOperationQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	operations, total, err := h.eventService.GetEventOperations(&query)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, operations, total, query.Page, query.Limit)
+}
+
+// UndoOperation 撤销事件合并/拆分
+// @Summary 撤销事件合并/拆分
+// @Description 按操作前的快照恢复事件、新闻和评论的归属；涉及的事件之后又被整理过时需要先撤销后面的操作
+// @Tags event-operations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "操作记录ID"
+// @Success 200 {object} utils.Response{data=models.EventOperationResult}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/operations/{id}/undo [post]
+func (h *EventOperationHandler) UndoOperation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid operation ID")
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	result, err := h.eventService.UndoEventOperation(uint(id), userID)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// CreateMilestone 添加事件里程碑
+// @Summary 添加事件里程碑
+// @Description 编辑为事件添加里程碑，出现在事件时间线对应时段
+// @Tags event-operations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param milestone body models.CreateMilestoneRequest true "里程碑信息"
+// @Success 201 {object} utils.Response{data=models.EventMilestone}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/{id}/milestones [post]
+func (h *EventOperationHandler) CreateMilestone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	var req models.CreateMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	milestone, err := h.eventService.CreateMilestone(uint(id), &req, userID)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Code:    201,
+		Message: "Milestone created successfully",
+		Data:    milestone,
+	})
+}
+
+// UpdateMilestone 更新事件里程碑
+// @Summary 更新事件里程碑
+// @Tags event-operations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "里程碑ID"
+// @Param milestone body models.UpdateMilestoneRequest true "更新内容"
+// @Success 200 {object} utils.Response{data=models.EventMilestone}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/milestones/{id} [put]
+func (h *EventOperationHandler) UpdateMilestone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid milestone ID")
+		return
+	}
+
+	var req models.UpdateMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	milestone, err := h.eventService.UpdateMilestone(uint(id), &req)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, milestone)
+}
+
+// DeleteMilestone 删除事件里程碑
+// @Summary 删除事件里程碑
+// @Tags event-operations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "里程碑ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/milestones/{id} [delete]
+func (h *EventOperationHandler) DeleteMilestone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid milestone ID")
+		return
+	}
+
+	if err := h.eventService.DeleteMilestone(uint(id)); err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Milestone deleted successfully"})
+}
+
+// ChangeEventStatus 修改事件状态
+// @Summary 修改事件状态
+// @Description 管理员按生命周期手动修改事件状态，只允许直接转换（如 ongoing→ended、ended→archived、archived→ended），转换会记录到状态历史
+// @Tags event-operations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param request body models.TransitionEventStatusRequest true "目标状态"
+// @Success 200 {object} utils.Response{data=models.EventResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/{id}/status [post]
+func (h *EventOperationHandler) ChangeEventStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	var req models.TransitionEventStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	event, err := h.eventService.ChangeEventStatus(uint(id), &req, &userID)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, event)
+}
+
+// RunLifecycleTransitions 立即推进事件状态
+// @Summary 立即推进事件状态
+// @Description 立即执行一次定时状态转换：按开始/结束时间和最近报道时间将事件推进到 ongoing、cooling、ended 或 archived
+// @Tags event-operations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.EventLifecycleResult}
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/lifecycle/run [post]
+func (h *EventOperationHandler) RunLifecycleTransitions(c *gin.Context) {
+	result, err := h.eventService.RunLifecycleTransitions()
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// GeocodeEvents 重新解析事件地理位置
+// @Summary 重新解析事件地理位置
+// @Description 按内置地名库重新解析事件的地点编码和经纬度；reextract 为 true 时先从关联新闻的标题和正文重新抽取地点
+// @Tags event-operations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.GeocodeEventsRequest false "处理范围"
+// @Success 200 {object} utils.Response{data=models.GeocodeEventsResult}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/geocode [post]
+func (h *EventOperationHandler) GeocodeEvents(c *gin.Context) {
+	var req models.GeocodeEventsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	result, err := h.eventService.GeocodeEvents(&req)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// SuggestEventRelations 推荐事件关系
+// @Summary 推荐事件关系
+// @Description 按共同标签、地点和标题描述的文本相似度推荐可能相关的事件，不包括已有关系或删除过关系的事件
+// @Tags event-operations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param limit query int false "返回数量" default(10)
+// @Success 200 {object} utils.Response{data=[]models.EventRelationSuggestion}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/{id}/relation-suggestions [get]
+func (h *EventOperationHandler) SuggestEventRelations(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	suggestions, err := h.eventService.SuggestEventRelations(uint(id), limit)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, suggestions)
+}
+
+// CreateEventRelation 添加事件关系
+// @Summary 添加事件关系
+// @Description 添加从路径中的事件指向 to_event_id 的关系。follow-up-of 表示路径中的事件是后续进展，caused-by 表示路径中的事件由 to_event_id 引起；
+// @Description 已有的同类关系（包括删除过的自动关系）改为管理员添加
+// @Tags event-operations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "事件ID"
+// @Param request body models.CreateEventRelationRequest true "关系信息"
+// @Success 201 {object} utils.Response{data=models.EventRelation}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/{id}/relations [post]
+func (h *EventOperationHandler) CreateEventRelation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	var req models.CreateEventRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	relation, err := h.eventService.CreateEventRelation(uint(id), &req, userID)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Code:    201,
+		Message: "Event relation created successfully",
+		Data:    relation,
+	})
+}
+
+// DeleteEventRelation 删除事件关系
+// @Summary 删除事件关系
+// @Description 删除后不会再自动建立这两个事件之间的关系
+// @Tags event-operations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "关系ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/relations/{id} [delete]
+func (h *EventOperationHandler) DeleteEventRelation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid relation ID")
+		return
+	}
+
+	if err := h.eventService.DeleteEventRelation(uint(id)); err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Event relation deleted successfully"})
+}
+
+// AutoLinkEvents 自动建立事件关系
+// @Summary 自动建立事件关系
+// @Description 提交 event_relation_link 后台任务：为最近30天开始的事件计算关系，置信度足够高的自动建立，已有的自动关系按最新结果更新；管理员添加或删除过的关系不受影响。立即返回任务记录，之后轮询 GET /api/v1/admin/jobs/{id}
+// @Tags event-operations
+// @Security BearerAuth
+// @Produce json
+// @Success 202 {object} utils.Response{data=models.JobResponse}
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/relations/auto [post]
+func (h *EventOperationHandler) AutoLinkEvents(c *gin.Context) {
+	submitJob(c, h.jobService, &models.SubmitJobRequest{Type: models.JobTypeEventRelationLink})
+}
+
+// SuggestEventsForNews 推荐新闻所属的事件
+// @Summary 推荐新闻所属的事件
+// @Description 在时间相近的事件中按文本相似度推荐新闻可能所属的事件，给出相似度、是否达到增量生成的归入阈值和共同关键词；提到不同地域的事件、新闻当前所属的事件和拒绝过的事件不推荐
+// @Tags news
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "新闻ID"
+// @Param limit query int false "返回数量" default(5)
+// @Success 200 {object} utils.Response{data=models.NewsEventSuggestionsResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/news/{id}/event-suggestions [get]
+func (h *EventOperationHandler) SuggestEventsForNews(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid news ID")
+		return
+	}
+
+	var query models.EventSuggestionQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	suggestions, err := h.eventService.SuggestEventsForNews(uint(id), &query)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, suggestions)
+}
+
+// ReviewEventSuggestions 批量审核推荐的事件
+// @Summary 批量审核推荐的事件
+// @Description 接受的新闻归入事件并更新事件的时间跨度、标签和实体，拒绝的事件不再推荐给该新闻；审核结果连同当时的相似度保存，用于评估归入阈值。新闻已属于其他事件或同一条新闻接受了多个事件时跳过并说明原因
+// @Tags news
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.ReviewEventSuggestionsRequest true "审核结果"
+// @Success 200 {object} utils.Response{data=models.ReviewEventSuggestionsResult}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/news/event-suggestions/review [post]
+func (h *EventOperationHandler) ReviewEventSuggestions(c *gin.Context) {
+	var req models.ReviewEventSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	result, err := h.eventService.ReviewEventSuggestions(&req, userID)
+	if err != nil {
+		respondEventOperationError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// GetSuggestionFeedbackStats 推荐事件的审核统计
+// @Summary 推荐事件的审核统计
+// @Description 按相似度区间统计接受和拒绝的数量，给出当前归入阈值的准确率和召回率；审核记录足够时给出准确率最高的阈值，可据此调整 clustering.attach_threshold
+// @Tags event-operations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.SuggestionFeedbackStats}
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/events/suggestion-feedback [get]
+func (h *EventOperationHandler) GetSuggestionFeedbackStats(c *gin.Context) {
+	stats, err := h.eventService.GetSuggestionFeedbackStats()
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, stats)
+}
+
+// respondEventOperationError 将事件整理服务的错误映射为HTTP响应
+func respondEventOperationError(c *gin.Context, err error) {
+	switch msg := err.Error(); {
+	case msg == "event not found", msg == "news not found", msg == "event operation not found",
+		msg == "milestone not found", msg == "event relation not found":
+		utils.NotFound(c, msg)
+	case msg == "event operation already undone", strings.Contains(msg, "was changed by later operation"),
+		msg == "reverse relation already exists":
+		utils.Error(c, http.StatusConflict, msg)
+	case msg == "cannot merge an event into itself", msg == "news does not belong to this event",
+		msg == "no source events", msg == "no news to split",
+		msg == "cannot move all news out of the event", msg == "invalid event status",
+		msg == "cannot relate an event to itself", msg == "invalid relation type":
+		utils.BadRequest(c, msg)
+	case strings.HasPrefix(msg, "invalid status transition"):
+		utils.Error(c, http.StatusConflict, msg)
+	default:
+		utils.InternalServerError(c, msg)
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	jobService *services.JobService
+}
+
+func NewJobHandler() *JobHandler {
+	return &JobHandler{
+		jobService: services.NewJobService(),
+	}
+}
+
+// SubmitJob 提交后台任务
+// @Summary 提交后台任务
+// @Description 在后台执行耗时的管理操作，立即返回任务记录，通过 GET /api/v1/admin/jobs/{id} 查询进度。
+// @Description type=event_generation 时可指定 mode（full/incremental）和 dry_run；type=rss_fetch_all 抓取所有活跃RSS源；type=entity_extraction 重新识别全部新闻的实体；type=news_tagging 重新提取全部新闻的关键词标签；type=news_classification 用最新的分类模型重新预测全部新闻的分类；type=search_index 重建全部新闻和事件的全文搜索索引；type=summary_regeneration 按 summaries 指定的范围重新生成新闻摘要和事件描述；type=event_relation_link 为最近30天开始的事件自动建立关系；type=classifier_training 重新训练新闻分类模型。同类任务同时只执行一个
+// @Tags jobs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.SubmitJobRequest true "任务类型和参数"
+// @Success 202 {object} utils.Response{data=models.JobResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/jobs [post]
+func (h *JobHandler) SubmitJob(c *gin.Context) {
+	var req models.SubmitJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	submitJob(c, h.jobService, &req)
+}
+
+// submitJob 提交后台任务并返回 202
+func submitJob(c *gin.Context, jobService *services.JobService, req *models.SubmitJobRequest) {
+	userID, _, _ := currentUser(c)
+	job, err := jobService.SubmitJob(req, userID)
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.Response{
+		Code:    202,
+		Message: "Job submitted",
+		Data:    job,
+	})
+}
+
+// GetJobs 获取后台任务列表
+// @Summary 获取后台任务列表
+// @Description 按提交时间倒序返回任务，不包含结果
+// @Tags jobs
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "任务类型" Enums(event_generation, rss_fetch_all, entity_extraction, news_tagging, news_classification, search_index, summary_regeneration, event_relation_link, classifier_training)
+// @Param status query string false "任务状态" Enums(queued, running, succeeded, failed, canceled)
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.PageResponse{data=[]models.JobResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/jobs [get]
+func (h *JobHandler) GetJobs(c *gin.Context) {
+	var query models.JobQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	jobs, total, err := h.jobService.GetJobs(&query)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, jobs, total, query.Page, query.Limit)
+}
+
+// GetJob 获取后台任务详情
+// @Summary 获取后台任务详情
+// @Description 返回任务状态、进度、部分结果（执行中）或最终结果，以及执行过程中的错误
+// @Tags jobs
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "任务ID"
+// @Success 200 {object} utils.Response{data=models.JobResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid job ID")
+		return
+	}
+
+	job, err := h.jobService.GetJob(uint(id))
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	utils.Success(c, job)
+}
+
+// CancelJob 取消后台任务
+// @Summary 取消后台任务
+// @Description 执行中的任务在完成当前步骤（一个事件或一个RSS源）后停止，已完成部分保留在结果中
+// @Tags jobs
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "任务ID"
+// @Success 200 {object} utils.Response{data=models.JobResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/jobs/{id}/cancel [post]
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid job ID")
+		return
+	}
+
+	job, err := h.jobService.CancelJob(uint(id))
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	utils.Success(c, job)
+}
+
+// respondJobError 将后台任务相关错误映射为HTTP响应
+func respondJobError(c *gin.Context, err error) {
+	switch msg := err.Error(); {
+	case errors.Is(err, services.ErrJobTypeRunning), msg == "job already finished":
+		utils.Error(c, http.StatusConflict, msg)
+	case msg == "job not found":
+		utils.NotFound(c, msg)
+	case msg == "invalid job type", msg == "invalid generation mode", msg == "invalid summary target":
+		utils.BadRequest(c, msg)
+	default:
+		utils.InternalServerError(c, msg)
+	}
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	moderationService *services.ModerationService
+}
+
+func NewModerationHandler() *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: services.NewModerationService(),
+	}
+}
+
+// GetQueue 获取审核队列
+// @Summary 获取审核队列
+// @Description 获取待审核（或指定状态）的内容列表，按提交时间先后排序
+// @Tags moderation
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "审核状态" Enums(pending, approved, rejected, superseded, all) default(pending)
+// @Param target_type query string false "内容类型" Enums(comment, news)
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.PageResponse{data=[]models.ModerationRecord}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/moderation/queue [get]
+func (h *ModerationHandler) GetQueue(c *gin.Context) {
+	var query models.ModerationQueueQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	records, total, err := h.moderationService.GetQueue(&query)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, records, total, query.Page, query.Limit)
+}
+
+// ApproveContent 审核通过
+// @Summary 审核通过
+// @Description 审核通过后内容恢复公开展示
+// @Tags moderation
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "审核记录ID"
+// @Param request body models.ModerationReviewRequest false "审核理由"
+// @Success 200 {object} utils.Response{data=models.ModerationRecord}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/moderation/queue/{id}/approve [post]
+func (h *ModerationHandler) ApproveContent(c *gin.Context) {
+	h.review(c, true)
+}
+
+// RejectContent 审核拒绝
+// @Summary 审核拒绝
+// @Description 审核拒绝，需要填写理由，内容保持不可见
+// @Tags moderation
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "审核记录ID"
+// @Param request body models.ModerationReviewRequest true "拒绝理由"
+// @Success 200 {object} utils.Response{data=models.ModerationRecord}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/moderation/queue/{id}/reject [post]
+func (h *ModerationHandler) RejectContent(c *gin.Context) {
+	h.review(c, false)
+}
+
+// GetWords 获取敏感词列表
+// @Summary 获取敏感词列表
+// @Tags moderation
+// @Security BearerAuth
+// @Produce json
+// @Param category query string false "分类"
+// @Param severity query string false "严重程度" Enums(low, medium, high)
+// @Param search query string false "搜索关键词"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.PageResponse{data=[]models.SensitiveWord}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/moderation/words [get]
+func (h *ModerationHandler) GetWords(c *gin.Context) {
+	var query models.SensitiveWordQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	words, total, err := h.moderationService.GetWords(&query)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, words, total, query.Page, query.Limit)
+}
+
+// CreateWord 添加敏感词
+// @Summary 添加敏感词
+// @Description 添加敏感词，可附带拼音写法以识别拼音变体
+// @Tags moderation
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param word body models.CreateSensitiveWordRequest true "敏感词信息"
+// @Success 201 {object} utils.Response{data=models.SensitiveWord}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/moderation/words [post]
+func (h *ModerationHandler) CreateWord(c *gin.Context) {
+	var req models.CreateSensitiveWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	word, err := h.moderationService.CreateWord(&req, userID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Code:    201,
+		Message: "Sensitive word created successfully",
+		Data:    word,
+	})
+}
+
+// UpdateWord 更新敏感词
+// @Summary 更新敏感词
+// @Tags moderation
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "敏感词ID"
+// @Param word body models.UpdateSensitiveWordRequest true "更新内容"
+// @Success 200 {object} utils.Response{data=models.SensitiveWord}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/moderation/words/{id} [put]
+func (h *ModerationHandler) UpdateWord(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid word ID")
+		return
+	}
+
+	var req models.UpdateSensitiveWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	word, err := h.moderationService.UpdateWord(uint(id), &req)
+	if err != nil {
+		if err.Error() == "sensitive word not found" {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, word)
+}
+
+// DeleteWord 删除敏感词
+// @Summary 删除敏感词
+// @Tags moderation
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "敏感词ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/moderation/words/{id} [delete]
+func (h *ModerationHandler) DeleteWord(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid word ID")
+		return
+	}
+
+	if err := h.moderationService.DeleteWord(uint(id)); err != nil {
+		if err.Error() == "sensitive word not found" {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Sensitive word deleted successfully"})
+}
+
+// CheckText 检测文本
+// @Summary 检测文本
+// @Description 使用当前词库检测一段文本，返回命中的敏感词和处理动作，便于调试词库
+// @Tags moderation
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.ModerationCheckRequest true "待检测文本"
+// @Success 200 {object} utils.Response{data=models.ModerationResult}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/moderation/check [post]
+func (h *ModerationHandler) CheckText(c *gin.Context) {
+	var req models.ModerationCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.moderationService.CheckText(req.Text)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+func (h *ModerationHandler) review(c *gin.Context, approved bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid moderation record ID")
+		return
+	}
+
+	var req models.ModerationReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	reviewerID, _, _ := currentUser(c)
+
+	var record *models.ModerationRecord
+	if approved {
+		record, err = h.moderationService.Approve(uint(id), reviewerID, req.Reason)
+	} else {
+		record, err = h.moderationService.Reject(uint(id), reviewerID, req.Reason)
+	}
+	if err != nil {
+		switch err.Error() {
+		case "moderation record not found":
+			utils.NotFound(c, err.Error())
+		case "moderation record already reviewed", "reject reason is required":
+			utils.BadRequest(c, err.Error())
+		default:
+			utils.InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	utils.Success(c, record)
+}
@@ -2,6 +2,7 @@
 package api
 
 import (
+	"errors"
 	"strconv" // 用于字符串和数字转换
 
 	"github.com/EasyPeek/EasyPeek-backend/internal/models"   // 导入新闻模型和请求/响应结构体
@@ -68,8 +69,9 @@ func (h *NewsHandler) GetNewsByID(c *gin.Context) {
 		return
 	}
 
-	// 调用 NewsService 的 GetNewsByID 方法
-	news, err := h.newsService.GetNewsByID(uint(id))
+	// 审核中或被驳回的新闻只有发布者和管理员可以查看
+	viewerID, isAdmin, _ := optionalUser(c)
+	news, err := h.newsService.GetVisibleNewsByID(uint(id), viewerID, isAdmin)
 	if err != nil {
 		if err.Error() == "news not found" {
 			utils.NotFound(c, err.Error()) // 如果新闻未找到，返回 404
@@ -144,6 +146,10 @@ func (h *NewsHandler) UpdateNews(c *gin.Context) {
 	// 调用 NewsService 的 UpdateNews 方法进行更新
 	// UpdateNews 接收的是现有新闻对象和更新请求
 	if err := h.newsService.UpdateNews(news, &req); err != nil {
+		if errors.Is(err, services.ErrContentBlocked) {
+			utils.BadRequest(c, err.Error()) // 内容命中严重敏感词
+			return
+		}
 		utils.InternalServerError(c, err.Error()) // 更新失败通常是数据库错误
 		return
 	}
@@ -197,21 +203,15 @@ func (h *NewsHandler) SearchNews(c *gin.Context) {
 		size = 10
 	}
 
-	// 调用 NewsService 的 SearchNews 方法进行搜索
-	newsList, total, err := h.newsService.SearchNews(queryStr, page, size)
+	// 调用 NewsService 的 SearchNews 方法进行全文搜索，支持与统一搜索相同的语法，结果按相关度排序并带有高亮片段
+	results, total, err := h.newsService.SearchNews(queryStr, page, size)
 	if err != nil {
 		utils.InternalServerError(c, err.Error()) // 数据库或其他内部错误
 		return
 	}
 
-	// 将搜索结果转换为响应格式
-	var newsResponses []models.NewsResponse
-	for _, news := range newsList {
-		newsResponses = append(newsResponses, news.ToResponse())
-	}
-
 	// 返回带分页信息成功的响应
-	utils.SuccessWithPagination(c, newsResponses, total, page, size)
+	utils.SuccessWithPagination(c, results, total, page, size)
 }
 
 // GetNewsByTitle 根据标题获取新闻
@@ -359,6 +359,24 @@ func (h *NewsHandler) GetNewsByEventID(c *gin.Context) {
 	utils.Success(c, newsResponses)
 }
 
+// GetPopularTags 获取新闻热门标签，标签来自RSS条目的分类和自动提取的关键词
+// 支持 limit、min_count、days（最近几天）和 category 参数
+func (h *NewsHandler) GetPopularTags(c *gin.Context) {
+	var query models.NewsTagQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	tags, err := h.newsService.GetPopularTags(&query)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to get popular tags")
+		return
+	}
+
+	utils.Success(c, tags)
+}
+
 // GetHotNews 获取热门新闻
 func (h *NewsHandler) GetHotNews(c *gin.Context) {
 	// 获取limit参数，默认为10
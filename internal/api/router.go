@@ -25,6 +25,17 @@ func SetupRoutes() *gin.Engine {
 	rssHandler := NewRSSHandler()
 	adminHandler := NewAdminHandler()
 	newsHandler := NewNewsHandler()
+	commentHandler := NewCommentHandler()
+	moderationHandler := NewModerationHandler()
+	taxonomyHandler := NewTaxonomyHandler()
+	eventOperationHandler := NewEventOperationHandler()
+	summaryHandler := NewSummaryHandler()
+	jobHandler := NewJobHandler()
+	entityHandler := NewEntityHandler()
+	classifierHandler := NewClassifierHandler()
+	categoryHandler := NewCategoryHandler()
+	tagHandler := NewTagHandler()
+	searchHandler := NewSearchHandler()
 
 	// API v1 routes
 	v1 := r.Group("/api/v1")
@@ -57,19 +68,24 @@ func SetupRoutes() *gin.Engine {
 			news.GET("/:id", newsHandler.GetNewsByID)                      // 根据ID获取单条新闻
 			news.GET("/search", newsHandler.SearchNews)                    // 搜索新闻
 			news.GET("/hot", newsHandler.GetHotNews)                       // 获取热门新闻
+			news.GET("/tags", newsHandler.GetPopularTags)                  // 获取新闻热门标签
 			news.GET("/title", newsHandler.GetNewsByTitle)                 // 根据标题获取新闻
 			news.GET("/category/:category", newsHandler.GetNewsByCategory) // 根据分类获取新闻
 			news.GET("/unlinked", newsHandler.GetUnlinkedNews)             // 获取未关联事件的新闻
 			news.GET("/event/:event_id", newsHandler.GetNewsByEventID)     // 根据事件ID获取新闻
+			news.GET("/:id/comments", commentHandler.GetNewsComments)      // 获取新闻评论
 
 			// 需要身份验证的路由
 			authNews := news.Group("")
 			authNews.Use(middleware.AuthMiddleware())
 			{
-				authNews.POST("", newsHandler.CreateNews)                                  // 创建新闻
-				authNews.PUT("/:id", newsHandler.UpdateNews)                               // 更新新闻
-				authNews.DELETE("/:id", newsHandler.DeleteNews)                            // 删除新闻
-				authNews.PUT("/event-association", newsHandler.UpdateNewsEventAssociation) // 批量更新新闻事件关联
+				authNews.POST("", newsHandler.CreateNews)                                                // 创建新闻
+				authNews.PUT("/:id", newsHandler.UpdateNews)                                             // 更新新闻
+				authNews.DELETE("/:id", newsHandler.DeleteNews)                                          // 删除新闻
+				authNews.PUT("/event-association", newsHandler.UpdateNewsEventAssociation)               // 批量更新新闻事件关联
+				authNews.GET("/:id/event-suggestions", eventOperationHandler.SuggestEventsForNews)       // 推荐新闻所属的事件
+				authNews.POST("/event-suggestions/review", eventOperationHandler.ReviewEventSuggestions) // 批量审核推荐的事件
+				authNews.POST("/:id/comments", commentHandler.CreateNewsComment)                         // 发表新闻评论
 			}
 		}
 
@@ -83,9 +99,14 @@ func SetupRoutes() *gin.Engine {
 			events.GET("/categories", eventHandler.GetEventCategories)
 			events.GET("/category/:category", eventHandler.GetEventsByCategory)
 			events.GET("/tags", eventHandler.GetPopularTags)
+			events.GET("/statuses", eventHandler.GetEventStatuses)
 			events.GET("/:id", eventHandler.GetEvent)
 			events.GET("/:id/news", eventHandler.GetNewsByEventID)
+			events.GET("/:id/timeline", eventHandler.GetEventTimeline)
+			events.GET("/:id/status-history", eventHandler.GetEventStatusHistory)
+			events.GET("/:id/related", eventHandler.GetRelatedEvents)
 			events.GET("/:id/stats", eventHandler.GetEventStats)
+			events.GET("/:id/comments", commentHandler.GetEventComments)
 			events.GET("/status/:status", eventHandler.GetEventsByStatus)
 			events.POST("/:id/view", eventHandler.IncrementViewCount)
 			events.POST("/:id/share", eventHandler.ShareEvent)
@@ -99,6 +120,7 @@ func SetupRoutes() *gin.Engine {
 				authEvents.DELETE("/:id", eventHandler.DeleteEvent)
 				authEvents.POST("/:id/like", eventHandler.LikeEvent)
 				authEvents.POST("/:id/comment", eventHandler.AddComment)
+				authEvents.POST("/:id/comments", commentHandler.CreateEventComment)
 			}
 
 			// 管理员专用路由
@@ -108,6 +130,8 @@ func SetupRoutes() *gin.Engine {
 			{
 				adminEvents.PUT("/:id/tags", eventHandler.UpdateEventTags)
 				adminEvents.POST("/generate", eventHandler.GenerateEventsFromNews)
+				adminEvents.GET("/generate/plans/:id", eventHandler.GetEventGenerationPlan)
+				adminEvents.POST("/generate/commit", eventHandler.CommitEventProposals)
 			}
 
 			// 系统内部路由（需要系统权限或管理员权限）
@@ -119,6 +143,38 @@ func SetupRoutes() *gin.Engine {
 			}
 		}
 
+		// category routes
+		v1.GET("/categories", categoryHandler.GetCategories)
+
+		// tag routes
+		v1.GET("/tags/:slug", tagHandler.GetTag)
+
+		// search routes
+		search := v1.Group("/search")
+		{
+			search.GET("", searchHandler.Search)          // 统一搜索新闻和事件
+			search.GET("/suggest", searchHandler.Suggest) // 搜索建议和拼写纠正
+		}
+
+		// entity routes
+		entities := v1.Group("/entities")
+		{
+			entities.GET("/:id", entityHandler.GetEntity)
+		}
+
+		// comment routes
+		comments := v1.Group("/comments")
+		{
+			comments.GET("/:id/history", commentHandler.GetCommentHistory)
+
+			authComments := comments.Group("")
+			authComments.Use(middleware.AuthMiddleware())
+			{
+				authComments.PUT("/:id", commentHandler.UpdateComment)
+				authComments.DELETE("/:id", commentHandler.DeleteComment)
+			}
+		}
+
 		// RSS routes
 		rss := v1.Group("/rss")
 		{
@@ -170,6 +226,27 @@ func SetupRoutes() *gin.Engine {
 				events.GET("", adminHandler.GetAllEvents)       // 获取所有事件
 				events.PUT("/:id", adminHandler.UpdateEvent)    // 更新事件
 				events.DELETE("/:id", adminHandler.DeleteEvent) // 删除事件
+				// 事件合并与拆分
+				events.POST("/merge", eventOperationHandler.MergeEvents)                 // 合并事件
+				events.POST("/:id/split", eventOperationHandler.SplitEvent)              // 拆分事件
+				events.GET("/operations", eventOperationHandler.GetOperations)           // 合并/拆分记录
+				events.POST("/operations/:id/undo", eventOperationHandler.UndoOperation) // 撤销合并/拆分
+				// 时间线里程碑
+				events.POST("/:id/milestones", eventOperationHandler.CreateMilestone)   // 添加里程碑
+				events.PUT("/milestones/:id", eventOperationHandler.UpdateMilestone)    // 更新里程碑
+				events.DELETE("/milestones/:id", eventOperationHandler.DeleteMilestone) // 删除里程碑
+				// 生命周期
+				events.POST("/:id/status", eventOperationHandler.ChangeEventStatus)          // 修改事件状态
+				events.POST("/lifecycle/run", eventOperationHandler.RunLifecycleTransitions) // 立即推进事件状态
+				// 地理位置
+				events.POST("/geocode", eventOperationHandler.GeocodeEvents) // 重新解析事件地点和坐标
+
+				events.GET("/:id/relation-suggestions", eventOperationHandler.SuggestEventRelations) // 推荐事件关系
+				events.POST("/:id/relations", eventOperationHandler.CreateEventRelation)             // 添加事件关系
+				events.DELETE("/relations/:id", eventOperationHandler.DeleteEventRelation)           // 删除事件关系
+				events.POST("/relations/auto", eventOperationHandler.AutoLinkEvents)                 // 自动建立事件关系
+				// 新闻归入事件的审核记录
+				events.GET("/suggestion-feedback", eventOperationHandler.GetSuggestionFeedbackStats) // 推荐事件的审核统计
 			}
 
 			// 新闻管理
@@ -190,6 +267,73 @@ func SetupRoutes() *gin.Engine {
 				rssAdmin.POST("/:id/fetch", adminHandler.FetchRSSFeed)     // 手动抓取RSS源
 				rssAdmin.POST("/fetch-all", adminHandler.FetchAllRSSFeeds) // 抓取所有RSS源
 			}
+
+			// 内容审核
+			moderation := admin.Group("/moderation")
+			{
+				moderation.GET("/queue", moderationHandler.GetQueue)                    // 审核队列
+				moderation.POST("/queue/:id/approve", moderationHandler.ApproveContent) // 审核通过
+				moderation.POST("/queue/:id/reject", moderationHandler.RejectContent)   // 审核拒绝
+				moderation.GET("/words", moderationHandler.GetWords)                    // 敏感词列表
+				moderation.POST("/words", moderationHandler.CreateWord)                 // 添加敏感词
+				moderation.PUT("/words/:id", moderationHandler.UpdateWord)              // 更新敏感词
+				moderation.DELETE("/words/:id", moderationHandler.DeleteWord)           // 删除敏感词
+				moderation.POST("/check", moderationHandler.CheckText)                  // 检测文本
+			}
+
+			// 事件聚类词库
+			taxonomy := admin.Group("/taxonomy")
+			{
+				taxonomy.GET("", taxonomyHandler.GetTaxonomy)                      // 当前生效的词库
+				taxonomy.GET("/groups", taxonomyHandler.GetGroups)                 // 关键词组列表
+				taxonomy.POST("/groups", taxonomyHandler.CreateGroup)              // 创建关键词组
+				taxonomy.PUT("/groups/:id", taxonomyHandler.UpdateGroup)           // 更新关键词组
+				taxonomy.DELETE("/groups/:id", taxonomyHandler.DeleteGroup)        // 删除关键词组
+				taxonomy.GET("/stop-words", taxonomyHandler.GetStopWords)          // 停用词列表
+				taxonomy.POST("/stop-words", taxonomyHandler.CreateStopWords)      // 批量添加停用词
+				taxonomy.DELETE("/stop-words/:id", taxonomyHandler.DeleteStopWord) // 删除停用词
+				taxonomy.GET("/changes", taxonomyHandler.GetChanges)               // 词库变更记录
+			}
+
+			// 摘要
+			admin.POST("/summaries/regenerate", summaryHandler.RegenerateSummaries) // 重新生成新闻摘要和事件描述
+
+			// 分类管理
+			categories := admin.Group("/categories")
+			{
+				categories.GET("", categoryHandler.GetAdminCategories)         // 分类列表（含停用）
+				categories.POST("", categoryHandler.CreateCategory)            // 创建分类
+				categories.PUT("/:id", categoryHandler.UpdateCategory)         // 更新分类
+				categories.DELETE("/:id", categoryHandler.DeleteCategory)      // 删除分类
+				categories.POST("/migrate", categoryHandler.MigrateCategories) // 从分类字符串迁移并重新关联
+			}
+
+			// 标签管理
+			tags := admin.Group("/tags")
+			{
+				tags.GET("", tagHandler.GetTags)              // 标签列表
+				tags.PUT("/:id", tagHandler.UpdateTag)        // 修改名称、slug 和同义词
+				tags.POST("/:id/merge", tagHandler.MergeTags) // 将其他标签并入该标签
+				tags.DELETE("/:id", tagHandler.DeleteTag)     // 删除标签
+				tags.POST("/migrate", tagHandler.MigrateTags) // 从标签字符串迁移并重新计数
+			}
+
+			// 新闻分类模型
+			classifier := admin.Group("/classifier")
+			{
+				classifier.GET("", classifierHandler.GetClassifier)            // 当前模型及混淆矩阵
+				classifier.POST("/train", classifierHandler.TrainClassifier)   // 重新训练
+				classifier.POST("/predict", classifierHandler.PredictCategory) // 预测文本分类
+			}
+
+			// 后台任务
+			jobs := admin.Group("/jobs")
+			{
+				jobs.POST("", jobHandler.SubmitJob)            // 提交后台任务
+				jobs.GET("", jobHandler.GetJobs)               // 任务列表
+				jobs.GET("/:id", jobHandler.GetJob)            // 任务状态、进度和结果
+				jobs.POST("/:id/cancel", jobHandler.CancelJob) // 取消任务
+			}
 		}
 
 		// 系统管理路由（需要系统权限）
@@ -12,11 +12,13 @@ import (
 
 type RSSHandler struct {
 	rssService *services.RSSService
+	jobService *services.JobService
 }
 
 func NewRSSHandler() *RSSHandler {
 	return &RSSHandler{
 		rssService: services.NewRSSService(),
+		jobService: services.NewJobService(),
 	}
 }
 
@@ -206,14 +208,28 @@ func (h *RSSHandler) FetchRSSFeed(c *gin.Context) {
 
 // FetchAllRSSFeeds 抓取所有RSS源
 // @Summary 抓取所有RSS源
-// @Description 手动触发所有活跃RSS源的内容抓取
+// @Description 手动触发所有活跃RSS源的内容抓取，async=true 时提交为后台任务并返回任务记录
 // @Tags rss
 // @Produce json
+// @Param async query bool false "作为后台任务执行" default(false)
 // @Success 200 {object} utils.Response{data=models.RSSFetchResult}
+// @Success 202 {object} utils.Response{data=models.JobResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Security BearerAuth
 // @Router /api/v1/rss/fetch-all [post]
 func (h *RSSHandler) FetchAllRSSFeeds(c *gin.Context) {
+	async, err := strconv.ParseBool(c.DefaultQuery("async", "false"))
+	if err != nil {
+		utils.BadRequest(c, "Invalid async parameter")
+		return
+	}
+	if async {
+		submitJob(c, h.jobService, &models.SubmitJobRequest{Type: models.JobTypeRSSFetchAll})
+		return
+	}
+
 	result, err := h.rssService.FetchAllRSSFeeds()
 	if err != nil {
 		utils.InternalServerError(c, "Failed to fetch RSS feeds: "+err.Error())
@@ -229,14 +245,16 @@ func (h *RSSHandler) FetchAllRSSFeeds(c *gin.Context) {
 // @Tags rss
 // @Produce json
 // @Param rss_source_id query int false "RSS源ID"
-// @Param category query string false "分类筛选"
+// @Param category query string false "分类筛选，可以是分类的 slug、名称或别名，包含子分类"
 // @Param status query string false "状态筛选"
-// @Param search query string false "搜索关键词"
-// @Param sort_by query string false "排序方式" Enums(published_at, hotness, views)
+// @Param search query string false "全文搜索关键词，支持与 /api/v1/search 相同的语法，结果带有高亮片段"
+// @Param sort_by query string false "排序方式，搜索时默认按相关度" Enums(published_at, hotness, views)
 // @Param page query int false "页码" default(1)
 // @Param limit query int false "每页数量" default(10)
 // @Param start_date query string false "开始日期 YYYY-MM-DD"
 // @Param end_date query string false "结束日期 YYYY-MM-DD"
+// @Param entity_id query int false "只返回提到该实体的新闻"
+// @Param facets query bool false "返回筛选结果中出现最多的人物、机构和地点"
 // @Success 200 {object} utils.Response{data=models.NewsListResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -259,7 +277,7 @@ func (h *RSSHandler) GetNews(c *gin.Context) {
 
 // GetNewsItem 获取新闻详情
 // @Summary 获取新闻详情
-// @Description 根据ID获取单个新闻详情（会增加浏览量）
+// @Description 根据ID获取单个新闻详情（会记录浏览量，同一访客短时间内重复浏览只计一次）
 // @Tags rss
 // @Produce json
 // @Param id path int true "新闻ID"
@@ -276,7 +294,7 @@ func (h *RSSHandler) GetNewsItem(c *gin.Context) {
 		return
 	}
 
-	newsItem, err := h.rssService.GetNewsItem(uint(id))
+	newsItem, err := h.rssService.GetNewsItem(uint(id), viewerKey(c))
 	if err != nil {
 		if err.Error() == "news item not found" {
 			utils.NotFound(c, "News item not found")
@@ -360,7 +378,7 @@ func (h *RSSHandler) GetLatestNews(c *gin.Context) {
 // @Description 根据分类获取新闻列表
 // @Tags rss
 // @Produce json
-// @Param category path string true "新闻分类"
+// @Param category path string true "新闻分类，可以是分类的 slug、名称或别名，包含子分类"
 // @Param page query int false "页码" default(1)
 // @Param limit query int false "每页数量" default(10)
 // @Param sort_by query string false "排序方式" Enums(published_at, hotness, views)
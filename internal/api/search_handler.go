@@ -0,0 +1,100 @@
+package api
+
+import (
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService *services.SearchService
+}
+
+func NewSearchHandler() *SearchHandler {
+	return &SearchHandler{
+		searchService: services.NewSearchService(),
+	}
+}
+
+// Search 统一搜索
+// @Summary 统一搜索新闻和事件
+// @Description 同时搜索新闻和事件，按相关度、时间和热度的综合得分统一排序，每条结果标明类型并带有高亮片段；同时返回按类型、分类、来源、日期分段和事件状态的分面统计，每个分面忽略自身的筛选条件。q 支持高级语法："短语"、-排除词、source:来源、category:分类（两者可用 - 排除）、type:news|event、status:事件状态、date:2024-01-01..2024-03-31（任一端可省略，也可以是 2024-05 或 week）；q 和筛选参数不能同时为空
+// @Tags search
+// @Produce json
+// @Param q query string false "搜索语句"
+// @Param type query string false "结果类型" Enums(news, event)
+// @Param category query string false "分类的 slug、名称或别名，包含子分类"
+// @Param source query string false "来源"
+// @Param status query string false "事件状态，指定后只返回事件"
+// @Param date query string false "日期分段" Enums(day, week, month, year)
+// @Param start_date query string false "开始日期 YYYY-MM-DD"
+// @Param end_date query string false "结束日期 YYYY-MM-DD"
+// @Param sort_by query string false "排序方式" Enums(relevance, time, hotness)
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response{data=models.SearchResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	var req models.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	response, err := h.searchService.Search(&req)
+	if err != nil {
+		switch err.Error() {
+		case "search query cannot be empty":
+			utils.BadRequest(c, "Search query cannot be empty")
+		case "invalid search type":
+			utils.BadRequest(c, "Invalid search type")
+		case "invalid date bucket":
+			utils.BadRequest(c, "Invalid date bucket")
+		case "invalid start_date":
+			utils.BadRequest(c, "Invalid start_date")
+		case "invalid end_date":
+			utils.BadRequest(c, "Invalid end_date")
+		case "invalid sort_by":
+			utils.BadRequest(c, "Invalid sort_by")
+		default:
+			utils.InternalServerError(c, "Failed to search")
+		}
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// Suggest 搜索建议
+// @Summary 搜索建议
+// @Description 按前缀补全事件标题、标签、实体名称和热门搜索词（最近一段时间内被多次搜索且有结果的语句），按来源权重和热度、计数排序；did_you_mean 为按语料中的词纠正拼写后的语句，没有补全结果时按纠正后的语句补全。建议来自内存中定时重建的索引
+// @Tags search
+// @Produce json
+// @Param q query string true "已输入的搜索语句"
+// @Param limit query int false "返回的建议数，最多 20" default(10)
+// @Success 200 {object} utils.Response{data=models.SuggestResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	var req models.SuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	response, err := h.searchService.Suggest(&req)
+	if err != nil {
+		if err.Error() == "search query cannot be empty" {
+			utils.BadRequest(c, "Search query cannot be empty")
+			return
+		}
+		utils.InternalServerError(c, "Failed to get suggestions")
+		return
+	}
+
+	utils.Success(c, response)
+}
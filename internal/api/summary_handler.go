@@ -0,0 +1,43 @@
+package api
+
+import (
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SummaryHandler struct {
+	jobService *services.JobService
+}
+
+func NewSummaryHandler() *SummaryHandler {
+	return &SummaryHandler{
+		jobService: services.NewJobService(),
+	}
+}
+
+// RegenerateSummaries 重新生成摘要
+// @Summary 重新生成新闻摘要和事件描述
+// @Description 提交 summary_regeneration 后台任务，使用外部模型或抽取式摘要重新生成新闻摘要和事件描述；手动创建的新闻只在摘要为空时填充。立即返回任务记录，之后轮询 GET /api/v1/admin/jobs/{id}
+// @Tags summaries
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.RegenerateSummariesRequest false "重新生成范围"
+// @Success 202 {object} utils.Response{data=models.JobResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/summaries/regenerate [post]
+func (h *SummaryHandler) RegenerateSummaries(c *gin.Context) {
+	var req models.RegenerateSummariesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	submitJob(c, h.jobService, &models.SubmitJobRequest{Type: models.JobTypeSummaryRegeneration, Summaries: &req})
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagService *services.TagService
+}
+
+func NewTagHandler() *TagHandler {
+	return &TagHandler{
+		tagService: services.NewTagService(),
+	}
+}
+
+// GetTag 获取标签页
+// @Summary 获取标签页
+// @Description 返回标签的名称、同义词和计数，以及带有该标签的事件（按热度）和新闻（按发布时间），两个列表使用相同的分页；slug 也可以是标签的名称或同义词
+// @Tags tags
+// @Produce json
+// @Param slug path string true "标签 slug"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response{data=models.TagDetailResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/tags/{slug} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	var query models.TagDetailQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	detail, err := h.tagService.GetTag(c.Param("slug"), &query)
+	if err != nil {
+		if err.Error() == "tag not found" {
+			utils.NotFound(c, "Tag not found")
+			return
+		}
+		utils.InternalServerError(c, "Failed to get tag")
+		return
+	}
+
+	utils.Success(c, detail)
+}
+
+// GetTags 获取标签列表
+// @Summary 获取标签列表
+// @Description 按关联的新闻和事件数从多到少返回标签，q 按名称、slug 或同义词搜索
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "搜索关键词"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.PageResponse{data=[]models.TagInfo}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	var query models.TagQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	tags, total, err := h.tagService.GetTags(&query)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, tags, total, query.Page, query.Limit)
+}
+
+// UpdateTag 更新标签
+// @Summary 更新标签
+// @Description 修改标签的名称、slug 和同义词，synonyms 传入时整体替换；改名后原名称保留为同义词（同时传入 synonyms 时以传入的为准），相关新闻和事件的标签改为新名称。同义词已是其他标签时需要使用合并
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "标签ID"
+// @Param tag body models.UpdateTagRequest true "更新内容"
+// @Success 200 {object} utils.Response{data=models.TagInfo}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	var req models.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(uint(id), &req)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	utils.Success(c, tag)
+}
+
+// MergeTags 合并标签
+// @Summary 合并标签
+// @Description 将 source_ids 中的标签并入路径中的标签：新闻和事件改为关联目标标签，被合并标签的名称成为同义词，原来的标签页地址仍然有效
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "目标标签ID"
+// @Param request body models.MergeTagsRequest true "被合并的标签"
+// @Success 200 {object} utils.Response{data=models.TagInfo}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/tags/{id}/merge [post]
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	var req models.MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	tag, err := h.tagService.MergeTags(uint(id), &req)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	utils.Success(c, tag)
+}
+
+// DeleteTag 删除标签
+// @Summary 删除标签
+// @Description 删除标签及其同义词，并从相关新闻和事件的标签中去掉
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "标签ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	if err := h.tagService.DeleteTag(uint(id)); err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Tag deleted successfully"})
+}
+
+// MigrateTags 从标签字符串迁移标签
+// @Summary 从标签字符串迁移标签
+// @Description 为新闻和事件标签字符串中还没有对应标签的名称新建标签，然后重新关联全部新闻和事件并重新计数
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.MigrateTagsResult}
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/tags/migrate [post]
+func (h *TagHandler) MigrateTags(c *gin.Context) {
+	result, err := h.tagService.MigrateLegacyTags()
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// respondTagError 将标签服务的错误映射为HTTP响应
+func respondTagError(c *gin.Context, err error) {
+	switch msg := err.Error(); {
+	case msg == "tag not found":
+		utils.NotFound(c, msg)
+	case msg == "invalid tag slug", msg == "tag slug already exists", msg == "no tags to merge",
+		strings.HasPrefix(msg, "tag name or synonym already in use"):
+		utils.BadRequest(c, msg)
+	default:
+		utils.InternalServerError(c, msg)
+	}
+}
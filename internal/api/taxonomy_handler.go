@@ -0,0 +1,296 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/services"
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TaxonomyHandler struct {
+	taxonomyService *services.TaxonomyService
+}
+
+func NewTaxonomyHandler() *TaxonomyHandler {
+	return &TaxonomyHandler{
+		taxonomyService: services.NewTaxonomyService(),
+	}
+}
+
+// GetTaxonomy 获取当前生效的聚类词库
+// @Summary 获取当前生效的聚类词库
+// @Description 返回事件聚类当前使用的地域组、主题组和停用词及词库版本
+// @Tags taxonomy
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.TaxonomySnapshot}
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy [get]
+func (h *TaxonomyHandler) GetTaxonomy(c *gin.Context) {
+	taxonomy, err := h.taxonomyService.CurrentTaxonomy()
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, taxonomy)
+}
+
+// GetGroups 获取关键词组列表
+// @Summary 获取关键词组列表
+// @Tags taxonomy
+// @Security BearerAuth
+// @Produce json
+// @Param kind query string false "类型" Enums(region, topic)
+// @Param search query string false "搜索组名或关键词"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.PageResponse{data=[]models.KeywordGroupResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy/groups [get]
+func (h *TaxonomyHandler) GetGroups(c *gin.Context) {
+	var query models.KeywordGroupQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	groups, total, err := h.taxonomyService.GetGroups(&query)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, groups, total, query.Page, query.Limit)
+}
+
+// CreateGroup 创建关键词组
+// @Summary 创建关键词组
+// @Description 创建地域组或主题组，同一关键词只能属于一个启用的地域组
+// @Tags taxonomy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param group body models.CreateKeywordGroupRequest true "关键词组信息"
+// @Success 201 {object} utils.Response{data=models.KeywordGroupResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy/groups [post]
+func (h *TaxonomyHandler) CreateGroup(c *gin.Context) {
+	var req models.CreateKeywordGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	group, err := h.taxonomyService.CreateGroup(&req, userID)
+	if err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Code:    201,
+		Message: "Keyword group created successfully",
+		Data:    group,
+	})
+}
+
+// UpdateGroup 更新关键词组
+// @Summary 更新关键词组
+// @Tags taxonomy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "关键词组ID"
+// @Param group body models.UpdateKeywordGroupRequest true "更新内容"
+// @Success 200 {object} utils.Response{data=models.KeywordGroupResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy/groups/{id} [put]
+func (h *TaxonomyHandler) UpdateGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid keyword group ID")
+		return
+	}
+
+	var req models.UpdateKeywordGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	group, err := h.taxonomyService.UpdateGroup(uint(id), &req, userID)
+	if err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	utils.Success(c, group)
+}
+
+// DeleteGroup 删除关键词组
+// @Summary 删除关键词组
+// @Tags taxonomy
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "关键词组ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy/groups/{id} [delete]
+func (h *TaxonomyHandler) DeleteGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid keyword group ID")
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	if err := h.taxonomyService.DeleteGroup(uint(id), userID); err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Keyword group deleted successfully"})
+}
+
+// GetStopWords 获取停用词列表
+// @Summary 获取停用词列表
+// @Tags taxonomy
+// @Security BearerAuth
+// @Produce json
+// @Param search query string false "搜索关键词"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(50)
+// @Success 200 {object} utils.PageResponse{data=[]models.StopWord}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy/stop-words [get]
+func (h *TaxonomyHandler) GetStopWords(c *gin.Context) {
+	var query models.StopWordQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	words, total, err := h.taxonomyService.GetStopWords(&query)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, words, total, query.Page, query.Limit)
+}
+
+// CreateStopWords 批量添加停用词
+// @Summary 批量添加停用词
+// @Description 批量添加停用词，已存在的词会被跳过，返回实际新增的停用词
+// @Tags taxonomy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateStopWordsRequest true "停用词列表"
+// @Success 201 {object} utils.Response{data=[]models.StopWord}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy/stop-words [post]
+func (h *TaxonomyHandler) CreateStopWords(c *gin.Context) {
+	var req models.CreateStopWordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	words, err := h.taxonomyService.CreateStopWords(&req, userID)
+	if err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Code:    201,
+		Message: "Stop words created successfully",
+		Data:    words,
+	})
+}
+
+// DeleteStopWord 删除停用词
+// @Summary 删除停用词
+// @Tags taxonomy
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "停用词ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy/stop-words/{id} [delete]
+func (h *TaxonomyHandler) DeleteStopWord(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid stop word ID")
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	if err := h.taxonomyService.DeleteStopWord(uint(id), userID); err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Stop word deleted successfully"})
+}
+
+// GetChanges 获取词库变更记录
+// @Summary 获取词库变更记录
+// @Description 每次变更对应一个递增的词库版本，记录变更前后的快照
+// @Tags taxonomy
+// @Security BearerAuth
+// @Produce json
+// @Param entity_type query string false "变更对象类型" Enums(keyword_group, stop_word)
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.PageResponse{data=[]models.TaxonomyChange}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/taxonomy/changes [get]
+func (h *TaxonomyHandler) GetChanges(c *gin.Context) {
+	var query models.TaxonomyChangeQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	changes, total, err := h.taxonomyService.GetChanges(&query)
+	if err != nil {
+		utils.InternalServerError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, changes, total, query.Page, query.Limit)
+}
+
+// respondTaxonomyError 将词库服务的错误映射为HTTP响应
+func respondTaxonomyError(c *gin.Context, err error) {
+	switch msg := err.Error(); {
+	case msg == "keyword group not found", msg == "stop word not found":
+		utils.NotFound(c, msg)
+	case msg == "keyword group already exists", msg == "keywords cannot be empty", msg == "stop words cannot be empty",
+		strings.HasPrefix(msg, "keyword ") && strings.Contains(msg, "already belongs to region group"):
+		utils.BadRequest(c, msg)
+	default:
+		utils.InternalServerError(c, msg)
+	}
+}
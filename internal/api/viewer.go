@@ -0,0 +1,27 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// viewerKey 生成用于浏览去重的访客标识
+// 公开接口不经过认证中间件，带有有效token时按用户ID去重，否则按IP和User-Agent生成匿名指纹
+func viewerKey(c *gin.Context) string {
+	if userID, _, ok := currentUser(c); ok {
+		return "u:" + strconv.FormatUint(uint64(userID), 10)
+	}
+
+	if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+		if claims, err := utils.ParseToken(authHeader); err == nil {
+			return "u:" + strconv.FormatUint(uint64(claims.UserID), 10)
+		}
+	}
+
+	sum := sha1.Sum([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+	return "a:" + hex.EncodeToString(sum[:])
+}
@@ -2,11 +2,19 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
 
 	"github.com/EasyPeek/EasyPeek-backend/internal/config"
 	"github.com/redis/go-redis/v9"
 )
 
+// Cache is the global redis cache instance, nil when redis is unavailable
+var Cache *RedisCache
+
 type RedisCache struct {
 	client *redis.Client
 }
@@ -27,6 +35,93 @@ func NewRedisCache(cfg config.RedisConfig) (*RedisCache, error) {
 	}, nil
 }
 
+// Initialize connect to redis and set the global cache instance
+func Initialize(cfg *config.Config) error {
+	c, err := NewRedisCache(cfg.Redis)
+	if err != nil {
+		return fmt.Errorf("failed to connect redis: %w", err)
+	}
+
+	Cache = c
+	log.Println("redis connected successfully")
+	return nil
+}
+
+// GetCache get the global cache instance, may be nil
+func GetCache() *RedisCache {
+	return Cache
+}
+
+// CloseCache close the global cache instance
+func CloseCache() error {
+	if Cache == nil {
+		return nil
+	}
+	return Cache.Close()
+}
+
 func (c *RedisCache) Close() error {
 	return c.client.Close()
 }
+
+// SetNX set key only if it does not exist, returns true if the key was set
+func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Get get a string value, returns false if the key does not exist
+func (c *RedisCache) Get(ctx context.Context, key string) (string, bool, error) {
+	val, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return val, true, nil
+}
+
+// Set set a value with ttl, zero ttl means no expiration
+func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// HIncrBy increment a hash field by delta
+func (c *RedisCache) HIncrBy(ctx context.Context, key, field string, delta int64) (int64, error) {
+	return c.client.HIncrBy(ctx, key, field, delta).Result()
+}
+
+// HGetInt get a hash field as int64, missing fields return 0
+func (c *RedisCache) HGetInt(ctx context.Context, key, field string) (int64, error) {
+	val, err := c.client.HGet(ctx, key, field).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
+
+// DrainHash atomically take all fields of a hash and remove it
+// HGETALL and DEL run in one MULTI/EXEC so increments arriving meanwhile go to a fresh key,
+// a missing key yields an empty map
+func (c *RedisCache) DrainHash(ctx context.Context, key string) (map[string]string, error) {
+	pipe := c.client.TxPipeline()
+	values := pipe.HGetAll(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return values.Val(), nil
+}
+
+// RestoreHash add counters back to a hash, used when persisting drained values fails
+func (c *RedisCache) RestoreHash(ctx context.Context, key string, values map[string]int64) error {
+	pipe := c.client.TxPipeline()
+	for field, delta := range values {
+		pipe.HIncrBy(ctx, key, field, delta)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
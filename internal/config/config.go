@@ -8,10 +8,18 @@ import (
 )
 
 type Config struct {
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	CORS     CORSConfig     `mapstructure:"cors"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Redis      RedisConfig      `mapstructure:"redis"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	CORS       CORSConfig       `mapstructure:"cors"`
+	Views      ViewsConfig      `mapstructure:"views"`
+	Clustering ClusteringConfig `mapstructure:"clustering"`
+	Summary    SummaryConfig    `mapstructure:"summary"`
+	Tagging    TaggingConfig    `mapstructure:"tagging"`
+	Classifier ClassifierConfig `mapstructure:"classifier"`
+	Search     SearchConfig     `mapstructure:"search"`
+	LLM        LLMConfig        `mapstructure:"llm"`
+	Lifecycle  LifecycleConfig  `mapstructure:"lifecycle"`
 }
 
 var AppConfig *Config
@@ -36,6 +44,21 @@ func LoadConfig(filepath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// LoadClusteringConfig 只读取配置文件中的聚类配置，不修改全局配置，用于离线评估不同的聚类参数
+func LoadClusteringConfig(filepath string) (ClusteringConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(filepath)
+
+	var cfg ClusteringConfig
+	if err := v.ReadInConfig(); err != nil {
+		return cfg, err
+	}
+	if err := v.UnmarshalKey("clustering", &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
 type DatabaseConfig struct {
 	Host         string `mapstructure:"host"`
 	Port         int    `mapstructure:"port"`
@@ -68,3 +91,73 @@ type JWTConfig struct {
 type CORSConfig struct {
 	AllowOrigins []string `mapstructure:"allow_origins"`
 }
+
+type ViewsConfig struct {
+	DedupWindowMinutes int `mapstructure:"dedup_window_minutes"` // 同一访客在窗口内重复浏览只计一次
+	FlushBatchSize     int `mapstructure:"flush_batch_size"`     // 每个事务回写的最大记录数
+}
+
+type ClusteringConfig struct {
+	Method               string  `mapstructure:"method"`                 // single_pass 或 agglomerative
+	SimilarityThreshold  float64 `mapstructure:"similarity_threshold"`   // 新闻归入同一事件的最低余弦相似度
+	AttachThreshold      float64 `mapstructure:"attach_threshold"`       // 增量生成时新闻归入已有事件的最低余弦相似度
+	TimeWindowHours      int     `mapstructure:"time_window_hours"`      // 同一事件内相邻新闻的最大时间间隔
+	TitleWeight          int     `mapstructure:"title_weight"`           // 标题词相对正文词的权重
+	MaxContentChars      int     `mapstructure:"max_content_chars"`      // 参与向量化的正文最大字数
+	MaxAgglomerativeSize int     `mapstructure:"max_agglomerative_size"` // 层次聚类的最大新闻数，超过时退化为单遍聚类
+}
+
+type SummaryConfig struct {
+	NewsMaxSentences  int `mapstructure:"news_max_sentences"`  // 新闻摘要最多句数
+	NewsMaxChars      int `mapstructure:"news_max_chars"`      // 新闻摘要最大字数
+	EventMaxSentences int `mapstructure:"event_max_sentences"` // 事件描述最多句数
+	EventMaxChars     int `mapstructure:"event_max_chars"`     // 事件描述最大字数
+	MinSentenceChars  int `mapstructure:"min_sentence_chars"`  // 参与排序的句子最少字数
+}
+
+type TaggingConfig struct {
+	Disabled       bool    `mapstructure:"disabled"`        // 关闭新闻关键词标签的自动提取
+	MaxTags        int     `mapstructure:"max_tags"`        // 每条新闻最多自动添加的标签数
+	MinScore       float64 `mapstructure:"min_score"`       // 关键词的最低 TF-IDF 权重（向量归一化后，0-1）
+	CorpusSize     int     `mapstructure:"corpus_size"`     // 统计文档频率使用的最近新闻数
+	RefreshMinutes int     `mapstructure:"refresh_minutes"` // 重新统计文档频率的间隔
+}
+
+type ClassifierConfig struct {
+	MinConfidence   float64 `mapstructure:"min_confidence"`    // 采用预测分类的最低置信度（0-1）
+	MinSamples      int     `mapstructure:"min_samples"`       // 参与训练的类别至少需要的新闻数
+	HoldoutPercent  int     `mapstructure:"holdout_percent"`   // 留作评估、不参与训练的新闻比例
+	MaxTrainingNews int     `mapstructure:"max_training_news"` // 训练使用的最近新闻数上限
+}
+
+type SearchConfig struct {
+	RecencyWeight float64 `mapstructure:"recency_weight"` // 相关度中随发布时间衰减的比例（0-1），其余部分不随时间变化
+	HalfLifeDays  float64 `mapstructure:"half_life_days"` // 时间衰减的半衰期
+	HotnessWeight float64 `mapstructure:"hotness_weight"` // 热度加成系数，相关度乘以 1 + 系数 × ln(1 + 热度)
+	SnippetLength int     `mapstructure:"snippet_length"` // 高亮摘要的字数
+
+	SuggestRefreshMinutes int `mapstructure:"suggest_refresh_minutes"`  // 重建搜索建议索引的间隔
+	PopularQueryDays      int `mapstructure:"popular_query_days"`       // 统计热门搜索词的天数
+	QueryLogRetentionDays int `mapstructure:"query_log_retention_days"` // 搜索记录的保留天数
+}
+
+type LifecycleConfig struct {
+	ActiveWindowHours int `mapstructure:"active_window_hours"` // 最近一条报道之后仍视为进行中的时长
+	CoolingHours      int `mapstructure:"cooling_hours"`       // 进行中结束后的降温观察期
+	ArchiveAfterDays  int `mapstructure:"archive_after_days"`  // 进行中结束后多少天归档
+	IntervalMinutes   int `mapstructure:"interval_minutes"`    // 定时推进事件状态的间隔
+}
+
+type LLMConfig struct {
+	Provider              string `mapstructure:"provider"`                // none（仅使用内置抽取式摘要）或 openai（OpenAI 兼容接口）
+	BaseURL               string `mapstructure:"base_url"`                // 接口地址，如 https://api.openai.com/v1
+	APIKey                string `mapstructure:"api_key"`                 // 接口密钥，可通过环境变量 EASYPEEK_LLM_API_KEY 设置
+	Model                 string `mapstructure:"model"`                   // 模型名称
+	TimeoutSeconds        int    `mapstructure:"timeout_seconds"`         // 单次请求超时
+	Concurrency           int    `mapstructure:"concurrency"`             // 最大并发请求数
+	RequestsPerMinute     int    `mapstructure:"requests_per_minute"`     // 每分钟最多请求数，0 表示不限制
+	MaxInputChars         int    `mapstructure:"max_input_chars"`         // 发送给模型的正文最大字数
+	CacheTTLHours         int    `mapstructure:"cache_ttl_hours"`         // 按内容哈希缓存结果的时长
+	WorkerIntervalSeconds int    `mapstructure:"worker_interval_seconds"` // 后台处理未处理新闻的间隔
+	WorkerBatchSize       int    `mapstructure:"worker_batch_size"`       // 每轮处理的新闻数
+}
@@ -0,0 +1,345 @@
+package geo
+
+import (
+	"bufio"
+	_ "embed"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+)
+
+// 地名层级
+const (
+	LevelCountry  = "country"
+	LevelProvince = "province"
+	LevelCity     = "city"
+)
+
+//go:embed gazetteer.tsv
+var gazetteerData string
+
+// Place 地名库中的一个地点
+type Place struct {
+	Code      string   `json:"code"`   // 国家为 ISO 3166-1，中国省份为 ISO 3166-2，中国城市为 CN-行政区划代码，外国城市为 UN/LOCODE
+	Level     string   `json:"level"`  // country / province / city
+	Parent    string   `json:"parent"` // 上级地点编码，国家为空
+	Name      string   `json:"name"`   // 中文名
+	NameEn    string   `json:"name_en"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+}
+
+// Gazetteer 内置地名库，构建后只读，可并发使用
+type Gazetteer struct {
+	places   map[string]*Place
+	byName   map[string]*Place
+	matcher  *nlp.Matcher
+	patterns []patternInfo
+}
+
+type patternInfo struct {
+	place *Place
+	ascii bool // 英文地名需要在单词边界上命中
+}
+
+var (
+	defaultGazetteer *Gazetteer
+	defaultOnce      sync.Once
+)
+
+// Default 返回内置地名库
+func Default() *Gazetteer {
+	defaultOnce.Do(func() {
+		defaultGazetteer = parseGazetteer(gazetteerData)
+	})
+	return defaultGazetteer
+}
+
+func parseGazetteer(data string) *Gazetteer {
+	g := &Gazetteer{
+		places: make(map[string]*Place),
+		byName: make(map[string]*Place),
+	}
+	builder := nlp.NewMatcherBuilder()
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 8 {
+			log.Printf("[GEO WARNING] skipping malformed gazetteer line: %s", line)
+			continue
+		}
+		lat, errLat := strconv.ParseFloat(fields[6], 64)
+		lon, errLon := strconv.ParseFloat(fields[7], 64)
+		if errLat != nil || errLon != nil {
+			log.Printf("[GEO WARNING] invalid coordinates for %s", fields[0])
+			continue
+		}
+
+		place := &Place{
+			Code:      fields[0],
+			Level:     fields[1],
+			Name:      fields[3],
+			NameEn:    fields[4],
+			Latitude:  lat,
+			Longitude: lon,
+		}
+		if fields[2] != "-" {
+			place.Parent = fields[2]
+		}
+		if fields[5] != "-" {
+			place.Aliases = strings.Split(fields[5], ",")
+		}
+		g.places[place.Code] = place
+
+		names := append([]string{place.Name, place.NameEn}, place.Aliases...)
+		for _, name := range names {
+			key := lookupKey(name)
+			if key == "" {
+				continue
+			}
+			// 同名时保留先出现的（层级更高的）地点
+			if _, exists := g.byName[key]; !exists {
+				g.byName[key] = place
+			}
+
+			pattern := matchText(name)
+			if len([]rune(pattern)) < 2 {
+				continue
+			}
+			if builder.AddPattern(pattern) >= 0 {
+				g.patterns = append(g.patterns, patternInfo{place: place, ascii: isASCII(pattern)})
+			}
+		}
+	}
+
+	g.matcher = builder.Build()
+	return g
+}
+
+// Get 按编码获取地点
+func (g *Gazetteer) Get(code string) (*Place, bool) {
+	place, ok := g.places[code]
+	return place, ok
+}
+
+// Lookup 按名称、别名或编码查找地点，忽略大小写、空格和标点
+func (g *Gazetteer) Lookup(name string) (*Place, bool) {
+	if place, ok := g.places[strings.ToUpper(strings.TrimSpace(name))]; ok {
+		return place, true
+	}
+	place, ok := g.byName[lookupKey(name)]
+	return place, ok
+}
+
+// Ancestors 返回地点及其所有上级，从自身开始
+func (g *Gazetteer) Ancestors(place *Place) []*Place {
+	var chain []*Place
+	for place != nil {
+		chain = append(chain, place)
+		parent, ok := g.places[place.Parent]
+		if !ok {
+			break
+		}
+		place = parent
+	}
+	return chain
+}
+
+// Extractor 累积多段文本中的地名命中，选出主要地点
+type Extractor struct {
+	g      *Gazetteer
+	scores map[string]float64 // 直接命中的加权次数
+	order  map[string]int     // 首次命中的顺序，得分相同时先出现的优先
+}
+
+// NewExtractor 创建地名抽取器
+func (g *Gazetteer) NewExtractor() *Extractor {
+	return &Extractor{
+		g:      g,
+		scores: make(map[string]float64),
+		order:  make(map[string]int),
+	}
+}
+
+// Add 以给定权重统计一段文本中的地名，重叠的命中只保留最长的一个
+func (e *Extractor) Add(text string, weight float64) {
+	for _, mention := range e.g.Mentions(text) {
+		code := mention.Place.Code
+		if _, seen := e.order[code]; !seen {
+			e.order[code] = len(e.order)
+		}
+		e.scores[code] += weight
+	}
+}
+
+// Mention 文本中的一次地名命中
+type Mention struct {
+	Place *Place
+	Start int // 在文本 rune 序列中的起始位置
+	End   int // 结束位置（不含）
+}
+
+// Mentions 按出现顺序返回文本中的地名，重叠的命中只保留最长的一个，英文地名需要在单词边界上
+func (g *Gazetteer) Mentions(text string) []Mention {
+	runes := []rune(matchText(text))
+	matches := g.matcher.FindAll(string(runes))
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+
+	mentions := make([]Mention, 0, len(matches))
+	covered := 0
+	for _, m := range matches {
+		if m.Start < covered {
+			continue
+		}
+		info := g.patterns[m.Pattern]
+		if info.ascii && !atWordBoundary(runes, m.Start, m.End) {
+			continue
+		}
+		covered = m.End
+		mentions = append(mentions, Mention{Place: info.place, Start: m.Start, End: m.End})
+	}
+	return mentions
+}
+
+// Dominant 返回主要地点：从得分最高的国家开始，只要某个下级地点占上级得分的一半以上就继续细化
+// 总得分低于 minScore 时认为没有明确地点
+func (e *Extractor) Dominant(minScore float64) (*Place, bool) {
+	// 将直接命中的得分累加到所有上级
+	total := make(map[string]float64)
+	first := make(map[string]int)
+	for code, score := range e.scores {
+		for _, place := range e.g.Ancestors(e.g.places[code]) {
+			total[place.Code] += score
+			if order, ok := first[place.Code]; !ok || e.order[code] < order {
+				first[place.Code] = e.order[code]
+			}
+		}
+	}
+
+	children := make(map[string][]string)
+	var roots []string
+	for code := range total {
+		place := e.g.places[code]
+		if _, ok := total[place.Parent]; ok {
+			children[place.Parent] = append(children[place.Parent], code)
+		} else {
+			roots = append(roots, code)
+		}
+	}
+
+	best := func(codes []string) string {
+		bestCode := ""
+		for _, code := range codes {
+			if bestCode == "" || total[code] > total[bestCode] ||
+				(total[code] == total[bestCode] && first[code] < first[bestCode]) {
+				bestCode = code
+			}
+		}
+		return bestCode
+	}
+
+	current := best(roots)
+	if current == "" || total[current] < minScore {
+		return nil, false
+	}
+	for {
+		child := best(children[current])
+		if child == "" || total[child]*2 < total[current] {
+			break
+		}
+		current = child
+	}
+	return e.g.places[current], true
+}
+
+// earthRadiusKm 地球平均半径
+const earthRadiusKm = 6371.0
+
+// DistanceKm 用 haversine 公式计算两点间的球面距离（公里）
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox 返回以某点为中心、半径 radiusKm 的外接经纬度范围
+// 跨越日期变更线时 minLon 大于 maxLon
+func BoundingBox(lat, lon, radiusKm float64) (minLat, minLon, maxLat, maxLon float64) {
+	dLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	minLat = math.Max(-90, lat-dLat)
+	maxLat = math.Min(90, lat+dLat)
+
+	cosLat := math.Cos(lat * math.Pi / 180)
+	if maxLat >= 90 || minLat <= -90 || cosLat < 1e-6 {
+		return minLat, -180, maxLat, 180
+	}
+	dLon := dLat / cosLat
+	if dLon >= 180 {
+		return minLat, -180, maxLat, 180
+	}
+	minLon = normalizeLon(lon - dLon)
+	maxLon = normalizeLon(lon + dLon)
+	return minLat, minLon, maxLat, maxLon
+}
+
+func normalizeLon(lon float64) float64 {
+	for lon < -180 {
+		lon += 360
+	}
+	for lon > 180 {
+		lon -= 360
+	}
+	return lon
+}
+
+// matchText 匹配用的规范化：全角转半角、统一小写，保留空格以区分英文单词
+func matchText(text string) string {
+	return strings.ToLower(nlp.ToHalfWidth(text))
+}
+
+// lookupKey 查找用的规范化：额外去除空格和标点
+func lookupKey(name string) string {
+	return nlp.NormalizeForMatch(name)
+}
+
+func isASCII(text string) bool {
+	for _, r := range text {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+func atWordBoundary(runes []rune, start, end int) bool {
+	isWord := func(r rune) bool {
+		return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+	}
+	if start > 0 && isWord(runes[start-1]) {
+		return false
+	}
+	if end < len(runes) && isWord(runes[end]) {
+		return false
+	}
+	return true
+}
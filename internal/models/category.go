@@ -0,0 +1,128 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 分类别名的来源，名称、slug 和各语言名称也作为别名参与识别
+const (
+	CategoryAliasName  = "name"  // 默认显示名称
+	CategoryAliasSlug  = "slug"  // URL 标识
+	CategoryAliasI18n  = "i18n"  // 其他语言的显示名称
+	CategoryAliasAlias = "alias" // 管理员添加的别名，如 RSS 源使用的分类名
+)
+
+// Category 规范化的新闻和事件分类，支持层级
+type Category struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	ParentID  *uint           `json:"parent_id" gorm:"index"`
+	Slug      string          `json:"slug" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Name      string          `json:"name" gorm:"type:varchar(100);not null"` // 默认（中文）显示名称
+	Names     string          `json:"-" gorm:"type:text"`                     // 其他语言的显示名称（JSON对象，语言代码 -> 名称）
+	SortOrder int             `json:"sort_order" gorm:"default:0"`
+	IsActive  bool            `json:"is_active" gorm:"default:true"`
+	Aliases   []CategoryAlias `json:"-" gorm:"foreignKey:CategoryID"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CategoryAlias 识别分类字符串使用的名称，新闻和事件的分类字符串按规范化形式对应到分类
+type CategoryAlias struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	CategoryID uint   `json:"category_id" gorm:"not null;index"`
+	Key        string `json:"-" gorm:"type:varchar(100);not null;uniqueIndex"` // 小写、去掉首尾空白的名称
+	Alias      string `json:"alias" gorm:"type:varchar(100);not null"`
+	Kind       string `json:"kind" gorm:"type:varchar(10);not null"`
+}
+
+// NewsCategory 新闻所属的分类
+type NewsCategory struct {
+	NewsID     uint `json:"news_id" gorm:"primaryKey"`
+	CategoryID uint `json:"category_id" gorm:"primaryKey;index"`
+}
+
+// EventCategory 事件所属的分类
+type EventCategory struct {
+	EventID    uint `json:"event_id" gorm:"primaryKey"`
+	CategoryID uint `json:"category_id" gorm:"primaryKey;index"`
+}
+
+// CategoryResponse 分类信息
+type CategoryResponse struct {
+	ID          uint               `json:"id"`
+	ParentID    *uint              `json:"parent_id"`
+	Slug        string             `json:"slug"`
+	Name        string             `json:"name"`
+	DisplayName string             `json:"display_name"` // 请求语言的显示名称，没有该语言时为默认名称
+	Names       map[string]string  `json:"names"`
+	Aliases     []string           `json:"aliases"`
+	SortOrder   int                `json:"sort_order"`
+	IsActive    bool               `json:"is_active"`
+	NewsCount   int64              `json:"news_count"`
+	EventCount  int64              `json:"event_count"`
+	Children    []CategoryResponse `json:"children,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// CategoryQueryRequest 分类列表查询请求
+type CategoryQueryRequest struct {
+	Lang            string `form:"lang"`             // 显示名称的语言，如 en
+	Flat            bool   `form:"flat"`             // 按排序返回平铺列表，默认返回树
+	IncludeInactive bool   `form:"include_inactive"` // 包含停用的分类，仅管理接口有效
+}
+
+// CreateCategoryRequest 创建分类请求
+type CreateCategoryRequest struct {
+	Name      string            `json:"name" binding:"required,min=1,max=100"`
+	Slug      string            `json:"slug" binding:"omitempty,max=100"` // 为空时由英文名称或默认名称生成
+	ParentID  *uint             `json:"parent_id"`
+	Names     map[string]string `json:"names" binding:"omitempty,dive,keys,min=2,max=10,endkeys,min=1,max=100"`
+	Aliases   []string          `json:"aliases" binding:"omitempty,dive,min=1,max=100"`
+	SortOrder int               `json:"sort_order"`
+}
+
+// UpdateCategoryRequest 更新分类请求，Names 和 Aliases 传入时整体替换
+type UpdateCategoryRequest struct {
+	Name      string            `json:"name" binding:"omitempty,min=1,max=100"`
+	Slug      string            `json:"slug" binding:"omitempty,max=100"`
+	ParentID  *uint             `json:"parent_id"` // 0 表示移到顶层
+	Names     map[string]string `json:"names" binding:"omitempty,dive,keys,min=2,max=10,endkeys,min=1,max=100"`
+	Aliases   []string          `json:"aliases" binding:"omitempty,dive,min=1,max=100"`
+	SortOrder *int              `json:"sort_order"`
+	IsActive  *bool             `json:"is_active"`
+}
+
+// MigrateCategoriesResult 从分类字符串迁移分类的结果
+type MigrateCategoriesResult struct {
+	CreatedCategories []string `json:"created_categories"` // 由无法识别的分类字符串新建的分类
+	LinkedNews        int64    `json:"linked_news"`
+	LinkedEvents      int64    `json:"linked_events"`
+	Duration          string   `json:"duration"`
+}
+
+// DisplayNames 解析其他语言的显示名称
+func (c *Category) DisplayNames() map[string]string {
+	names := map[string]string{}
+	if c.Names != "" {
+		json.Unmarshal([]byte(c.Names), &names)
+	}
+	return names
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+func (CategoryAlias) TableName() string {
+	return "category_aliases"
+}
+
+func (NewsCategory) TableName() string {
+	return "news_categories"
+}
+
+func (EventCategory) TableName() string {
+	return "event_categories"
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+)
+
+// CategoryModel 新闻分类模型，每次训练保存一个版本，分类时使用最新的版本
+type CategoryModel struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Categories string    `json:"categories" gorm:"type:text"` // 参与训练的类别（JSON数组）
+	TrainSize  int       `json:"train_size"`
+	TestSize   int       `json:"test_size"`
+	Accuracy   float64   `json:"accuracy"`
+	MacroF1    float64   `json:"macro_f1"`
+	Report     string    `json:"-" gorm:"type:text"` // 留出集上各类别的指标和混淆矩阵（JSON）
+	Model      string    `json:"-" gorm:"type:text"` // 序列化的朴素贝叶斯模型
+	TrainedBy  *uint     `json:"trained_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CategoryMetrics 单个类别在留出集上的指标
+type CategoryMetrics struct {
+	Category  string  `json:"category"`
+	Support   int     `json:"support"` // 留出集中该类别的新闻数
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+// ConfusionMatrix 混淆矩阵，行为新闻原有的类别，列为预测的类别，顺序与 Labels 一致
+type ConfusionMatrix struct {
+	Labels []string `json:"labels"`
+	Matrix [][]int  `json:"matrix"`
+}
+
+// CategoryModelReport 分类模型在留出集上的评估结果
+type CategoryModelReport struct {
+	Metrics   []CategoryMetrics `json:"metrics"`
+	Confusion ConfusionMatrix   `json:"confusion"`
+}
+
+// CategoryModelResponse 分类模型信息及评估结果
+type CategoryModelResponse struct {
+	ID            uint              `json:"id"`
+	Categories    []string          `json:"categories"`
+	TrainSize     int               `json:"train_size"`
+	TestSize      int               `json:"test_size"`
+	Accuracy      float64           `json:"accuracy"`
+	MacroF1       float64           `json:"macro_f1"`
+	MinConfidence float64           `json:"min_confidence"` // 当前配置下采用预测分类的最低置信度
+	Metrics       []CategoryMetrics `json:"metrics"`
+	Confusion     ConfusionMatrix   `json:"confusion"`
+	TrainedBy     *uint             `json:"trained_by"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// PredictCategoryRequest 预测分类请求
+type PredictCategoryRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content"`
+}
+
+// CategoryProbability 一个类别的预测概率
+type CategoryProbability struct {
+	Category    string  `json:"category"`
+	Probability float64 `json:"probability"`
+}
+
+// CategoryPredictionResponse 分类预测结果
+type CategoryPredictionResponse struct {
+	ModelID    uint                  `json:"model_id"`
+	Category   string                `json:"category"`
+	Confidence float64               `json:"confidence"`
+	Confident  bool                  `json:"confident"` // 置信度达到阈值，新闻没有自带分类时会采用
+	Candidates []CategoryProbability `json:"candidates"`
+}
+
+// ClassifyNewsResult 重新预测全部新闻分类的结果
+type ClassifyNewsResult struct {
+	ProcessedNews    int    `json:"processed_news"`
+	ChangedCategory  int    `json:"changed_category"`  // 分类被预测结果替换或恢复为来源分类的新闻数
+	ConfidentPredict int    `json:"confident_predict"` // 预测置信度达到阈值的新闻数
+	Duration         string `json:"duration"`
+}
+
+func (CategoryModel) TableName() string {
+	return "category_models"
+}
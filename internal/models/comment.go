@@ -0,0 +1,133 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CommentTargetType 评论对象类型
+type CommentTargetType string
+
+const (
+	CommentTargetEvent CommentTargetType = "event" // 事件评论
+	CommentTargetNews  CommentTargetType = "news"  // 新闻评论
+)
+
+// 评论状态
+const (
+	CommentStatusPublished = "published" // 已发布
+	CommentStatusPending   = "pending"   // 待审核
+	CommentStatusRejected  = "rejected"  // 审核未通过
+)
+
+// Comment 评论模型，事件和新闻共用
+type Comment struct {
+	ID         uint              `json:"id" gorm:"primaryKey"`
+	TargetType CommentTargetType `json:"target_type" gorm:"type:varchar(20);not null;index:idx_comment_target"` // 评论对象类型
+	TargetID   uint              `json:"target_id" gorm:"not null;index:idx_comment_target"`                    // 评论对象ID
+	UserID     uint              `json:"user_id" gorm:"not null;index"`                                         // 评论作者
+	ParentID   *uint             `json:"parent_id" gorm:"index"`                                                // 父评论ID，顶层评论为空
+	Content    string            `json:"content" gorm:"type:text;not null"`                                     // 评论内容
+	ReplyCount int64             `json:"reply_count" gorm:"default:0"`                                          // 回复数
+	Status     string            `json:"status" gorm:"type:varchar(20);default:'published';index"`              // 审核状态
+	IsEdited   bool              `json:"is_edited" gorm:"default:false"`                                        // 是否被编辑过
+	EditedAt   *time.Time        `json:"edited_at"`                                                             // 最后编辑时间
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID"`
+}
+
+// CommentEdit 评论编辑历史
+type CommentEdit struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	CommentID       uint      `json:"comment_id" gorm:"not null;index"`  // 被编辑的评论
+	PreviousContent string    `json:"previous_content" gorm:"type:text"` // 编辑前的内容
+	EditedBy        uint      `json:"edited_by" gorm:"not null"`         // 编辑者
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// CommentAuthor 评论作者信息
+type CommentAuthor struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Avatar   string `json:"avatar"`
+}
+
+// CommentResponse 评论响应结构
+type CommentResponse struct {
+	ID         uint              `json:"id"`
+	TargetType CommentTargetType `json:"target_type"`
+	TargetID   uint              `json:"target_id"`
+	ParentID   *uint             `json:"parent_id,omitempty"`
+	Content    string            `json:"content"`
+	ReplyCount int64             `json:"reply_count"`
+	Status     string            `json:"status"`
+	IsEdited   bool              `json:"is_edited"`
+	EditedAt   *time.Time        `json:"edited_at,omitempty"`
+	Author     *CommentAuthor    `json:"author,omitempty"`
+	Replies    []CommentResponse `json:"replies,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// CommentListResponse 评论列表响应结构
+type CommentListResponse struct {
+	Total    int64             `json:"total"`
+	Comments []CommentResponse `json:"comments"`
+}
+
+// CommentQueryRequest 评论查询请求
+type CommentQueryRequest struct {
+	SortBy string `form:"sort_by"` // 排序方式: newest, top
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+}
+
+// CreateCommentRequest 创建评论请求
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,min=1,max=2000"`
+	ParentID *uint  `json:"parent_id"` // 回复的评论ID，可选
+}
+
+// UpdateCommentRequest 编辑评论请求
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=2000"`
+}
+
+func (c *Comment) ToResponse() CommentResponse {
+	response := CommentResponse{
+		ID:         c.ID,
+		TargetType: c.TargetType,
+		TargetID:   c.TargetID,
+		ParentID:   c.ParentID,
+		Content:    c.Content,
+		ReplyCount: c.ReplyCount,
+		Status:     c.Status,
+		IsEdited:   c.IsEdited,
+		EditedAt:   c.EditedAt,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+	}
+
+	if c.User != nil {
+		response.Author = &CommentAuthor{
+			ID:       c.User.ID,
+			Username: c.User.Username,
+			Avatar:   c.User.Avatar,
+		}
+	}
+
+	return response
+}
+
+func (Comment) TableName() string {
+	return "comments"
+}
+
+func (CommentEdit) TableName() string {
+	return "comment_edits"
+}
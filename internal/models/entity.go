@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+)
+
+// 实体类型
+const (
+	EntityTypePerson       = "person"       // 人物
+	EntityTypeOrganization = "organization" // 机构
+	EntityTypePlace        = "place"        // 地点
+)
+
+// EntityTypes 全部实体类型，也是分面统计的顺序
+var EntityTypes = []string{EntityTypePerson, EntityTypeOrganization, EntityTypePlace}
+
+// Entity 从新闻中识别出的人物、机构或地点
+type Entity struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	Type      string        `json:"type" gorm:"type:varchar(20);not null;uniqueIndex:idx_entity_type_key,priority:1"`
+	Key       string        `json:"-" gorm:"type:varchar(200);not null;uniqueIndex:idx_entity_type_key,priority:2"` // 规范化的名称
+	Name      string        `json:"name" gorm:"type:varchar(200);not null;index"`
+	NameEn    string        `json:"name_en" gorm:"type:varchar(200)"`
+	PlaceCode string        `json:"place_code,omitempty" gorm:"type:varchar(20);index"` // 地点在地名库中的编码
+	Origin    string        `json:"origin" gorm:"type:varchar(20)"`                     // dictionary / gazetteer / rule
+	Aliases   []EntityAlias `json:"-" gorm:"foreignKey:EntityID"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// EntityAlias 实体的名称和别名，识别出的名称按类型和规范化形式对应到实体
+type EntityAlias struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	EntityID uint   `json:"entity_id" gorm:"not null;index"`
+	Type     string `json:"type" gorm:"type:varchar(20);not null;uniqueIndex:idx_entity_alias,priority:1"`
+	Key      string `json:"-" gorm:"type:varchar(200);not null;uniqueIndex:idx_entity_alias,priority:2"`
+	Alias    string `json:"alias" gorm:"type:varchar(200);not null"`
+}
+
+// NewsEntity 新闻提到的实体
+type NewsEntity struct {
+	NewsID   uint `json:"news_id" gorm:"primaryKey"`
+	EntityID uint `json:"entity_id" gorm:"primaryKey;index"`
+	Mentions int  `json:"mentions"` // 标题和正文中的提及次数
+	InTitle  bool `json:"in_title"`
+}
+
+// EventEntity 事件关联新闻提到的实体，由新闻的实体汇总而来
+type EventEntity struct {
+	EventID   uint `json:"event_id" gorm:"primaryKey"`
+	EntityID  uint `json:"entity_id" gorm:"primaryKey;index"`
+	NewsCount int  `json:"news_count"` // 提到该实体的新闻数
+	Mentions  int  `json:"mentions"`
+}
+
+// EntityResponse 实体信息
+type EntityResponse struct {
+	ID         uint     `json:"id"`
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	NameEn     string   `json:"name_en,omitempty"`
+	Aliases    []string `json:"aliases"`
+	PlaceCode  string   `json:"place_code,omitempty"`
+	Origin     string   `json:"origin"`
+	NewsCount  int64    `json:"news_count"`
+	EventCount int64    `json:"event_count"`
+}
+
+// EntityDetailResponse 实体详情，包含相关事件和相关新闻
+type EntityDetailResponse struct {
+	Entity EntityResponse  `json:"entity"`
+	Events []EventResponse `json:"events"` // 全部相关事件，按提到该实体的新闻数和热度排序
+	News   []NewsResponse  `json:"news"`   // 相关新闻，按发布时间倒序分页
+	Page   int             `json:"page"`
+	Limit  int             `json:"limit"`
+}
+
+// EntityDetailQueryRequest 实体详情查询请求，分页只作用于相关新闻
+type EntityDetailQueryRequest struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=20"`
+}
+
+// EntityFacet 列表中某个实体出现的次数
+type EntityFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// EntityFacets 按实体类型分组的分面统计
+type EntityFacets map[string][]EntityFacet
+
+// ExtractEntitiesResult 重新识别新闻实体的结果
+type ExtractEntitiesResult struct {
+	ProcessedNews int    `json:"processed_news"`
+	Entities      int    `json:"entities"`       // 识别出的实体提及数（每条新闻中的同一实体只计一次）
+	UpdatedEvents int    `json:"updated_events"` // 重新汇总实体的事件数
+	Duration      string `json:"duration"`
+}
+
+func (Entity) TableName() string {
+	return "entities"
+}
+
+func (EntityAlias) TableName() string {
+	return "entity_aliases"
+}
+
+func (NewsEntity) TableName() string {
+	return "news_entities"
+}
+
+func (EventEntity) TableName() string {
+	return "event_entities"
+}
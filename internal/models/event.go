@@ -15,10 +15,15 @@ type Event struct {
 	StartTime   time.Time `json:"start_time" gorm:"not null"`
 	EndTime     time.Time `json:"end_time" gorm:"not null"`
 	Location    string    `json:"location" gorm:"type:varchar(255)"`
-	Status      string    `json:"status" gorm:"type:varchar(20);default:'进行中';index"` // 进行中、已结束
+	Status      string    `json:"status" gorm:"type:varchar(20);default:'ongoing';index"` // 生命周期状态代码：upcoming、ongoing、cooling、ended、archived
 	CreatedBy   uint      `json:"created_by" gorm:"not null"`
 	Image       string    `json:"image" gorm:"type:varchar(255)"`
 
+	// 地理位置字段，由 Location 按内置地名库解析，无法解析时为空
+	LocationCode string   `json:"location_code" gorm:"type:varchar(20);index"`          // 地点编码，如 CN-GD、CN-440300、US-NYC
+	Latitude     *float64 `json:"latitude" gorm:"index:idx_events_lat_lon,priority:1"`  // 纬度
+	Longitude    *float64 `json:"longitude" gorm:"index:idx_events_lat_lon,priority:2"` // 经度
+
 	// 基础分类字段
 	Category     string `json:"category" gorm:"type:varchar(50);index"` // 事件分类
 	Tags         string `json:"tags" gorm:"type:text"`                  // 事件标签（JSON字符串）
@@ -32,6 +37,9 @@ type Event struct {
 	CommentCount int64   `json:"comment_count" gorm:"default:0"`       // 评论数
 	ShareCount   int64   `json:"share_count" gorm:"default:0"`         // 分享数
 	HotnessScore float64 `json:"hotness_score" gorm:"default:0;index"` // 事件热度分值
+
+	// 搜索字段，分词结果只通过 SQL 写入，不随模型读写
+	SearchVector string `json:"-" gorm:"type:tsvector;index:idx_events_search_vector,type:gin;->:false;<-:false"`
 }
 
 // EventResponse 事件响应结构
@@ -43,7 +51,11 @@ type EventResponse struct {
 	StartTime    time.Time `json:"start_time"`
 	EndTime      time.Time `json:"end_time"`
 	Location     string    `json:"location"`
+	LocationCode string    `json:"location_code,omitempty"`
+	Latitude     *float64  `json:"latitude,omitempty"`
+	Longitude    *float64  `json:"longitude,omitempty"`
 	Status       string    `json:"status"`
+	StatusLabel  string    `json:"status_label"` // 状态的中文名称
 	CreatedBy    uint      `json:"created_by"`
 	Image        string    `json:"image,omitempty"`
 	Category     string    `json:"category"`
@@ -58,22 +70,48 @@ type EventResponse struct {
 	HotnessScore float64   `json:"hotness_score"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	RedirectedFrom *uint            `json:"redirected_from,omitempty"` // 请求的事件已合并时，原请求的事件ID
+	DistanceKm     *float64         `json:"distance_km,omitempty"`     // 按 near 查询时与查询点的距离（公里）
+	Highlight      *SearchHighlight `json:"highlight,omitempty"`       // 按关键词搜索时的高亮片段
 }
 
 // EventListResponse 事件列表响应结构
 type EventListResponse struct {
 	Total  int64           `json:"total"`
 	Events []EventResponse `json:"events"`
+	Facets EntityFacets    `json:"facets,omitempty"` // facets=true 时返回筛选结果中出现最多的人物、机构和地点
 }
 
 // EventQueryRequest 事件查询请求
 type EventQueryRequest struct {
-	Status   string `form:"status"`   // 状态筛选
-	Category string `form:"category"` // 分类筛选
-	Search   string `form:"search"`   // 搜索关键词
-	SortBy   string `form:"sort_by"`  // 排序方式: time, hotness, views
+	Status   string `form:"status"`    // 状态筛选，状态代码或中文名称
+	Category string `form:"category"`  // 分类筛选
+	Search   string `form:"search"`    // 搜索关键词，支持与统一搜索相同的语法
+	SortBy   string `form:"sort_by"`   // 排序方式: time, hotness, views, distance（需要 near）
+	EntityID uint   `form:"entity_id"` // 只返回提到该实体的事件
+	Facets   bool   `form:"facets"`    // 是否返回实体分面统计
 	Page     int    `form:"page,default=1"`
 	Limit    int    `form:"limit,default=10"`
+
+	// 地理筛选，只返回已解析出坐标的事件
+	Near   string  `form:"near"`   // 中心点 "lat,lon"
+	Radius float64 `form:"radius"` // 半径（公里），默认 50
+	BBox   string  `form:"bbox"`   // 范围 "minLon,minLat,maxLon,maxLat"，minLon 大于 maxLon 表示跨越日期变更线
+}
+
+// GeocodeEventsRequest 重新解析事件地理位置请求
+type GeocodeEventsRequest struct {
+	IDs       []uint `json:"ids"`       // 只处理指定事件，为空时处理全部事件
+	Reextract bool   `json:"reextract"` // 从关联新闻重新抽取地点，否则只按现有 location 解析坐标
+}
+
+// GeocodeEventsResult 重新解析事件地理位置结果
+type GeocodeEventsResult struct {
+	Processed  int `json:"processed"`
+	Geocoded   int `json:"geocoded"`   // 解析出坐标的事件
+	Unresolved int `json:"unresolved"` // location 无法在地名库中找到
+	Relocated  int `json:"relocated"`  // 重新抽取后 location 发生变化
 }
 
 // CreateEventRequest 创建事件请求
@@ -100,7 +138,7 @@ type UpdateEventRequest struct {
 	StartTime    time.Time `json:"start_time"`
 	EndTime      time.Time `json:"end_time" binding:"omitempty,gtfield=StartTime"`
 	Location     string    `json:"location" binding:"omitempty,max=255"`
-	Status       string    `json:"status" binding:"omitempty,oneof=进行中 已结束"`
+	Status       string    `json:"status"` // 状态代码或中文名称，只允许生命周期中的直接转换
 	Category     string    `json:"category" binding:"omitempty,max=50"`
 	Tags         []string  `json:"tags"`
 	Source       string    `json:"source" binding:"omitempty,max=100"`
@@ -112,6 +150,7 @@ type UpdateEventRequest struct {
 // TagResponse 标签响应结构
 type TagResponse struct {
 	Tag      string `json:"tag"`
+	Slug     string `json:"slug"` // 标签页 /api/v1/tags/{slug} 的标识
 	Count    int    `json:"count"`
 	Category string `json:"category"`
 }
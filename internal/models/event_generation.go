@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+)
+
+// 事件生成提案的操作类型
+const (
+	EventProposalCreate = "create" // 新建事件
+	EventProposalAttach = "attach" // 归入已有事件
+)
+
+// EventGenerationPlan 预演事件生成得到的提案集合，管理员审核后可以提交其中一部分
+type EventGenerationPlan struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	Mode          string    `json:"mode" gorm:"type:varchar(20);not null"`
+	Proposals     string    `json:"-" gorm:"type:text"` // 提案及预演时新闻的事件归属（JSON字符串）
+	ProcessedNews int       `json:"processed_news"`
+	CreatedBy     uint      `json:"created_by"`
+	ExpiresAt     time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// EventProposal 一条事件生成提案
+type EventProposal struct {
+	ID               int        `json:"id"`
+	Action           string     `json:"action"` // create 新建事件 / attach 归入已有事件
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Category         string     `json:"category"`
+	Location         string     `json:"location"`
+	Status           string     `json:"status"`
+	Source           string     `json:"source"`
+	Tags             []string   `json:"tags"`
+	NewsIDs          []uint     `json:"news_ids"`
+	StartTime        time.Time  `json:"start_time"`
+	EndTime          time.Time  `json:"end_time"`
+	HotnessScore     float64    `json:"hotness_score"`
+	AttachToEventID  *uint      `json:"attach_to_event_id,omitempty"` // attach 时归入的事件
+	CurrentEventIDs  []uint     `json:"current_event_ids,omitempty"`  // 新闻当前所属的事件，提交后新闻会改为关联到新事件
+	CommittedEventID *uint      `json:"committed_event_id,omitempty"` // 已提交时生成或更新的事件
+	CommittedAt      *time.Time `json:"committed_at,omitempty"`
+}
+
+// EventGenerationPreview 预演事件生成的结果
+type EventGenerationPreview struct {
+	PlanID            uint            `json:"plan_id"`
+	Mode              string          `json:"mode"`
+	Proposals         []EventProposal `json:"proposals"`
+	NewEvents         int             `json:"new_events"`      // 新建事件的提案数
+	AttachedEvents    int             `json:"attached_events"` // 归入已有事件的提案数
+	ProcessedNews     int             `json:"processed_news"`
+	CategoryBreakdown map[string]int  `json:"category_breakdown,omitempty"`
+	ExpiresAt         time.Time       `json:"expires_at"`
+	ElapsedTime       string          `json:"elapsed_time,omitempty"`
+}
+
+// CommitEventProposalsRequest 提交事件生成提案请求
+type CommitEventProposalsRequest struct {
+	PlanID      uint  `json:"plan_id" binding:"required"`
+	ProposalIDs []int `json:"proposal_ids" binding:"required,min=1"`
+}
+
+// SkippedEventProposal 未能提交的提案及原因
+type SkippedEventProposal struct {
+	ProposalID int    `json:"proposal_id"`
+	Reason     string `json:"reason"`
+}
+
+// CommitEventProposalsResult 提交事件生成提案的结果
+type CommitEventProposalsResult struct {
+	PlanID          uint                   `json:"plan_id"`
+	GeneratedEvents []EventResponse        `json:"generated_events"`
+	UpdatedEvents   []EventResponse        `json:"updated_events"`
+	Skipped         []SkippedEventProposal `json:"skipped"`
+}
+
+func (EventGenerationPlan) TableName() string {
+	return "event_generation_plans"
+}
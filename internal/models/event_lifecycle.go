@@ -0,0 +1,159 @@
+package models
+
+import (
+	"time"
+)
+
+// 事件生命周期状态：upcoming → ongoing → cooling → ended → archived
+const (
+	EventStatusUpcoming = "upcoming" // 未开始
+	EventStatusOngoing  = "ongoing"  // 进行中
+	EventStatusCooling  = "cooling"  // 降温中：已过结束时间但仍在观察期内
+	EventStatusEnded    = "ended"    // 已结束
+	EventStatusArchived = "archived" // 已归档：不再参与增量归并和热点
+)
+
+// EventStatuses 按生命周期顺序排列的全部状态
+var EventStatuses = []string{
+	EventStatusUpcoming,
+	EventStatusOngoing,
+	EventStatusCooling,
+	EventStatusEnded,
+	EventStatusArchived,
+}
+
+// eventStatusLabels 各状态的中英文名称
+var eventStatusLabels = map[string][2]string{
+	EventStatusUpcoming: {"未开始", "Upcoming"},
+	EventStatusOngoing:  {"进行中", "Ongoing"},
+	EventStatusCooling:  {"降温中", "Cooling"},
+	EventStatusEnded:    {"已结束", "Ended"},
+	EventStatusArchived: {"已归档", "Archived"},
+}
+
+// eventStatusTransitions 允许的状态转换
+// 降温中和已结束的事件有新报道时可以重新进入进行中，已归档的事件只能由管理员恢复为已结束
+var eventStatusTransitions = map[string][]string{
+	EventStatusUpcoming: {EventStatusOngoing, EventStatusEnded},
+	EventStatusOngoing:  {EventStatusCooling, EventStatusEnded},
+	EventStatusCooling:  {EventStatusOngoing, EventStatusEnded},
+	EventStatusEnded:    {EventStatusOngoing, EventStatusArchived},
+	EventStatusArchived: {EventStatusEnded},
+}
+
+// NormalizeEventStatus 将状态代码或中英文名称统一为状态代码，兼容旧数据中的中文状态
+func NormalizeEventStatus(status string) (string, bool) {
+	for code, labels := range eventStatusLabels {
+		if status == code || status == labels[0] || status == labels[1] {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// EventStatusLabel 返回状态在指定语言下的名称，lang 为 en 时返回英文，否则返回中文
+func EventStatusLabel(status, lang string) string {
+	labels, ok := eventStatusLabels[status]
+	if !ok {
+		return status
+	}
+	if lang == "en" {
+		return labels[1]
+	}
+	return labels[0]
+}
+
+// CanTransitionEventStatus 判断是否允许从 from 直接转换到 to
+func CanTransitionEventStatus(from, to string) bool {
+	for _, next := range eventStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// EventStatusPath 返回从 from 到 to 经过的最短状态序列（不含 from），无法到达时返回 nil
+func EventStatusPath(from, to string) []string {
+	if from == to {
+		return []string{}
+	}
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range eventStatusTransitions[current] {
+			if _, seen := prev[next]; seen {
+				continue
+			}
+			prev[next] = current
+			if next == to {
+				path := []string{}
+				for step := to; step != from; step = prev[step] {
+					path = append([]string{step}, path...)
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+// 状态转换的触发方式
+const (
+	EventTransitionAuto   = "auto"   // 定时任务或新闻变化触发
+	EventTransitionManual = "manual" // 管理员手动修改
+)
+
+// EventStatusHistory 事件状态转换记录
+type EventStatusHistory struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	EventID    uint      `json:"event_id" gorm:"not null;index"`
+	FromStatus string    `json:"from_status" gorm:"type:varchar(20)"`
+	ToStatus   string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	Trigger    string    `json:"trigger" gorm:"type:varchar(20);not null"` // auto / manual
+	Reason     string    `json:"reason" gorm:"type:varchar(255)"`
+	OperatorID *uint     `json:"operator_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+func (EventStatusHistory) TableName() string {
+	return "event_status_histories"
+}
+
+// EventStatusInfo 状态说明
+type EventStatusInfo struct {
+	Code        string   `json:"code"`
+	Label       string   `json:"label"`
+	LabelEn     string   `json:"label_en"`
+	Transitions []string `json:"transitions"` // 可以直接转换到的状态
+}
+
+// EventStatusInfos 返回全部状态的说明，按生命周期顺序排列
+func EventStatusInfos() []EventStatusInfo {
+	infos := make([]EventStatusInfo, 0, len(EventStatuses))
+	for _, code := range EventStatuses {
+		infos = append(infos, EventStatusInfo{
+			Code:        code,
+			Label:       eventStatusLabels[code][0],
+			LabelEn:     eventStatusLabels[code][1],
+			Transitions: append([]string{}, eventStatusTransitions[code]...),
+		})
+	}
+	return infos
+}
+
+// TransitionEventStatusRequest 管理员修改事件状态请求
+type TransitionEventStatusRequest struct {
+	Status string `json:"status" binding:"required"` // 目标状态代码，也接受中文名称
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// EventLifecycleResult 定时状态转换结果
+type EventLifecycleResult struct {
+	Checked     int            `json:"checked"`
+	Transitions map[string]int `json:"transitions"` // 按 "from->to" 统计的转换次数
+	Duration    string         `json:"duration"`
+}
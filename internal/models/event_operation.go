@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+)
+
+// 事件整理操作类型
+const (
+	EventOperationMerge = "merge" // 合并：将多个事件并入目标事件
+	EventOperationSplit = "split" // 拆分：将部分新闻移到新事件
+)
+
+// EventRedirect 合并后旧事件ID到目标事件ID的重定向
+type EventRedirect struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	FromEventID uint      `json:"from_event_id" gorm:"not null;uniqueIndex"`
+	ToEventID   uint      `json:"to_event_id" gorm:"not null;index"`
+	OperationID uint      `json:"operation_id" gorm:"index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// EventOperation 事件合并/拆分记录，保存操作前的快照用于撤销
+type EventOperation struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	Type           string     `json:"type" gorm:"type:varchar(20);not null;index"`
+	TargetEventID  uint       `json:"target_event_id" gorm:"index"`      // 合并的目标事件 / 拆分产生的新事件
+	SourceEventIDs string     `json:"source_event_ids" gorm:"type:text"` // 被合并的事件 / 被拆分的原事件（JSON字符串）
+	NewsIDs        string     `json:"news_ids" gorm:"type:text"`         // 移动的新闻（JSON字符串）
+	Snapshot       string     `json:"-" gorm:"type:text"`                // 操作前的状态快照
+	PerformedBy    uint       `json:"performed_by"`
+	UndoneAt       *time.Time `json:"undone_at"`
+	UndoneBy       *uint      `json:"undone_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// MergeEventsRequest 合并事件请求
+type MergeEventsRequest struct {
+	TargetID  uint   `json:"target_id" binding:"required"`
+	SourceIDs []uint `json:"source_ids" binding:"required,min=1"`
+}
+
+// SplitEventRequest 拆分事件请求
+type SplitEventRequest struct {
+	NewsIDs     []uint `json:"news_ids" binding:"required,min=1"`
+	Title       string `json:"title" binding:"omitempty,max=200"`
+	Description string `json:"description"`
+}
+
+// EventOperationQueryRequest 事件整理记录查询请求
+type EventOperationQueryRequest struct {
+	Type    string `form:"type"`
+	EventID uint   `form:"event_id"`
+	Page    int    `form:"page,default=1"`
+	Limit   int    `form:"limit,default=20"`
+}
+
+// EventOperationResult 合并/拆分结果
+type EventOperationResult struct {
+	Operation EventOperation  `json:"operation"`
+	Events    []EventResponse `json:"events"` // 操作后受影响的事件
+}
+
+func (EventRedirect) TableName() string {
+	return "event_redirects"
+}
+
+func (EventOperation) TableName() string {
+	return "event_operations"
+}
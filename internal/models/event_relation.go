@@ -0,0 +1,133 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 事件关系类型
+// follow-up-of 和 caused-by 有方向：FromEventID 是后续事件 / 结果事件，ToEventID 是前序事件 / 原因事件；
+// related-to 和 part-of-series 没有方向，保存时 FromEventID 取较小的事件ID
+const (
+	EventRelationFollowUpOf   = "follow-up-of"   // 后续进展
+	EventRelationCausedBy     = "caused-by"      // 由……引起
+	EventRelationRelatedTo    = "related-to"     // 相关
+	EventRelationPartOfSeries = "part-of-series" // 同一系列
+)
+
+// 事件关系来源
+const (
+	EventRelationManual = "manual" // 管理员添加
+	EventRelationAuto   = "auto"   // 按共同标签、地点和文本相似度自动建立
+)
+
+// eventRelationTypes 各关系类型中起点事件和终点事件的名称，以及排序权重
+var eventRelationTypes = map[string]struct {
+	fromName, toName string
+	directed         bool
+	weight           float64
+}{
+	EventRelationFollowUpOf:   {"后续进展", "前序事件", true, 1.0},
+	EventRelationCausedBy:     {"引发的事件", "起因", true, 1.0},
+	EventRelationPartOfSeries: {"同一系列", "同一系列", false, 0.9},
+	EventRelationRelatedTo:    {"相关事件", "相关事件", false, 0.7},
+}
+
+// EventRelation 事件之间的关系
+type EventRelation struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	FromEventID uint           `json:"from_event_id" gorm:"not null;uniqueIndex:idx_event_relation,priority:1"`
+	ToEventID   uint           `json:"to_event_id" gorm:"not null;uniqueIndex:idx_event_relation,priority:2;index"`
+	Type        string         `json:"type" gorm:"type:varchar(30);not null;uniqueIndex:idx_event_relation,priority:3"`
+	Confidence  float64        `json:"confidence" gorm:"not null;default:1"` // 0-1，管理员添加的关系默认为 1
+	Origin      string         `json:"origin" gorm:"type:varchar(10);not null;index"`
+	Reason      string         `json:"reason" gorm:"type:varchar(500)"` // 自动建立时的依据，如共同标签
+	CreatedBy   *uint          `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"` // 管理员删除的自动关系保留记录，之后不再自动建立
+}
+
+// CreateEventRelationRequest 添加事件关系请求，关系从路径中的事件指向 ToEventID
+type CreateEventRelationRequest struct {
+	ToEventID  uint     `json:"to_event_id" binding:"required"`
+	Type       string   `json:"type" binding:"required,oneof=follow-up-of caused-by related-to part-of-series"`
+	Confidence *float64 `json:"confidence" binding:"omitempty,gt=0,lte=1"`
+	Reason     string   `json:"reason" binding:"omitempty,max=500"`
+}
+
+// RelatedEventQueryRequest 相关事件查询请求
+type RelatedEventQueryRequest struct {
+	Type          string  `form:"type"`
+	MinConfidence float64 `form:"min_confidence"`
+	Limit         int     `form:"limit,default=10"`
+}
+
+// RelatedEventResponse 与指定事件相关的事件
+type RelatedEventResponse struct {
+	RelationID uint          `json:"relation_id"`
+	Type       string        `json:"type"`
+	Direction  string        `json:"direction"` // outgoing：指定事件是关系的起点；incoming：指定事件是关系的终点
+	Label      string        `json:"label"`     // 从指定事件看这个相关事件的关系名称
+	Confidence float64       `json:"confidence"`
+	Origin     string        `json:"origin"`
+	Strength   float64       `json:"strength"` // 排序依据：置信度乘以关系类型权重
+	Reason     string        `json:"reason,omitempty"`
+	Event      EventResponse `json:"event"`
+}
+
+// EventRelationSuggestion 自动推荐的事件关系
+type EventRelationSuggestion struct {
+	FromEventID    uint          `json:"from_event_id"`
+	ToEventID      uint          `json:"to_event_id"`
+	Type           string        `json:"type"`
+	Confidence     float64       `json:"confidence"`
+	SharedTags     []string      `json:"shared_tags"`
+	SharedEntities []string      `json:"shared_entities"` // 两个事件都提到的人物和机构
+	SameArea       bool          `json:"same_area"`       // 地点相同或存在包含关系
+	Similarity     float64       `json:"similarity"`      // 标题和描述的文本相似度
+	Reason         string        `json:"reason"`
+	Event          EventResponse `json:"event"` // 推荐的相关事件
+}
+
+// AutoLinkEventsResult 自动建立事件关系的结果
+type AutoLinkEventsResult struct {
+	CheckedEvents int    `json:"checked_events"`
+	Created       int    `json:"created"`
+	Updated       int    `json:"updated"`
+	Duration      string `json:"duration"`
+}
+
+// ValidEventRelationType 关系类型是否有效
+func ValidEventRelationType(relationType string) bool {
+	_, ok := eventRelationTypes[relationType]
+	return ok
+}
+
+// EventRelationDirected 关系类型是否有方向
+func EventRelationDirected(relationType string) bool {
+	return eventRelationTypes[relationType].directed
+}
+
+// EventRelationWeight 关系类型在相关事件排序中的权重
+func EventRelationWeight(relationType string) float64 {
+	return eventRelationTypes[relationType].weight
+}
+
+// EventRelationLabel 从关系一端看另一端事件的名称，incoming 为 true 表示从关系终点看起点
+func EventRelationLabel(relationType string, incoming bool) string {
+	info, ok := eventRelationTypes[relationType]
+	if !ok {
+		return relationType
+	}
+	// 从终点看到的是起点事件，例如从前序事件看到的是“后续进展”
+	if incoming {
+		return info.fromName
+	}
+	return info.toName
+}
+
+func (EventRelation) TableName() string {
+	return "event_relations"
+}
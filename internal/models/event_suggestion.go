@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+)
+
+// 候选事件的审核结果
+const (
+	SuggestionAccepted = "accepted" // 新闻归入该事件
+	SuggestionRejected = "rejected" // 新闻不属于该事件，之后不再推荐
+)
+
+// EventSuggestionFeedback 编辑对新闻候选事件的审核记录
+// 记录审核时新闻与事件的相似度，用作调整增量生成归入阈值（attach_threshold）的训练信号
+type EventSuggestionFeedback struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	NewsID          uint      `json:"news_id" gorm:"not null;uniqueIndex:idx_suggestion_feedback,priority:1"`
+	EventID         uint      `json:"event_id" gorm:"not null;uniqueIndex:idx_suggestion_feedback,priority:2;index"`
+	Decision        string    `json:"decision" gorm:"type:varchar(10);not null;index"`
+	Similarity      float64   `json:"similarity"`                        // 审核时新闻与事件的余弦相似度
+	Rank            int       `json:"rank"`                              // 审核时在候选列表中的名次，从 1 开始，不在列表中为 0
+	MatchedKeywords string    `json:"matched_keywords" gorm:"type:text"` // JSON 数组
+	SameCategory    bool      `json:"same_category"`                     // 新闻与事件分类相同
+	OperatorID      uint      `json:"operator_id" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// EventSuggestionQueryRequest 候选事件查询请求
+type EventSuggestionQueryRequest struct {
+	Limit int `form:"limit,default=5"`
+}
+
+// NewsEventSuggestion 新闻的候选事件
+type NewsEventSuggestion struct {
+	Rank            int           `json:"rank"`
+	Similarity      float64       `json:"similarity"`       // 新闻与事件标题、描述和内容的余弦相似度
+	AboveThreshold  bool          `json:"above_threshold"`  // 相似度达到增量生成的归入阈值
+	SameCategory    bool          `json:"same_category"`    // 增量生成只归入分类相同的事件
+	MatchedKeywords []string      `json:"matched_keywords"` // 对相似度贡献最大的共同关键词
+	Event           EventResponse `json:"event"`
+}
+
+// NewsEventSuggestionsResponse 新闻的候选事件列表
+type NewsEventSuggestionsResponse struct {
+	NewsID          uint                  `json:"news_id"`
+	BelongedEventID *uint                 `json:"belonged_event_id"`
+	AttachThreshold float64               `json:"attach_threshold"`
+	Suggestions     []NewsEventSuggestion `json:"suggestions"`
+}
+
+// ReviewEventSuggestionItem 一条候选事件的审核结果
+type ReviewEventSuggestionItem struct {
+	NewsID   uint   `json:"news_id" binding:"required"`
+	EventID  uint   `json:"event_id" binding:"required"`
+	Decision string `json:"decision" binding:"required,oneof=accepted rejected"`
+}
+
+// ReviewEventSuggestionsRequest 批量审核候选事件请求
+type ReviewEventSuggestionsRequest struct {
+	Items []ReviewEventSuggestionItem `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// SkippedSuggestion 未能执行的审核结果及原因
+type SkippedSuggestion struct {
+	NewsID  uint   `json:"news_id"`
+	EventID uint   `json:"event_id"`
+	Reason  string `json:"reason"`
+}
+
+// ReviewEventSuggestionsResult 批量审核候选事件的结果
+type ReviewEventSuggestionsResult struct {
+	Accepted      int                 `json:"accepted"`
+	Rejected      int                 `json:"rejected"`
+	Skipped       []SkippedSuggestion `json:"skipped"`
+	UpdatedEvents []EventResponse     `json:"updated_events"` // 接受的新闻归入后更新的事件
+}
+
+// SimilarityBucket 某一相似度区间内接受和拒绝的数量
+type SimilarityBucket struct {
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Accepted int64   `json:"accepted"`
+	Rejected int64   `json:"rejected"`
+}
+
+// SuggestionFeedbackStats 候选事件审核记录的统计，用于评估和调整归入阈值
+type SuggestionFeedbackStats struct {
+	Accepted             int64              `json:"accepted"`
+	Rejected             int64              `json:"rejected"`
+	AttachThreshold      float64            `json:"attach_threshold"`      // 当前配置的归入阈值
+	Precision            float64            `json:"precision"`             // 当前阈值下达到阈值的审核记录中被接受的比例
+	Recall               float64            `json:"recall"`                // 当前阈值下被接受的记录中达到阈值的比例
+	RecommendedThreshold *float64           `json:"recommended_threshold"` // 审核记录上准确率最高的阈值，记录不足时为空
+	Buckets              []SimilarityBucket `json:"buckets"`
+}
+
+func (EventSuggestionFeedback) TableName() string {
+	return "event_suggestion_feedback"
+}
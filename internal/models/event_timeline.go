@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 时间线分组粒度
+const (
+	TimelineGranularityDay  = "day"
+	TimelineGranularityHour = "hour"
+)
+
+// EventMilestone 编辑添加的事件里程碑，与关联新闻一起出现在事件时间线上
+type EventMilestone struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	EventID     uint           `json:"event_id" gorm:"not null;index"`
+	OccurredAt  time.Time      `json:"occurred_at" gorm:"not null"`
+	Title       string         `json:"title" gorm:"type:varchar(200);not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	Link        string         `json:"link" gorm:"type:varchar(1000)"`
+	CreatedBy   uint           `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// CreateMilestoneRequest 创建里程碑请求
+type CreateMilestoneRequest struct {
+	OccurredAt  time.Time `json:"occurred_at" binding:"required"`
+	Title       string    `json:"title" binding:"required,min=1,max=200"`
+	Description string    `json:"description"`
+	Link        string    `json:"link" binding:"omitempty,url,max=1000"`
+}
+
+// UpdateMilestoneRequest 更新里程碑请求
+type UpdateMilestoneRequest struct {
+	OccurredAt  *time.Time `json:"occurred_at"`
+	Title       string     `json:"title" binding:"omitempty,min=1,max=200"`
+	Description *string    `json:"description"`
+	Link        *string    `json:"link" binding:"omitempty,max=1000"`
+}
+
+// EventTimelineQueryRequest 事件时间线查询请求
+type EventTimelineQueryRequest struct {
+	Granularity string `form:"granularity,default=day" binding:"omitempty,oneof=day hour"` // 分组粒度：day、hour
+	Order       string `form:"order,default=asc" binding:"omitempty,oneof=asc desc"`       // 时间顺序
+}
+
+// TimelineNewsItem 时间线中的新闻
+type TimelineNewsItem struct {
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	Summary        string    `json:"summary,omitempty"`
+	Source         string    `json:"source"`
+	Link           string    `json:"link,omitempty"`
+	PublishedAt    time.Time `json:"published_at"`
+	IsFirstReport  bool      `json:"is_first_report"`  // 事件的首条报道
+	IsLatestUpdate bool      `json:"is_latest_update"` // 事件的最新进展
+}
+
+// TimelineSourceCount 时间线条目中各来源的报道数
+type TimelineSourceCount struct {
+	Source string `json:"source"`
+	Count  int    `json:"count"`
+}
+
+// TimelineEntry 时间线条目，对应一天或一小时
+type TimelineEntry struct {
+	PeriodStart    time.Time             `json:"period_start"`
+	PeriodEnd      time.Time             `json:"period_end"`
+	Label          string                `json:"label"`
+	NewsCount      int                   `json:"news_count"`
+	SourceCount    int                   `json:"source_count"` // 不同来源数，衡量该时段报道的来源多样性
+	Sources        []TimelineSourceCount `json:"sources"`
+	News           []TimelineNewsItem    `json:"news"`
+	Milestones     []EventMilestone      `json:"milestones"`
+	IsFirstReport  bool                  `json:"is_first_report"`  // 包含事件首条报道
+	IsLatestUpdate bool                  `json:"is_latest_update"` // 包含事件最新进展
+}
+
+// EventTimelineResponse 事件时间线响应
+type EventTimelineResponse struct {
+	EventID        uint            `json:"event_id"`
+	Title          string          `json:"title"`
+	Granularity    string          `json:"granularity"`
+	TotalNews      int             `json:"total_news"`
+	TotalSources   int             `json:"total_sources"`
+	FirstReportAt  *time.Time      `json:"first_report_at"`
+	LatestUpdateAt *time.Time      `json:"latest_update_at"`
+	Entries        []TimelineEntry `json:"entries"`
+	RedirectedFrom *uint           `json:"redirected_from,omitempty"`
+}
+
+func (EventMilestone) TableName() string {
+	return "event_milestones"
+}
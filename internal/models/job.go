@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 后台任务类型
+const (
+	JobTypeEventGeneration     = "event_generation"     // 从新闻生成事件
+	JobTypeRSSFetchAll         = "rss_fetch_all"        // 抓取所有活跃RSS源
+	JobTypeEntityExtraction    = "entity_extraction"    // 重新识别全部新闻的实体
+	JobTypeNewsTagging         = "news_tagging"         // 重新提取全部新闻的关键词标签
+	JobTypeNewsClassification  = "news_classification"  // 用最新的分类模型重新预测全部新闻的分类
+	JobTypeSearchIndex         = "search_index"         // 重建全部新闻的全文搜索索引
+	JobTypeSummaryRegeneration = "summary_regeneration" // 重新生成新闻摘要和事件描述
+	JobTypeEventRelationLink   = "event_relation_link"  // 为最近开始的事件自动建立关系
+	JobTypeClassifierTraining  = "classifier_training"  // 用已分类的新闻重新训练分类模型
+)
+
+// JobTypes 支持提交的后台任务类型
+var JobTypes = []string{JobTypeEventGeneration, JobTypeRSSFetchAll, JobTypeEntityExtraction, JobTypeNewsTagging, JobTypeNewsClassification, JobTypeSearchIndex, JobTypeSummaryRegeneration, JobTypeEventRelationLink, JobTypeClassifierTraining}
+
+// 后台任务状态
+const (
+	JobStatusQueued    = "queued"    // 已提交，等待执行
+	JobStatusRunning   = "running"   // 执行中
+	JobStatusSucceeded = "succeeded" // 执行成功
+	JobStatusFailed    = "failed"    // 执行失败或被服务重启中断
+	JobStatusCanceled  = "canceled"  // 已取消
+)
+
+// Job 后台任务记录，进度和部分结果在执行过程中持续写入，服务重启后仍可查询
+type Job struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Type            string     `json:"type" gorm:"type:varchar(40);not null;index;uniqueIndex:idx_jobs_active_type,where:status = 'queued' OR status = 'running'"` // 同类任务同时只有一个未结束
+	Status          string     `json:"status" gorm:"type:varchar(20);not null;default:'queued';index"`
+	Params          string     `json:"-" gorm:"type:text"` // 提交参数（JSON字符串）
+	Processed       int        `json:"processed"`
+	Total           int        `json:"total"`
+	Message         string     `json:"message" gorm:"type:varchar(255)"` // 当前执行阶段
+	Result          string     `json:"-" gorm:"type:text"`               // 部分结果或最终结果（JSON字符串）
+	Errors          string     `json:"-" gorm:"type:text"`               // 执行过程中的错误（JSON字符串）
+	CancelRequested bool       `json:"cancel_requested" gorm:"default:false"`
+	Owner           string     `json:"owner" gorm:"type:varchar(100)"` // 执行任务的服务实例
+	HeartbeatAt     *time.Time `json:"heartbeat_at"`                   // 执行实例最近一次确认任务仍在执行的时间
+	CreatedBy       uint       `json:"created_by" gorm:"index"`
+	StartedAt       *time.Time `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// JobResponse 后台任务响应
+type JobResponse struct {
+	ID              uint            `json:"id"`
+	Type            string          `json:"type"`
+	Status          string          `json:"status"`
+	Params          json.RawMessage `json:"params,omitempty"`
+	Progress        float64         `json:"progress"` // 完成百分比
+	Processed       int             `json:"processed"`
+	Total           int             `json:"total"`
+	Message         string          `json:"message"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Errors          []string        `json:"errors"`
+	CancelRequested bool            `json:"cancel_requested"`
+	Owner           string          `json:"owner"`
+	HeartbeatAt     *time.Time      `json:"heartbeat_at"`
+	CreatedBy       uint            `json:"created_by"`
+	StartedAt       *time.Time      `json:"started_at"`
+	FinishedAt      *time.Time      `json:"finished_at"`
+	Duration        string          `json:"duration,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// SubmitJobRequest 提交后台任务请求
+type SubmitJobRequest struct {
+	Type      string                      `json:"type" binding:"required"`
+	Mode      string                      `json:"mode"`                // event_generation：full / incremental，默认 full
+	DryRun    bool                        `json:"dry_run"`             // event_generation：只预演不写入
+	Summaries *RegenerateSummariesRequest `json:"summaries,omitempty"` // summary_regeneration：重新生成范围，默认全部
+}
+
+// JobQueryRequest 后台任务查询请求
+type JobQueryRequest struct {
+	Type   string `form:"type"`
+	Status string `form:"status"`
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+}
+
+// IsFinished 任务是否已结束
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCanceled
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
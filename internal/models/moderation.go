@@ -0,0 +1,122 @@
+package models
+
+import (
+	"time"
+)
+
+// 敏感词严重程度
+const (
+	SeverityLow    = "low"    // 轻微：放行
+	SeverityMedium = "medium" // 中等：进入人工审核
+	SeverityHigh   = "high"   // 严重：直接拦截
+)
+
+// 内容审核动作
+const (
+	ModerationActionAllow  = "allow"  // 放行
+	ModerationActionReview = "review" // 待人工审核
+	ModerationActionBlock  = "block"  // 拦截
+)
+
+// 审核记录状态
+const (
+	ModerationStatusPending    = "pending"
+	ModerationStatusApproved   = "approved"
+	ModerationStatusRejected   = "rejected"
+	ModerationStatusSuperseded = "superseded" // 待审核期间内容被再次修改，由新的审核记录代替
+)
+
+// 被审核内容类型
+const (
+	ModerationTargetComment = "comment"
+	ModerationTargetNews    = "news"
+)
+
+// SensitiveWord 敏感词，由管理员维护
+type SensitiveWord struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Word      string    `json:"word" gorm:"type:varchar(100);not null;uniqueIndex"`         // 敏感词
+	Pinyin    string    `json:"pinyin" gorm:"type:varchar(200)"`                            // 拼音写法，用于识别拼音变体
+	Category  string    `json:"category" gorm:"type:varchar(50);index"`                     // 分类：政治、色情、辱骂、广告等
+	Severity  string    `json:"severity" gorm:"type:varchar(20);not null;default:'medium'"` // 严重程度
+	IsActive  bool      `json:"is_active" gorm:"default:true"`                              // 是否启用
+	CreatedBy uint      `json:"created_by"`                                                 // 添加者
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ModerationRecord 审核队列记录
+type ModerationRecord struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	TargetType   string     `json:"target_type" gorm:"type:varchar(20);not null;index:idx_moderation_target"` // 内容类型
+	TargetID     uint       `json:"target_id" gorm:"not null;index:idx_moderation_target"`                    // 内容ID
+	AuthorID     uint       `json:"author_id" gorm:"index"`                                                   // 内容作者
+	Content      string     `json:"content" gorm:"type:text"`                                                 // 提交时的内容快照
+	MatchedWords string     `json:"matched_words" gorm:"type:text"`                                           // 命中的敏感词（JSON字符串）
+	Severity     string     `json:"severity" gorm:"type:varchar(20)"`                                         // 命中的最高严重程度
+	Action       string     `json:"action" gorm:"type:varchar(20)"`                                           // 自动审核动作
+	Status       string     `json:"status" gorm:"type:varchar(20);default:'pending';index"`                   // 审核状态
+	Reason       string     `json:"reason" gorm:"type:varchar(500)"`                                          // 审核理由
+	ReviewedBy   *uint      `json:"reviewed_by"`                                                              // 审核人
+	ReviewedAt   *time.Time `json:"reviewed_at"`                                                              // 审核时间
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ModerationResult 文本审核结果
+type ModerationResult struct {
+	Action       string   `json:"action"`
+	Severity     string   `json:"severity,omitempty"`
+	MatchedWords []string `json:"matched_words"`
+}
+
+// CreateSensitiveWordRequest 添加敏感词请求
+type CreateSensitiveWordRequest struct {
+	Word     string `json:"word" binding:"required,min=1,max=100"`
+	Pinyin   string `json:"pinyin" binding:"omitempty,max=200"`
+	Category string `json:"category" binding:"omitempty,max=50"`
+	Severity string `json:"severity" binding:"required,oneof=low medium high"`
+}
+
+// UpdateSensitiveWordRequest 更新敏感词请求
+type UpdateSensitiveWordRequest struct {
+	Pinyin   *string `json:"pinyin" binding:"omitempty,max=200"`
+	Category string  `json:"category" binding:"omitempty,max=50"`
+	Severity string  `json:"severity" binding:"omitempty,oneof=low medium high"`
+	IsActive *bool   `json:"is_active"`
+}
+
+// SensitiveWordQueryRequest 敏感词查询请求
+type SensitiveWordQueryRequest struct {
+	Category string `form:"category"`
+	Severity string `form:"severity"`
+	Search   string `form:"search"`
+	Page     int    `form:"page,default=1"`
+	Limit    int    `form:"limit,default=20"`
+}
+
+// ModerationQueueQueryRequest 审核队列查询请求
+type ModerationQueueQueryRequest struct {
+	Status     string `form:"status"`
+	TargetType string `form:"target_type"`
+	Page       int    `form:"page,default=1"`
+	Limit      int    `form:"limit,default=20"`
+}
+
+// ModerationReviewRequest 审核操作请求
+type ModerationReviewRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// ModerationCheckRequest 文本检测请求
+type ModerationCheckRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+func (SensitiveWord) TableName() string {
+	return "sensitive_words"
+}
+
+func (ModerationRecord) TableName() string {
+	return "moderation_records"
+}
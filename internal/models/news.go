@@ -30,6 +30,11 @@ type News struct {
 	// 事件关联字段
 	BelongedEventID *uint `json:"belonged_event_id" gorm:"index"` // 关联的事件ID
 
+	// 分类预测字段
+	SourceCategory     string  `json:"source_category" gorm:"type:varchar(100)"`    // 来源给出的分类，RSS 新闻为条目分类或源的分类
+	PredictedCategory  string  `json:"predicted_category" gorm:"type:varchar(100)"` // 分类模型预测的分类
+	CategoryConfidence float64 `json:"category_confidence" gorm:"default:0"`        // 预测分类的置信度
+
 	// RSS相关字段
 	SourceType  NewsType `json:"source_type" gorm:"type:varchar(20);default:'manual';index"` // 新闻类型
 	RSSSourceID *uint    `json:"rss_source_id" gorm:"index"`                                 // RSS源ID
@@ -51,6 +56,9 @@ type News struct {
 	Status      string `json:"status" gorm:"type:varchar(20);default:'published';index"` // 状态
 	IsProcessed bool   `json:"is_processed" gorm:"default:false"`                        // 是否已处理
 
+	// 搜索字段，分词结果只通过 SQL 写入，不随模型读写
+	SearchVector string `json:"-" gorm:"type:tsvector;index:idx_news_search_vector,type:gin;->:false;<-:false"`
+
 	// GORM 自动维护的时间戳
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -95,6 +103,11 @@ type NewsResponse struct {
 	CreatedAt       time.Time          `json:"created_at"`
 	UpdatedAt       time.Time          `json:"updated_at"`
 	RSSSource       *RSSSourceResponse `json:"rss_source,omitempty"`
+
+	// 分类预测
+	SourceCategory     string  `json:"source_category"`
+	PredictedCategory  string  `json:"predicted_category"`
+	CategoryConfidence float64 `json:"category_confidence"`
 }
 
 // NewsCreateRequest 用于创建新闻时的请求体
@@ -151,6 +164,10 @@ func (n *News) ToResponse() NewsResponse {
 		IsProcessed:     n.IsProcessed,
 		CreatedAt:       n.CreatedAt,
 		UpdatedAt:       n.UpdatedAt,
+
+		SourceCategory:     n.SourceCategory,
+		PredictedCategory:  n.PredictedCategory,
+		CategoryConfidence: n.CategoryConfidence,
 	}
 
 	// 如果有RSS源关联，添加RSS源信息
@@ -169,6 +186,7 @@ func (n *News) ToResponse() NewsResponse {
 			Tags:        n.RSSSource.Tags,
 			Priority:    n.RSSSource.Priority,
 			UpdateFreq:  n.RSSSource.UpdateFreq,
+			SkipTagging: n.RSSSource.SkipTagging,
 			CreatedAt:   n.RSSSource.CreatedAt,
 			UpdatedAt:   n.RSSSource.UpdatedAt,
 		}
@@ -21,6 +21,7 @@ type RSSSource struct {
 	Tags        string    `json:"tags" gorm:"type:text"`                                     // 标签（JSON字符串）
 	Priority    int       `json:"priority" gorm:"default:1"`                                 // 优先级（1-10）
 	UpdateFreq  int       `json:"update_freq" gorm:"default:60"`                             // 更新频率（分钟）
+	SkipTagging bool      `json:"skip_tagging" gorm:"default:false"`                         // 不自动提取关键词标签
 }
 
 // NewsItem 新闻条目
@@ -68,6 +69,7 @@ type RSSSourceResponse struct {
 	Tags        string    `json:"tags"`
 	Priority    int       `json:"priority"`
 	UpdateFreq  int       `json:"update_freq"`
+	SkipTagging bool      `json:"skip_tagging"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -96,6 +98,7 @@ type NewsItemResponse struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	RSSSource    *RSSSourceResponse `json:"rss_source,omitempty"`
+	Highlight    *SearchHighlight   `json:"highlight,omitempty"` // 按关键词搜索时的高亮片段
 }
 
 // 创建RSS源请求
@@ -108,6 +111,7 @@ type CreateRSSSourceRequest struct {
 	Tags        []string `json:"tags"`
 	Priority    int      `json:"priority" binding:"omitempty,min=1,max=10"`
 	UpdateFreq  int      `json:"update_freq" binding:"omitempty,min=5,max=1440"`
+	SkipTagging bool     `json:"skip_tagging"` // 不自动提取关键词标签
 }
 
 // 更新RSS源请求
@@ -121,6 +125,7 @@ type UpdateRSSSourceRequest struct {
 	Tags        []string `json:"tags"`
 	Priority    int      `json:"priority" binding:"omitempty,min=1,max=10"`
 	UpdateFreq  int      `json:"update_freq" binding:"omitempty,min=5,max=1440"`
+	SkipTagging *bool    `json:"skip_tagging"`
 }
 
 // 新闻查询请求
@@ -134,12 +139,15 @@ type NewsQueryRequest struct {
 	Limit       int    `form:"limit,default=10"`
 	StartDate   string `form:"start_date"` // YYYY-MM-DD
 	EndDate     string `form:"end_date"`   // YYYY-MM-DD
+	EntityID    uint   `form:"entity_id"`  // 只返回提到该实体的新闻
+	Facets      bool   `form:"facets"`     // 是否返回实体分面统计
 }
 
 // 新闻列表响应
 type NewsListResponse struct {
-	Total int64              `json:"total"`
-	News  []NewsItemResponse `json:"news"`
+	Total  int64              `json:"total"`
+	News   []NewsItemResponse `json:"news"`
+	Facets EntityFacets       `json:"facets,omitempty"` // facets=true 时返回筛选结果中出现最多的人物、机构和地点
 }
 
 // RSS抓取统计
@@ -152,6 +160,7 @@ type RSSFetchStats struct {
 	ErrorItems   int       `json:"error_items"`
 	FetchTime    time.Time `json:"fetch_time"`
 	Duration     string    `json:"duration"`
+	Error        string    `json:"error,omitempty"` // 抓取失败的原因
 }
 
 // RSS抓取结果
@@ -0,0 +1,138 @@
+package models
+
+import "time"
+
+// 统一搜索的结果类型
+const (
+	SearchTypeNews  = "news"
+	SearchTypeEvent = "event"
+)
+
+// 统一搜索的日期分段，按距今时间累计：day 包含在 week 中，以此类推
+const (
+	SearchDateDay   = "day"   // 最近 24 小时
+	SearchDateWeek  = "week"  // 最近 7 天
+	SearchDateMonth = "month" // 最近 30 天
+	SearchDateYear  = "year"  // 最近一年
+)
+
+// SearchDateBuckets 按时间范围从小到大排列的日期分段
+var SearchDateBuckets = []string{SearchDateDay, SearchDateWeek, SearchDateMonth, SearchDateYear}
+
+// SearchHighlight 搜索结果的高亮，命中的词用 <em> 包裹，其余文本已做 HTML 转义
+type SearchHighlight struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"` // 正文中命中位置附近的摘要，没有命中时为正文开头
+}
+
+// NewsSearchResult 新闻搜索结果
+type NewsSearchResult struct {
+	NewsResponse
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+}
+
+// SearchRequest 统一搜索请求
+// q 支持高级语法："短语"、-排除词、source:来源、category:分类、type:news|event、status:事件状态、
+// date:2024-01-01..2024-03-31（任一端可省略）或 date:week；参数中的筛选条件与语法中的条件同时生效
+type SearchRequest struct {
+	Query     string `form:"q"`
+	Type      string `form:"type"`       // news 或 event，为空时同时搜索
+	Category  string `form:"category"`   // 分类的 slug、名称或别名，包含子分类
+	Source    string `form:"source"`     // 新闻或事件来源
+	Status    string `form:"status"`     // 事件状态，指定后只搜索事件
+	Date      string `form:"date"`       // 日期分段：day、week、month、year
+	StartDate string `form:"start_date"` // YYYY-MM-DD
+	EndDate   string `form:"end_date"`   // YYYY-MM-DD
+	SortBy    string `form:"sort_by"`    // relevance（默认）、time、hotness
+	Page      int    `form:"page,default=1"`
+	Limit     int    `form:"limit,default=20"`
+}
+
+// SearchResult 统一搜索的一条结果，按 type 返回新闻或事件
+type SearchResult struct {
+	Type      string           `json:"type"` // news 或 event
+	ID        uint             `json:"id"`
+	Score     float64          `json:"score"` // 综合相关度、时间和热度的得分，只有筛选条件时为 0
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+	News      *NewsResponse    `json:"news,omitempty"`
+	Event     *EventResponse   `json:"event,omitempty"`
+}
+
+// SearchFacet 分面中的一项
+type SearchFacet struct {
+	Value string `json:"value"` // 作为筛选参数使用的值
+	Label string `json:"label"` // 显示名称
+	Count int64  `json:"count"`
+}
+
+// SearchFacets 统一搜索的分面统计，每个分面忽略自身的筛选条件，便于切换选项
+type SearchFacets struct {
+	Type     []SearchFacet `json:"type"`
+	Category []SearchFacet `json:"category"`
+	Source   []SearchFacet `json:"source"`
+	Date     []SearchFacet `json:"date"`   // 累计的日期分段
+	Status   []SearchFacet `json:"status"` // 事件状态
+}
+
+// SearchResponse 统一搜索响应
+type SearchResponse struct {
+	Total   int64          `json:"total"`
+	Results []SearchResult `json:"results"`
+	Facets  SearchFacets   `json:"facets"`
+	Page    int            `json:"page"`
+	Limit   int            `json:"limit"`
+}
+
+// 记录搜索语句的接口
+const (
+	SearchEndpointUnified = "search"      // /api/v1/search
+	SearchEndpointNews    = "news_search" // /api/v1/news/search
+	SearchEndpointRSS     = "rss_news"    // /api/v1/rss/news?search=
+	SearchEndpointEvents  = "events"      // /api/v1/events?search=
+)
+
+// SearchQueryLog 搜索记录，只记录第一页的请求，用于统计热门搜索词作为补全建议
+type SearchQueryLog struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Query       string    `json:"query" gorm:"type:varchar(200);not null;index"` // 规范化的搜索语句：小写半角，连续空白合并为一个空格
+	Endpoint    string    `json:"endpoint" gorm:"type:varchar(20);not null"`
+	ResultCount int64     `json:"result_count"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
+
+// 搜索建议的来源
+const (
+	SuggestionTypeEvent  = "event"
+	SuggestionTypeTag    = "tag"
+	SuggestionTypeEntity = "entity"
+	SuggestionTypeQuery  = "query" // 热门搜索词
+)
+
+// SuggestRequest 搜索建议请求
+type SuggestRequest struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit,default=10"`
+}
+
+// SearchSuggestion 一条搜索建议
+type SearchSuggestion struct {
+	Text       string `json:"text"`
+	Type       string `json:"type"`                  // event、tag、entity 或 query
+	ID         uint   `json:"id,omitempty"`          // 事件、标签或实体的ID
+	Slug       string `json:"slug,omitempty"`        // 标签的 slug
+	EntityType string `json:"entity_type,omitempty"` // 实体类型
+}
+
+// SuggestResponse 搜索建议响应
+type SuggestResponse struct {
+	Query       string             `json:"query"`
+	Suggestions []SearchSuggestion `json:"suggestions"`
+	DidYouMean  string             `json:"did_you_mean,omitempty"` // 按语料中的词纠正拼写后的搜索语句，没有需要纠正的词时为空
+}
+
+// RebuildSearchIndexResult 重建搜索索引的结果
+type RebuildSearchIndexResult struct {
+	ProcessedNews   int    `json:"processed_news"`
+	ProcessedEvents int    `json:"processed_events"`
+	Duration        string `json:"duration"`
+}
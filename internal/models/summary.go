@@ -0,0 +1,31 @@
+package models
+
+// 摘要重新生成的对象
+const (
+	SummaryTargetNews   = "news"   // 新闻摘要
+	SummaryTargetEvents = "events" // 事件描述
+	SummaryTargetAll    = "all"
+)
+
+// RegenerateSummariesRequest 重新生成摘要请求
+type RegenerateSummariesRequest struct {
+	Target    string `json:"target" binding:"omitempty,oneof=news events all"` // 默认 all
+	IDs       []uint `json:"ids"`                                              // 只处理指定ID的新闻或事件，target 为 all 时忽略
+	OnlyEmpty bool   `json:"only_empty"`                                       // 只填充为空的摘要/描述
+	Titles    bool   `json:"titles"`                                           // 配置了外部模型时同时重新生成事件标题
+}
+
+// RegenerateSummariesResult 重新生成摘要结果
+type RegenerateSummariesResult struct {
+	NewsUpdated   int    `json:"news_updated"`
+	NewsSkipped   int    `json:"news_skipped"` // 正文过短无法生成摘要
+	EventsUpdated int    `json:"events_updated"`
+	EventsSkipped int    `json:"events_skipped"` // 没有关联新闻或无法生成描述
+	Duration      string `json:"duration"`
+}
+
+// PendingNewsResult 后台处理未处理新闻的结果
+type PendingNewsResult struct {
+	Processed int `json:"processed"` // 已生成摘要并标记为已处理
+	Deferred  int `json:"deferred"`  // 外部模型暂时不可用，留待下一轮
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+)
+
+// 标签名称的来源，名称和 slug 也作为识别名称
+const (
+	TagAliasName    = "name"    // 标签的规范名称
+	TagAliasSlug    = "slug"    // URL 标识，修改后原来的 slug 保留，旧的标签页地址仍然有效
+	TagAliasSynonym = "synonym" // 同义词，包括被合并的标签和改名前的名称
+)
+
+// Tag 规范化的新闻和事件标签
+// 新闻和事件仍以 JSON 字符串保存标签的规范名称，同时通过关联表关联到标签；计数随关联的增删增量维护
+type Tag struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Slug       string     `json:"slug" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	NewsCount  int64      `json:"news_count" gorm:"not null;default:0;index"`  // 关联的未删除新闻数
+	EventCount int64      `json:"event_count" gorm:"not null;default:0;index"` // 关联的未删除事件数
+	Aliases    []TagAlias `json:"-" gorm:"foreignKey:TagID"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TagAlias 识别标签使用的名称，新闻和事件的标签按规范化形式对应到标签
+type TagAlias struct {
+	ID    uint   `json:"id" gorm:"primaryKey"`
+	TagID uint   `json:"tag_id" gorm:"not null;index"`
+	Key   string `json:"-" gorm:"type:varchar(100);not null;uniqueIndex"` // 小写、去掉首尾空白的名称
+	Alias string `json:"alias" gorm:"type:varchar(100);not null"`
+	Kind  string `json:"kind" gorm:"type:varchar(10);not null"`
+}
+
+// NewsTag 新闻的标签
+type NewsTag struct {
+	NewsID   uint `json:"news_id" gorm:"primaryKey"`
+	TagID    uint `json:"tag_id" gorm:"primaryKey;index"`
+	Position int  `json:"position"` // 标签在新闻标签中的顺序
+}
+
+// EventTag 事件的标签
+type EventTag struct {
+	EventID  uint `json:"event_id" gorm:"primaryKey"`
+	TagID    uint `json:"tag_id" gorm:"primaryKey;index"`
+	Position int  `json:"position"` // 标签在事件标签中的顺序
+}
+
+// TagInfo 标签信息
+type TagInfo struct {
+	ID         uint      `json:"id"`
+	Slug       string    `json:"slug"`
+	Name       string    `json:"name"`
+	Synonyms   []string  `json:"synonyms"`
+	NewsCount  int64     `json:"news_count"`
+	EventCount int64     `json:"event_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TagDetailResponse 标签页，包含带有该标签的事件和新闻
+type TagDetailResponse struct {
+	Tag    TagInfo         `json:"tag"`
+	Events []EventResponse `json:"events"` // 按热度排序
+	News   []NewsResponse  `json:"news"`   // 按发布时间倒序
+	Page   int             `json:"page"`
+	Limit  int             `json:"limit"`
+}
+
+// TagDetailQueryRequest 标签页查询请求，分页同时作用于事件和新闻
+type TagDetailQueryRequest struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=20"`
+}
+
+// TagQueryRequest 标签列表查询请求
+type TagQueryRequest struct {
+	Query string `form:"q"` // 按名称、slug 或同义词搜索
+	Page  int    `form:"page,default=1"`
+	Limit int    `form:"limit,default=20"`
+}
+
+// UpdateTagRequest 更新标签请求，Synonyms 传入时整体替换
+type UpdateTagRequest struct {
+	Name     string   `json:"name" binding:"omitempty,min=1,max=100"`
+	Slug     string   `json:"slug" binding:"omitempty,max=100"`
+	Synonyms []string `json:"synonyms" binding:"omitempty,dive,min=1,max=100"`
+}
+
+// MergeTagsRequest 合并标签请求
+type MergeTagsRequest struct {
+	SourceIDs []uint `json:"source_ids" binding:"required,min=1"` // 并入目标标签的标签
+}
+
+// MigrateTagsResult 从标签字符串迁移标签的结果
+type MigrateTagsResult struct {
+	CreatedTags  int    `json:"created_tags"`
+	LinkedNews   int64  `json:"linked_news"`
+	LinkedEvents int64  `json:"linked_events"`
+	Duration     string `json:"duration"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+func (TagAlias) TableName() string {
+	return "tag_aliases"
+}
+
+func (NewsTag) TableName() string {
+	return "news_tags"
+}
+
+func (EventTag) TableName() string {
+	return "event_tags"
+}
@@ -0,0 +1,16 @@
+package models
+
+// NewsTagQueryRequest 热门新闻标签查询请求
+type NewsTagQueryRequest struct {
+	Limit    int    `form:"limit,default=50"`
+	MinCount int    `form:"min_count,default=1"`
+	Days     int    `form:"days"`     // 只统计最近几天发布的新闻，0 表示不限
+	Category string `form:"category"` // 只统计该分类的新闻
+}
+
+// RetagNewsResult 重新提取新闻关键词标签的结果
+type RetagNewsResult struct {
+	ProcessedNews int    `json:"processed_news"`
+	UpdatedNews   int    `json:"updated_news"` // 标签发生变化的新闻数
+	Duration      string `json:"duration"`
+}
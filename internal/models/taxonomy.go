@@ -0,0 +1,151 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 关键词组类型
+const (
+	KeywordGroupRegion = "region" // 地域组：提到不同地域的新闻不会聚为同一事件
+	KeywordGroupTopic  = "topic"  // 主题组：补充分词词典
+)
+
+// 词库变更对象类型
+const (
+	TaxonomyEntityGroup    = "keyword_group"
+	TaxonomyEntityStopWord = "stop_word"
+)
+
+// 词库变更动作
+const (
+	TaxonomyActionCreate = "create"
+	TaxonomyActionUpdate = "update"
+	TaxonomyActionDelete = "delete"
+)
+
+// KeywordGroup 事件聚类使用的关键词组，由管理员维护
+type KeywordGroup struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Kind        string    `json:"kind" gorm:"type:varchar(20);not null;uniqueIndex:idx_keyword_group_kind_name"` // 类型：region、topic
+	Name        string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_keyword_group_kind_name"` // 组名
+	Keywords    string    `json:"keywords" gorm:"type:text"`                                                     // 关键词（JSON字符串）
+	Description string    `json:"description" gorm:"type:varchar(255)"`
+	IsActive    bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// StopWord 分词停用词
+type StopWord struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Word      string    `json:"word" gorm:"type:varchar(50);not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaxonomyChange 词库变更记录，每次变更产生一个递增的版本号
+type TaxonomyChange struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Version    uint      `json:"version" gorm:"not null;uniqueIndex"`
+	EntityType string    `json:"entity_type" gorm:"type:varchar(20);not null"`
+	EntityID   uint      `json:"entity_id"`
+	Action     string    `json:"action" gorm:"type:varchar(20);not null"`
+	Before     string    `json:"before" gorm:"type:text"` // 变更前快照（JSON字符串）
+	After      string    `json:"after" gorm:"type:text"`  // 变更后快照（JSON字符串）
+	ChangedBy  uint      `json:"changed_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// KeywordGroupResponse 关键词组响应
+type KeywordGroupResponse struct {
+	ID          uint      `json:"id"`
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	Keywords    []string  `json:"keywords"`
+	Description string    `json:"description"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TaxonomySnapshot 当前生效的词库
+type TaxonomySnapshot struct {
+	Version        uint                `json:"version"`
+	RegionalGroups map[string][]string `json:"regional_groups"`
+	TopicGroups    map[string][]string `json:"topic_groups"`
+	StopWords      []string            `json:"stop_words"`
+}
+
+// CreateKeywordGroupRequest 创建关键词组请求
+type CreateKeywordGroupRequest struct {
+	Kind        string   `json:"kind" binding:"required,oneof=region topic"`
+	Name        string   `json:"name" binding:"required,min=1,max=50"`
+	Keywords    []string `json:"keywords" binding:"required,min=1,dive,min=1,max=50"`
+	Description string   `json:"description" binding:"omitempty,max=255"`
+}
+
+// UpdateKeywordGroupRequest 更新关键词组请求
+type UpdateKeywordGroupRequest struct {
+	Name        string   `json:"name" binding:"omitempty,min=1,max=50"`
+	Keywords    []string `json:"keywords" binding:"omitempty,min=1,dive,min=1,max=50"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// KeywordGroupQueryRequest 关键词组查询请求
+type KeywordGroupQueryRequest struct {
+	Kind   string `form:"kind"`
+	Search string `form:"search"`
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+}
+
+// CreateStopWordsRequest 批量添加停用词请求
+type CreateStopWordsRequest struct {
+	Words []string `json:"words" binding:"required,min=1,dive,min=1,max=50"`
+}
+
+// StopWordQueryRequest 停用词查询请求
+type StopWordQueryRequest struct {
+	Search string `form:"search"`
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=50"`
+}
+
+// TaxonomyChangeQueryRequest 词库变更记录查询请求
+type TaxonomyChangeQueryRequest struct {
+	EntityType string `form:"entity_type"`
+	Page       int    `form:"page,default=1"`
+	Limit      int    `form:"limit,default=20"`
+}
+
+// ToResponse 转换为响应格式
+func (g *KeywordGroup) ToResponse() KeywordGroupResponse {
+	keywords := []string{}
+	if g.Keywords != "" {
+		json.Unmarshal([]byte(g.Keywords), &keywords)
+	}
+
+	return KeywordGroupResponse{
+		ID:          g.ID,
+		Kind:        g.Kind,
+		Name:        g.Name,
+		Keywords:    keywords,
+		Description: g.Description,
+		IsActive:    g.IsActive,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+}
+
+func (KeywordGroup) TableName() string {
+	return "keyword_groups"
+}
+
+func (StopWord) TableName() string {
+	return "stop_words"
+}
+
+func (TaxonomyChange) TableName() string {
+	return "taxonomy_changes"
+}
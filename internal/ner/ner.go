@@ -0,0 +1,666 @@
+// Package ner 离线的人物、机构和地点识别：内置词典和地名库匹配，加上中英文的规则识别
+package ner
+
+import (
+	"bufio"
+	_ "embed"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/geo"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+)
+
+// 实体类型
+const (
+	TypePerson       = "person"
+	TypeOrganization = "organization"
+	TypePlace        = "place"
+)
+
+// 实体来源
+const (
+	OriginDictionary = "dictionary" // 内置词典
+	OriginGazetteer  = "gazetteer"  // 内置地名库
+	OriginRule       = "rule"       // 规则识别，名称为原文写法
+)
+
+//go:embed entities.tsv
+var dictionaryData string
+
+// Entity 识别出的实体，同一实体在文本中的多次提及合并为一个
+type Entity struct {
+	Type      string
+	Name      string // 规范名称：词典和地名库中的中文名，规则识别的为原文写法
+	NameEn    string
+	Aliases   []string
+	PlaceCode string // 地点的地名库编码
+	Origin    string
+	Mentions  int  // 提及次数
+	InTitle   bool // 标题中是否提到
+	first     int  // 首次提及的顺序
+}
+
+// Key 实体名称的规范化形式，同一类型下相同 Key 的名称视为同一实体
+func Key(name string) string {
+	return nlp.NormalizeForMatch(name)
+}
+
+type dictEntry struct {
+	typ     string
+	name    string
+	nameEn  string
+	aliases []string
+}
+
+type dictPattern struct {
+	entry   *dictEntry
+	text    string // 词典中的原始写法
+	ascii   bool   // 英文名称需要在单词边界上命中
+	acronym bool   // 全大写缩写区分大小写
+}
+
+// wordList 一组词语的多模式匹配
+type wordList struct {
+	words   []string
+	matcher *nlp.Matcher
+}
+
+func newWordList(words []string) *wordList {
+	builder := nlp.NewMatcherBuilder()
+	for _, word := range words {
+		builder.AddPattern(word)
+	}
+	return &wordList{words: words, matcher: builder.Build()}
+}
+
+// startsAt 返回从位置 i 开始的最长词语长度，没有时返回 0
+func (w *wordList) startsAt(runes []rune, i int) int {
+	longest := 0
+	for _, word := range w.words {
+		n := utf8.RuneCountInString(word)
+		if n > longest && i+n <= len(runes) && string(runes[i:i+n]) == word {
+			longest = n
+		}
+	}
+	return longest
+}
+
+// Recognizer 实体识别器，构建后只读，可并发使用
+type Recognizer struct {
+	gazetteer *geo.Gazetteer
+	segmenter *nlp.Segmenter
+	entries   map[string]*dictEntry // 规范化的名称和别名 → 词条
+	matcher   *nlp.Matcher
+	patterns  []dictPattern
+}
+
+var (
+	defaultRecognizer *Recognizer
+	defaultOnce       sync.Once
+)
+
+// Default 返回使用内置词典和地名库的识别器
+func Default() *Recognizer {
+	defaultOnce.Do(func() {
+		defaultRecognizer = newRecognizer(dictionaryData, geo.Default())
+	})
+	return defaultRecognizer
+}
+
+func newRecognizer(data string, gazetteer *geo.Gazetteer) *Recognizer {
+	r := &Recognizer{
+		gazetteer: gazetteer,
+		segmenter: nlp.DefaultSegmenter(),
+		entries:   make(map[string]*dictEntry),
+	}
+	builder := nlp.NewMatcherBuilder()
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 || (fields[0] != TypePerson && fields[0] != TypeOrganization) {
+			log.Printf("[NER WARNING] skipping malformed dictionary line: %s", line)
+			continue
+		}
+
+		entry := &dictEntry{typ: fields[0], name: fields[1], nameEn: fields[2]}
+		if fields[3] != "-" {
+			entry.aliases = strings.Split(fields[3], ",")
+		}
+
+		names := append([]string{entry.name, entry.nameEn}, entry.aliases...)
+		for _, name := range names {
+			key := Key(name)
+			if key == "" {
+				continue
+			}
+			if _, exists := r.entries[key]; !exists {
+				r.entries[key] = entry
+			}
+
+			pattern := strings.ToLower(nlp.ToHalfWidth(name))
+			if utf8.RuneCountInString(pattern) < 2 {
+				continue
+			}
+			if builder.AddPattern(pattern) >= 0 {
+				r.patterns = append(r.patterns, dictPattern{
+					entry:   entry,
+					text:    name,
+					ascii:   isASCII(name),
+					acronym: isASCII(name) && strings.ToUpper(name) == name,
+				})
+			}
+		}
+	}
+
+	r.matcher = builder.Build()
+	return r
+}
+
+// mention 文本中的一次实体提及
+type mention struct {
+	start, end int
+	entity     Entity
+}
+
+// Recognize 识别标题和正文中的实体，按标题中出现、提及次数、首次出现的顺序排列
+func (r *Recognizer) Recognize(title, body string) []Entity {
+	found := make(map[string]*Entity)
+	add := func(text string, inTitle bool) {
+		for _, m := range r.scan(text) {
+			key := m.entity.Type + ":" + Key(m.entity.Name)
+			if e, ok := found[key]; ok {
+				e.Mentions++
+				e.InTitle = e.InTitle || inTitle
+				continue
+			}
+			e := m.entity
+			e.Mentions = 1
+			e.InTitle = inTitle
+			e.first = len(found)
+			found[key] = &e
+		}
+	}
+	add(title, true)
+	add(body, false)
+
+	entities := make([]Entity, 0, len(found))
+	for _, e := range found {
+		entities = append(entities, *e)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		a, b := entities[i], entities[j]
+		if a.InTitle != b.InTitle {
+			return a.InTitle
+		}
+		if a.Mentions != b.Mentions {
+			return a.Mentions > b.Mentions
+		}
+		return a.first < b.first
+	})
+	return entities
+}
+
+// scan 按出现顺序返回一段文本中的实体提及
+// 依次使用词典、中文机构规则、英文规则、中文人名规则和地名库，重叠时先识别的优先
+func (r *Recognizer) scan(text string) []mention {
+	runes := []rune(nlp.ToHalfWidth(text))
+	if len(runes) == 0 {
+		return nil
+	}
+	covered := make([]bool, len(runes))
+	var mentions []mention
+	claim := func(start, end int, entity Entity) {
+		if start < 0 || end > len(runes) || start >= end {
+			return
+		}
+		for i := start; i < end; i++ {
+			if covered[i] {
+				return
+			}
+		}
+		for i := start; i < end; i++ {
+			covered[i] = true
+		}
+		mentions = append(mentions, mention{start: start, end: end, entity: entity})
+	}
+
+	for _, m := range r.dictionaryMatches(runes) {
+		claim(m.start, m.end, m.entity)
+	}
+
+	// 地名在机构和人名规则中不能被切开
+	places := r.gazetteer.Mentions(string(runes))
+	protected := make([]bool, len(runes))
+	for _, p := range places {
+		for i := p.Start; i < p.End && i < len(runes); i++ {
+			protected[i] = true
+		}
+	}
+
+	for _, m := range r.chineseOrganizations(runes, protected) {
+		claim(m.start, m.end, m.entity)
+	}
+	for _, m := range r.englishEntities(runes) {
+		claim(m.start, m.end, m.entity)
+	}
+	for _, m := range r.chinesePersons(runes, protected) {
+		claim(m.start, m.end, m.entity)
+	}
+	for _, p := range places {
+		claim(p.Start, p.End, Entity{
+			Type:      TypePlace,
+			Name:      p.Place.Name,
+			NameEn:    p.Place.NameEn,
+			Aliases:   p.Place.Aliases,
+			PlaceCode: p.Place.Code,
+			Origin:    OriginGazetteer,
+		})
+	}
+
+	sort.Slice(mentions, func(i, j int) bool {
+		return mentions[i].start < mentions[j].start
+	})
+	return mentions
+}
+
+// dictionaryMatches 词典命中，重叠时保留最长的；英文名称需要在单词边界上且大小写符合
+func (r *Recognizer) dictionaryMatches(runes []rune) []mention {
+	lower := make([]rune, len(runes))
+	for i, c := range runes {
+		lower[i] = unicode.ToLower(c)
+	}
+	matches := r.matcher.FindAll(string(lower))
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+
+	var mentions []mention
+	covered := 0
+	for _, m := range matches {
+		if m.Start < covered {
+			continue
+		}
+		p := r.patterns[m.Pattern]
+		if p.ascii {
+			if !atWordBoundary(runes, m.Start, m.End) {
+				continue
+			}
+			original := string(runes[m.Start:m.End])
+			if p.acronym && original != p.text {
+				continue
+			}
+			if !p.acronym && []rune(original)[0] != []rune(p.text)[0] {
+				continue
+			}
+		}
+		covered = m.End
+		mentions = append(mentions, mention{start: m.Start, end: m.End, entity: dictionaryEntity(p.entry)})
+	}
+	return mentions
+}
+
+func dictionaryEntity(entry *dictEntry) Entity {
+	return Entity{
+		Type:    entry.typ,
+		Name:    entry.name,
+		NameEn:  entry.nameEn,
+		Aliases: entry.aliases,
+		Origin:  OriginDictionary,
+	}
+}
+
+// resolve 规则识别出的名称在词典中有收录时使用词典中的实体
+func (r *Recognizer) resolve(entityType, name string) Entity {
+	if entry, ok := r.entries[Key(name)]; ok {
+		return dictionaryEntity(entry)
+	}
+	return Entity{Type: entityType, Name: name, Origin: OriginRule}
+}
+
+// 中文机构名称的后缀，机构名称由后缀前不超过 maxOrgPrefix 个字组成
+var orgSuffixes = newWordList([]string{
+	"股份有限公司", "有限责任公司", "有限公司", "集团", "公司", "银行", "大学", "学院", "研究院", "研究所", "实验室",
+	"医院", "委员会", "协会", "学会", "基金会", "联合会", "交易所", "法院", "检察院", "公安局", "管理局", "俱乐部",
+})
+
+const maxOrgPrefix = 10
+
+// orgBoundaries 机构名称不会跨过的虚词和动词，名称从其后开始
+var orgBoundaries = newWordList([]string{
+	"的", "在", "与", "和", "及", "对", "向", "由", "被", "将", "把", "是", "了", "据", "从", "跟", "让", "给", "于",
+	"这", "那", "该", "某", "其", "各", "每", "等", "或", "也", "都", "已", "曾", "并", "即", "称", "说", "今", "昨",
+	"表示", "宣布", "收购", "起诉", "联合", "旗下", "包括", "成为", "加入", "来自", "位于", "发布", "投资", "援引",
+	"报道", "通过", "指出", "认为", "前往", "访问", "会见", "参加", "举行", "一家", "两家", "多家", "这家", "几家", "数家",
+})
+
+// orgProtected 包含边界字但不应被切开的词
+var orgProtected = newWordList([]string{"国家", "共和国", "人民", "对外", "和平", "中央"})
+
+// genericOrgPrefixes 泛指而非具体机构的前缀，如“科技公司”“上市公司”
+var genericOrgPrefixes = map[string]bool{
+	"科技": true, "上市": true, "互联网": true, "保险": true, "外资": true, "国有": true, "民营": true, "相关": true,
+	"有关": true, "涉事": true, "投资": true, "航空": true, "汽车": true, "商业": true, "地方": true, "国内": true,
+	"国外": true, "海外": true, "全球": true, "当地": true, "大型": true, "中小": true, "知名": true, "头部": true,
+	"上述": true, "此前": true, "其他": true, "其它": true, "部分": true, "所有": true, "主要": true, "各大": true,
+	"医疗": true, "金融": true, "能源": true, "科研": true, "研究": true, "咨询": true, "物流": true, "电商": true,
+	"游戏": true, "初创": true, "创业": true, "跨国": true, "合资": true, "独资": true, "本地": true, "母": true,
+	"子": true, "分": true, "新": true, "老": true, "人民": true, "中央": true, "商业银行": true, "地方法": true,
+}
+
+// orgSuffixTail 紧跟在后缀之后说明不是机构名称的字，如“银行业”“大学生”
+const orgSuffixTail = "业界类股生法卡费派"
+
+// chineseOrganizations 按“名称 + 机构后缀”识别中文机构
+func (r *Recognizer) chineseOrganizations(runes []rune, protected []bool) []mention {
+	var mentions []mention
+	for _, m := range orgSuffixes.matcher.FindAll(string(runes)) {
+		start, end := m.Start, m.End
+		// 后缀后面紧跟另一个后缀（如“集团公司”）或说明性的字时由后面的命中处理或跳过
+		if end < len(runes) && (orgSuffixes.startsAt(runes, end) > 0 || strings.ContainsRune(orgSuffixTail, runes[end])) {
+			continue
+		}
+
+		windowStart := start
+		for windowStart > 0 && start-windowStart < maxOrgPrefix && isOrgRune(runes[windowStart-1]) {
+			windowStart--
+		}
+		cut := r.lastBoundary(runes, windowStart, start, orgBoundaries, protected)
+		prefix := string(runes[cut:start])
+		if utf8.RuneCountInString(prefix) < 2 || genericOrgPrefixes[prefix] || unicode.IsDigit([]rune(prefix)[0]) {
+			continue
+		}
+		suffix := string(runes[start:end])
+		// “美国公司”“英国集团”这类只有国家或地区名的泛指
+		if _, isPlace := r.gazetteer.Lookup(prefix); isPlace && (suffix == "公司" || suffix == "集团" || suffix == "有限公司") {
+			continue
+		}
+
+		name := prefix + suffix
+		mentions = append(mentions, mention{start: cut, end: end, entity: r.resolve(TypeOrganization, name)})
+	}
+	return mentions
+}
+
+// lastBoundary 返回 [from, to) 中最后一个边界词之后的位置，边界词与受保护的地名或词语重叠时忽略
+func (r *Recognizer) lastBoundary(runes []rune, from, to int, boundaries *wordList, protected []bool) int {
+	window := runes[from:to]
+	shielded := make([]bool, len(window))
+	for i := range window {
+		shielded[i] = protected[from+i]
+	}
+	for _, m := range orgProtected.matcher.FindAll(string(window)) {
+		for i := m.Start; i < m.End; i++ {
+			shielded[i] = true
+		}
+	}
+
+	cut := 0
+	for _, m := range boundaries.matcher.FindAll(string(window)) {
+		overlaps := false
+		for i := m.Start; i < m.End; i++ {
+			if shielded[i] {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps && m.End > cut {
+			cut = m.End
+		}
+	}
+	return from + cut
+}
+
+func isOrgRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+}
+
+// 中文人名规则用到的词语
+var (
+	speechVerbs = newWordList([]string{
+		"表示", "说", "称", "指出", "强调", "认为", "透露", "介绍", "回应", "宣布", "坦言", "告诉", "呼吁", "提到",
+		"解释", "证实", "否认", "承认", "警告", "声称", "宣称",
+	})
+	personTitles = newWordList([]string{
+		"总统", "副总统", "总理", "副总理", "首相", "主席", "副主席", "部长", "外长", "防长", "国务卿", "发言人",
+		"董事长", "总裁", "首席执行官", "CEO", "创始人", "教授", "院士", "市长", "省长", "州长", "局长", "书记",
+		"主任", "总经理", "大使", "主教练", "教练", "队长", "秘书长", "总干事", "议长", "行长", "国王", "女王", "王子",
+	})
+)
+
+// personStopChars 人名之后常见的字，遇到时人名结束
+const personStopChars = "在的于和与今近日曾也则就还将对向又已均"
+
+// personBadChars 不会出现在人名第二、三个字的字，用来排除“公司表示”“方面称”等
+const personBadChars = "们者员方府院司队界业部局会的了是在都也不还则又这那些个"
+
+// commonNonNames 姓氏开头、词典中没有收录但不是人名的常见词
+var commonNonNames = map[string]bool{
+	"方面": true, "高层": true, "金融": true, "石油": true, "安全": true, "司法": true, "黄金": true, "周期": true,
+	"程序": true, "任何": true, "许多": true, "马上": true, "常委": true, "成员": true, "文件": true, "时间": true,
+	"应该": true, "高度": true, "林业": true, "白宫": true, "钱包": true, "江湖": true, "万元": true, "明确": true,
+	"温度": true, "康复": true, "齐心": true, "易于": true, "严重": true, "武装": true, "华人": true, "全体": true,
+}
+
+// 常见姓氏
+const singleSurnames = "王李张刘陈杨黄赵吴周徐孙马朱胡郭何高林罗郑梁谢宋唐许韩冯邓曹彭曾肖田董袁潘于蒋蔡余杜叶程苏魏吕丁任沈姚卢姜崔钟谭陆汪范金石廖贾夏韦付方白邹孟熊秦邱江尹薛闫段雷侯龙史陶黎贺顾毛郝龚邵万钱严覃武戴莫孔向汤常温康施文牛樊葛邢安齐易乔伍庞颜倪庄聂章鲁岳翟殷詹申欧耿关兰焦俞左柳甘祝包宁尚符舒阮柯纪梅童凌毕单季裴霍涂成苗谷盛曲翁冉骆蓝路游辛靳管柴蒙鲍华喻祁蒲房滕屈饶解牟艾尤阳时穆农司卓古吉缪简车项连芦麦褚娄窦戚岑景党宫费卜冷晏席卫米柏宗瞿桂全佟应臧闵苟邬边卞姬师和仇栾隋商刁沙荣巫寇桑郎甄丛仲虞敖巩明佘池查麻苑迟邝"
+
+var compoundSurnames = []string{"欧阳", "司马", "诸葛", "上官", "慕容", "令狐", "皇甫", "尉迟", "长孙", "宇文", "司徒", "公孙", "夏侯", "端木"}
+
+// ambiguousSurnames 也常作介词或副词的姓氏，三字人名以它们开头时优先按两字人名识别
+const ambiguousSurnames = "向于和曾时常应万方高"
+
+// chinesePersons 识别中文人名：“姓氏 + 名 + 表示/称”，“职务 + 人名 + 表示/在”，以及“名·姓”形式的外国人名
+func (r *Recognizer) chinesePersons(runes []rune, protected []bool) []mention {
+	var mentions []mention
+
+	// 姓氏开头、后面跟着“表示”等词的两到三字人名
+	for _, m := range speechVerbs.matcher.FindAll(string(runes)) {
+		if name, start, ok := r.surnameName(runes, m.Start, protected); ok {
+			mentions = append(mentions, mention{start: start, end: m.Start, entity: r.resolve(TypePerson, name)})
+		}
+	}
+
+	// 职务之后的人名
+	for _, m := range personTitles.matcher.FindAll(string(runes)) {
+		end := m.End
+		for end < len(runes) && end-m.End < 4 && unicode.Is(unicode.Han, runes[end]) && !protected[end] &&
+			speechVerbs.startsAt(runes, end) == 0 && !strings.ContainsRune(personStopChars, runes[end]) {
+			end++
+		}
+		// 必须明确结束，避免把“总统访问”之类的动作当成人名
+		if end == len(runes) || end-m.End < 2 {
+			continue
+		}
+		if unicode.Is(unicode.Han, runes[end]) && speechVerbs.startsAt(runes, end) == 0 &&
+			!strings.ContainsRune(personStopChars, runes[end]) {
+			continue
+		}
+		name := string(runes[m.End:end])
+		if r.segmenter.HasWord(name) || personTitles.startsAt(runes, m.End) > 0 {
+			continue
+		}
+		mentions = append(mentions, mention{start: m.End, end: end, entity: r.resolve(TypePerson, name)})
+	}
+
+	// “唐纳德·特朗普”形式的外国人名
+	for i, c := range runes {
+		if c != '·' && c != '•' && c != '‧' {
+			continue
+		}
+		start := i
+		for start > 0 && i-start < 5 && unicode.Is(unicode.Han, runes[start-1]) &&
+			!strings.ContainsRune(personStopChars, runes[start-1]) {
+			start--
+		}
+		start = r.lastBoundary(runes, start, i, personTitles, protected)
+		end := i + 1
+		for end < len(runes) && end-i <= 8 && (unicode.Is(unicode.Han, runes[end]) || runes[end] == c) &&
+			speechVerbs.startsAt(runes, end) == 0 && !strings.ContainsRune(personStopChars, runes[end]) {
+			end++
+		}
+		if start == i || end-i < 3 || runes[end-1] == c {
+			continue
+		}
+		// 地名开头时（如“美国唐纳德·特朗普”）去掉地名
+		for start < i && protected[start] {
+			start++
+		}
+		if start == i {
+			continue
+		}
+		name := strings.Map(func(r rune) rune {
+			if r == '•' || r == '‧' {
+				return '·'
+			}
+			return r
+		}, string(runes[start:end]))
+		mentions = append(mentions, mention{start: start, end: end, entity: r.resolve(TypePerson, name)})
+	}
+
+	return mentions
+}
+
+// surnameName 在位置 verb 之前找姓氏开头的两到三字人名
+func (r *Recognizer) surnameName(runes []rune, verb int, protected []bool) (string, int, bool) {
+	valid := func(start int) bool {
+		if start < 0 {
+			return false
+		}
+		for i := start; i < verb; i++ {
+			if !unicode.Is(unicode.Han, runes[i]) || protected[i] {
+				return false
+			}
+			if i > start && strings.ContainsRune(personBadChars, runes[i]) {
+				return false
+			}
+		}
+		name := string(runes[start:verb])
+		return !r.segmenter.HasWord(name) && !commonNonNames[name]
+	}
+	hasSurname := func(start int) bool {
+		for _, surname := range compoundSurnames {
+			if strings.HasPrefix(string(runes[start:verb]), surname) {
+				return true
+			}
+		}
+		return strings.ContainsRune(singleSurnames, runes[start])
+	}
+
+	// 三字人名，或复姓加单名
+	if start := verb - 3; valid(start) && hasSurname(start) && !strings.ContainsRune(ambiguousSurnames, runes[start]) {
+		return string(runes[start:verb]), start, true
+	}
+	if start := verb - 2; valid(start) && hasSurname(start) {
+		return string(runes[start:verb]), start, true
+	}
+	return "", 0, false
+}
+
+// 英文规则
+var (
+	englishOrgPattern   = regexp.MustCompile(`\b(?:[A-Z][\w&'.-]*\s+)(?:(?:of|and|for|the|de|[A-Z][\w&'.-]*)\s+){0,4}?(?:Inc\.?|Corp\.?|Corporation|Ltd\.?|LLC|Group|Bank|University|Institute|Association|Foundation|Agency|Council|Committee|Company|Holdings|Technologies|Airlines|Motors|Laboratories)\b`)
+	englishTitledPerson = regexp.MustCompile(`\b(?:President|Vice President|Prime Minister|Chancellor|Minister|Secretary|Senator|Governor|Mayor|CEO|Chairman|Chairwoman|Chief Executive|Dr\.|Mr\.|Mrs\.|Ms\.|Professor|Prof\.|Judge|General|Pope|King|Queen|Prince|Princess|Ambassador|Spokesperson|Spokesman|Spokeswoman)\s+([A-Z][a-z]+(?:[ -][A-Z][a-z]+){0,2})`)
+	englishSaidPerson   = regexp.MustCompile(`\b([A-Z][a-z]+(?:\s+[A-Z][a-z]+){1,2}),?\s+(?:said|says|told|added|noted|wrote|announced)\b`)
+)
+
+// englishNonNames 不会出现在英文人名中的大写词
+var englishNonNames = map[string]bool{
+	"The": true, "This": true, "That": true, "These": true, "Those": true, "A": true, "An": true, "It": true,
+	"He": true, "She": true, "They": true, "We": true, "I": true, "In": true, "On": true, "At": true, "But": true,
+	"And": true, "Officials": true, "Police": true, "According": true, "Monday": true, "Tuesday": true,
+	"Wednesday": true, "Thursday": true, "Friday": true, "Saturday": true, "Sunday": true, "January": true,
+	"February": true, "March": true, "April": true, "May": true, "June": true, "July": true, "August": true,
+	"September": true, "October": true, "November": true, "December": true, "Minister": true, "Ministry": true,
+	"President": true, "Government": true, "House": true, "Department": true, "Company": true, "News": true,
+}
+
+// englishEntities 识别英文机构（名称 + Inc./Group/University 等）和人名（职务 + 人名，或人名 + said）
+func (r *Recognizer) englishEntities(runes []rune) []mention {
+	text := string(runes)
+	var mentions []mention
+
+	for _, loc := range englishOrgPattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if strings.HasPrefix(text[start:end], "The ") {
+			start += len("The ")
+		}
+		// 只有后缀时（如 "The Bank said"）是泛指
+		if !strings.Contains(text[start:end], " ") {
+			continue
+		}
+		mentions = append(mentions, mention{
+			start:  runeIndex(text, start),
+			end:    runeIndex(text, end),
+			entity: r.resolve(TypeOrganization, text[start:end]),
+		})
+	}
+
+	for _, pattern := range []*regexp.Regexp{englishTitledPerson, englishSaidPerson} {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[2], loc[3]
+			name := text[start:end]
+			if !r.englishPersonName(name) {
+				continue
+			}
+			mentions = append(mentions, mention{
+				start:  runeIndex(text, start),
+				end:    runeIndex(text, end),
+				entity: r.resolve(TypePerson, name),
+			})
+		}
+	}
+	return mentions
+}
+
+func (r *Recognizer) englishPersonName(name string) bool {
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == ' ' || r == '-' }) {
+		if englishNonNames[word] {
+			return false
+		}
+	}
+	if entry, ok := r.entries[Key(name)]; ok && entry.typ != TypePerson {
+		return false
+	}
+	_, isPlace := r.gazetteer.Lookup(name)
+	return !isPlace
+}
+
+func runeIndex(text string, byteOffset int) int {
+	return utf8.RuneCountInString(text[:byteOffset])
+}
+
+func isASCII(text string) bool {
+	for _, r := range text {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+func atWordBoundary(runes []rune, start, end int) bool {
+	isWord := func(r rune) bool {
+		return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+	}
+	if start > 0 && isWord(runes[start-1]) {
+		return false
+	}
+	if end < len(runes) && isWord(runes[end]) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package nlp
+
+// Matcher 基于 Aho-Corasick 自动机的多模式匹配器
+// 构建后只读，可在多个 goroutine 中并发使用
+type Matcher struct {
+	nodes []acNode
+}
+
+type acNode struct {
+	next    map[rune]int
+	fail    int
+	outputs []int // 在此结束的模式编号
+}
+
+// Match 一次命中
+type Match struct {
+	Pattern int // 模式编号（AddPattern 的顺序）
+	Start   int // 在输入 rune 序列中的起始位置
+	End     int // 结束位置（不含）
+}
+
+// MatcherBuilder 用于逐个添加模式后构建 Matcher
+type MatcherBuilder struct {
+	nodes   []acNode
+	lengths []int
+}
+
+func NewMatcherBuilder() *MatcherBuilder {
+	return &MatcherBuilder{
+		nodes: []acNode{{next: make(map[rune]int)}},
+	}
+}
+
+// AddPattern 添加模式并返回其编号，空模式返回 -1
+func (b *MatcherBuilder) AddPattern(pattern string) int {
+	runes := []rune(pattern)
+	if len(runes) == 0 {
+		return -1
+	}
+
+	id := len(b.lengths)
+	b.lengths = append(b.lengths, len(runes))
+
+	cur := 0
+	for _, r := range runes {
+		nxt, ok := b.nodes[cur].next[r]
+		if !ok {
+			b.nodes = append(b.nodes, acNode{next: make(map[rune]int)})
+			nxt = len(b.nodes) - 1
+			b.nodes[cur].next[r] = nxt
+		}
+		cur = nxt
+	}
+	b.nodes[cur].outputs = append(b.nodes[cur].outputs, id)
+	return id
+}
+
+// Build 计算失败指针并返回匹配器
+func (b *MatcherBuilder) Build() *Matcher {
+	queue := make([]int, 0, len(b.nodes))
+	for _, child := range b.nodes[0].next {
+		b.nodes[child].fail = 0
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range b.nodes[cur].next {
+			f := b.nodes[cur].fail
+			for f != 0 {
+				if _, ok := b.nodes[f].next[r]; ok {
+					break
+				}
+				f = b.nodes[f].fail
+			}
+			if nxt, ok := b.nodes[f].next[r]; ok && nxt != child {
+				b.nodes[child].fail = nxt
+			} else {
+				b.nodes[child].fail = 0
+			}
+			b.nodes[child].outputs = append(b.nodes[child].outputs, b.nodes[b.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	m := &Matcher{nodes: b.nodes}
+	for i := range m.nodes {
+		m.nodes[i].outputs = appendLengths(m.nodes[i].outputs, b.lengths)
+	}
+	return m
+}
+
+// appendLengths 在输出列表后附带模式长度，便于匹配时计算起点
+// 存储格式为 [id0, len0, id1, len1, ...]
+func appendLengths(outputs []int, lengths []int) []int {
+	if len(outputs) == 0 {
+		return nil
+	}
+	result := make([]int, 0, len(outputs)*2)
+	for _, id := range outputs {
+		result = append(result, id, lengths[id])
+	}
+	return result
+}
+
+// FindAll 返回文本中所有模式的命中（允许重叠）
+func (m *Matcher) FindAll(text string) []Match {
+	if m == nil || len(m.nodes) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	cur := 0
+	pos := 0
+	for _, r := range text {
+		for cur != 0 {
+			if _, ok := m.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if nxt, ok := m.nodes[cur].next[r]; ok {
+			cur = nxt
+		}
+		pos++
+		out := m.nodes[cur].outputs
+		for i := 0; i < len(out); i += 2 {
+			matches = append(matches, Match{Pattern: out[i], Start: pos - out[i+1], End: pos})
+		}
+	}
+	return matches
+}
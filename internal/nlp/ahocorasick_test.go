@@ -0,0 +1,84 @@
+package nlp
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatcherFindAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		want     []Match
+	}{
+		{
+			name:     "overlapping and nested patterns",
+			patterns: []string{"he", "she", "his", "hers"},
+			text:     "ushers",
+			want:     []Match{{Pattern: 0, Start: 2, End: 4}, {Pattern: 1, Start: 1, End: 4}, {Pattern: 3, Start: 2, End: 6}},
+		},
+		{
+			name:     "repeated occurrences",
+			patterns: []string{"aa"},
+			text:     "aaaa",
+			want:     []Match{{Pattern: 0, Start: 0, End: 2}, {Pattern: 0, Start: 1, End: 3}, {Pattern: 0, Start: 2, End: 4}},
+		},
+		{
+			name:     "positions count runes not bytes",
+			patterns: []string{"赌博", "博彩"},
+			text:     "网络赌博彩票",
+			want:     []Match{{Pattern: 0, Start: 2, End: 4}, {Pattern: 1, Start: 3, End: 5}},
+		},
+		{
+			name:     "failure link into shorter pattern",
+			patterns: []string{"abcd", "bc"},
+			text:     "abce",
+			want:     []Match{{Pattern: 1, Start: 1, End: 3}},
+		},
+		{
+			name:     "no match",
+			patterns: []string{"敏感"},
+			text:     "正常内容",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewMatcherBuilder()
+			for i, pattern := range tt.patterns {
+				if id := builder.AddPattern(pattern); id != i {
+					t.Fatalf("AddPattern(%q) = %d, want %d", pattern, id, i)
+				}
+			}
+
+			got := builder.Build().FindAll(tt.text)
+			sort.Slice(got, func(i, j int) bool {
+				if got[i].Pattern != got[j].Pattern {
+					return got[i].Pattern < got[j].Pattern
+				}
+				return got[i].Start < got[j].Start
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherEmptyPattern(t *testing.T) {
+	builder := NewMatcherBuilder()
+	if id := builder.AddPattern(""); id != -1 {
+		t.Errorf("AddPattern(\"\") = %d, want -1", id)
+	}
+	if id := builder.AddPattern("词"); id != 0 {
+		t.Errorf("AddPattern after empty pattern = %d, want 0", id)
+	}
+
+	var matcher *Matcher
+	if got := matcher.FindAll("词"); got != nil {
+		t.Errorf("nil matcher FindAll() = %v, want nil", got)
+	}
+}
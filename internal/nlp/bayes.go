@@ -0,0 +1,112 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+)
+
+// NaiveBayes 多项式朴素贝叶斯文本分类模型，保存词频计数以便序列化后重新加载
+type NaiveBayes struct {
+	Labels      []string         `json:"labels"`
+	DocCounts   []int            `json:"doc_counts"`   // 各类别的训练文档数
+	TokenTotals []int            `json:"token_totals"` // 各类别的总词数
+	TermCounts  map[string][]int `json:"term_counts"`  // 词 -> 各类别中的出现次数
+	Alpha       float64          `json:"alpha"`        // 拉普拉斯平滑系数
+}
+
+// Prediction 一个类别的预测概率
+type Prediction struct {
+	Label       string  `json:"label"`
+	Probability float64 `json:"probability"`
+}
+
+// TrainNaiveBayes 在分词后的文档和对应类别上训练模型，总出现次数少于 minCount 的词不进入词表
+func TrainNaiveBayes(docs [][]string, labels []string, alpha float64, minCount int) *NaiveBayes {
+	if alpha <= 0 {
+		alpha = 1
+	}
+
+	index := make(map[string]int)
+	m := &NaiveBayes{TermCounts: make(map[string][]int), Alpha: alpha}
+	for _, label := range labels {
+		if _, ok := index[label]; !ok {
+			index[label] = len(m.Labels)
+			m.Labels = append(m.Labels, label)
+		}
+	}
+	m.DocCounts = make([]int, len(m.Labels))
+	m.TokenTotals = make([]int, len(m.Labels))
+
+	for i, tokens := range docs {
+		c := index[labels[i]]
+		m.DocCounts[c]++
+		for _, token := range tokens {
+			counts, ok := m.TermCounts[token]
+			if !ok {
+				counts = make([]int, len(m.Labels))
+				m.TermCounts[token] = counts
+			}
+			counts[c]++
+		}
+	}
+
+	// 去掉低频词后重新统计各类别的总词数
+	for term, counts := range m.TermCounts {
+		total := 0
+		for _, count := range counts {
+			total += count
+		}
+		if total < minCount {
+			delete(m.TermCounts, term)
+			continue
+		}
+		for c, count := range counts {
+			m.TokenTotals[c] += count
+		}
+	}
+	return m
+}
+
+// Predict 返回各类别的后验概率，从高到低排列；不在词表中的词忽略
+func (m *NaiveBayes) Predict(tokens []string) []Prediction {
+	if len(m.Labels) == 0 {
+		return nil
+	}
+
+	docs := 0
+	for _, count := range m.DocCounts {
+		docs += count
+	}
+	vocabulary := float64(len(m.TermCounts))
+
+	scores := make([]float64, len(m.Labels))
+	for c := range m.Labels {
+		scores[c] = math.Log(float64(m.DocCounts[c]+1) / float64(docs+len(m.Labels)))
+		denominator := math.Log(float64(m.TokenTotals[c]) + m.Alpha*vocabulary)
+		for _, token := range tokens {
+			if counts, ok := m.TermCounts[token]; ok {
+				scores[c] += math.Log(float64(counts[c])+m.Alpha) - denominator
+			}
+		}
+	}
+
+	// 对数概率做 softmax 得到归一化的后验概率
+	maxScore := scores[0]
+	for _, score := range scores[1:] {
+		maxScore = math.Max(maxScore, score)
+	}
+	var sum float64
+	for c := range scores {
+		scores[c] = math.Exp(scores[c] - maxScore)
+		sum += scores[c]
+	}
+
+	predictions := make([]Prediction, len(m.Labels))
+	for c, label := range m.Labels {
+		predictions[c] = Prediction{Label: label, Probability: scores[c] / sum}
+	}
+	sort.SliceStable(predictions, func(i, j int) bool {
+		return predictions[i].Probability > predictions[j].Probability
+	})
+	return predictions
+}
@@ -0,0 +1,69 @@
+package nlp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTrainNaiveBayes(t *testing.T) {
+	docs := [][]string{{"芯片", "芯片", "出口"}, {"关税"}, {"股市", "关税"}}
+	labels := []string{"科技", "经济", "经济"}
+
+	m := TrainNaiveBayes(docs, labels, 0, 2)
+	if m.Alpha != 1 {
+		t.Errorf("Alpha = %v, want default 1", m.Alpha)
+	}
+	if want := []string{"科技", "经济"}; !reflect.DeepEqual(m.Labels, want) {
+		t.Errorf("Labels = %q, want %q", m.Labels, want)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(m.DocCounts, want) {
+		t.Errorf("DocCounts = %v, want %v", m.DocCounts, want)
+	}
+	// 出口和股市只出现一次，不进入词表，也不计入总词数
+	wantTerms := map[string][]int{"芯片": {2, 0}, "关税": {0, 2}}
+	if !reflect.DeepEqual(m.TermCounts, wantTerms) {
+		t.Errorf("TermCounts = %v, want %v", m.TermCounts, wantTerms)
+	}
+	if want := []int{2, 2}; !reflect.DeepEqual(m.TokenTotals, want) {
+		t.Errorf("TokenTotals = %v, want %v", m.TokenTotals, want)
+	}
+}
+
+func TestNaiveBayesPredict(t *testing.T) {
+	m := TrainNaiveBayes([][]string{{"芯片", "芯片", "出口"}, {"关税"}, {"股市", "关税"}}, []string{"科技", "经济", "经济"}, 1, 2)
+
+	tests := []struct {
+		name   string
+		tokens []string
+		want   []Prediction
+	}{
+		// 科技: 2/5 * 3/4，经济: 3/5 * 1/4
+		{"known term", []string{"芯片"}, []Prediction{{"科技", 2.0 / 3.0}, {"经济", 1.0 / 3.0}}},
+		{"opposite term", []string{"关税"}, []Prediction{{"经济", 9.0 / 11.0}, {"科技", 2.0 / 11.0}}},
+		{"unknown terms fall back to priors", []string{"出口", "航天"}, []Prediction{{"经济", 0.6}, {"科技", 0.4}}},
+		{"no tokens", nil, []Prediction{{"经济", 0.6}, {"科技", 0.4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Predict(tt.tokens)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Predict() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Label != tt.want[i].Label || math.Abs(got[i].Probability-tt.want[i].Probability) > epsilon {
+					t.Errorf("Predict() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNaiveBayesPredictEmpty(t *testing.T) {
+	m := TrainNaiveBayes(nil, nil, 1, 1)
+	if got := m.Predict([]string{"芯片"}); got != nil {
+		t.Errorf("Predict() on empty model = %v, want nil", got)
+	}
+}
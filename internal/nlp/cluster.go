@@ -0,0 +1,225 @@
+package nlp
+
+import (
+	"sort"
+	"time"
+)
+
+// 聚类算法
+const (
+	ClusterSinglePass    = "single_pass"   // 单遍聚类：按时间顺序将文档归入最相似的簇质心
+	ClusterAgglomerative = "agglomerative" // 平均链接层次聚类
+)
+
+// Document 待聚类的文档
+type Document struct {
+	Vector Vector
+	Time   time.Time
+}
+
+// ClusterOptions 聚类参数
+type ClusterOptions struct {
+	Method     string        // 聚类算法
+	Threshold  float64       // 归入同一簇的最低余弦相似度
+	TimeWindow time.Duration // 同一簇内相邻文档的最大时间间隔，0 表示不限制
+	// MaxAgglomerativeSize 层次聚类的最大文档数，超过时退化为单遍聚类
+	MaxAgglomerativeSize int
+	// CannotLink 返回 true 表示两篇文档不能出现在同一簇中，可为 nil
+	CannotLink func(i, j int) bool
+}
+
+// Cluster 对文档聚类，返回每个簇包含的文档下标
+// 簇内下标按时间升序，簇之间按最早文档的时间升序
+func Cluster(docs []Document, opts ClusterOptions) [][]int {
+	var groups [][]int
+	if opts.Method == ClusterAgglomerative && (opts.MaxAgglomerativeSize <= 0 || len(docs) <= opts.MaxAgglomerativeSize) {
+		groups = agglomerative(docs, opts)
+	} else {
+		groups = singlePass(docs, opts)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return docs[group[i]].Time.Before(docs[group[j]].Time)
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return docs[groups[i][0]].Time.Before(docs[groups[j][0]].Time)
+	})
+	return groups
+}
+
+type singlePassCluster struct {
+	centroid Vector
+	members  []int
+	last     time.Time
+}
+
+func singlePass(docs []Document, opts ClusterOptions) [][]int {
+	order := timeOrder(docs)
+	clusters := make([]*singlePassCluster, 0)
+
+	for _, idx := range order {
+		doc := docs[idx]
+
+		var best *singlePassCluster
+		bestSim := opts.Threshold
+		for _, c := range clusters {
+			if opts.TimeWindow > 0 && doc.Time.Sub(c.last) > opts.TimeWindow {
+				continue
+			}
+			if cannotLinkAny(opts.CannotLink, idx, c.members) {
+				continue
+			}
+			if sim := Cosine(doc.Vector, c.centroid); sim >= bestSim {
+				best, bestSim = c, sim
+			}
+		}
+
+		if best == nil {
+			centroid := make(Vector, len(doc.Vector))
+			centroid.Add(doc.Vector, 1)
+			clusters = append(clusters, &singlePassCluster{centroid: centroid, members: []int{idx}, last: doc.Time})
+			continue
+		}
+
+		best.centroid.Add(doc.Vector, 1)
+		best.members = append(best.members, idx)
+		if doc.Time.After(best.last) {
+			best.last = doc.Time
+		}
+	}
+
+	groups := make([][]int, len(clusters))
+	for i, c := range clusters {
+		groups[i] = c.members
+	}
+	return groups
+}
+
+// agglomerative 平均链接层次聚类，相似度用 Lance-Williams 公式增量更新
+func agglomerative(docs []Document, opts ClusterOptions) [][]int {
+	n := len(docs)
+	if n == 0 {
+		return nil
+	}
+
+	sim := make([][]float64, n)
+	blocked := make([][]bool, n)
+	for i := range sim {
+		sim[i] = make([]float64, n)
+		blocked[i] = make([]bool, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			s := Cosine(docs[i].Vector, docs[j].Vector)
+			sim[i][j], sim[j][i] = s, s
+			if opts.CannotLink != nil && opts.CannotLink(i, j) {
+				blocked[i][j], blocked[j][i] = true, true
+			}
+		}
+	}
+
+	members := make([][]int, n)
+	first := make([]time.Time, n)
+	last := make([]time.Time, n)
+	active := make([]bool, n)
+	for i := range docs {
+		members[i] = []int{i}
+		first[i], last[i] = docs[i].Time, docs[i].Time
+		active[i] = true
+	}
+
+	for {
+		bi, bj := -1, -1
+		bestSim := opts.Threshold
+		for i := 0; i < n; i++ {
+			if !active[i] {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if !active[j] || blocked[i][j] || sim[i][j] < bestSim {
+					continue
+				}
+				if opts.TimeWindow > 0 && spanGap(first[i], last[i], first[j], last[j]) > opts.TimeWindow {
+					continue
+				}
+				bi, bj, bestSim = i, j, sim[i][j]
+			}
+		}
+		if bi < 0 {
+			break
+		}
+
+		ni, nj := float64(len(members[bi])), float64(len(members[bj]))
+		for k := 0; k < n; k++ {
+			if !active[k] || k == bi || k == bj {
+				continue
+			}
+			s := (ni*sim[bi][k] + nj*sim[bj][k]) / (ni + nj)
+			sim[bi][k], sim[k][bi] = s, s
+			b := blocked[bi][k] || blocked[bj][k]
+			blocked[bi][k], blocked[k][bi] = b, b
+		}
+
+		members[bi] = append(members[bi], members[bj]...)
+		if first[bj].Before(first[bi]) {
+			first[bi] = first[bj]
+		}
+		if last[bj].After(last[bi]) {
+			last[bi] = last[bj]
+		}
+		active[bj] = false
+	}
+
+	groups := make([][]int, 0)
+	for i := 0; i < n; i++ {
+		if active[i] {
+			groups = append(groups, members[i])
+		}
+	}
+	return groups
+}
+
+// Centroid 计算一组文档向量的质心
+func Centroid(docs []Document, indexes []int) Vector {
+	centroid := make(Vector)
+	for _, idx := range indexes {
+		centroid.Add(docs[idx].Vector, 1)
+	}
+	return centroid
+}
+
+func timeOrder(docs []Document) []int {
+	order := make([]int, len(docs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return docs[order[i]].Time.Before(docs[order[j]].Time)
+	})
+	return order
+}
+
+func cannotLinkAny(cannotLink func(i, j int) bool, idx int, members []int) bool {
+	if cannotLink == nil {
+		return false
+	}
+	for _, m := range members {
+		if cannotLink(idx, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// spanGap 两个时间区间之间的间隔，重叠时为 0
+func spanGap(firstA, lastA, firstB, lastB time.Time) time.Duration {
+	if firstB.After(lastA) {
+		return firstB.Sub(lastA)
+	}
+	if firstA.After(lastB) {
+		return firstA.Sub(lastB)
+	}
+	return 0
+}
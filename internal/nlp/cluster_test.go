@@ -0,0 +1,73 @@
+package nlp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCluster(t *testing.T) {
+	base := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	docs := []Document{
+		{Vector: Vector{"芯片": 1, "出口": 1}, Time: base.Add(2 * time.Hour)},
+		{Vector: Vector{"关税": 1, "股市": 1}, Time: base},
+		{Vector: Vector{"芯片": 1, "出口": 0.8}, Time: base.Add(time.Hour)},
+		{Vector: Vector{"关税": 1, "股市": 0.9}, Time: base.Add(72 * time.Hour)},
+		{Vector: Vector{"航天": 1}, Time: base.Add(3 * time.Hour)},
+	}
+
+	tests := []struct {
+		name string
+		opts ClusterOptions
+		want [][]int
+	}{
+		{
+			name: "single pass",
+			opts: ClusterOptions{Method: ClusterSinglePass, Threshold: 0.5},
+			want: [][]int{{1, 3}, {2, 0}, {4}},
+		},
+		{
+			name: "agglomerative",
+			opts: ClusterOptions{Method: ClusterAgglomerative, Threshold: 0.5},
+			want: [][]int{{1, 3}, {2, 0}, {4}},
+		},
+		{
+			name: "time window splits distant documents",
+			opts: ClusterOptions{Method: ClusterAgglomerative, Threshold: 0.5, TimeWindow: 24 * time.Hour},
+			want: [][]int{{1}, {2, 0}, {4}, {3}},
+		},
+		{
+			name: "agglomerative falls back to single pass",
+			opts: ClusterOptions{Method: ClusterAgglomerative, Threshold: 0.5, TimeWindow: 24 * time.Hour, MaxAgglomerativeSize: 2},
+			want: [][]int{{1}, {2, 0}, {4}, {3}},
+		},
+		{
+			name: "cannot link",
+			opts: ClusterOptions{Method: ClusterSinglePass, Threshold: 0.5, CannotLink: func(i, j int) bool {
+				return (i == 0 && j == 2) || (i == 2 && j == 0)
+			}},
+			want: [][]int{{1, 3}, {2}, {0}, {4}},
+		},
+		{
+			name: "high threshold keeps documents apart",
+			opts: ClusterOptions{Method: ClusterSinglePass, Threshold: 0.999},
+			want: [][]int{{1}, {2}, {0}, {4}, {3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cluster(docs, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cluster() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterEmpty(t *testing.T) {
+	for _, method := range []string{ClusterSinglePass, ClusterAgglomerative} {
+		if got := Cluster(nil, ClusterOptions{Method: method, Threshold: 0.5}); len(got) != 0 {
+			t.Errorf("Cluster(nil) with %s = %v, want empty", method, got)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+)
+
+// ClusterMetrics 聚类结果与标注结果的对比指标
+type ClusterMetrics struct {
+	Documents     int     `json:"documents"`      // 参与评估的文档数
+	Clusters      int     `json:"clusters"`       // 预测的簇数
+	GoldClusters  int     `json:"gold_clusters"`  // 标注的簇数
+	Purity        float64 `json:"purity"`         // 每个预测簇中占多数的标注类文档占比
+	InversePurity float64 `json:"inverse_purity"` // 每个标注类中占多数的预测簇文档占比
+	NMI           float64 `json:"nmi"`            // 归一化互信息（算术平均归一化）
+	PairPrecision float64 `json:"pair_precision"` // 被聚到一起的文档对中标注也相同的比例
+	PairRecall    float64 `json:"pair_recall"`    // 标注相同的文档对中被聚到一起的比例
+	PairF1        float64 `json:"pair_f1"`
+
+	Sizes SizeDistribution `json:"sizes"` // 预测簇大小分布
+}
+
+// SizeDistribution 簇大小分布
+type SizeDistribution struct {
+	Singletons int          `json:"singletons"` // 只有一篇文档的簇数
+	Max        int          `json:"max"`
+	Mean       float64      `json:"mean"`
+	Median     float64      `json:"median"`
+	Buckets    []SizeBucket `json:"buckets"`
+}
+
+// SizeBucket 大小在 [Min, Max] 区间内的簇数，Max 为 0 表示不设上限
+type SizeBucket struct {
+	Label string `json:"label"`
+	Min   int    `json:"min"`
+	Max   int    `json:"max"`
+	Count int    `json:"count"`
+}
+
+// sizeBuckets 簇大小分布的统计区间
+var sizeBuckets = []SizeBucket{
+	{Label: "1", Min: 1, Max: 1},
+	{Label: "2", Min: 2, Max: 2},
+	{Label: "3-5", Min: 3, Max: 5},
+	{Label: "6-10", Min: 6, Max: 10},
+	{Label: "11-20", Min: 11, Max: 20},
+	{Label: ">20", Min: 21},
+}
+
+// EvaluateClusters 将预测的聚类结果与标注对比
+// groups 为每个簇包含的文档下标，gold 为每篇文档的标注类别；未出现在任何簇中的文档视为单独成簇
+func EvaluateClusters(groups [][]int, gold []string) ClusterMetrics {
+	n := len(gold)
+	metrics := ClusterMetrics{Documents: n}
+	if n == 0 {
+		return metrics
+	}
+
+	// 为每篇文档分配预测簇编号
+	predicted := make([]int, n)
+	for i := range predicted {
+		predicted[i] = -1
+	}
+	next := 0
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		for _, idx := range group {
+			predicted[idx] = next
+		}
+		next++
+	}
+	for i := range predicted {
+		if predicted[i] < 0 {
+			predicted[i] = next
+			next++
+		}
+	}
+
+	goldIDs := make(map[string]int)
+	goldOf := make([]int, n)
+	for i, label := range gold {
+		id, ok := goldIDs[label]
+		if !ok {
+			id = len(goldIDs)
+			goldIDs[label] = id
+		}
+		goldOf[i] = id
+	}
+
+	// 列联表
+	contingency := make(map[[2]int]int)
+	predSizes := make([]int, next)
+	goldSizes := make([]int, len(goldIDs))
+	for i := 0; i < n; i++ {
+		contingency[[2]int{predicted[i], goldOf[i]}]++
+		predSizes[predicted[i]]++
+		goldSizes[goldOf[i]]++
+	}
+
+	metrics.Clusters = next
+	metrics.GoldClusters = len(goldIDs)
+
+	// 纯度与逆纯度
+	predMax := make([]int, next)
+	goldMax := make([]int, len(goldIDs))
+	for key, count := range contingency {
+		if count > predMax[key[0]] {
+			predMax[key[0]] = count
+		}
+		if count > goldMax[key[1]] {
+			goldMax[key[1]] = count
+		}
+	}
+	metrics.Purity = float64(sum(predMax)) / float64(n)
+	metrics.InversePurity = float64(sum(goldMax)) / float64(n)
+
+	// 归一化互信息
+	total := float64(n)
+	var mutual float64
+	for key, count := range contingency {
+		pij := float64(count) / total
+		pi := float64(predSizes[key[0]]) / total
+		pj := float64(goldSizes[key[1]]) / total
+		mutual += pij * math.Log(pij/(pi*pj))
+	}
+	hPred, hGold := entropy(predSizes, total), entropy(goldSizes, total)
+	switch {
+	case hPred+hGold == 0:
+		metrics.NMI = 1 // 两边都只有一个簇
+	case hPred == 0 || hGold == 0:
+		metrics.NMI = 0 // 只有一边是单个簇时互信息为 0，此时纯度或逆纯度恒为 1，没有参考意义
+	default:
+		metrics.NMI = 2 * mutual / (hPred + hGold)
+	}
+
+	// 文档对的精确率和召回率
+	var together, sameGold, both float64
+	for _, size := range predSizes {
+		together += pairs(size)
+	}
+	for _, size := range goldSizes {
+		sameGold += pairs(size)
+	}
+	for _, count := range contingency {
+		both += pairs(count)
+	}
+	metrics.PairPrecision = ratio(both, together)
+	metrics.PairRecall = ratio(both, sameGold)
+	if metrics.PairPrecision+metrics.PairRecall > 0 {
+		metrics.PairF1 = 2 * metrics.PairPrecision * metrics.PairRecall / (metrics.PairPrecision + metrics.PairRecall)
+	}
+
+	metrics.Sizes = sizeDistribution(predSizes)
+	return metrics
+}
+
+func sizeDistribution(sizes []int) SizeDistribution {
+	dist := SizeDistribution{Buckets: make([]SizeBucket, len(sizeBuckets))}
+	copy(dist.Buckets, sizeBuckets)
+	if len(sizes) == 0 {
+		return dist
+	}
+
+	sorted := append([]int{}, sizes...)
+	sort.Ints(sorted)
+	for _, size := range sorted {
+		if size == 1 {
+			dist.Singletons++
+		}
+		for i := range dist.Buckets {
+			bucket := &dist.Buckets[i]
+			if size >= bucket.Min && (bucket.Max == 0 || size <= bucket.Max) {
+				bucket.Count++
+				break
+			}
+		}
+	}
+
+	dist.Max = sorted[len(sorted)-1]
+	dist.Mean = float64(sum(sorted)) / float64(len(sorted))
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		dist.Median = float64(sorted[mid])
+	} else {
+		dist.Median = float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+	return dist
+}
+
+func entropy(sizes []int, total float64) float64 {
+	var h float64
+	for _, size := range sizes {
+		if size == 0 {
+			continue
+		}
+		p := float64(size) / total
+		h -= p * math.Log(p)
+	}
+	return h
+}
+
+func pairs(size int) float64 {
+	return float64(size) * float64(size-1) / 2
+}
+
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,118 @@
+package nlp
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestEvaluateClusters(t *testing.T) {
+	// 100 篇同一标注事件的新闻被切成 91 个簇：纯度恒为 1，NMI 恒为 0
+	oneEvent := make([]string, 100)
+	var oversplit [][]int
+	for i := range oneEvent {
+		oneEvent[i] = "政治"
+		if i < 90 {
+			oversplit = append(oversplit, []int{i})
+		}
+	}
+	oversplit = append(oversplit, []int{90, 91, 92, 93, 94, 95, 96, 97, 98, 99})
+
+	tests := []struct {
+		name   string
+		groups [][]int
+		gold   []string
+		want   ClusterMetrics
+	}{
+		{
+			name:   "perfect",
+			groups: [][]int{{0, 1}, {2, 3}},
+			gold:   []string{"a", "a", "b", "b"},
+			want: ClusterMetrics{Documents: 4, Clusters: 2, GoldClusters: 2, Purity: 1, InversePurity: 1, NMI: 1,
+				PairPrecision: 1, PairRecall: 1, PairF1: 1},
+		},
+		{
+			name:   "unassigned documents are singletons",
+			groups: nil,
+			gold:   []string{"a", "a", "b", "b"},
+			want:   ClusterMetrics{Documents: 4, Clusters: 4, GoldClusters: 2, Purity: 1, InversePurity: 0.5, NMI: 2.0 / 3.0},
+		},
+		{
+			name:   "everything in one cluster",
+			groups: [][]int{{0, 1, 2, 3}},
+			gold:   []string{"a", "a", "b", "b"},
+			want: ClusterMetrics{Documents: 4, Clusters: 1, GoldClusters: 2, Purity: 0.5, InversePurity: 1, NMI: 0,
+				PairPrecision: 1.0 / 3.0, PairRecall: 1, PairF1: 0.5},
+		},
+		{
+			name:   "mixed",
+			groups: [][]int{{0, 1, 2}, {}, {3, 4}},
+			gold:   []string{"a", "a", "b", "b", "b"},
+			want: ClusterMetrics{Documents: 5, Clusters: 2, GoldClusters: 2, Purity: 0.8, InversePurity: 0.8, NMI: 0.4325380677663125,
+				PairPrecision: 0.5, PairRecall: 0.5, PairF1: 0.5},
+		},
+		{
+			name:   "single cluster and single event",
+			groups: [][]int{{0, 1, 2}},
+			gold:   []string{"a", "a", "a"},
+			want: ClusterMetrics{Documents: 3, Clusters: 1, GoldClusters: 1, Purity: 1, InversePurity: 1, NMI: 1,
+				PairPrecision: 1, PairRecall: 1, PairF1: 1},
+		},
+		{
+			name:   "single event oversplit",
+			groups: oversplit,
+			gold:   oneEvent,
+			want: ClusterMetrics{Documents: 100, Clusters: 91, GoldClusters: 1, Purity: 1, InversePurity: 0.1, NMI: 0,
+				PairPrecision: 1, PairRecall: 45.0 / 4950.0, PairF1: 2 * (45.0 / 4950.0) / (1 + 45.0/4950.0)},
+		},
+		{
+			name: "empty",
+			want: ClusterMetrics{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluateClusters(tt.groups, tt.gold)
+			if got.Documents != tt.want.Documents || got.Clusters != tt.want.Clusters || got.GoldClusters != tt.want.GoldClusters {
+				t.Errorf("counts = %d/%d/%d, want %d/%d/%d", got.Documents, got.Clusters, got.GoldClusters,
+					tt.want.Documents, tt.want.Clusters, tt.want.GoldClusters)
+			}
+			scores := []struct {
+				name      string
+				got, want float64
+			}{
+				{"Purity", got.Purity, tt.want.Purity},
+				{"InversePurity", got.InversePurity, tt.want.InversePurity},
+				{"NMI", got.NMI, tt.want.NMI},
+				{"PairPrecision", got.PairPrecision, tt.want.PairPrecision},
+				{"PairRecall", got.PairRecall, tt.want.PairRecall},
+				{"PairF1", got.PairF1, tt.want.PairF1},
+			}
+			for _, s := range scores {
+				if math.Abs(s.got-s.want) > epsilon {
+					t.Errorf("%s = %v, want %v", s.name, s.got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateClustersSizes(t *testing.T) {
+	groups := [][]int{{0}, {1, 2}, {3, 4, 5}, {6, 7, 8, 9, 10, 11, 12}}
+	gold := make([]string, 13)
+	for i := range gold {
+		gold[i] = fmt.Sprint(i % 3)
+	}
+
+	sizes := EvaluateClusters(groups, gold).Sizes
+	if sizes.Singletons != 1 || sizes.Max != 7 || sizes.Mean != 3.25 || sizes.Median != 2.5 {
+		t.Errorf("Sizes = %+v", sizes)
+	}
+	wantCounts := []int{1, 1, 1, 1, 0, 0}
+	for i, bucket := range sizes.Buckets {
+		if bucket.Count != wantCounts[i] {
+			t.Errorf("bucket %s count = %d, want %d", bucket.Label, bucket.Count, wantCounts[i])
+		}
+	}
+}
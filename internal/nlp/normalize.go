@@ -0,0 +1,109 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ToHalfWidth 将全角字符转换为半角字符
+func ToHalfWidth(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		b.WriteRune(toHalfWidthRune(r))
+	}
+	return b.String()
+}
+
+func toHalfWidthRune(r rune) rune {
+	switch {
+	case r == 0x3000: // 全角空格
+		return ' '
+	case r >= 0xFF01 && r <= 0xFF5E: // 全角ASCII
+		return r - 0xFEE0
+	}
+	return r
+}
+
+// combiningDiaeresis ü 分解为 NFD 后跟在 u 后面的分音符
+const combiningDiaeresis = '\u0308'
+
+// NormalizeForMatch 为敏感词匹配规范化文本：
+// 全角转半角、统一小写，并去除空白、标点、符号和变音符号，防止用分隔符或带声调的拼音绕过检测；
+// 先分解为 NFD，预组合的声调字母（如 ǎ）拆成字母和组合符号后再去掉符号，ü 按拼音输入习惯写作 v
+func NormalizeForMatch(text string) string {
+	normalized, _ := NormalizeForMatchWithBoundaries(text)
+	return normalized
+}
+
+// NormalizeForMatchWithBoundaries 与 NormalizeForMatch 相同，同时返回规范化结果中各位置是否为词边界
+// boundaries[i] 表示第 i 个 rune 之前是否为边界，长度为 rune 数加一，首尾总是边界；
+// 原文在此处有被去掉的空白、标点或符号，或者两侧一边是 ASCII 字母数字、一边不是时视为边界
+func NormalizeForMatchWithBoundaries(text string) (string, []bool) {
+	runes := make([]rune, 0, len(text))
+	boundaries := []bool{true}
+	separated := false
+	for _, r := range norm.NFD.String(text) {
+		r = unicode.ToLower(toHalfWidthRune(r))
+		switch {
+		case r == combiningDiaeresis && !separated && len(runes) > 0 && runes[len(runes)-1] == 'u':
+			runes[len(runes)-1] = 'v'
+			continue
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r):
+			separated = true
+			continue
+		}
+		if variant, ok := charVariants[r]; ok {
+			r = variant
+		}
+		if n := len(runes); n > 0 {
+			boundaries[n] = separated || isASCIIAlnum(runes[n-1]) != isASCIIAlnum(r)
+		}
+		runes = append(runes, r)
+		boundaries = append(boundaries, true)
+		separated = false
+	}
+	return string(runes), boundaries
+}
+
+// IsASCIIWord 判断规范化后的模式是否只由 ASCII 字母和数字组成
+// 这类模式（英文单词、拼音）去掉空白后容易跨词误中，需要在词边界处匹配
+func IsASCIIWord(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	for _, r := range pattern {
+		if !isASCIIAlnum(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIIAlnum(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// charVariants 常见的形近/异体字替换，统一到规范写法
+var charVariants = map[rune]rune{
+	'①': '1', '②': '2', '③': '3', '④': '4', '⑤': '5',
+	'⑥': '6', '⑦': '7', '⑧': '8', '⑨': '9', '⓪': '0',
+	'零': '0', '〇': '0',
+	'Ⓐ': 'a', 'Ⓑ': 'b', 'Ⓒ': 'c', 'Ⓓ': 'd', 'Ⓔ': 'e',
+	'ⓐ': 'a', 'ⓑ': 'b', 'ⓒ': 'c', 'ⓓ': 'd', 'ⓔ': 'e',
+}
+
+// NormalizePinyin 规范化拼音写法：按 NormalizeForMatch 去掉分隔符和声调、ü 写作 v 后只保留字母
+func NormalizePinyin(pinyin string) string {
+	var b strings.Builder
+	for _, r := range NormalizeForMatch(pinyin) {
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package nlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeForMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"full width and case", "ＡＢＣ１２３", "abc123"},
+		{"separators removed", "赌 * 博-网.站", "赌博网站"},
+		{"precomposed tone marks", "dǔ bó", "dubo"},
+		{"combining tone marks", "dǔbó", "dubo"},
+		{"variants", "①⓪零〇", "1000"},
+		{"full width space", "敏　感", "敏感"},
+		{"u with diaeresis as v", "Lǜ Sè lüse", "lvselvse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeForMatch(tt.text); got != tt.want {
+				t.Errorf("NormalizeForMatch(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePinyin(t *testing.T) {
+	tests := []struct {
+		pinyin string
+		want   string
+	}{
+		{"Dǔ Bó", "dubo"},
+		{"lǜ-sè", "lvse"},
+		{"ＸＩ'ＡＮ", "xian"},
+		{"nǚ ér", "nver"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizePinyin(tt.pinyin); got != tt.want {
+			t.Errorf("NormalizePinyin(%q) = %q, want %q", tt.pinyin, got, tt.want)
+		}
+		if got := NormalizeForMatch(tt.pinyin); got != tt.want {
+			t.Errorf("NormalizeForMatch(%q) = %q, want %q", tt.pinyin, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeForMatchWithBoundaries(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		want       string
+		boundaries []bool
+	}{
+		{"separators", "is best", "isbest", []bool{true, false, true, false, false, false, true}},
+		{"tone marks are not separators", "dǔbó", "dubo", []bool{true, false, false, false, true}},
+		{"script change", "赌博dubo", "赌博dubo", []bool{true, false, true, false, false, false, true}},
+		{"empty", " ， ", "", []bool{true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, boundaries := NormalizeForMatchWithBoundaries(tt.text)
+			if got != tt.want || !reflect.DeepEqual(boundaries, tt.boundaries) {
+				t.Errorf("NormalizeForMatchWithBoundaries(%q) = %q, %v, want %q, %v", tt.text, got, boundaries, tt.want, tt.boundaries)
+			}
+		})
+	}
+}
+
+func TestIsASCIIWord(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"dubo", true},
+		{"sb250", true},
+		{"赌博", false},
+		{"赌bo", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsASCIIWord(tt.pattern); got != tt.want {
+			t.Errorf("IsASCIIWord(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
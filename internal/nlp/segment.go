@@ -0,0 +1,200 @@
+package nlp
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed dict.txt
+var defaultDict string
+
+// DefaultStopWords 默认停用词，分词后过滤
+var DefaultStopWords = []string{
+	"的", "了", "是", "在", "有", "和", "与", "为", "将", "被", "把", "对", "向", "从", "到", "于", "以", "及", "或", "而", "且", "但", "不", "没", "无", "非",
+	"a", "an", "the", "to", "of", "for", "and", "or", "in", "on", "at", "by", "with", "from", "up", "about", "into", "through", "during", "before", "after", "above", "below", "between", "among", "this", "that", "these", "those",
+	"新闻", "报道", "消息", "最新", "今日", "昨日", "今天", "昨天", "明天", "本周", "上周", "下周", "本月", "上月", "下月", "今年", "去年", "明年",
+	"视频", "记者", "表示", "指出", "认为",
+}
+
+// Segmenter 基于词典的中文分词器
+// 汉字串采用正向最大匹配，词典未收录的连续汉字切分为重叠的二元组；
+// 字母数字串整体作为一个词。构建后只读，可在多个 goroutine 中并发使用
+type Segmenter struct {
+	dict   map[string]struct{}
+	maxLen int // 词典中最长词的字数
+}
+
+var (
+	defaultSegmenter     *Segmenter
+	defaultSegmenterOnce sync.Once
+)
+
+// NewSegmenter 使用内置词典和额外词语构建分词器
+func NewSegmenter(extraWords ...[]string) *Segmenter {
+	s := &Segmenter{dict: make(map[string]struct{})}
+	for _, line := range strings.Split(defaultDict, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, word := range strings.Fields(line) {
+			s.addWord(word)
+		}
+	}
+	for _, words := range extraWords {
+		for _, word := range words {
+			s.addWord(word)
+		}
+	}
+	return s
+}
+
+// DefaultSegmenter 获取只包含内置词典的共享分词器
+func DefaultSegmenter() *Segmenter {
+	defaultSegmenterOnce.Do(func() {
+		defaultSegmenter = NewSegmenter()
+	})
+	return defaultSegmenter
+}
+
+func (s *Segmenter) addWord(word string) {
+	word = strings.ToLower(ToHalfWidth(strings.TrimSpace(word)))
+	n := len([]rune(word))
+	if n < 2 {
+		return
+	}
+	s.dict[word] = struct{}{}
+	if n > s.maxLen {
+		s.maxLen = n
+	}
+}
+
+// HasWord 判断词典是否收录该词
+func (s *Segmenter) HasWord(word string) bool {
+	_, ok := s.dict[strings.ToLower(ToHalfWidth(word))]
+	return ok
+}
+
+// Cut 对文本分词，返回的词均为小写半角形式，不含标点和单字
+func (s *Segmenter) Cut(text string) []string {
+	tokens := make([]string, 0, len(text)/3)
+	runes := []rune(strings.ToLower(ToHalfWidth(text)))
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.Is(unicode.Han, r):
+			j := i
+			for j < len(runes) && unicode.Is(unicode.Han, runes[j]) {
+				j++
+			}
+			tokens = s.cutHan(runes[i:j], tokens)
+			i = j
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			if j-i >= 2 {
+				tokens = append(tokens, string(runes[i:j]))
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// Tokens 分词并过滤停用词
+func (s *Segmenter) Tokens(text string, stopWords map[string]bool) []string {
+	tokens := s.Cut(text)
+	result := tokens[:0]
+	for _, token := range tokens {
+		if !stopWords[token] {
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+// cutHan 对连续汉字串做正向最大匹配
+func (s *Segmenter) cutHan(runes []rune, tokens []string) []string {
+	s.walkHan(runes, func(word []rune) {
+		tokens = append(tokens, string(word))
+	}, func(gap []rune) {
+		tokens = appendBigrams(gap, tokens)
+	})
+	return tokens
+}
+
+// UnknownRuns 文本中未登录词典的连续汉字串（至少两个字），即分词时被切分为二元组的部分
+func (s *Segmenter) UnknownRuns(text string) []string {
+	runs := make([]string, 0)
+	runes := []rune(strings.ToLower(ToHalfWidth(text)))
+	for i := 0; i < len(runes); {
+		if !unicode.Is(unicode.Han, runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && unicode.Is(unicode.Han, runes[j]) {
+			j++
+		}
+		s.walkHan(runes[i:j], func([]rune) {}, func(gap []rune) {
+			if len(gap) >= 2 {
+				runs = append(runs, string(gap))
+			}
+		})
+		i = j
+	}
+	return runs
+}
+
+// walkHan 对连续汉字串做正向最大匹配，依次回调词典中的词和未登录的汉字串
+func (s *Segmenter) walkHan(runes []rune, word func([]rune), gap func([]rune)) {
+	gapStart := -1
+	for i := 0; i < len(runes); {
+		matched := 0
+		for n := min(s.maxLen, len(runes)-i); n >= 2; n-- {
+			if _, ok := s.dict[string(runes[i:i+n])]; ok {
+				matched = n
+				break
+			}
+		}
+
+		if matched == 0 {
+			if gapStart < 0 {
+				gapStart = i
+			}
+			i++
+			continue
+		}
+
+		if gapStart >= 0 {
+			gap(runes[gapStart:i])
+			gapStart = -1
+		}
+		word(runes[i : i+matched])
+		i += matched
+	}
+
+	if gapStart >= 0 {
+		gap(runes[gapStart:])
+	}
+}
+
+// appendBigrams 未登录的汉字串切分为重叠二元组，单字丢弃
+func appendBigrams(runes []rune, tokens []string) []string {
+	for i := 0; i+2 <= len(runes); i++ {
+		tokens = append(tokens, string(runes[i:i+2]))
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
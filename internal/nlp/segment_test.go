@@ -0,0 +1,81 @@
+package nlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSegmenterCut(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra []string
+		text  string
+		want  []string
+	}{
+		{"longest dictionary match", nil, "人工智能芯片", []string{"人工智能", "芯片"}},
+		{"unknown run becomes bigrams", nil, "饕餮魍魉", []string{"饕餮", "餮魍", "魍魉"}},
+		{"single characters dropped", nil, "芯片的", []string{"芯片"}},
+		{"gap between dictionary words", nil, "芯片饕餮芯片", []string{"芯片", "饕餮", "芯片"}},
+		{"full width alphanumerics", nil, "ＡＩ芯片５Ｇ", []string{"ai", "芯片", "5g"}},
+		{"short alphanumeric dropped", nil, "A股 iPhone16发布", []string{"iphone16", "发布"}},
+		{"punctuation splits runs", nil, "芯片，科技！", []string{"芯片", "科技"}},
+		{"extra words", []string{"饕餮盛宴"}, "一场饕餮盛宴", []string{"一场", "饕餮盛宴"}},
+		{"empty", nil, "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSegmenter(tt.extra)
+			if got := s.Cut(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cut(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSegmenterTokens(t *testing.T) {
+	stopWords := map[string]bool{"发布": true, "the": true}
+	got := DefaultSegmenter().Tokens("The 芯片发布", stopWords)
+	if want := []string{"芯片"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokens() = %q, want %q", got, want)
+	}
+}
+
+func TestSegmenterUnknownRuns(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"芯片饕餮魍魉科技", []string{"饕餮魍魉"}},
+		{"饕餮，芯片的魍魉", []string{"饕餮", "的魍魉"}},
+		{"芯片的科技", []string{}},
+		{"AI芯片", []string{}},
+	}
+
+	s := DefaultSegmenter()
+	for _, tt := range tests {
+		if got := s.UnknownRuns(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("UnknownRuns(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSegmenterHasWord(t *testing.T) {
+	s := NewSegmenter([]string{"ＯｐｅｎＡＩ", "字"})
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"人工智能", true},
+		{"openai", true},
+		{"OpenAI", true},
+		{"字", false},
+		{"饕餮", false},
+	}
+
+	for _, tt := range tests {
+		if got := s.HasWord(tt.word); got != tt.want {
+			t.Errorf("HasWord(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package nlp
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	htmlBlockPattern = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	htmlBreakPattern = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/li|/h[1-6])\s*/?>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+	spacePattern     = regexp.MustCompile(`[ \t\r\f\v\x{00a0}\x{3000}]+`)
+)
+
+// StripHTML 去除 HTML 标签并反转义实体，段落和换行标签转为换行
+func StripHTML(text string) string {
+	text = htmlBlockPattern.ReplaceAllString(text, " ")
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, " ")
+	text = html.UnescapeString(text)
+	return spacePattern.ReplaceAllString(text, " ")
+}
+
+// isSentenceEnd 中文句末标点、分号和问叹号
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '。', '！', '？', '!', '?', '；', ';', '…':
+		return true
+	}
+	return false
+}
+
+// isClosingMark 可以跟在句末标点之后的右引号和右括号
+func isClosingMark(r rune) bool {
+	switch r {
+	case '”', '’', '」', '』', '）', ')', '"', '\'', '】', '》':
+		return true
+	}
+	return false
+}
+
+// SplitSentences 将文本切分为句子
+// 以中文句末标点、问叹号、分号和换行为界，句末标点后的右引号和右括号归入前一句；
+// 英文句点只在其后为空白或文本结尾时断句，避免切开小数和网址
+func SplitSentences(text string) []string {
+	runes := []rune(text)
+	sentences := make([]string, 0, len(runes)/30+1)
+	start := 0
+
+	flush := func(end int) {
+		sentence := strings.TrimSpace(string(runes[start:end]))
+		if sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			flush(i)
+			start = i + 1
+		case isSentenceEnd(r):
+			j := i + 1
+			for j < len(runes) && (isSentenceEnd(runes[j]) || isClosingMark(runes[j])) {
+				j++
+			}
+			flush(j)
+			i = j - 1
+		case r == '.' && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])):
+			flush(i + 1)
+		}
+	}
+	flush(len(runes))
+
+	return sentences
+}
@@ -0,0 +1,154 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+)
+
+// SummaryOptions 抽取式摘要参数，未设置的项使用默认值
+type SummaryOptions struct {
+	MaxSentences        int     // 最多选取的句子数
+	MaxChars            int     // 摘要最大字数，至少保留一句
+	MinSentenceChars    int     // 短于该字数的句子不参与排序
+	RedundancyThreshold float64 // 与已选句子的相似度超过该值时跳过，避免多篇报道的同一句话重复入选
+	Damping             float64 // TextRank 阻尼系数
+	Iterations          int     // 最大迭代次数
+}
+
+func (o SummaryOptions) withDefaults() SummaryOptions {
+	if o.MaxSentences <= 0 {
+		o.MaxSentences = 3
+	}
+	if o.MinSentenceChars <= 0 {
+		o.MinSentenceChars = 8
+	}
+	if o.RedundancyThreshold <= 0 {
+		o.RedundancyThreshold = 0.6
+	}
+	if o.Damping <= 0 || o.Damping >= 1 {
+		o.Damping = 0.85
+	}
+	if o.Iterations <= 0 {
+		o.Iterations = 100
+	}
+	return o
+}
+
+// TextRank 以句子 TF-IDF 向量的余弦相似度为边权构建图，迭代计算每个句子的中心度得分
+func TextRank(vectors []Vector, damping float64, iterations int) []float64 {
+	n := len(vectors)
+	scores := make([]float64, n)
+	if n == 0 {
+		return scores
+	}
+
+	weights := make([][]float64, n)
+	outSum := make([]float64, n)
+	for i := range weights {
+		weights[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			w := Cosine(vectors[i], vectors[j])
+			weights[i][j], weights[j][i] = w, w
+			outSum[i] += w
+			outSum[j] += w
+		}
+	}
+
+	for i := range scores {
+		scores[i] = 1.0 / float64(n)
+	}
+	next := make([]float64, n)
+	for iter := 0; iter < iterations; iter++ {
+		var delta float64
+		for i := 0; i < n; i++ {
+			var sum float64
+			for j := 0; j < n; j++ {
+				if weights[j][i] > 0 {
+					sum += weights[j][i] / outSum[j] * scores[j]
+				}
+			}
+			next[i] = (1-damping)/float64(n) + damping*sum
+			delta += math.Abs(next[i] - scores[i])
+		}
+		scores, next = next, scores
+		if delta < 1e-6 {
+			break
+		}
+	}
+	return scores
+}
+
+// Summarize 从句子中选出最核心的若干句，按原文顺序返回
+// tokenize 将句子转换为分词结果；得分相同时优先选择靠前的句子
+func Summarize(sentences []string, tokenize func(string) []string, opts SummaryOptions) []string {
+	opts = opts.withDefaults()
+
+	candidates := make([]int, 0, len(sentences))
+	tokens := make([][]string, 0, len(sentences))
+	seen := make(map[string]bool, len(sentences))
+	for i, sentence := range sentences {
+		if len([]rune(sentence)) < opts.MinSentenceChars || seen[sentence] {
+			continue
+		}
+		t := tokenize(sentence)
+		if len(t) == 0 {
+			continue
+		}
+		seen[sentence] = true
+		candidates = append(candidates, i)
+		tokens = append(tokens, t)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	model := FitTFIDF(tokens)
+	vectors := make([]Vector, len(tokens))
+	for i, t := range tokens {
+		vectors[i] = model.Transform(t)
+	}
+	scores := TextRank(vectors, opts.Damping, opts.Iterations)
+
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	selected := make([]int, 0, opts.MaxSentences)
+	chars := 0
+	for _, idx := range order {
+		if len(selected) >= opts.MaxSentences {
+			break
+		}
+		length := len([]rune(sentences[candidates[idx]]))
+		if opts.MaxChars > 0 && len(selected) > 0 && chars+length > opts.MaxChars {
+			continue
+		}
+
+		redundant := false
+		for _, chosen := range selected {
+			if Cosine(vectors[idx], vectors[chosen]) > opts.RedundancyThreshold {
+				redundant = true
+				break
+			}
+		}
+		if redundant {
+			continue
+		}
+
+		selected = append(selected, idx)
+		chars += length
+	}
+
+	sort.Ints(selected)
+	result := make([]string, 0, len(selected))
+	for _, idx := range selected {
+		result = append(result, sentences[candidates[idx]])
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+)
+
+// Vector 稀疏词向量
+type Vector map[string]float64
+
+// Norm 向量的 L2 范数
+func (v Vector) Norm() float64 {
+	var sum float64
+	for _, w := range v {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
+
+// Add 将另一个向量按权重累加到当前向量
+func (v Vector) Add(other Vector, weight float64) {
+	for term, w := range other {
+		v[term] += w * weight
+	}
+}
+
+// Cosine 计算两个向量的余弦相似度
+func Cosine(a, b Vector) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	var dot float64
+	for term, w := range a {
+		dot += w * b[term]
+	}
+	if dot == 0 {
+		return 0
+	}
+	return dot / (a.Norm() * b.Norm())
+}
+
+// TFIDF 在一批文档上统计的 TF-IDF 模型
+type TFIDF struct {
+	docs int
+	df   map[string]int
+}
+
+// FitTFIDF 根据分词后的文档统计文档频率
+func FitTFIDF(docs [][]string) *TFIDF {
+	m := &TFIDF{docs: len(docs), df: make(map[string]int)}
+	for _, tokens := range docs {
+		seen := make(map[string]bool, len(tokens))
+		for _, token := range tokens {
+			if !seen[token] {
+				seen[token] = true
+				m.df[token]++
+			}
+		}
+	}
+	return m
+}
+
+// IDF 平滑后的逆文档频率
+func (m *TFIDF) IDF(term string) float64 {
+	return math.Log(float64(1+m.docs)/float64(1+m.df[term])) + 1
+}
+
+// Transform 将分词结果转换为 L2 归一化的 TF-IDF 向量，词频取对数以抑制长文本
+func (m *TFIDF) Transform(tokens []string) Vector {
+	tf := make(map[string]int, len(tokens))
+	for _, token := range tokens {
+		tf[token]++
+	}
+
+	vec := make(Vector, len(tf))
+	for term, count := range tf {
+		vec[term] = (1 + math.Log(float64(count))) * m.IDF(term)
+	}
+
+	if norm := vec.Norm(); norm > 0 {
+		for term := range vec {
+			vec[term] /= norm
+		}
+	}
+	return vec
+}
+
+// TopTerms 返回向量中权重最高的 n 个词
+func (v Vector) TopTerms(n int) []string {
+	terms := make([]string, 0, len(v))
+	for term := range v {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if v[terms[i]] != v[terms[j]] {
+			return v[terms[i]] > v[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
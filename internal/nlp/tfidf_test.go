@@ -0,0 +1,89 @@
+package nlp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+const epsilon = 1e-9
+
+func TestTFIDF(t *testing.T) {
+	model := FitTFIDF([][]string{
+		{"芯片", "出口"},
+		{"芯片", "芯片", "关税"},
+		{"关税", "股市"},
+	})
+
+	idfTests := []struct {
+		term string
+		want float64
+	}{
+		{"芯片", math.Log(4.0/3.0) + 1},
+		{"股市", math.Log(4.0/2.0) + 1},
+		{"未出现", math.Log(4.0/1.0) + 1},
+	}
+	for _, tt := range idfTests {
+		if got := model.IDF(tt.term); math.Abs(got-tt.want) > epsilon {
+			t.Errorf("IDF(%q) = %v, want %v", tt.term, got, tt.want)
+		}
+	}
+
+	vec := model.Transform([]string{"芯片", "芯片", "股市"})
+	if norm := vec.Norm(); math.Abs(norm-1) > epsilon {
+		t.Errorf("Transform() norm = %v, want 1", norm)
+	}
+	chip := (1 + math.Log(2)) * model.IDF("芯片")
+	stock := model.IDF("股市")
+	if got, want := vec["芯片"]/vec["股市"], chip/stock; math.Abs(got-want) > epsilon {
+		t.Errorf("Transform() weight ratio = %v, want %v", got, want)
+	}
+
+	if got := model.Transform(nil); len(got) != 0 {
+		t.Errorf("Transform(nil) = %v, want empty", got)
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Vector
+		want float64
+	}{
+		{"identical", Vector{"a": 1, "b": 2}, Vector{"a": 1, "b": 2}, 1},
+		{"scaled", Vector{"a": 1, "b": 1}, Vector{"a": 3, "b": 3}, 1},
+		{"orthogonal", Vector{"a": 1}, Vector{"b": 1}, 0},
+		{"partial overlap", Vector{"a": 1, "b": 1}, Vector{"a": 1}, 1 / math.Sqrt2},
+		{"empty", Vector{}, Vector{"a": 1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cosine(tt.a, tt.b); math.Abs(got-tt.want) > epsilon {
+				t.Errorf("Cosine() = %v, want %v", got, tt.want)
+			}
+			if got := Cosine(tt.b, tt.a); math.Abs(got-tt.want) > epsilon {
+				t.Errorf("Cosine() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVectorTopTerms(t *testing.T) {
+	vec := Vector{"关税": 0.5, "芯片": 0.9, "出口": 0.5, "股市": 0.1}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{1, []string{"芯片"}},
+		{3, []string{"芯片", "关税", "出口"}},
+		{10, []string{"芯片", "关税", "出口", "股市"}},
+		{0, []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := vec.TopTerms(tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TopTerms(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
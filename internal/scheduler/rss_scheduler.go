@@ -1,16 +1,23 @@
 package scheduler
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"time"
 
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
 	"github.com/EasyPeek/EasyPeek-backend/internal/services"
 	"github.com/robfig/cron/v3"
 )
 
 type RSSScheduler struct {
-	cron       *cron.Cron
-	rssService *services.RSSService
+	cron         *cron.Cron
+	rssService   *services.RSSService
+	eventService *services.EventService
+	viewCounter  *services.ViewCounter
+	summarizer   *services.SummaryService
+	jobService   *services.JobService
 }
 
 func NewRSSScheduler() *RSSScheduler {
@@ -18,8 +25,12 @@ func NewRSSScheduler() *RSSScheduler {
 	c := cron.New(cron.WithSeconds())
 	
 	return &RSSScheduler{
-		cron:       c,
-		rssService: services.NewRSSService(),
+		cron:         c,
+		rssService:   services.NewRSSService(),
+		eventService: services.NewEventService(),
+		viewCounter:  services.NewViewCounter(),
+		summarizer:   services.NewSummaryService(),
+		jobService:   services.NewJobService(),
 	}
 }
 
@@ -43,6 +54,30 @@ func (s *RSSScheduler) Start() error {
 		return err
 	}
 
+	// 每分钟将缓冲的浏览量回写数据库
+	_, err = s.cron.AddFunc("0 * * * * *", s.flushViewCounts)
+	if err != nil {
+		return err
+	}
+
+	// 每分钟结束执行实例已退出的后台任务
+	_, err = s.cron.AddFunc("30 * * * * *", s.recoverInterruptedJobs)
+	if err != nil {
+		return err
+	}
+
+	// 定期为未处理的新闻生成摘要
+	_, err = s.cron.AddFunc(fmt.Sprintf("@every %ds", summaryWorkerInterval()), s.processPendingNews)
+	if err != nil {
+		return err
+	}
+
+	// 定期按生命周期推进事件状态
+	_, err = s.cron.AddFunc(fmt.Sprintf("@every %dm", lifecycleInterval()), s.advanceEventLifecycle)
+	if err != nil {
+		return err
+	}
+
 	// 启动调度器
 	s.cron.Start()
 	log.Println("RSS scheduler started")
@@ -55,10 +90,83 @@ func (s *RSSScheduler) Start() error {
 
 // Stop 停止RSS调度器
 func (s *RSSScheduler) Stop() {
-	s.cron.Stop()
+	<-s.cron.Stop().Done()
+
+	// 停止前回写剩余的浏览量
+	s.flushViewCounts()
 	log.Println("RSS scheduler stopped")
 }
 
+// flushViewCounts 将缓冲的浏览量回写数据库
+func (s *RSSScheduler) flushViewCounts() {
+	stats, err := s.viewCounter.Flush()
+	if err != nil {
+		log.Printf("[VIEWS ERROR] Failed to flush view counts: %v", err)
+		return
+	}
+
+	if stats.Views > 0 {
+		log.Printf("[VIEWS] Flushed %d views (events: %d, news: %d)", stats.Views, stats.Events, stats.News)
+	}
+}
+
+// recoverInterruptedJobs 将心跳超时的后台任务标记为失败
+func (s *RSSScheduler) recoverInterruptedJobs() {
+	if err := s.jobService.RecoverInterruptedJobs(); err != nil {
+		log.Printf("[JOB ERROR] Failed to recover interrupted jobs: %v", err)
+	}
+}
+
+// processPendingNews 为未处理的新闻生成摘要
+func (s *RSSScheduler) processPendingNews() {
+	batchSize := 20
+	if config.AppConfig != nil && config.AppConfig.LLM.WorkerBatchSize > 0 {
+		batchSize = config.AppConfig.LLM.WorkerBatchSize
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(summaryWorkerInterval())*time.Second)
+	defer cancel()
+
+	result, err := s.summarizer.ProcessPendingNews(ctx, batchSize)
+	if err != nil {
+		log.Printf("[SUMMARY ERROR] Failed to process pending news: %v", err)
+		return
+	}
+
+	if result.Processed > 0 || result.Deferred > 0 {
+		log.Printf("[SUMMARY] Processed %d pending news, deferred %d", result.Processed, result.Deferred)
+	}
+}
+
+// advanceEventLifecycle 按时间和新闻活跃度推进事件状态
+func (s *RSSScheduler) advanceEventLifecycle() {
+	result, err := s.eventService.RunLifecycleTransitions()
+	if err != nil {
+		log.Printf("[LIFECYCLE ERROR] Failed to advance event lifecycle: %v", err)
+		return
+	}
+
+	for transition, count := range result.Transitions {
+		log.Printf("[LIFECYCLE] %s: %d events", transition, count)
+	}
+}
+
+// lifecycleInterval 事件状态推进任务的执行间隔（分钟）
+func lifecycleInterval() int {
+	if config.AppConfig != nil && config.AppConfig.Lifecycle.IntervalMinutes > 0 {
+		return config.AppConfig.Lifecycle.IntervalMinutes
+	}
+	return 10
+}
+
+// summaryWorkerInterval 后台摘要任务的执行间隔（秒）
+func summaryWorkerInterval() int {
+	if config.AppConfig != nil && config.AppConfig.LLM.WorkerIntervalSeconds > 0 {
+		return config.AppConfig.LLM.WorkerIntervalSeconds
+	}
+	return 60
+}
+
 // fetchAllRSSFeeds 抓取所有RSS源
 func (s *RSSScheduler) fetchAllRSSFeeds() {
 	log.Println("[RSS SCHEDULER] Starting scheduled RSS fetch...")
@@ -88,6 +196,43 @@ func (s *RSSScheduler) fetchAllRSSFeeds() {
 	
 	log.Printf("RSS fetch summary - New: %d, Updated: %d, Errors: %d", 
 		totalNew, totalUpdated, totalErrors)
+
+	// 抓取完成后将新新闻增量归入事件
+	s.generateEventsIncrementally()
+}
+
+// generateEventsIncrementally 增量生成事件
+func (s *RSSScheduler) generateEventsIncrementally() {
+	result, err := s.eventService.GenerateEventsIncrementally()
+	if err != nil {
+		log.Printf("[EVENT SCHEDULER ERROR] Incremental event generation failed: %v", err)
+		// 失败前已完成的事件仍需建立关系
+		if result == nil {
+			return
+		}
+	}
+
+	log.Printf("[EVENT SCHEDULER] Incremental event generation completed - News: %d, Attached: %d, Updated events: %d, New events: %d",
+		result.ProcessedNews, result.AttachedNews, len(result.UpdatedEvents), result.TotalEvents)
+
+	// 为新生成和有更新的事件自动建立关系
+	eventIDs := make([]uint, 0, len(result.GeneratedEvents)+len(result.UpdatedEvents))
+	for _, event := range result.GeneratedEvents {
+		eventIDs = append(eventIDs, event.ID)
+	}
+	for _, event := range result.UpdatedEvents {
+		eventIDs = append(eventIDs, event.ID)
+	}
+	if len(eventIDs) == 0 {
+		return
+	}
+	linkResult, err := s.eventService.AutoLinkEvents(context.Background(), eventIDs, nil)
+	if err != nil {
+		log.Printf("[EVENT SCHEDULER ERROR] Event auto-linking failed: %v", err)
+		return
+	}
+	log.Printf("[EVENT SCHEDULER] Event auto-linking completed - Checked: %d, Created: %d, Updated: %d",
+		linkResult.CheckedEvents, linkResult.Created, linkResult.Updated)
 }
 
 // cleanupOldNews 清理过期新闻
@@ -163,7 +163,7 @@ func (s *AdminService) GetSystemStats() (*SystemStats, error) {
 
 	// 事件统计
 	s.db.Model(&models.Event{}).Count(&stats.TotalEvents)
-	s.db.Model(&models.Event{}).Where("status = ?", "进行中").Count(&stats.ActiveEvents)
+	s.db.Model(&models.Event{}).Where("status = ?", models.EventStatusOngoing).Count(&stats.ActiveEvents)
 
 	// RSS源统计
 	s.db.Model(&models.RSSSource{}).Count(&stats.TotalRSSSources)
@@ -191,10 +191,10 @@ func (s *AdminService) GetAllEvents(page, pageSize int, filter AdminEventFilter)
 
 	// 应用过滤条件
 	if filter.Status != "" {
-		query = query.Where("status = ?", filter.Status)
+		query = query.Where("status = ?", normalizeStatusFilter(filter.Status))
 	}
 	if filter.Category != "" {
-		query = query.Where("category = ?", filter.Category)
+		query = categoryFilter(s.db, query, "event_categories", "event_id", "id", "category", filter.Category)
 	}
 	if filter.CreatedBy != 0 {
 		query = query.Where("created_by = ?", filter.CreatedBy)
@@ -233,7 +233,7 @@ func (s *AdminService) GetAllNews(page, pageSize int, filter AdminNewsFilter) ([
 		query = query.Where("status = ?", filter.Status)
 	}
 	if filter.Category != "" {
-		query = query.Where("category = ?", filter.Category)
+		query = categoryFilter(s.db, query, "news_categories", "news_id", "id", "category", filter.Category)
 	}
 	if filter.SourceType != "" {
 		query = query.Where("source_type = ?", filter.SourceType)
@@ -0,0 +1,498 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+)
+
+const (
+	// classifierReloadInterval 检查是否有新训练的分类模型的间隔
+	classifierReloadInterval = time.Minute
+	// classifierMinTermCount 训练集中出现次数少于此值的词不进入词表
+	classifierMinTermCount = 2
+	// maxPredictionCandidates 预测结果中返回的候选类别数
+	maxPredictionCandidates = 5
+	// classifierProgressStep 训练时每分词这么多条新闻汇报一次进度
+	classifierProgressStep = 200
+	// uncategorized 没有分类的新闻在生成事件时归入的类别
+	uncategorized = "未分类"
+)
+
+// classifierOptions 分类模型的参数，来自配置文件，未配置的项使用默认值
+type classifierOptions struct {
+	minConfidence   float64
+	minSamples      int
+	holdoutPercent  int
+	maxTrainingNews int
+}
+
+func loadClassifierOptions() classifierOptions {
+	opts := classifierOptions{
+		minConfidence:   0.6,
+		minSamples:      20,
+		holdoutPercent:  20,
+		maxTrainingNews: 20000,
+	}
+
+	if config.AppConfig == nil {
+		return opts
+	}
+
+	cfg := config.AppConfig.Classifier
+	if cfg.MinConfidence > 0 {
+		opts.minConfidence = cfg.MinConfidence
+	}
+	if cfg.MinSamples > 0 {
+		opts.minSamples = cfg.MinSamples
+	}
+	if cfg.HoldoutPercent > 0 && cfg.HoldoutPercent < 100 {
+		opts.holdoutPercent = cfg.HoldoutPercent
+	}
+	if cfg.MaxTrainingNews > 0 {
+		opts.maxTrainingNews = cfg.MaxTrainingNews
+	}
+	return opts
+}
+
+// classifierCache 最新的分类模型，定期检查是否有新版本，词库变更时重建分词器
+var classifierCache struct {
+	mu        sync.Mutex
+	pipeline  *textPipeline
+	version   uint
+	model     *nlp.NaiveBayes
+	modelID   uint
+	checkedAt time.Time
+}
+
+// CategoryClassifierService 新闻分类服务
+// 用已有分类的新闻训练多项式朴素贝叶斯模型，为没有分类或只有 RSS 源默认分类的新闻预测分类
+type CategoryClassifierService struct {
+	db   *gorm.DB
+	opts classifierOptions
+}
+
+func NewCategoryClassifierService() *CategoryClassifierService {
+	return &CategoryClassifierService{
+		db:   database.GetDB(),
+		opts: loadClassifierOptions(),
+	}
+}
+
+// labeledNews 参与训练的新闻及其分类
+type labeledNews struct {
+	tokens  []string
+	label   string
+	holdout bool
+}
+
+// Train 用最近的已分类新闻训练新的分类模型，留出一部分新闻评估准确率并生成混淆矩阵
+// 新闻按 ID 划分训练集和留出集，同样的数据重新训练得到相同的划分；分词时汇报进度，ctx 取消时不保存模型
+func (s *CategoryClassifierService) Train(ctx context.Context, operatorID uint, progress func(done, total int, partial *models.CategoryModelResponse)) (*models.CategoryModelResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	samples, err := s.trainingSamples(ctx, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只保留新闻数足够的类别
+	supports := make(map[string]int)
+	for _, sample := range samples {
+		supports[sample.label]++
+	}
+	labels := make([]string, 0, len(supports))
+	for label, count := range supports {
+		if count >= s.opts.minSamples {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) < 2 {
+		return nil, errors.New("not enough categorized news to train classifier")
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if supports[labels[i]] != supports[labels[j]] {
+			return supports[labels[i]] > supports[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+	index := make(map[string]int, len(labels))
+	for i, label := range labels {
+		index[label] = i
+	}
+
+	trainDocs := make([][]string, 0, len(samples))
+	trainLabels := make([]string, 0, len(samples))
+	testSamples := make([]labeledNews, 0)
+	for _, sample := range samples {
+		if _, ok := index[sample.label]; !ok {
+			continue
+		}
+		if sample.holdout {
+			testSamples = append(testSamples, sample)
+			continue
+		}
+		trainDocs = append(trainDocs, sample.tokens)
+		trainLabels = append(trainLabels, sample.label)
+	}
+	model := nlp.TrainNaiveBayes(trainDocs, trainLabels, 1, classifierMinTermCount)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 在留出集上统计混淆矩阵，行为原有分类，列为预测分类
+	confusion := models.ConfusionMatrix{Labels: labels, Matrix: make([][]int, len(labels))}
+	for i := range confusion.Matrix {
+		confusion.Matrix[i] = make([]int, len(labels))
+	}
+	correct := 0
+	for _, sample := range testSamples {
+		predictions := model.Predict(sample.tokens)
+		if len(predictions) == 0 {
+			continue
+		}
+		actual, predicted := index[sample.label], index[predictions[0].Label]
+		confusion.Matrix[actual][predicted]++
+		if actual == predicted {
+			correct++
+		}
+	}
+	report := models.CategoryModelReport{Metrics: categoryMetrics(confusion), Confusion: confusion}
+
+	record := &models.CategoryModel{
+		Categories: sliceToJSON(labels),
+		TrainSize:  len(trainDocs),
+		TestSize:   len(testSamples),
+		TrainedBy:  &operatorID,
+	}
+	if len(testSamples) > 0 {
+		record.Accuracy = float64(correct) / float64(len(testSamples))
+	}
+	for _, metrics := range report.Metrics {
+		record.MacroF1 += metrics.F1 / float64(len(report.Metrics))
+	}
+	encodedReport, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	encodedModel, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	record.Report = string(encodedReport)
+	record.Model = string(encodedModel)
+	if err := s.db.Create(record).Error; err != nil {
+		return nil, err
+	}
+
+	// 立即切换到新模型，不等待下一次检查
+	classifierCache.mu.Lock()
+	classifierCache.model = model
+	classifierCache.modelID = record.ID
+	classifierCache.checkedAt = time.Now()
+	classifierCache.mu.Unlock()
+
+	return s.toModelResponse(record, report), nil
+}
+
+// GetLatestModel 获取最新的分类模型及其在留出集上的评估结果
+func (s *CategoryClassifierService) GetLatestModel() (*models.CategoryModelResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	var record models.CategoryModel
+	if err := s.db.Omit("model").Order("id DESC").First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("classifier not trained")
+		}
+		return nil, err
+	}
+
+	var report models.CategoryModelReport
+	if err := json.Unmarshal([]byte(record.Report), &report); err != nil {
+		return nil, err
+	}
+	return s.toModelResponse(&record, report), nil
+}
+
+// Predict 预测一段新闻文本的分类
+func (s *CategoryClassifierService) Predict(title, content string) (*models.CategoryPredictionResponse, error) {
+	pipeline, model, modelID := s.current()
+	if model == nil {
+		return nil, errors.New("classifier not trained")
+	}
+
+	predictions := model.Predict(pipeline.newsTokens(models.News{Title: title, Content: content}))
+	response := &models.CategoryPredictionResponse{
+		ModelID:    modelID,
+		Candidates: make([]models.CategoryProbability, 0, maxPredictionCandidates),
+	}
+	for i, prediction := range predictions {
+		if i >= maxPredictionCandidates {
+			break
+		}
+		response.Candidates = append(response.Candidates, models.CategoryProbability{
+			Category:    prediction.Label,
+			Probability: prediction.Probability,
+		})
+	}
+	if len(predictions) > 0 {
+		response.Category = predictions[0].Label
+		response.Confidence = predictions[0].Probability
+		response.Confident = response.Confidence >= s.opts.minConfidence
+	}
+	return response, nil
+}
+
+// ClassifyNews 记录新闻的预测分类；fallback 为 true 表示新闻的分类只是默认值，此时置信度足够高的预测分类会替换它
+// 调用前应设置 SourceCategory，还没有训练模型时保持原样
+func (s *CategoryClassifierService) ClassifyNews(news *models.News, fallback bool) {
+	pipeline, model, _ := s.current()
+	if model == nil {
+		return
+	}
+
+	predictions := model.Predict(pipeline.newsTokens(*news))
+	if len(predictions) == 0 {
+		return
+	}
+	news.PredictedCategory = predictions[0].Label
+	news.CategoryConfidence = predictions[0].Probability
+	if fallback && news.CategoryConfidence >= s.opts.minConfidence {
+		news.Category = news.PredictedCategory
+	}
+}
+
+// ReclassifyAllNews 用最新的模型重新预测全部新闻的分类
+// 新闻的分类为空、“未分类”或 RSS 源的默认分类时采用置信度足够高的预测分类，否则恢复为来源给出的分类
+func (s *CategoryClassifierService) ReclassifyAllNews(ctx context.Context, progress func(done, total int, partial *models.ClassifyNewsResult)) (*models.ClassifyNewsResult, error) {
+	if _, model, _ := s.current(); model == nil {
+		return nil, errors.New("classifier not trained")
+	}
+
+	start := time.Now()
+	result := &models.ClassifyNewsResult{}
+
+	sourceCategory, err := s.sourceCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := s.db.Model(&models.News{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	err = forEachNewsBatch(ctx, s.db, retagBatchSize, func(batch []models.News) error {
+		for i := range batch {
+			news := &batch[i]
+
+			backfillSourceCategory(news)
+			before := news.Category
+			news.Category = news.SourceCategory
+			s.ClassifyNews(news, isFallbackCategory(news, sourceCategory))
+			if news.CategoryConfidence >= s.opts.minConfidence {
+				result.ConfidentPredict++
+			}
+			if err := s.db.Model(news).UpdateColumns(map[string]interface{}{
+				"category":            news.Category,
+				"source_category":     news.SourceCategory,
+				"predicted_category":  news.PredictedCategory,
+				"category_confidence": news.CategoryConfidence,
+			}).Error; err != nil {
+				return err
+			}
+			if news.Category != before {
+				result.ChangedCategory++
+				if err := syncNewsCategories(s.db, news); err != nil {
+					return err
+				}
+			}
+			result.ProcessedNews++
+		}
+
+		if progress != nil {
+			result.Duration = time.Since(start).String()
+			progress(result.ProcessedNews, int(total), result)
+		}
+		return nil
+	})
+
+	result.Duration = time.Since(start).String()
+	return result, err
+}
+
+// trainingSamples 加载最近的已分类新闻作为训练数据
+// 分类为空、“未分类”、包含多个分类或只是 RSS 源默认分类的新闻不参与训练，多主题的源会让默认分类成为错误的标签
+func (s *CategoryClassifierService) trainingSamples(ctx context.Context, progress func(done, total int, partial *models.CategoryModelResponse)) ([]labeledNews, error) {
+	sourceCategory, err := s.sourceCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	var recent []models.News
+	if err := s.db.Select("id", "title", "summary", "description", "content", "category", "source_category", "predicted_category", "source_type", "rss_source_id").
+		Where("COALESCE(NULLIF(source_category, ''), category, '') NOT IN ?", []string{"", uncategorized}).
+		Order("id DESC").
+		Limit(s.opts.maxTrainingNews).
+		Find(&recent).Error; err != nil {
+		return nil, err
+	}
+
+	pipeline, _, _ := s.current()
+	samples := make([]labeledNews, 0, len(recent))
+	for i := range recent {
+		if i%classifierProgressStep == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if progress != nil {
+				progress(i, len(recent), nil)
+			}
+		}
+
+		news := &recent[i]
+		backfillSourceCategory(news)
+		label := strings.TrimSpace(news.SourceCategory)
+		if label == "" || strings.Contains(label, ",") || isFallbackCategory(news, sourceCategory) {
+			continue
+		}
+		samples = append(samples, labeledNews{
+			tokens:  pipeline.newsTokens(*news),
+			label:   label,
+			holdout: int(news.ID%100) < s.opts.holdoutPercent,
+		})
+	}
+	return samples, nil
+}
+
+// sourceCategories RSS 源的默认分类
+func (s *CategoryClassifierService) sourceCategories() (map[uint]string, error) {
+	var sources []models.RSSSource
+	if err := s.db.Select("id", "category").Find(&sources).Error; err != nil {
+		return nil, err
+	}
+	categories := make(map[uint]string, len(sources))
+	for _, source := range sources {
+		categories[source.ID] = source.Category
+	}
+	return categories, nil
+}
+
+// current 返回当前的分词器和最新的分类模型，还没有训练模型时模型为空
+func (s *CategoryClassifierService) current() (*textPipeline, *nlp.NaiveBayes, uint) {
+	var version uint
+	if taxonomy, err := NewTaxonomyService().CurrentTaxonomy(); err == nil {
+		version = taxonomy.Version
+	}
+
+	classifierCache.mu.Lock()
+	defer classifierCache.mu.Unlock()
+	if classifierCache.pipeline == nil || classifierCache.version != version {
+		classifierCache.pipeline = newTextPipeline(loadClusteringOptions())
+		classifierCache.version = version
+	}
+	if s.db != nil && time.Since(classifierCache.checkedAt) >= classifierReloadInterval {
+		classifierCache.checkedAt = time.Now()
+		if err := s.reloadModel(); err != nil {
+			log.Printf("[CLASSIFIER WARNING] failed to load category model: %v", err)
+		}
+	}
+	return classifierCache.pipeline, classifierCache.model, classifierCache.modelID
+}
+
+// reloadModel 有新训练的模型时加载，调用方需持有 classifierCache.mu
+func (s *CategoryClassifierService) reloadModel() error {
+	var latestID uint
+	if err := s.db.Model(&models.CategoryModel{}).Select("COALESCE(MAX(id), 0)").Scan(&latestID).Error; err != nil {
+		return err
+	}
+	if latestID == 0 || latestID == classifierCache.modelID {
+		return nil
+	}
+
+	var record models.CategoryModel
+	if err := s.db.Select("id", "model").First(&record, latestID).Error; err != nil {
+		return err
+	}
+	var model nlp.NaiveBayes
+	if err := json.Unmarshal([]byte(record.Model), &model); err != nil {
+		return err
+	}
+	classifierCache.model = &model
+	classifierCache.modelID = record.ID
+	return nil
+}
+
+func (s *CategoryClassifierService) toModelResponse(record *models.CategoryModel, report models.CategoryModelReport) *models.CategoryModelResponse {
+	return &models.CategoryModelResponse{
+		ID:            record.ID,
+		Categories:    jsonToSlice(record.Categories),
+		TrainSize:     record.TrainSize,
+		TestSize:      record.TestSize,
+		Accuracy:      record.Accuracy,
+		MacroF1:       record.MacroF1,
+		MinConfidence: s.opts.minConfidence,
+		Metrics:       report.Metrics,
+		Confusion:     report.Confusion,
+		TrainedBy:     record.TrainedBy,
+		CreatedAt:     record.CreatedAt,
+	}
+}
+
+// backfillSourceCategory 添加分类预测之前的新闻没有记录来源分类，原有的分类就是来源给出的分类
+func backfillSourceCategory(news *models.News) {
+	if news.SourceCategory == "" && news.PredictedCategory == "" {
+		news.SourceCategory = news.Category
+	}
+}
+
+// isFallbackCategory 新闻的分类只是默认值：为空、“未分类”，或 RSS 条目没有自带分类而使用了源的分类
+func isFallbackCategory(news *models.News, sourceCategory map[uint]string) bool {
+	category := strings.TrimSpace(news.SourceCategory)
+	if category == "" || category == uncategorized {
+		return true
+	}
+	return news.SourceType == models.NewsTypeRSS && news.RSSSourceID != nil &&
+		category == sourceCategory[*news.RSSSourceID]
+}
+
+// categoryMetrics 由混淆矩阵计算各类别的精确率、召回率和 F1
+func categoryMetrics(confusion models.ConfusionMatrix) []models.CategoryMetrics {
+	metrics := make([]models.CategoryMetrics, len(confusion.Labels))
+	for i, label := range confusion.Labels {
+		var support, predicted int
+		for j := range confusion.Labels {
+			support += confusion.Matrix[i][j]
+			predicted += confusion.Matrix[j][i]
+		}
+		correct := confusion.Matrix[i][i]
+
+		m := models.CategoryMetrics{Category: label, Support: support}
+		if predicted > 0 {
+			m.Precision = float64(correct) / float64(predicted)
+		}
+		if support > 0 {
+			m.Recall = float64(correct) / float64(support)
+		}
+		if m.Precision+m.Recall > 0 {
+			m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+		}
+		metrics[i] = m
+	}
+	return metrics
+}
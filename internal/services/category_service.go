@@ -0,0 +1,916 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// categoryCheckInterval 缓存的分类与数据库核对的间隔，保证多实例部署时其他实例的变更能被感知
+const categoryCheckInterval = time.Minute
+
+// defaultCategory 初始分类
+type defaultCategory struct {
+	name    string
+	slug    string
+	en      string
+	aliases []string
+}
+
+// defaultCategories 首次启动时写入的分类，之后由管理员维护
+var defaultCategories = []defaultCategory{
+	{"政治", "politics", "Politics", nil},
+	{"国际", "world", "World", []string{"国际新闻", "International"}},
+	{"经济", "economy", "Economy", []string{"财经", "Business", "Finance"}},
+	{"科技", "technology", "Technology", []string{"Tech", "Science & Technology"}},
+	{"军事", "military", "Military", []string{"Defense"}},
+	{"社会", "society", "Society", nil},
+	{"体育", "sports", "Sports", []string{"Sport"}},
+	{"文化", "culture", "Culture", []string{"Arts"}},
+	{"娱乐", "entertainment", "Entertainment", nil},
+	{"健康", "health", "Health", []string{"医疗"}},
+	{"教育", "education", "Education", nil},
+	{"环境", "environment", "Environment", []string{"环保", "Climate"}},
+	{"能源", "energy", "Energy", nil},
+}
+
+// categoryIndex 全部分类及其识别名称，用于把分类字符串对应到分类和展开子分类
+type categoryIndex struct {
+	byKey    map[string]uint // 规范化的名称、slug、各语言名称和别名 -> 分类ID
+	children map[uint][]uint
+}
+
+// categoryStore 进程内缓存的分类索引，本实例变更后立即失效，其他实例的变更定期重新加载
+var categoryStore struct {
+	mu       sync.Mutex
+	index    *categoryIndex
+	loadedAt time.Time
+}
+
+// CategoryService 分类管理服务
+// 新闻和事件保留原有的分类字符串，同时按分类的名称和别名关联到规范化的分类，筛选和统计都使用关联
+type CategoryService struct {
+	db *gorm.DB
+}
+
+func NewCategoryService() *CategoryService {
+	return &CategoryService{
+		db: database.GetDB(),
+	}
+}
+
+// SeedDefaults 分类表为空时写入默认分类，并从已有的分类字符串迁移
+func (s *CategoryService) SeedDefaults() error {
+	if s.db == nil {
+		return errors.New("database connection not initialized")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Category{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i, def := range defaultCategories {
+			category := models.Category{
+				Slug:      def.slug,
+				Name:      def.name,
+				Names:     categoryNamesJSON(map[string]string{"en": def.en}),
+				SortOrder: (i + 1) * 10,
+				IsActive:  true,
+			}
+			if err := tx.Create(&category).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(categoryAliases(&category, def.aliases)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to seed categories: %w", err)
+	}
+	invalidateCategories()
+
+	result, err := s.MigrateLegacyCategories()
+	if err != nil {
+		return fmt.Errorf("failed to migrate categories: %w", err)
+	}
+	log.Printf("Default categories seeded, %d categories created from existing data", len(result.CreatedCategories))
+	return nil
+}
+
+// MigrateLegacyCategories 为无法识别的分类字符串新建分类，然后重新关联全部新闻和事件
+// 事件、RSS 源和只有单个分类的新闻的分类字符串会新建分类；RSS 条目的多个分类只关联能识别的部分，不新建分类
+func (s *CategoryService) MigrateLegacyCategories() (*models.MigrateCategoriesResult, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	start := time.Now()
+	result := &models.MigrateCategoriesResult{CreatedCategories: []string{}}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var names []string
+		if err := tx.Raw(`
+			SELECT COALESCE(TRIM(category), '') FROM events WHERE deleted_at IS NULL
+			UNION SELECT COALESCE(TRIM(category), '') FROM rss_sources
+			UNION SELECT COALESCE(TRIM(category), '') FROM news WHERE deleted_at IS NULL AND POSITION(',' IN category) = 0
+		`).Scan(&names).Error; err != nil {
+			return err
+		}
+		sort.Strings(names)
+
+		var maxOrder int
+		if err := tx.Model(&models.Category{}).Select("COALESCE(MAX(sort_order), 0)").Scan(&maxOrder).Error; err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			key := categoryKey(name)
+			if key == "" || name == uncategorized || strings.Contains(name, ",") {
+				continue
+			}
+			var exists int64
+			if err := tx.Model(&models.CategoryAlias{}).Where("key = ?", key).Count(&exists).Error; err != nil {
+				return err
+			}
+			if exists > 0 {
+				continue
+			}
+
+			slug, err := uniqueCategorySlug(tx, slugify(name), 0)
+			if err != nil {
+				return err
+			}
+			maxOrder += 10
+			category := models.Category{Slug: slug, Name: name, SortOrder: maxOrder, IsActive: true}
+			if err := tx.Create(&category).Error; err != nil {
+				return err
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(categoryAliases(&category, nil)).Error; err != nil {
+				return err
+			}
+			result.CreatedCategories = append(result.CreatedCategories, name)
+		}
+
+		var err error
+		result.LinkedNews, result.LinkedEvents, err = relinkCategories(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateCategories()
+	result.Duration = time.Since(start).String()
+	return result, nil
+}
+
+// GetCategories 获取分类列表，默认按层级返回树；admin 为 false 时不返回停用的分类
+func (s *CategoryService) GetCategories(query *models.CategoryQueryRequest, admin bool) ([]models.CategoryResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	var categories []models.Category
+	db := s.db.Preload("Aliases", "kind = ?", models.CategoryAliasAlias)
+	if !admin || !query.IncludeInactive {
+		db = db.Where("is_active = ?", true)
+	}
+	if err := db.Order("sort_order ASC, name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	newsCounts, err := categoryCounts(s.db, "news_categories", "news_id", "news")
+	if err != nil {
+		return nil, err
+	}
+	eventCounts, err := categoryCounts(s.db, "event_categories", "event_id", "events")
+	if err != nil {
+		return nil, err
+	}
+
+	// 父分类不在列表中（停用）时子分类作为顶层返回
+	included := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		included[category.ID] = true
+	}
+	children := make(map[uint][]*models.Category)
+	roots := make([]*models.Category, 0)
+	for i := range categories {
+		category := &categories[i]
+		if category.ParentID != nil && included[*category.ParentID] {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		} else {
+			roots = append(roots, category)
+		}
+	}
+
+	var build func(category *models.Category) models.CategoryResponse
+	build = func(category *models.Category) models.CategoryResponse {
+		response := convertToCategoryResponse(category, query.Lang)
+		response.NewsCount = newsCounts[category.ID]
+		response.EventCount = eventCounts[category.ID]
+		for _, child := range children[category.ID] {
+			response.Children = append(response.Children, build(child))
+		}
+		return response
+	}
+
+	responses := make([]models.CategoryResponse, 0, len(categories))
+	for _, root := range roots {
+		responses = append(responses, build(root))
+	}
+	if !query.Flat {
+		return responses, nil
+	}
+
+	// 平铺列表按树的先序排列
+	flat := make([]models.CategoryResponse, 0, len(categories))
+	var walk func(list []models.CategoryResponse)
+	walk = func(list []models.CategoryResponse) {
+		for _, response := range list {
+			subtree := response.Children
+			response.Children = nil
+			flat = append(flat, response)
+			walk(subtree)
+		}
+	}
+	walk(responses)
+	return flat, nil
+}
+
+// CreateCategory 创建分类，名称、slug、各语言名称和别名都不能与其他分类重复
+func (s *CategoryService) CreateCategory(req *models.CreateCategoryRequest) (*models.CategoryResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	category := models.Category{
+		Name:      strings.TrimSpace(req.Name),
+		Names:     categoryNamesJSON(req.Names),
+		SortOrder: req.SortOrder,
+		IsActive:  true,
+	}
+	slug := req.Slug
+	if slug == "" {
+		slug = req.Names["en"]
+	}
+	if slug == "" {
+		slug = category.Name
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if req.ParentID != nil && *req.ParentID > 0 {
+			if err := checkCategoryParent(tx, 0, *req.ParentID); err != nil {
+				return err
+			}
+			category.ParentID = req.ParentID
+		}
+
+		var err error
+		if category.Slug, err = uniqueCategorySlug(tx, slugify(slug), 0); err != nil {
+			return err
+		}
+		if req.Slug != "" && category.Slug != slugify(req.Slug) {
+			return errors.New("category slug already exists")
+		}
+
+		aliases := categoryAliases(&category, req.Aliases)
+		if err := checkCategoryAliases(tx, 0, aliases); err != nil {
+			return err
+		}
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+		for i := range aliases {
+			aliases[i].CategoryID = category.ID
+		}
+		if err := tx.Create(&aliases).Error; err != nil {
+			return err
+		}
+
+		return relinkCategoryKeys(tx, aliasKeys(aliases))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateCategories()
+	return s.getCategory(category.ID)
+}
+
+// UpdateCategory 更新分类，名称或别名变化后重新关联新闻和事件
+func (s *CategoryService) UpdateCategory(id uint, req *models.UpdateCategoryRequest) (*models.CategoryResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var category models.Category
+		if err := tx.Preload("Aliases").First(&category, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("category not found")
+			}
+			return err
+		}
+
+		aliases := make([]string, 0)
+		for _, alias := range category.Aliases {
+			if alias.Kind == models.CategoryAliasAlias {
+				aliases = append(aliases, alias.Alias)
+			}
+		}
+		keysChanged := false
+
+		if name := strings.TrimSpace(req.Name); name != "" && name != category.Name {
+			category.Name = name
+			keysChanged = true
+		}
+		if req.Slug != "" {
+			slug := slugify(req.Slug)
+			if slug == "" {
+				return errors.New("invalid category slug")
+			}
+			if slug != category.Slug {
+				var count int64
+				tx.Model(&models.Category{}).Where("slug = ? AND id <> ?", slug, category.ID).Count(&count)
+				if count > 0 {
+					return errors.New("category slug already exists")
+				}
+				category.Slug = slug
+				keysChanged = true
+			}
+		}
+		if req.Names != nil {
+			category.Names = categoryNamesJSON(req.Names)
+			keysChanged = true
+		}
+		if req.Aliases != nil {
+			aliases = req.Aliases
+			keysChanged = true
+		}
+		if req.ParentID != nil {
+			if *req.ParentID == 0 {
+				category.ParentID = nil
+			} else {
+				if err := checkCategoryParent(tx, category.ID, *req.ParentID); err != nil {
+					return err
+				}
+				category.ParentID = req.ParentID
+			}
+		}
+		if req.SortOrder != nil {
+			category.SortOrder = *req.SortOrder
+		}
+		if req.IsActive != nil {
+			category.IsActive = *req.IsActive
+		}
+
+		if err := tx.Omit("Aliases").Save(&category).Error; err != nil {
+			return err
+		}
+		if !keysChanged {
+			return nil
+		}
+
+		rows := categoryAliases(&category, aliases)
+		if err := checkCategoryAliases(tx, category.ID, rows); err != nil {
+			return err
+		}
+		if err := tx.Where("category_id = ?", category.ID).Delete(&models.CategoryAlias{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+		return relinkCategoryKeys(tx, append(aliasKeys(category.Aliases), aliasKeys(rows)...))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateCategories()
+	return s.getCategory(id)
+}
+
+// DeleteCategory 删除分类及其关联，有子分类时不能删除
+func (s *CategoryService) DeleteCategory(id uint) error {
+	if s.db == nil {
+		return errors.New("database connection not initialized")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var category models.Category
+		if err := tx.First(&category, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("category not found")
+			}
+			return err
+		}
+
+		var children int64
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return errors.New("category has subcategories")
+		}
+
+		var keys []string
+		if err := tx.Model(&models.CategoryAlias{}).Where("category_id = ?", id).Pluck("key", &keys).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("category_id = ?", id).Delete(&models.CategoryAlias{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&category).Error; err != nil {
+			return err
+		}
+		// 原来属于该分类的 RSS 新闻可能改为关联源的默认分类
+		return relinkCategoryKeys(tx, keys)
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidateCategories()
+	return nil
+}
+
+// getCategory 获取单个分类的管理视图
+func (s *CategoryService) getCategory(id uint) (*models.CategoryResponse, error) {
+	var category models.Category
+	if err := s.db.Preload("Aliases", "kind = ?", models.CategoryAliasAlias).First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	response := convertToCategoryResponse(&category, "")
+	if err := s.db.Model(&models.NewsCategory{}).Where("category_id = ?", id).Count(&response.NewsCount).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&models.EventCategory{}).Where("category_id = ?", id).Count(&response.EventCount).Error; err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// relinkCategories 按当前的分类别名重新关联全部新闻和事件
+// 分类字符串以逗号分隔，每一部分单独识别；RSS 新闻的分类都无法识别时关联源的默认分类
+func relinkCategories(tx *gorm.DB) (int64, int64, error) {
+	if err := relinkCategoryLinks(tx, nil, nil); err != nil {
+		return 0, 0, err
+	}
+
+	var linkedNews, linkedEvents int64
+	if err := tx.Model(&models.NewsCategory{}).Distinct("news_id").Count(&linkedNews).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := tx.Model(&models.EventCategory{}).Distinct("event_id").Count(&linkedEvents).Error; err != nil {
+		return 0, 0, err
+	}
+	return linkedNews, linkedEvents, nil
+}
+
+// relinkCategoryKeys 分类的识别名称变化后，只重新关联分类字符串或 RSS 源默认分类命中 keys 的新闻和事件
+// keys 应同时包含变更前后的识别名称，原来关联该分类和新近能关联该分类的记录都会被重新关联
+func relinkCategoryKeys(tx *gorm.DB, keys []string) error {
+	keys = uniqueCategoryKeys(keys)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	news := tx.Raw(`
+		SELECT n.id FROM news n
+		WHERE n.deleted_at IS NULL AND (
+			EXISTS (SELECT 1 FROM unnest(string_to_array(n.category, ',')) AS part(name) WHERE LOWER(TRIM(part.name)) IN ?)
+			OR n.rss_source_id IN (SELECT id FROM rss_sources WHERE LOWER(TRIM(category)) IN ?)
+		)`, keys, keys)
+	events := tx.Raw(`
+		SELECT e.id FROM events e
+		WHERE e.deleted_at IS NULL
+		AND EXISTS (SELECT 1 FROM unnest(string_to_array(e.category, ',')) AS part(name) WHERE LOWER(TRIM(part.name)) IN ?)`, keys)
+	return relinkCategoryLinks(tx, news, events)
+}
+
+// relinkCategoryLinks 删除并重新生成 newsIDs 和 eventIDs 子查询范围内的分类关联，子查询为 nil 时处理全部记录
+func relinkCategoryLinks(tx *gorm.DB, newsIDs, eventIDs *gorm.DB) error {
+	deleteNews, newsScope, newsArgs := "DELETE FROM news_categories", "", []interface{}{}
+	if newsIDs != nil {
+		deleteNews += " WHERE news_id IN (?)"
+		newsScope, newsArgs = " AND n.id IN (?)", []interface{}{newsIDs}
+	}
+	deleteEvents, eventScope, eventArgs := "DELETE FROM event_categories", "", []interface{}{}
+	if eventIDs != nil {
+		deleteEvents += " WHERE event_id IN (?)"
+		eventScope, eventArgs = " AND e.id IN (?)", []interface{}{eventIDs}
+	}
+
+	if err := tx.Exec(deleteNews, newsArgs...).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(deleteEvents, eventArgs...).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`
+		INSERT INTO news_categories (news_id, category_id)
+		SELECT DISTINCT n.id, a.category_id
+		FROM news n
+		CROSS JOIN LATERAL unnest(string_to_array(n.category, ',')) AS part(name)
+		JOIN category_aliases a ON a.key = LOWER(TRIM(part.name))
+		WHERE n.deleted_at IS NULL`+newsScope, newsArgs...).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`
+		INSERT INTO news_categories (news_id, category_id)
+		SELECT n.id, a.category_id
+		FROM news n
+		JOIN rss_sources r ON r.id = n.rss_source_id
+		JOIN category_aliases a ON a.key = LOWER(TRIM(r.category))
+		WHERE n.deleted_at IS NULL AND n.source_type = ?
+		AND NOT EXISTS (SELECT 1 FROM news_categories nc WHERE nc.news_id = n.id)`+newsScope,
+		append([]interface{}{models.NewsTypeRSS}, newsArgs...)...).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`
+		INSERT INTO event_categories (event_id, category_id)
+		SELECT DISTINCT e.id, a.category_id
+		FROM events e
+		CROSS JOIN LATERAL unnest(string_to_array(e.category, ',')) AS part(name)
+		JOIN category_aliases a ON a.key = LOWER(TRIM(part.name))
+		WHERE e.deleted_at IS NULL`+eventScope, eventArgs...).Error
+}
+
+// syncNewsCategories 按新闻当前的分类字符串重新关联分类，RSS 条目的分类都无法识别时关联源的默认分类
+func syncNewsCategories(tx *gorm.DB, news *models.News) error {
+	ids := loadCategoryIndex(tx).resolve(news.Category)
+	if len(ids) == 0 && news.SourceType == models.NewsTypeRSS && news.RSSSourceID != nil {
+		var sourceCategories []string
+		if err := tx.Model(&models.RSSSource{}).Where("id = ?", *news.RSSSourceID).Pluck("category", &sourceCategories).Error; err != nil {
+			return err
+		}
+		if len(sourceCategories) > 0 {
+			ids = loadCategoryIndex(tx).resolve(sourceCategories[0])
+		}
+	}
+
+	if err := tx.Where("news_id = ?", news.ID).Delete(&models.NewsCategory{}).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	links := make([]models.NewsCategory, 0, len(ids))
+	for _, id := range ids {
+		links = append(links, models.NewsCategory{NewsID: news.ID, CategoryID: id})
+	}
+	return tx.Create(&links).Error
+}
+
+// syncEventCategories 按事件当前的分类字符串重新关联分类
+func syncEventCategories(tx *gorm.DB, eventIDs ...uint) error {
+	eventIDs = uniqueIDs(eventIDs)
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	var events []models.Event
+	if err := tx.Select("id", "category").Where("id IN ?", eventIDs).Find(&events).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("event_id IN ?", eventIDs).Delete(&models.EventCategory{}).Error; err != nil {
+		return err
+	}
+
+	index := loadCategoryIndex(tx)
+	links := make([]models.EventCategory, 0, len(events))
+	for _, event := range events {
+		for _, id := range index.resolve(event.Category) {
+			links = append(links, models.EventCategory{EventID: event.ID, CategoryID: id})
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return tx.Create(&links).Error
+}
+
+// categoryFilter 按分类筛选，value 可以是分类的 slug、名称、其他语言名称或别名，包含子分类
+// 无法识别的分类按原有的分类字符串精确匹配
+func categoryFilter(db, query *gorm.DB, linkTable, ownerColumn, idColumn, column, value string) *gorm.DB {
+	index := loadCategoryIndex(db)
+	id, ok := index.byKey[categoryKey(value)]
+	if !ok {
+		return query.Where(column+" = ?", value)
+	}
+	return query.Where(idColumn+" IN (?)", db.Table(linkTable).Select(ownerColumn).Where("category_id IN ?", index.descendants(id)))
+}
+
+// categoriesFilter 满足任意一个分类的条件，用法与 categoryFilter 相同，返回的条件可以用于 Where 或 Not
+func categoriesFilter(db *gorm.DB, linkTable, ownerColumn, idColumn, column string, values []string) *gorm.DB {
+	index := loadCategoryIndex(db)
+	ids := make([]uint, 0)
+	unknown := make([]string, 0)
+	for _, value := range values {
+		id, ok := index.byKey[categoryKey(value)]
+		if !ok {
+			unknown = append(unknown, value)
+			continue
+		}
+		ids = append(ids, index.descendants(id)...)
+	}
+
+	condition := db.Session(&gorm.Session{NewDB: true})
+	if len(ids) > 0 {
+		condition = condition.Or(idColumn+" IN (?)", db.Table(linkTable).Select(ownerColumn).Where("category_id IN ?", uniqueIDs(ids)))
+	}
+	if len(unknown) > 0 {
+		condition = condition.Or(column+" IN ?", unknown)
+	}
+	return condition
+}
+
+// categoryCounts 各分类关联的未删除的新闻或事件数
+func categoryCounts(db *gorm.DB, linkTable, ownerColumn, ownerTable string) (map[uint]int64, error) {
+	var rows []struct {
+		CategoryID uint
+		Count      int64
+	}
+	if err := db.Table(linkTable + " AS l").
+		Select("l.category_id, COUNT(*) AS count").
+		Joins("JOIN " + ownerTable + " o ON o.id = l." + ownerColumn + " AND o.deleted_at IS NULL").
+		Group("l.category_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+// loadCategoryIndex 返回缓存的分类索引，超过核对间隔时重新加载；加载失败时返回空索引，分类按字符串匹配
+func loadCategoryIndex(db *gorm.DB) *categoryIndex {
+	categoryStore.mu.Lock()
+	defer categoryStore.mu.Unlock()
+	if categoryStore.index != nil && time.Since(categoryStore.loadedAt) < categoryCheckInterval {
+		return categoryStore.index
+	}
+
+	index := &categoryIndex{byKey: make(map[string]uint), children: make(map[uint][]uint)}
+	var categories []models.Category
+	if err := db.Session(&gorm.Session{NewDB: true}).Select("id", "parent_id").Find(&categories).Error; err != nil {
+		log.Printf("[CATEGORY WARNING] failed to load categories: %v", err)
+		return index
+	}
+	var aliases []models.CategoryAlias
+	if err := db.Session(&gorm.Session{NewDB: true}).Select("category_id", "key").Find(&aliases).Error; err != nil {
+		log.Printf("[CATEGORY WARNING] failed to load category aliases: %v", err)
+		return index
+	}
+	for _, category := range categories {
+		if category.ParentID != nil {
+			index.children[*category.ParentID] = append(index.children[*category.ParentID], category.ID)
+		}
+	}
+	for _, alias := range aliases {
+		index.byKey[alias.Key] = alias.CategoryID
+	}
+
+	categoryStore.index = index
+	categoryStore.loadedAt = time.Now()
+	return index
+}
+
+// invalidateCategories 本实例修改分类后清空缓存
+func invalidateCategories() {
+	categoryStore.mu.Lock()
+	categoryStore.index = nil
+	categoryStore.mu.Unlock()
+}
+
+// resolve 识别以逗号分隔的分类字符串，返回去重后的分类ID
+func (idx *categoryIndex) resolve(value string) []uint {
+	ids := make([]uint, 0)
+	for _, part := range strings.Split(value, ",") {
+		if id, ok := idx.byKey[categoryKey(part)]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return uniqueIDs(ids)
+}
+
+// descendants 分类及其全部子分类的ID
+func (idx *categoryIndex) descendants(id uint) []uint {
+	ids := []uint{id}
+	seen := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range idx.children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// checkCategoryParent 父分类必须存在，且不能是分类自身或其子分类
+func checkCategoryParent(tx *gorm.DB, id, parentID uint) error {
+	for current, depth := parentID, 0; current != 0; depth++ {
+		if current == id || depth > 100 {
+			return errors.New("invalid parent category")
+		}
+		var parent models.Category
+		if err := tx.Select("id", "parent_id").First(&parent, current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("parent category not found")
+			}
+			return err
+		}
+		current = 0
+		if parent.ParentID != nil {
+			current = *parent.ParentID
+		}
+	}
+	return nil
+}
+
+// checkCategoryAliases 分类的识别名称不能被其他分类使用
+func checkCategoryAliases(tx *gorm.DB, id uint, aliases []models.CategoryAlias) error {
+	keys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		keys = append(keys, alias.Key)
+	}
+
+	var conflict models.CategoryAlias
+	err := tx.Where("key IN ? AND category_id <> ?", keys, id).First(&conflict).Error
+	if err == nil {
+		return fmt.Errorf("category name or alias already in use: %s", conflict.Alias)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// uniqueCategorySlug 生成不与其他分类重复的 slug，重复时追加序号
+func uniqueCategorySlug(tx *gorm.DB, slug string, id uint) (string, error) {
+	if slug == "" {
+		return "", errors.New("invalid category slug")
+	}
+	candidate := slug
+	for i := 2; ; i++ {
+		var count int64
+		if err := tx.Model(&models.Category{}).Where("slug = ? AND id <> ?", candidate, id).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", slug, i)
+	}
+}
+
+// categoryAliases 分类的全部识别名称：默认名称、slug、各语言名称和别名，按规范化形式去重
+func categoryAliases(category *models.Category, aliases []string) []models.CategoryAlias {
+	rows := make([]models.CategoryAlias, 0, len(aliases)+2)
+	seen := make(map[string]bool)
+	add := func(alias, kind string) {
+		alias = strings.TrimSpace(alias)
+		key := categoryKey(alias)
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		rows = append(rows, models.CategoryAlias{CategoryID: category.ID, Key: key, Alias: alias, Kind: kind})
+	}
+
+	add(category.Name, models.CategoryAliasName)
+	add(category.Slug, models.CategoryAliasSlug)
+	names := category.DisplayNames()
+	for _, lang := range sortedStringKeys(names) {
+		add(names[lang], models.CategoryAliasI18n)
+	}
+	for _, alias := range aliases {
+		add(alias, models.CategoryAliasAlias)
+	}
+	return rows
+}
+
+func convertToCategoryResponse(category *models.Category, lang string) models.CategoryResponse {
+	names := category.DisplayNames()
+	displayName := category.Name
+	if name, ok := names[strings.ToLower(lang)]; ok && name != "" {
+		displayName = name
+	}
+
+	aliases := make([]string, 0, len(category.Aliases))
+	for _, alias := range category.Aliases {
+		if alias.Kind == models.CategoryAliasAlias {
+			aliases = append(aliases, alias.Alias)
+		}
+	}
+
+	return models.CategoryResponse{
+		ID:          category.ID,
+		ParentID:    category.ParentID,
+		Slug:        category.Slug,
+		Name:        category.Name,
+		DisplayName: displayName,
+		Names:       names,
+		Aliases:     aliases,
+		SortOrder:   category.SortOrder,
+		IsActive:    category.IsActive,
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+	}
+}
+
+// categoryNamesJSON 规范化各语言名称（语言代码小写，去掉空名称）并序列化
+func categoryNamesJSON(names map[string]string) string {
+	normalized := make(map[string]string, len(names))
+	for lang, name := range names {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if name = strings.TrimSpace(name); lang != "" && name != "" {
+			normalized[lang] = name
+		}
+	}
+	data, _ := json.Marshal(normalized)
+	return string(data)
+}
+
+// categoryKey 分类字符串的规范化形式，与关联时 SQL 中的 LOWER(TRIM(...)) 一致
+func categoryKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// aliasKeys 分类识别名称的规范化形式
+func aliasKeys(aliases []models.CategoryAlias) []string {
+	keys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		keys = append(keys, alias.Key)
+	}
+	return keys
+}
+
+// uniqueCategoryKeys 规范化并去掉空值和重复的识别名称
+func uniqueCategoryKeys(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		key := categoryKey(name)
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// slugify 由名称生成 slug：小写，字母和数字之外的字符替换为连字符，保留中文
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if b.Len() > 0 && !dash {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+)
+
+// LabeledNewsDataset 带有人工标注事件的新闻数据集
+type LabeledNewsDataset struct {
+	News    []models.News
+	Labels  []string // 每条新闻的标注事件，与 News 一一对应
+	Skipped int      // 没有标注的新闻数
+}
+
+// LoadLabeledNews 读取标注数据集，支持 data/new.json 的数组格式和 converted_news_data.json 的 {"news_items": [...]} 格式
+// labelField 为标注事件所在的字段，值可以是字符串或数字；没有标注的新闻会被跳过，至少需要两个不同的标注事件
+func LoadLabeledNews(path, labelField string) (*LabeledNewsDataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+
+	var items []json.RawMessage
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse dataset: %w", err)
+		}
+	} else {
+		var wrapper struct {
+			NewsItems []json.RawMessage `json:"news_items"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("failed to parse dataset: %w", err)
+		}
+		items = wrapper.NewsItems
+	}
+
+	dataset := &LabeledNewsDataset{}
+	for i, item := range items {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(item, &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse item %d: %w", i+1, err)
+		}
+		label := labelString(fields[labelField])
+		if label == "" {
+			dataset.Skipped++
+			continue
+		}
+
+		var newsData NewsJSONData
+		if err := json.Unmarshal(item, &newsData); err != nil {
+			return nil, fmt.Errorf("failed to parse item %d: %w", i+1, err)
+		}
+		publishedAt, err := time.ParseInLocation("2006-01-02 15:04:05", newsData.PublishedAt, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid published_at in item %d: %q", i+1, newsData.PublishedAt)
+		}
+
+		news := newsData.toNews(publishedAt)
+		news.ID = uint(len(dataset.News) + 1)
+		dataset.News = append(dataset.News, news)
+		dataset.Labels = append(dataset.Labels, label)
+	}
+
+	if len(dataset.News) == 0 {
+		return nil, fmt.Errorf("no news with label field %q in %s", labelField, path)
+	}
+	// 所有新闻标注相同时纯度恒为 1、NMI 恒为 0，评估结果没有意义
+	if !hasDistinctLabels(dataset.Labels) {
+		return nil, fmt.Errorf("all news in %s have the same %s %q, at least two labeled events are required", path, labelField, dataset.Labels[0])
+	}
+	return dataset, nil
+}
+
+func hasDistinctLabels(labels []string) bool {
+	for _, label := range labels[1:] {
+		if label != labels[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func labelString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return fmt.Sprintf("%g", v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ClusteringEvaluation 一组聚类参数在标注数据集上的评估结果
+type ClusteringEvaluation struct {
+	Name     string                  `json:"name"`
+	Config   config.ClusteringConfig `json:"config"`
+	Metrics  nlp.ClusterMetrics      `json:"metrics"`
+	Duration string                  `json:"duration"`
+}
+
+// EvaluateClustering 按给定参数在内存中聚类标注数据集并计算指标
+func EvaluateClustering(name string, dataset *LabeledNewsDataset, cfg config.ClusteringConfig) ClusteringEvaluation {
+	start := time.Now()
+	groups := ClusterNewsOffline(dataset.News, cfg)
+	return ClusteringEvaluation{
+		Name:     name,
+		Config:   cfg,
+		Metrics:  nlp.EvaluateClusters(groups, dataset.Labels),
+		Duration: time.Since(start).String(),
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadLabeledNews(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantTitles  []string
+		wantLabels  []string
+		wantSkipped int
+		wantErr     bool
+	}{
+		{
+			name: "array format",
+			data: `[
+				{"title": "降准", "published_at": "2025-06-01 08:00:00", "gold_event": "rrr"},
+				{"title": "无标注", "published_at": "2025-06-01 09:00:00"},
+				{"title": "关税", "published_at": "2025-06-01 10:00:00", "gold_event": 12}
+			]`,
+			wantTitles:  []string{"降准", "关税"},
+			wantLabels:  []string{"rrr", "12"},
+			wantSkipped: 1,
+		},
+		{
+			name: "news_items format",
+			data: `{"news_items": [
+				{"title": "降准", "published_at": "2025-06-01 08:00:00", "gold_event": " a "},
+				{"title": "关税", "published_at": "2025-06-01 10:00:00", "gold_event": "b"}
+			]}`,
+			wantTitles: []string{"降准", "关税"},
+			wantLabels: []string{"a", "b"},
+		},
+		{
+			name: "single event",
+			data: `[
+				{"title": "降准", "published_at": "2025-06-01 08:00:00", "gold_event": "政治"},
+				{"title": "关税", "published_at": "2025-06-01 10:00:00", "gold_event": "政治"}
+			]`,
+			wantErr: true,
+		},
+		{
+			name:    "no labels",
+			data:    `[{"title": "降准", "published_at": "2025-06-01 08:00:00"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid published_at",
+			data:    `[{"title": "降准", "published_at": "2025/06/01", "gold_event": "a"}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "labeled.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			dataset, err := LoadLabeledNews(path, "gold_event")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadLabeledNews() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadLabeledNews() error = %v", err)
+			}
+
+			titles := make([]string, len(dataset.News))
+			for i, news := range dataset.News {
+				titles[i] = news.Title
+				if news.ID != uint(i+1) {
+					t.Errorf("News[%d].ID = %d, want %d", i, news.ID, i+1)
+				}
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) || !reflect.DeepEqual(dataset.Labels, tt.wantLabels) || dataset.Skipped != tt.wantSkipped {
+				t.Errorf("got titles %q labels %q skipped %d, want %q %q %d",
+					titles, dataset.Labels, dataset.Skipped, tt.wantTitles, tt.wantLabels, tt.wantSkipped)
+			}
+		})
+	}
+}
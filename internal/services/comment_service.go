@@ -0,0 +1,393 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type CommentService struct {
+	db                *gorm.DB
+	moderationService *ModerationService
+}
+
+func NewCommentService() *CommentService {
+	return &CommentService{
+		db:                database.GetDB(),
+		moderationService: NewModerationService(),
+	}
+}
+
+// GetComments 获取事件或新闻的评论列表，顶层评论分页，回复随父评论一并返回
+func (s *CommentService) GetComments(targetType models.CommentTargetType, targetID uint, query *models.CommentQueryRequest) (*models.CommentListResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	if err := s.checkTarget(targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	db := s.db.Model(&models.Comment{}).
+		Where("target_type = ? AND target_id = ? AND parent_id IS NULL AND status = ?", targetType, targetID, models.CommentStatusPublished)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	orderBy := "created_at DESC"
+	if query.SortBy == "top" {
+		orderBy = "reply_count DESC, created_at DESC"
+	}
+
+	var comments []models.Comment
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Preload("User").Order(orderBy).Offset(offset).Limit(query.Limit).Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	// 一次性加载当前页顶层评论的所有回复
+	parentIDs := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		parentIDs = append(parentIDs, comment.ID)
+	}
+
+	repliesByParent := make(map[uint][]models.CommentResponse)
+	if len(parentIDs) > 0 {
+		var replies []models.Comment
+		if err := s.db.Preload("User").
+			Where("parent_id IN ? AND status = ?", parentIDs, models.CommentStatusPublished).
+			Order("created_at ASC").
+			Find(&replies).Error; err != nil {
+			return nil, fmt.Errorf("failed to get comment replies: %w", err)
+		}
+		for _, reply := range replies {
+			repliesByParent[*reply.ParentID] = append(repliesByParent[*reply.ParentID], reply.ToResponse())
+		}
+	}
+
+	responses := make([]models.CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		response := comment.ToResponse()
+		response.Replies = repliesByParent[comment.ID]
+		responses = append(responses, response)
+	}
+
+	return &models.CommentListResponse{
+		Total:    total,
+		Comments: responses,
+	}, nil
+}
+
+// CreateComment 创建评论，回复的回复会挂到同一根评论下
+func (s *CommentService) CreateComment(targetType models.CommentTargetType, targetID uint, userID uint, req *models.CreateCommentRequest) (*models.CommentResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, errors.New("comment content cannot be empty")
+	}
+
+	if err := s.checkTarget(targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	moderation, err := s.moderationService.CheckText(content)
+	if err != nil {
+		return nil, err
+	}
+	if moderation.Action == models.ModerationActionBlock {
+		return nil, ErrContentBlocked
+	}
+
+	comment := models.Comment{
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserID:     userID,
+		Content:    content,
+		Status:     models.CommentStatusPublished,
+	}
+	if moderation.Action == models.ModerationActionReview {
+		comment.Status = models.CommentStatusPending
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if req.ParentID != nil {
+			var parent models.Comment
+			if err := tx.First(&parent, *req.ParentID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("parent comment not found")
+				}
+				return err
+			}
+			if parent.TargetType != targetType || parent.TargetID != targetID {
+				return errors.New("parent comment does not belong to this target")
+			}
+
+			rootID := parent.ID
+			if parent.ParentID != nil {
+				rootID = *parent.ParentID
+			}
+			// 只能回复已发布的评论，回复的回复还要求根评论已发布
+			required := int64(1)
+			if rootID != parent.ID {
+				required = 2
+			}
+			var published int64
+			if err := tx.Model(&models.Comment{}).
+				Where("id IN ? AND status = ?", []uint{parent.ID, rootID}, models.CommentStatusPublished).
+				Count(&published).Error; err != nil {
+				return err
+			}
+			if published != required {
+				return errors.New("parent comment is not published")
+			}
+			comment.ParentID = &rootID
+		}
+
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+
+		if err := s.moderationService.Submit(tx, models.ModerationTargetComment, comment.ID, userID, content, moderation); err != nil {
+			return err
+		}
+
+		if comment.ParentID != nil {
+			return syncReplyCount(tx, *comment.ParentID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.SyncCommentCount(targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	s.db.Preload("User").First(&comment, comment.ID)
+	response := comment.ToResponse()
+	return &response, nil
+}
+
+// UpdateComment 编辑评论，保留编辑前的内容作为历史
+func (s *CommentService) UpdateComment(id uint, userID uint, isAdmin bool, req *models.UpdateCommentRequest) (*models.CommentResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	var comment models.Comment
+	if err := s.db.First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+
+	if comment.UserID != userID && !isAdmin {
+		return nil, errors.New("permission denied")
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, errors.New("comment content cannot be empty")
+	}
+	if content == comment.Content {
+		s.db.Preload("User").First(&comment, comment.ID)
+		response := comment.ToResponse()
+		return &response, nil
+	}
+
+	moderation, err := s.moderationService.CheckText(content)
+	if err != nil {
+		return nil, err
+	}
+	if moderation.Action == models.ModerationActionBlock {
+		return nil, ErrContentBlocked
+	}
+
+	updates := map[string]interface{}{
+		"content":   content,
+		"is_edited": true,
+		"edited_at": time.Now(),
+	}
+	if moderation.Action == models.ModerationActionReview {
+		updates["status"] = models.CommentStatusPending
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		edit := models.CommentEdit{
+			CommentID:       comment.ID,
+			PreviousContent: comment.Content,
+			EditedBy:        userID,
+		}
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&comment).Updates(updates).Error; err != nil {
+			return err
+		}
+		if comment.ParentID != nil {
+			if err := syncReplyCount(tx, *comment.ParentID); err != nil {
+				return err
+			}
+		}
+
+		return s.moderationService.Submit(tx, models.ModerationTargetComment, comment.ID, userID, content, moderation)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if moderation.Action == models.ModerationActionReview {
+		if err := s.SyncCommentCount(comment.TargetType, comment.TargetID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.db.Preload("User").First(&comment, comment.ID)
+	response := comment.ToResponse()
+	return &response, nil
+}
+
+// DeleteComment 软删除评论，删除顶层评论时一并删除其回复
+func (s *CommentService) DeleteComment(id uint, userID uint, isAdmin bool) error {
+	if s.db == nil {
+		return errors.New("database connection not initialized")
+	}
+
+	var comment models.Comment
+	if err := s.db.First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("comment not found")
+		}
+		return err
+	}
+
+	if comment.UserID != userID && !isAdmin {
+		return errors.New("permission denied")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("parent_id = ?", comment.ID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&comment).Error; err != nil {
+			return err
+		}
+		if comment.ParentID != nil {
+			return syncReplyCount(tx, *comment.ParentID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return s.SyncCommentCount(comment.TargetType, comment.TargetID)
+}
+
+// GetCommentHistory 获取评论的编辑历史，未发布的评论只有作者和管理员可以查看
+func (s *CommentService) GetCommentHistory(id uint, viewerID uint, isAdmin bool) ([]models.CommentEdit, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	var comment models.Comment
+	if err := s.db.First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	if comment.Status != models.CommentStatusPublished && !isAdmin && (viewerID == 0 || comment.UserID != viewerID) {
+		return nil, errors.New("comment not found")
+	}
+
+	var edits []models.CommentEdit
+	if err := s.db.Where("comment_id = ?", id).Order("created_at DESC").Find(&edits).Error; err != nil {
+		return nil, fmt.Errorf("failed to get comment history: %w", err)
+	}
+
+	return edits, nil
+}
+
+// syncReplyCount 按已发布的回复数回写根评论的回复数，待审核和被拒绝的回复不计入
+func syncReplyCount(tx *gorm.DB, parentID uint) error {
+	return tx.Model(&models.Comment{}).
+		Where("id = ?", parentID).
+		UpdateColumn("reply_count", tx.Model(&models.Comment{}).
+			Select("COUNT(*)").
+			Where("parent_id = ? AND status = ?", parentID, models.CommentStatusPublished)).Error
+}
+
+// SyncCommentCount 根据已发布评论的实际行数回写事件或新闻的评论数，并重新计算热度
+func (s *CommentService) SyncCommentCount(targetType models.CommentTargetType, targetID uint) error {
+	var count int64
+	if err := s.db.Model(&models.Comment{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.CommentStatusPublished).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	switch targetType {
+	case models.CommentTargetEvent:
+		if err := s.db.Model(&models.Event{}).
+			Where("id = ?", targetID).
+			UpdateColumn("comment_count", count).Error; err != nil {
+			return err
+		}
+		_, err := NewEventService().CalculateHotness(targetID, nil)
+		return err
+	case models.CommentTargetNews:
+		if err := s.db.Model(&models.News{}).
+			Where("id = ?", targetID).
+			UpdateColumn("comment_count", count).Error; err != nil {
+			return err
+		}
+		return NewRSSService().calculateNewsHotness(targetID)
+	}
+
+	return errors.New("invalid comment target type")
+}
+
+// checkTarget 检查评论对象是否存在
+func (s *CommentService) checkTarget(targetType models.CommentTargetType, targetID uint) error {
+	var count int64
+	switch targetType {
+	case models.CommentTargetEvent:
+		if err := s.db.Model(&models.Event{}).Where("id = ?", targetID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("event not found")
+		}
+	case models.CommentTargetNews:
+		if err := s.db.Model(&models.News{}).Where("id = ?", targetID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("news not found")
+		}
+	default:
+		return errors.New("invalid comment target type")
+	}
+	return nil
+}
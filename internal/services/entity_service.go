@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/ner"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// entityFacetLimit 分面统计中每种类型返回的实体数
+	entityFacetLimit = 10
+	// entityExtractionBatch 批量重新识别时每批处理的新闻数
+	entityExtractionBatch = 200
+)
+
+type EntityService struct {
+	db         *gorm.DB
+	recognizer *ner.Recognizer
+}
+
+func NewEntityService() *EntityService {
+	return &EntityService{
+		db:         database.GetDB(),
+		recognizer: ner.Default(),
+	}
+}
+
+// ExtractNewsEntities 识别新闻标题和正文中的实体，替换新闻原有的实体关联；新闻已归入事件时重新汇总事件的实体
+// 返回新闻关联的实体数
+func (s *EntityService) ExtractNewsEntities(news *models.News) (int, error) {
+	var linked int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if linked, err = s.linkNewsEntities(tx, news); err != nil {
+			return err
+		}
+		if news.BelongedEventID == nil {
+			return nil
+		}
+		return syncEventEntities(tx, *news.BelongedEventID)
+	})
+	return linked, err
+}
+
+// linkNewsEntities 识别新闻中的实体并保存新闻与实体的关联
+func (s *EntityService) linkNewsEntities(tx *gorm.DB, news *models.News) (int, error) {
+	recognized := s.recognizer.Recognize(news.Title, news.Description+"\n"+nlp.StripHTML(news.Content))
+
+	if err := tx.Where("news_id = ?", news.ID).Delete(&models.NewsEntity{}).Error; err != nil {
+		return 0, err
+	}
+
+	// 不同的识别结果可能通过别名对应到同一实体
+	links := make(map[uint]*models.NewsEntity)
+	order := make([]uint, 0, len(recognized))
+	for _, entity := range recognized {
+		id, err := resolveEntity(tx, entity)
+		if err != nil {
+			return 0, err
+		}
+		if id == 0 {
+			continue
+		}
+		if link, ok := links[id]; ok {
+			link.Mentions += entity.Mentions
+			link.InTitle = link.InTitle || entity.InTitle
+			continue
+		}
+		links[id] = &models.NewsEntity{NewsID: news.ID, EntityID: id, Mentions: entity.Mentions, InTitle: entity.InTitle}
+		order = append(order, id)
+	}
+	if len(order) == 0 {
+		return 0, nil
+	}
+
+	rows := make([]models.NewsEntity, 0, len(order))
+	for _, id := range order {
+		rows = append(rows, *links[id])
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// resolveEntity 按名称和别名找到已有实体，没有时新建，返回实体ID
+// 规则识别的实体后来被词典或地名库收录时，改用词典中的规范名称
+func resolveEntity(tx *gorm.DB, recognized ner.Entity) (uint, error) {
+	key := ner.Key(recognized.Name)
+	if key == "" {
+		return 0, nil
+	}
+
+	names := map[string]string{key: recognized.Name}
+	for _, name := range append([]string{recognized.NameEn}, recognized.Aliases...) {
+		if k := ner.Key(name); k != "" {
+			if _, exists := names[k]; !exists {
+				names[k] = name
+			}
+		}
+	}
+	keys := make([]string, 0, len(names))
+	for k := range names {
+		keys = append(keys, k)
+	}
+
+	var aliases []models.EntityAlias
+	if err := tx.Where("type = ? AND key IN ?", recognized.Type, keys).Find(&aliases).Error; err != nil {
+		return 0, err
+	}
+
+	var entityID uint
+	for _, alias := range aliases {
+		if entityID == 0 || alias.Key == key {
+			entityID = alias.EntityID
+		}
+	}
+
+	if entityID == 0 {
+		entity := models.Entity{
+			Type:      recognized.Type,
+			Key:       key,
+			Name:      truncateRunes(recognized.Name, 200),
+			NameEn:    truncateRunes(recognized.NameEn, 200),
+			PlaceCode: recognized.PlaceCode,
+			Origin:    recognized.Origin,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity).Error; err != nil {
+			return 0, err
+		}
+		if entity.ID == 0 {
+			// 并发识别时已由其他请求创建
+			if err := tx.Where("type = ? AND key = ?", recognized.Type, key).First(&entity).Error; err != nil {
+				return 0, err
+			}
+		}
+		entityID = entity.ID
+	} else if recognized.Origin != ner.OriginRule {
+		if err := tx.Model(&models.Entity{}).
+			Where("id = ? AND origin = ?", entityID, ner.OriginRule).
+			Updates(map[string]interface{}{
+				"name":       truncateRunes(recognized.Name, 200),
+				"name_en":    truncateRunes(recognized.NameEn, 200),
+				"place_code": recognized.PlaceCode,
+				"origin":     recognized.Origin,
+			}).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	// 补充尚未记录的名称和别名
+	known := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		known[alias.Key] = true
+	}
+	missing := make([]models.EntityAlias, 0)
+	for k, name := range names {
+		if !known[k] {
+			missing = append(missing, models.EntityAlias{
+				EntityID: entityID,
+				Type:     recognized.Type,
+				Key:      k,
+				Alias:    truncateRunes(name, 200),
+			})
+		}
+	}
+	if len(missing) > 0 {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error; err != nil {
+			return 0, err
+		}
+	}
+	return entityID, nil
+}
+
+// syncEventEntities 按事件当前展示的新闻重新汇总事件的实体
+func syncEventEntities(tx *gorm.DB, eventIDs ...uint) error {
+	ids := uniqueIDs(eventIDs)
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("event_id IN ?", ids).Delete(&models.EventEntity{}).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`INSERT INTO event_entities (event_id, entity_id, news_count, mentions)
+		SELECT news.belonged_event_id, news_entities.entity_id, COUNT(*), SUM(news_entities.mentions)
+		FROM news_entities JOIN news ON news.id = news_entities.news_id
+		WHERE news.belonged_event_id IN ? AND news.is_active = ? AND news.deleted_at IS NULL
+		GROUP BY news.belonged_event_id, news_entities.entity_id`, ids, true).Error
+}
+
+// GetEntity 获取实体详情：全部相关事件和分页的相关新闻
+func (s *EntityService) GetEntity(id uint, query *models.EntityDetailQueryRequest) (*models.EntityDetailResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	var entity models.Entity
+	if err := s.db.Preload("Aliases").First(&entity, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("entity not found")
+		}
+		return nil, err
+	}
+
+	newsQuery := s.db.Model(&models.News{}).
+		Joins("JOIN news_entities ON news_entities.news_id = news.id").
+		Where("news_entities.entity_id = ? AND news.is_active = ?", id, true)
+	eventQuery := s.db.Model(&models.Event{}).
+		Joins("JOIN event_entities ON event_entities.event_id = events.id").
+		Where("event_entities.entity_id = ?", id)
+
+	response := &models.EntityDetailResponse{
+		Entity: convertToEntityResponse(&entity),
+		Events: make([]models.EventResponse, 0),
+		News:   make([]models.NewsResponse, 0),
+		Page:   query.Page,
+		Limit:  query.Limit,
+	}
+	if err := newsQuery.Session(&gorm.Session{}).Count(&response.Entity.NewsCount).Error; err != nil {
+		return nil, err
+	}
+
+	var events []models.Event
+	if err := eventQuery.Order("event_entities.news_count DESC, events.hotness_score DESC, events.id DESC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	response.Entity.EventCount = int64(len(events))
+	for i := range events {
+		item := convertToEventResponse(&events[i])
+		item.Content = ""
+		response.Events = append(response.Events, item)
+	}
+
+	var newsList []models.News
+	if err := newsQuery.Order("news.published_at DESC, news.id DESC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&newsList).Error; err != nil {
+		return nil, err
+	}
+	for _, news := range newsList {
+		item := news.ToResponse()
+		item.Content = ""
+		response.News = append(response.News, item)
+	}
+	return response, nil
+}
+
+// ExtractAllNewsEntities 重新识别全部新闻的实体，并在同一事务中重新汇总每批新闻所属事件的实体
+func (s *EntityService) ExtractAllNewsEntities(ctx context.Context, progress func(done, total int, partial *models.ExtractEntitiesResult)) (*models.ExtractEntitiesResult, error) {
+	start := time.Now()
+	result := &models.ExtractEntitiesResult{}
+
+	var total int64
+	if err := s.db.Model(&models.News{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	events := make(map[uint]bool)
+	err := forEachNewsBatch(ctx, s.db, entityExtractionBatch, func(batch []models.News) error {
+		var batchEvents []uint
+		entities := 0
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			for i := range batch {
+				linked, err := s.linkNewsEntities(tx, &batch[i])
+				if err != nil {
+					return err
+				}
+				entities += linked
+				if batch[i].BelongedEventID != nil {
+					batchEvents = append(batchEvents, *batch[i].BelongedEventID)
+				}
+			}
+			// 事件的新闻可能分布在多个批次，每批都按当前结果重新汇总，中途取消时不会留下过期的事件实体
+			return syncEventEntities(tx, batchEvents...)
+		})
+		if err != nil {
+			return err
+		}
+		for _, id := range batchEvents {
+			events[id] = true
+		}
+		result.UpdatedEvents = len(events)
+		result.Entities += entities
+
+		result.ProcessedNews += len(batch)
+		if progress != nil {
+			progress(result.ProcessedNews, int(total), result)
+		}
+		return nil
+	})
+
+	result.Duration = time.Since(start).String()
+	return result, err
+}
+
+// entityFacets 统计 ids 子查询范围内每种类型出现次数最多的实体
+// linkTable 为 news_entities 或 event_entities，ownerColumn 为其中的新闻或事件ID列
+func entityFacets(db *gorm.DB, linkTable, ownerColumn string, ids *gorm.DB) (models.EntityFacets, error) {
+	facets := make(models.EntityFacets, len(models.EntityTypes))
+	for _, entityType := range models.EntityTypes {
+		items := make([]models.EntityFacet, 0)
+		if err := db.Table(linkTable).
+			Select("entities.id, entities.name, COUNT(*) AS count").
+			Joins("JOIN entities ON entities.id = "+linkTable+".entity_id").
+			Where(linkTable+"."+ownerColumn+" IN (?) AND entities.type = ?", ids, entityType).
+			Group("entities.id, entities.name").
+			Order("count DESC, entities.id ASC").
+			Limit(entityFacetLimit).
+			Scan(&items).Error; err != nil {
+			return nil, err
+		}
+		facets[entityType] = items
+	}
+	return facets, nil
+}
+
+// entityFilter 只保留提到指定实体的新闻或事件
+func entityFilter(db *gorm.DB, linkTable, ownerColumn string, entityID uint) *gorm.DB {
+	return db.Table(linkTable).Select(ownerColumn).Where("entity_id = ?", entityID)
+}
+
+func convertToEntityResponse(entity *models.Entity) models.EntityResponse {
+	aliases := make([]string, 0, len(entity.Aliases))
+	for _, alias := range entity.Aliases {
+		if alias.Alias != entity.Name {
+			aliases = append(aliases, alias.Alias)
+		}
+	}
+	return models.EntityResponse{
+		ID:        entity.ID,
+		Type:      entity.Type,
+		Name:      entity.Name,
+		NameEn:    entity.NameEn,
+		Aliases:   aliases,
+		PlaceCode: entity.PlaceCode,
+		Origin:    entity.Origin,
+	}
+}
+
+// eventEntityNames 查询事件提到的人物和机构名称，用于计算事件之间的关系
+func eventEntityNames(db *gorm.DB, eventIDs []uint) (map[uint][]string, error) {
+	var rows []struct {
+		EventID uint
+		Name    string
+	}
+	if err := db.Table("event_entities").
+		Select("event_entities.event_id, entities.name").
+		Joins("JOIN entities ON entities.id = event_entities.entity_id").
+		Where("event_entities.event_id IN ? AND entities.type IN ?", eventIDs,
+			[]string{models.EntityTypePerson, models.EntityTypeOrganization}).
+		Order("event_entities.news_count DESC, entities.id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint][]string)
+	for _, row := range rows {
+		names[row.EventID] = append(names[row.EventID], row.Name)
+	}
+	return names, nil
+}
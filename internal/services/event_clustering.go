@@ -0,0 +1,385 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+)
+
+// clusteringOptions 聚类参数，来自配置文件，未配置的项使用默认值
+type clusteringOptions struct {
+	method               string
+	threshold            float64
+	attachThreshold      float64
+	timeWindow           time.Duration
+	titleWeight          int
+	maxContentChars      int
+	maxAgglomerativeSize int
+}
+
+func loadClusteringOptions() clusteringOptions {
+	if config.AppConfig == nil {
+		return clusteringOptionsFromConfig(config.ClusteringConfig{})
+	}
+	return clusteringOptionsFromConfig(config.AppConfig.Clustering)
+}
+
+// clusteringOptionsFromConfig 由聚类配置生成参数，未配置的项使用默认值
+func clusteringOptionsFromConfig(cfg config.ClusteringConfig) clusteringOptions {
+	opts := clusteringOptions{
+		method:               nlp.ClusterSinglePass,
+		threshold:            0.3,
+		attachThreshold:      0.3,
+		timeWindow:           72 * time.Hour,
+		titleWeight:          2,
+		maxContentChars:      300,
+		maxAgglomerativeSize: 500,
+	}
+
+	if cfg.Method == nlp.ClusterSinglePass || cfg.Method == nlp.ClusterAgglomerative {
+		opts.method = cfg.Method
+	}
+	if cfg.SimilarityThreshold > 0 {
+		opts.threshold = cfg.SimilarityThreshold
+	}
+	if cfg.AttachThreshold > 0 {
+		opts.attachThreshold = cfg.AttachThreshold
+	}
+	if cfg.TimeWindowHours > 0 {
+		opts.timeWindow = time.Duration(cfg.TimeWindowHours) * time.Hour
+	}
+	if cfg.TitleWeight > 0 {
+		opts.titleWeight = cfg.TitleWeight
+	}
+	if cfg.MaxContentChars > 0 {
+		opts.maxContentChars = cfg.MaxContentChars
+	}
+	if cfg.MaxAgglomerativeSize > 0 {
+		opts.maxAgglomerativeSize = cfg.MaxAgglomerativeSize
+	}
+	return opts
+}
+
+// textPipeline 将新闻和事件文本转换为分词结果，并识别其中提到的地域
+type textPipeline struct {
+	opts      clusteringOptions
+	segmenter *nlp.Segmenter
+	stopWords map[string]bool
+	regionOf  map[string]string // 地域关键词 -> 地域组
+}
+
+// newTextPipeline 按当前词库构建分词器：地域组和主题组的关键词补充进分词词典，地域组同时用于地域冲突判断
+func newTextPipeline(opts clusteringOptions) *textPipeline {
+	taxonomy, err := NewTaxonomyService().CurrentTaxonomy()
+	if err != nil {
+		log.Printf("[CLUSTER WARNING] failed to load taxonomy, using defaults: %v", err)
+		taxonomy = defaultTaxonomy()
+	}
+
+	extraWords := make([]string, 0)
+	regionOf := make(map[string]string)
+	for region, keywords := range taxonomy.RegionalGroups {
+		for _, keyword := range keywords {
+			regionOf[strings.ToLower(nlp.ToHalfWidth(keyword))] = region
+			extraWords = append(extraWords, keyword)
+		}
+	}
+	for _, keywords := range taxonomy.TopicGroups {
+		extraWords = append(extraWords, keywords...)
+	}
+
+	stopWords := make(map[string]bool, len(taxonomy.StopWords))
+	for _, word := range taxonomy.StopWords {
+		stopWords[word] = true
+	}
+
+	return &textPipeline{
+		opts:      opts,
+		segmenter: nlp.NewSegmenter(extraWords),
+		stopWords: stopWords,
+		regionOf:  regionOf,
+	}
+}
+
+// tokens 标题词按权重重复，再拼接正文开头部分的词
+func (p *textPipeline) tokens(title, body string) []string {
+	titleTokens := p.segmenter.Tokens(title, p.stopWords)
+	tokens := make([]string, 0, len(titleTokens)*p.opts.titleWeight)
+	for i := 0; i < p.opts.titleWeight; i++ {
+		tokens = append(tokens, titleTokens...)
+	}
+	return append(tokens, p.segmenter.Tokens(truncateRunes(body, p.opts.maxContentChars), p.stopWords)...)
+}
+
+func (p *textPipeline) newsTokens(news models.News) []string {
+	body := news.Summary
+	if body == "" {
+		body = news.Description
+	}
+	return p.tokens(news.Title, body+" "+news.Content)
+}
+
+func (p *textPipeline) eventTokens(event models.Event) []string {
+	return p.tokens(event.Title, event.Description+" "+event.Content)
+}
+
+// regions 分词结果中提到的地域组
+func (p *textPipeline) regions(tokens []string) map[string]bool {
+	regions := make(map[string]bool)
+	for _, token := range tokens {
+		if region, ok := p.regionOf[token]; ok {
+			regions[region] = true
+		}
+	}
+	return regions
+}
+
+// regionConflict 两段文本都提到了地域且没有共同地域
+func regionConflict(a, b map[string]bool) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	for region := range a {
+		if b[region] {
+			return false
+		}
+	}
+	return true
+}
+
+// clusterNewsByTitle 基于 TF-IDF 余弦相似度聚类新闻
+// 标题和正文开头分词后向量化，在时间窗口约束下按配置的算法聚类，提到不同地域的新闻不会聚在一起
+func (s *EventService) clusterNewsByTitle(newsList []models.News) []*EventCluster {
+	if len(newsList) == 0 {
+		return []*EventCluster{}
+	}
+
+	docs, groups := clusterNews(newsList, newTextPipeline(loadClusteringOptions()))
+
+	clusters := make([]*EventCluster, 0, len(groups))
+	for _, group := range groups {
+		clusters = append(clusters, s.buildCluster(newsList, docs, group))
+	}
+	return clusters
+}
+
+// clusterNews 对新闻分词、向量化并聚类，返回各新闻的向量和聚类结果（新闻下标分组）
+func clusterNews(newsList []models.News, pipeline *textPipeline) ([]nlp.Document, [][]int) {
+	opts := pipeline.opts
+
+	tokens := make([][]string, len(newsList))
+	regions := make([]map[string]bool, len(newsList))
+	for i, news := range newsList {
+		tokens[i] = pipeline.newsTokens(news)
+		regions[i] = pipeline.regions(tokens[i])
+	}
+
+	model := nlp.FitTFIDF(tokens)
+	docs := make([]nlp.Document, len(newsList))
+	for i, news := range newsList {
+		docs[i] = nlp.Document{Vector: model.Transform(tokens[i]), Time: news.PublishedAt}
+	}
+
+	groups := nlp.Cluster(docs, nlp.ClusterOptions{
+		Method:               opts.method,
+		Threshold:            opts.threshold,
+		TimeWindow:           opts.timeWindow,
+		MaxAgglomerativeSize: opts.maxAgglomerativeSize,
+		CannotLink: func(i, j int) bool {
+			return regionConflict(regions[i], regions[j])
+		},
+	})
+	return docs, groups
+}
+
+// ClusterNewsOffline 按给定配置在内存中聚类新闻，不生成事件也不写数据库，用于评估聚类效果
+// 与事件生成一致，先按分类分组再分别聚类；词库使用数据库中的当前版本，数据库未初始化时使用内置默认词库
+// 返回的每个分组是 newsList 中的下标
+func ClusterNewsOffline(newsList []models.News, cfg config.ClusteringConfig) [][]int {
+	pipeline := newTextPipeline(clusteringOptionsFromConfig(cfg))
+
+	categories := make([]string, 0)
+	byCategory := make(map[string][]int)
+	for i, news := range newsList {
+		category := news.Category
+		if category == "" {
+			category = "未分类"
+		}
+		if _, ok := byCategory[category]; !ok {
+			categories = append(categories, category)
+		}
+		byCategory[category] = append(byCategory[category], i)
+	}
+
+	result := make([][]int, 0)
+	for _, category := range categories {
+		indexes := byCategory[category]
+		subset := make([]models.News, len(indexes))
+		for i, idx := range indexes {
+			subset[i] = newsList[idx]
+		}
+
+		_, groups := clusterNews(subset, pipeline)
+		for _, group := range groups {
+			mapped := make([]int, len(group))
+			for i, local := range group {
+				mapped[i] = indexes[local]
+			}
+			result = append(result, mapped)
+		}
+	}
+	return result
+}
+
+// buildCluster 由一组新闻生成事件聚类，以最接近质心的新闻作为代表
+func (s *EventService) buildCluster(newsList []models.News, docs []nlp.Document, group []int) *EventCluster {
+	centroid := nlp.Centroid(docs, group)
+	representative := newsList[group[0]]
+	bestSim := -1.0
+	for _, idx := range group {
+		if sim := nlp.Cosine(docs[idx].Vector, centroid); sim > bestSim {
+			representative, bestSim = newsList[idx], sim
+		}
+	}
+
+	first := newsList[group[0]]
+	cluster := &EventCluster{
+		Title:       representative.Title,
+		Description: representative.Summary,
+		Category:    representative.Category,
+		StartTime:   first.PublishedAt,
+		EndTime:     first.PublishedAt.Add(defaultEventDuration), // 默认事件持续一天
+		Location:    defaultEventLocation,
+		Status:      models.EventStatusOngoing,
+		Tags:        []string{},
+		Source:      representative.Source,
+		NewsList:    make([]models.News, 0, len(group)),
+	}
+
+	for _, idx := range group {
+		news := newsList[idx]
+		cluster.NewsList = append(cluster.NewsList, news)
+
+		if news.PublishedAt.After(cluster.EndTime) {
+			cluster.EndTime = news.PublishedAt
+		}
+
+		// 合并标签
+		for _, tag := range s.extractTags(news) {
+			if !s.contains(cluster.Tags, tag) {
+				cluster.Tags = append(cluster.Tags, tag)
+			}
+		}
+
+		// 累加热度分数
+		cluster.HotnessScore += float64(news.ViewCount + news.LikeCount*2 + news.CommentCount*3 + news.ShareCount*5)
+	}
+
+	// 按标题和正文中的地名确定事件地点，没有明确地点时保持默认
+	if place, ok := extractNewsLocation(cluster.NewsList); ok {
+		cluster.Location = place.Name
+	}
+
+	// 从聚类内各篇新闻中抽取最核心的句子作为事件描述，无法生成时使用代表新闻的摘要或第一条有内容的新闻开头
+	if description := s.summarizer.SummarizeEvent(cluster.NewsList); description != "" {
+		cluster.Description = description
+	}
+	if cluster.Description == "" {
+		for _, news := range cluster.NewsList {
+			if len([]rune(news.Content)) > 10 {
+				cluster.Description = truncateRunes(news.Content, 100) + "..."
+				break
+			}
+		}
+	}
+
+	// 按时间跨度确定初始状态
+	cluster.Status = initialEventStatus(cluster.StartTime, cluster.EndTime)
+
+	return cluster
+}
+
+// eventMatcher 为增量生成的新闻在开放事件中查找最相似的事件
+type eventMatcher struct {
+	opts         clusteringOptions
+	events       []models.Event
+	eventVectors []nlp.Vector
+	eventRegions []map[string]bool
+	newsVectors  []nlp.Vector
+	newsRegions  []map[string]bool
+}
+
+// newEventMatcher 在待处理新闻和开放事件的文本上统一建立 TF-IDF 模型
+func newEventMatcher(pendingNews []models.News, openEvents []models.Event) *eventMatcher {
+	opts := loadClusteringOptions()
+	pipeline := newTextPipeline(opts)
+
+	tokens := make([][]string, 0, len(pendingNews)+len(openEvents))
+	for _, event := range openEvents {
+		tokens = append(tokens, pipeline.eventTokens(event))
+	}
+	for _, news := range pendingNews {
+		tokens = append(tokens, pipeline.newsTokens(news))
+	}
+	model := nlp.FitTFIDF(tokens)
+
+	m := &eventMatcher{opts: opts, events: openEvents}
+	for i, t := range tokens {
+		if i < len(openEvents) {
+			m.eventVectors = append(m.eventVectors, model.Transform(t))
+			m.eventRegions = append(m.eventRegions, pipeline.regions(t))
+		} else {
+			m.newsVectors = append(m.newsVectors, model.Transform(t))
+			m.newsRegions = append(m.newsRegions, pipeline.regions(t))
+		}
+	}
+	return m
+}
+
+// match 返回与第 i 条待处理新闻最相似的开放事件下标，没有满足阈值的事件时返回 -1
+// 匹配成功后新闻向量并入事件向量，后续新闻按更新后的事件内容匹配
+func (m *eventMatcher) match(i int, news models.News) int {
+	best := -1
+	bestSim := m.opts.attachThreshold
+	for j := range m.events {
+		event := &m.events[j]
+
+		category := event.Category
+		if category == "" {
+			category = "未分类"
+		}
+		if category != news.Category {
+			continue
+		}
+
+		if news.PublishedAt.Before(event.StartTime.Add(-eventMatchSlack)) ||
+			news.PublishedAt.After(event.EndTime.Add(eventMatchSlack)) {
+			continue
+		}
+
+		if regionConflict(m.newsRegions[i], m.eventRegions[j]) {
+			continue
+		}
+
+		if sim := nlp.Cosine(m.newsVectors[i], m.eventVectors[j]); sim >= bestSim {
+			best, bestSim = j, sim
+		}
+	}
+
+	if best >= 0 {
+		m.eventVectors[best].Add(m.newsVectors[i], 1)
+		// 同步扩展内存中的时间跨度，让后续新闻按更新后的跨度匹配
+		extendEventSpan(&m.events[best], news.PublishedAt)
+	}
+	return best
+}
+
+// truncateRunes 按字符截断文本
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	return string(runes[:min(limit, len(runes))])
+}
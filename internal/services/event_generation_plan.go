@@ -0,0 +1,478 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// eventPlanTTL 预演生成的提案保留时长，过期后不能再提交
+const eventPlanTTL = 24 * time.Hour
+
+// generationPlan 一次事件生成要执行的变更：新建的聚类和追加新闻的已有事件
+type generationPlan struct {
+	mode              string
+	processedNews     int
+	clusters          []*EventCluster
+	attachments       []eventAttachment
+	categoryBreakdown map[string]int
+}
+
+// eventAttachment 归入同一个已有事件的新闻
+type eventAttachment struct {
+	event    models.Event
+	newsList []models.News
+}
+
+// storedEventProposal 保存在生成计划中的提案，附带预演时新闻的事件归属用于提交时检查
+type storedEventProposal struct {
+	Proposal   models.EventProposal `json:"proposal"`
+	NewsEvents map[uint]uint        `json:"news_events"` // 新闻ID -> 预演时所属事件ID，0 表示未关联
+}
+
+// planGeneration 按模式计算事件生成的计划
+func (s *EventService) planGeneration(mode string, now time.Time) (*generationPlan, error) {
+	switch mode {
+	case "full":
+		return s.planFullGeneration()
+	case "incremental":
+		return s.planIncrementalGeneration(now)
+	default:
+		return nil, errors.New("invalid generation mode")
+	}
+}
+
+// planFullGeneration 按分类对全部新闻聚类，得到全量生成的计划
+func (s *EventService) planFullGeneration() (*generationPlan, error) {
+	// 1. 获取所有新闻
+	var allNews []models.News
+	if err := s.db.Find(&allNews).Error; err != nil {
+		return nil, fmt.Errorf("获取新闻列表失败: %w", err)
+	}
+
+	if len(allNews) == 0 {
+		return nil, errors.New("数据库中没有新闻，无法生成事件")
+	}
+
+	// 2. 根据分类将新闻分组
+	categoryNews := make(map[string][]models.News)
+	for _, news := range allNews {
+		if news.Category == "" {
+			news.Category = "未分类"
+		}
+		categoryNews[news.Category] = append(categoryNews[news.Category], news)
+	}
+
+	// 3. 为每个分类生成事件聚类
+	plan := &generationPlan{
+		mode:              "full",
+		processedNews:     len(allNews),
+		clusters:          make([]*EventCluster, 0),
+		categoryBreakdown: make(map[string]int),
+	}
+	for category, newsList := range categoryNews {
+		clusters := s.clusterNewsByTitle(newsList)
+		plan.categoryBreakdown[category] = len(clusters)
+		plan.clusters = append(plan.clusters, clusters...)
+	}
+
+	// 4. 按热度对事件聚类排序
+	sortClustersByHotness(plan.clusters)
+	return plan, nil
+}
+
+// planIncrementalGeneration 为尚未关联事件的新闻匹配开放中的事件，其余新闻聚类，得到增量生成的计划
+func (s *EventService) planIncrementalGeneration(now time.Time) (*generationPlan, error) {
+	plan := &generationPlan{
+		mode:              "incremental",
+		clusters:          make([]*EventCluster, 0),
+		categoryBreakdown: make(map[string]int),
+	}
+
+	// 1. 获取未关联事件的新闻
+	var pendingNews []models.News
+	if err := s.db.Where("belonged_event_id IS NULL").
+		Order("published_at ASC").
+		Find(&pendingNews).Error; err != nil {
+		return nil, fmt.Errorf("获取待处理新闻失败: %w", err)
+	}
+
+	plan.processedNews = len(pendingNews)
+	if len(pendingNews) == 0 {
+		return plan, nil
+	}
+
+	// 2. 获取开放中的事件，最近活跃的优先匹配
+	var openEvents []models.Event
+	if err := s.db.Where("end_time >= ? AND status <> ?", now.Add(-openEventWindow), models.EventStatusArchived).
+		Order("end_time DESC").
+		Find(&openEvents).Error; err != nil {
+		return nil, fmt.Errorf("获取开放事件失败: %w", err)
+	}
+
+	// 3. 将新闻归入匹配的事件，匹配不到的按分类留待聚类
+	for i := range pendingNews {
+		if pendingNews[i].Category == "" {
+			pendingNews[i].Category = "未分类"
+		}
+	}
+
+	// 匹配过程会扩展内存中事件的时间跨度，计划中保留事件原样
+	matcher := newEventMatcher(pendingNews, append([]models.Event(nil), openEvents...))
+	attachments := make(map[uint][]models.News)
+	leftovers := make(map[string][]models.News)
+	for i, news := range pendingNews {
+		j := matcher.match(i, news)
+		if j < 0 {
+			leftovers[news.Category] = append(leftovers[news.Category], news)
+			continue
+		}
+		attachments[openEvents[j].ID] = append(attachments[openEvents[j].ID], news)
+	}
+
+	for _, event := range openEvents {
+		if newsList, ok := attachments[event.ID]; ok {
+			plan.attachments = append(plan.attachments, eventAttachment{event: event, newsList: newsList})
+		}
+	}
+
+	// 4. 剩余新闻聚类生成新事件
+	for category, newsList := range leftovers {
+		clusters := s.clusterNewsByTitle(newsList)
+		plan.categoryBreakdown[category] = len(clusters)
+		plan.clusters = append(plan.clusters, clusters...)
+	}
+	sortClustersByHotness(plan.clusters)
+	return plan, nil
+}
+
+func sortClustersByHotness(clusters []*EventCluster) {
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].HotnessScore > clusters[j].HotnessScore
+	})
+}
+
+// PreviewEventGeneration 预演事件生成，返回提案而不修改事件和新闻
+// 提案保存为生成计划，管理员审核后可通过 CommitEventProposals 提交其中一部分
+func (s *EventService) PreviewEventGeneration(mode string, operatorID uint) (*models.EventGenerationPreview, error) {
+	if !eventGenerationMu.TryLock() {
+		return nil, ErrEventGenerationRunning
+	}
+	defer eventGenerationMu.Unlock()
+
+	startTime := time.Now()
+
+	plan, err := s.planGeneration(mode, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make([]storedEventProposal, 0, len(plan.attachments)+len(plan.clusters))
+	for _, attachment := range plan.attachments {
+		stored = append(stored, s.attachmentProposal(len(stored)+1, attachment))
+	}
+	for _, cluster := range plan.clusters {
+		stored = append(stored, clusterProposal(len(stored)+1, cluster))
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+	record := models.EventGenerationPlan{
+		Mode:          plan.mode,
+		Proposals:     string(data),
+		ProcessedNews: plan.processedNews,
+		CreatedBy:     operatorID,
+		ExpiresAt:     startTime.Add(eventPlanTTL),
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// 顺带清理过期的计划
+		if err := tx.Where("expires_at < ?", startTime).Delete(&models.EventGenerationPlan{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存生成计划失败: %w", err)
+	}
+
+	preview := buildGenerationPreview(&record, stored)
+	preview.CategoryBreakdown = plan.categoryBreakdown
+	preview.ElapsedTime = time.Since(startTime).String()
+	return preview, nil
+}
+
+// attachmentProposal 将追加到已有事件的新闻转换为提案，标签和时间跨度为追加后的结果
+func (s *EventService) attachmentProposal(id int, attachment eventAttachment) storedEventProposal {
+	event := attachment.event
+	newsIDs := s.mergeNewsIntoEvent(&event, attachment.newsList)
+	eventID := event.ID
+
+	return storedEventProposal{
+		Proposal: models.EventProposal{
+			ID:              id,
+			Action:          models.EventProposalAttach,
+			Title:           event.Title,
+			Description:     event.Description,
+			Category:        event.Category,
+			Location:        event.Location,
+			Status:          event.Status,
+			Source:          event.Source,
+			Tags:            jsonToSlice(event.Tags),
+			NewsIDs:         newsIDs,
+			StartTime:       event.StartTime,
+			EndTime:         event.EndTime,
+			HotnessScore:    event.HotnessScore,
+			AttachToEventID: &eventID,
+		},
+		NewsEvents: newsEventMap(attachment.newsList),
+	}
+}
+
+// clusterProposal 将新聚类转换为提案
+func clusterProposal(id int, cluster *EventCluster) storedEventProposal {
+	proposal := models.EventProposal{
+		ID:           id,
+		Action:       models.EventProposalCreate,
+		Title:        cluster.Title,
+		Description:  cluster.Description,
+		Category:     cluster.Category,
+		Location:     cluster.Location,
+		Status:       cluster.Status,
+		Source:       cluster.Source,
+		Tags:         cluster.Tags,
+		NewsIDs:      make([]uint, 0, len(cluster.NewsList)),
+		StartTime:    cluster.StartTime,
+		EndTime:      cluster.EndTime,
+		HotnessScore: cluster.HotnessScore,
+	}
+	for _, news := range cluster.NewsList {
+		proposal.NewsIDs = append(proposal.NewsIDs, news.ID)
+		if news.BelongedEventID != nil && !containsID(proposal.CurrentEventIDs, *news.BelongedEventID) {
+			proposal.CurrentEventIDs = append(proposal.CurrentEventIDs, *news.BelongedEventID)
+		}
+	}
+
+	return storedEventProposal{Proposal: proposal, NewsEvents: newsEventMap(cluster.NewsList)}
+}
+
+func newsEventMap(newsList []models.News) map[uint]uint {
+	events := make(map[uint]uint, len(newsList))
+	for _, news := range newsList {
+		if news.BelongedEventID != nil {
+			events[news.ID] = *news.BelongedEventID
+		} else {
+			events[news.ID] = 0
+		}
+	}
+	return events
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func buildGenerationPreview(record *models.EventGenerationPlan, stored []storedEventProposal) *models.EventGenerationPreview {
+	preview := &models.EventGenerationPreview{
+		PlanID:        record.ID,
+		Mode:          record.Mode,
+		Proposals:     make([]models.EventProposal, 0, len(stored)),
+		ProcessedNews: record.ProcessedNews,
+		ExpiresAt:     record.ExpiresAt,
+	}
+	for _, item := range stored {
+		preview.Proposals = append(preview.Proposals, item.Proposal)
+		if item.Proposal.Action == models.EventProposalAttach {
+			preview.AttachedEvents++
+		} else {
+			preview.NewEvents++
+		}
+	}
+	return preview
+}
+
+// loadGenerationPlan 读取生成计划及其提案
+func (s *EventService) loadGenerationPlan(tx *gorm.DB, id uint) (*models.EventGenerationPlan, []storedEventProposal, error) {
+	var record models.EventGenerationPlan
+	if err := tx.First(&record, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("generation plan not found")
+		}
+		return nil, nil, err
+	}
+
+	var stored []storedEventProposal
+	if err := json.Unmarshal([]byte(record.Proposals), &stored); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode generation plan: %w", err)
+	}
+	return &record, stored, nil
+}
+
+// GetEventGenerationPlan 获取预演生成的提案及提交情况
+func (s *EventService) GetEventGenerationPlan(id uint) (*models.EventGenerationPreview, error) {
+	record, stored, err := s.loadGenerationPlan(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return buildGenerationPreview(record, stored), nil
+}
+
+// CommitEventProposals 提交生成计划中选中的提案
+// 预演之后新闻的事件归属发生变化、新闻被删除或要归入的事件已不存在的提案会被跳过，不会覆盖期间的其他修改
+func (s *EventService) CommitEventProposals(req *models.CommitEventProposalsRequest) (*models.CommitEventProposalsResult, error) {
+	if !eventGenerationMu.TryLock() {
+		return nil, ErrEventGenerationRunning
+	}
+	defer eventGenerationMu.Unlock()
+
+	record, stored, err := s.loadGenerationPlan(s.db, req.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return nil, errors.New("generation plan expired")
+	}
+
+	index := make(map[int]int, len(stored))
+	for i, item := range stored {
+		index[item.Proposal.ID] = i
+	}
+
+	result := &models.CommitEventProposalsResult{
+		PlanID:          record.ID,
+		GeneratedEvents: []models.EventResponse{},
+		UpdatedEvents:   []models.EventResponse{},
+		Skipped:         []models.SkippedEventProposal{},
+	}
+	skip := func(id int, reason string) {
+		result.Skipped = append(result.Skipped, models.SkippedEventProposal{ProposalID: id, Reason: reason})
+	}
+
+	committed := 0
+	seen := make(map[int]bool, len(req.ProposalIDs))
+	for _, id := range req.ProposalIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		i, ok := index[id]
+		if !ok {
+			skip(id, "proposal not found")
+			continue
+		}
+		item := &stored[i]
+		if item.Proposal.CommittedEventID != nil {
+			skip(id, "proposal already committed")
+			continue
+		}
+
+		newsList, reason, err := s.proposalNews(item)
+		if err != nil {
+			return nil, s.saveCommittedProposals(record, stored, committed, err)
+		}
+		if reason != "" {
+			skip(id, reason)
+			continue
+		}
+
+		var event *models.EventResponse
+		if item.Proposal.Action == models.EventProposalAttach {
+			var count int64
+			if err := s.db.Model(&models.Event{}).Where("id = ?", *item.Proposal.AttachToEventID).Count(&count).Error; err != nil {
+				return nil, s.saveCommittedProposals(record, stored, committed, err)
+			}
+			if count == 0 {
+				skip(id, "event no longer exists")
+				continue
+			}
+			if event, err = s.attachNewsToEvent(*item.Proposal.AttachToEventID, newsList); err != nil {
+				return nil, s.saveCommittedProposals(record, stored, committed, err)
+			}
+			result.UpdatedEvents = append(result.UpdatedEvents, *event)
+		} else {
+			cluster := &EventCluster{
+				Title:        item.Proposal.Title,
+				Description:  item.Proposal.Description,
+				Category:     item.Proposal.Category,
+				StartTime:    item.Proposal.StartTime,
+				EndTime:      item.Proposal.EndTime,
+				Location:     item.Proposal.Location,
+				Status:       item.Proposal.Status,
+				Tags:         item.Proposal.Tags,
+				Source:       item.Proposal.Source,
+				NewsList:     newsList,
+				HotnessScore: item.Proposal.HotnessScore,
+			}
+			events, err := s.saveClusters([]*EventCluster{cluster})
+			if err != nil {
+				return nil, s.saveCommittedProposals(record, stored, committed, err)
+			}
+			event = &events[0]
+			result.GeneratedEvents = append(result.GeneratedEvents, *event)
+		}
+
+		now := time.Now()
+		eventID := event.ID
+		item.Proposal.CommittedEventID = &eventID
+		item.Proposal.CommittedAt = &now
+		committed++
+	}
+
+	if err := s.saveCommittedProposals(record, stored, committed, nil); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// proposalNews 读取提案中的新闻，新闻被删除或事件归属在预演后发生变化时返回跳过原因
+func (s *EventService) proposalNews(item *storedEventProposal) ([]models.News, string, error) {
+	var newsList []models.News
+	if err := s.db.Where("id IN ?", item.Proposal.NewsIDs).
+		Order("published_at ASC").
+		Find(&newsList).Error; err != nil {
+		return nil, "", err
+	}
+	if len(newsList) != len(item.Proposal.NewsIDs) {
+		return nil, "news was deleted after preview", nil
+	}
+
+	for _, news := range newsList {
+		var current uint
+		if news.BelongedEventID != nil {
+			current = *news.BelongedEventID
+		}
+		if current != item.NewsEvents[news.ID] {
+			return nil, "news association changed after preview", nil
+		}
+	}
+	return newsList, "", nil
+}
+
+// saveCommittedProposals 保存提案的提交状态，cause 不为空时在保存后原样返回
+func (s *EventService) saveCommittedProposals(record *models.EventGenerationPlan, stored []storedEventProposal, committed int, cause error) error {
+	if committed > 0 {
+		data, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		if err := s.db.Model(record).Update("proposals", string(data)).Error; err != nil {
+			if cause != nil {
+				return cause
+			}
+			return fmt.Errorf("保存提案提交状态失败: %w", err)
+		}
+	}
+	return cause
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// openEventWindow 结束时间在此窗口内的事件仍视为开放，可以继续接收新闻
+	openEventWindow = 72 * time.Hour
+	// eventMatchSlack 新闻发布时间超出事件时间跨度的容差
+	eventMatchSlack = 48 * time.Hour
+	// defaultEventDuration 单条新闻默认延续的事件时长
+	defaultEventDuration = 24 * time.Hour
+)
+
+// ErrEventGenerationRunning 已有事件生成任务在执行
+var ErrEventGenerationRunning = errors.New("event generation already in progress")
+
+// eventGenerationMu 防止定时任务与管理员手动触发的生成任务并发执行
+var eventGenerationMu sync.Mutex
+
+// GenerationProgress 事件生成进度回调，done/total 为已执行和全部的事件变更数，partial 为目前的结果
+type GenerationProgress func(done, total int, partial *EventGenerationResult)
+
+// GenerateEventsIncrementally 增量生成事件
+// 只处理尚未关联事件的新闻：优先归入开放中的事件并更新其时间跨度、标签和相关链接，
+// 剩余新闻再聚类生成新事件
+func (s *EventService) GenerateEventsIncrementally() (*EventGenerationResult, error) {
+	return s.GenerateEvents(context.Background(), "incremental", nil)
+}
+
+// GenerateEvents 按模式生成事件，每完成一个事件的新建或更新后汇报进度
+// ctx 取消或某个事件保存失败时停止执行后续变更，返回已完成部分的结果和对应的错误
+func (s *EventService) GenerateEvents(ctx context.Context, mode string, progress GenerationProgress) (*EventGenerationResult, error) {
+	if !eventGenerationMu.TryLock() {
+		return nil, ErrEventGenerationRunning
+	}
+	defer eventGenerationMu.Unlock()
+
+	startTime := time.Now()
+
+	plan, err := s.planGeneration(mode, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EventGenerationResult{
+		Mode:              plan.mode,
+		GeneratedEvents:   []models.EventResponse{},
+		UpdatedEvents:     []models.EventResponse{},
+		ProcessedNews:     plan.processedNews,
+		CategoryBreakdown: plan.categoryBreakdown,
+	}
+	finish := func() {
+		result.TotalEvents = len(result.GeneratedEvents)
+		result.GenerationTime = time.Now()
+		result.ElapsedTime = time.Since(startTime).String()
+	}
+
+	total := len(plan.attachments) + len(plan.clusters)
+	done := 0
+	report := func() {
+		done++
+		if progress != nil {
+			finish()
+			progress(done, total, result)
+		}
+	}
+	if progress != nil {
+		progress(0, total, result)
+	}
+
+	// 更新被追加新闻的事件
+	for _, attachment := range plan.attachments {
+		if err := ctx.Err(); err != nil {
+			finish()
+			return result, err
+		}
+		updated, err := s.attachNewsToEvent(attachment.event.ID, attachment.newsList)
+		if err != nil {
+			finish()
+			return result, err
+		}
+		result.UpdatedEvents = append(result.UpdatedEvents, *updated)
+		result.AttachedNews += len(attachment.newsList)
+		report()
+	}
+
+	// 剩余新闻聚类生成新事件，按热度从高到低保存
+	for _, cluster := range plan.clusters {
+		if err := ctx.Err(); err != nil {
+			finish()
+			return result, err
+		}
+		generated, err := s.saveClusters([]*EventCluster{cluster})
+		if err != nil {
+			finish()
+			return result, err
+		}
+		result.GeneratedEvents = append(result.GeneratedEvents, generated...)
+		report()
+	}
+
+	finish()
+	return result, nil
+}
+
+// attachNewsToEvent 将新闻追加到已有事件，更新时间跨度、标签、相关链接和内容，并重新计算热度
+func (s *EventService) attachNewsToEvent(eventID uint, newsList []models.News) (*models.EventResponse, error) {
+	var event models.Event
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&event, eventID).Error; err != nil {
+			return err
+		}
+
+		newsIDs := s.mergeNewsIntoEvent(&event, newsList)
+
+		if err := syncEventStatus(tx, &event, "news attached"); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&event).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.News{}).
+			Where("id IN ?", newsIDs).
+			Update("belonged_event_id", event.ID).Error; err != nil {
+			return err
+		}
+		if err := linkEventTags(tx, &event); err != nil {
+			return err
+		}
+		if err := indexEvent(tx, &event); err != nil {
+			return err
+		}
+		return syncEventEntities(tx, event.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("更新事件 %d 失败: %w", eventID, err)
+	}
+
+	if _, err := s.CalculateHotness(event.ID, nil); err != nil {
+		log.Printf("[EVENT WARNING] failed to recalculate hotness for event %d: %v", event.ID, err)
+	}
+
+	response := convertToEventResponse(&event)
+	return &response, nil
+}
+
+// mergeNewsIntoEvent 将新闻并入事件的时间跨度、标签、相关链接和内容，事件还没有明确地点时用新闻补充，返回新闻ID
+// 已属于该事件的新闻会被跳过，避免重复追加内容
+func (s *EventService) mergeNewsIntoEvent(event *models.Event, newsList []models.News) []uint {
+	tags := jsonToSlice(event.Tags)
+	links := jsonToSlice(event.RelatedLinks)
+	newsIDs := make([]uint, 0, len(newsList))
+	merged := make([]models.News, 0, len(newsList))
+	for _, news := range newsList {
+		if news.BelongedEventID != nil && *news.BelongedEventID == event.ID {
+			continue
+		}
+		extendEventSpan(event, news.PublishedAt)
+
+		for _, tag := range s.extractTags(news) {
+			if !s.contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if news.Link != "" && !s.contains(links, news.Link) {
+			links = append(links, news.Link)
+		}
+
+		event.Content += formatNewsSection(news)
+		newsIDs = append(newsIDs, news.ID)
+		merged = append(merged, news)
+	}
+
+	event.Tags = sliceToJSON(tags)
+	event.RelatedLinks = sliceToJSON(links)
+
+	if event.LocationCode == "" {
+		if place, ok := extractNewsLocation(merged); ok {
+			event.Location = place.Name
+			applyEventLocation(event)
+		}
+	}
+	return newsIDs
+}
+
+// extendEventSpan 按新闻发布时间扩展事件的时间跨度
+func extendEventSpan(event *models.Event, publishedAt time.Time) {
+	if publishedAt.IsZero() {
+		return
+	}
+	if publishedAt.Before(event.StartTime) {
+		event.StartTime = publishedAt
+	}
+	if end := publishedAt.Add(defaultEventDuration); end.After(event.EndTime) {
+		event.EndTime = end
+	}
+}
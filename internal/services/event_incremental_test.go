@@ -0,0 +1,60 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+)
+
+func TestMergeNewsIntoEventRefetchedNews(t *testing.T) {
+	eventID := uint(7)
+	publishedAt := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	existing := models.News{
+		ID:              11,
+		Title:           "芯片出口管制升级",
+		Summary:         "旧摘要",
+		Link:            "https://example.com/a",
+		PublishedAt:     publishedAt,
+		BelongedEventID: &eventID,
+	}
+
+	// 重新抓取同一条新闻：统计数据和事件关联沿用已有记录
+	refetched := models.News{
+		Title:       existing.Title,
+		Summary:     "新摘要",
+		Link:        existing.Link,
+		PublishedAt: publishedAt,
+	}
+	keepExistingNewsState(&refetched, &existing)
+	if refetched.ID != existing.ID {
+		t.Fatalf("ID = %d, want %d", refetched.ID, existing.ID)
+	}
+	if refetched.BelongedEventID == nil || *refetched.BelongedEventID != eventID {
+		t.Fatalf("BelongedEventID = %v, want %d", refetched.BelongedEventID, eventID)
+	}
+
+	other := models.News{ID: 12, Title: "晶圆厂扩产", Link: "https://example.com/b", PublishedAt: publishedAt.Add(time.Hour)}
+	event := models.Event{
+		Content:      formatNewsSection(existing),
+		LocationCode: "CN",
+		StartTime:    publishedAt,
+		EndTime:      publishedAt,
+	}
+	event.ID = eventID
+
+	got := (&EventService{}).mergeNewsIntoEvent(&event, []models.News{refetched, other})
+	if len(got) != 1 || got[0] != other.ID {
+		t.Fatalf("merged news IDs = %v, want [%d]", got, other.ID)
+	}
+	if n := strings.Count(event.Content, "### "+existing.Title); n != 1 {
+		t.Errorf("existing news section appears %d times, want 1", n)
+	}
+	if !strings.Contains(event.Content, "### "+other.Title) {
+		t.Errorf("content misses section for news %d", other.ID)
+	}
+	if want := other.PublishedAt.Add(defaultEventDuration); !event.EndTime.Equal(want) {
+		t.Errorf("EndTime = %v, want %v", event.EndTime, want)
+	}
+}
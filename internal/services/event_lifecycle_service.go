@@ -0,0 +1,312 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// lifecycleOptions 事件生命周期的时间参数
+type lifecycleOptions struct {
+	activeWindow time.Duration // 最近一条报道之后仍视为进行中的时长
+	cooling      time.Duration // 进行中结束后的降温观察期
+	archiveAfter time.Duration // 进行中结束后多久归档
+}
+
+func loadLifecycleOptions() lifecycleOptions {
+	opts := lifecycleOptions{
+		activeWindow: 24 * time.Hour,
+		cooling:      72 * time.Hour,
+		archiveAfter: 30 * 24 * time.Hour,
+	}
+
+	if config.AppConfig == nil {
+		return opts
+	}
+
+	cfg := config.AppConfig.Lifecycle
+	if cfg.ActiveWindowHours > 0 {
+		opts.activeWindow = time.Duration(cfg.ActiveWindowHours) * time.Hour
+	}
+	if cfg.CoolingHours > 0 {
+		opts.cooling = time.Duration(cfg.CoolingHours) * time.Hour
+	}
+	if cfg.ArchiveAfterDays > 0 {
+		opts.archiveAfter = time.Duration(cfg.ArchiveAfterDays) * 24 * time.Hour
+	}
+	if opts.archiveAfter < opts.cooling {
+		opts.archiveAfter = opts.cooling
+	}
+	return opts
+}
+
+// computeLifecycleStatus 按事件时间跨度和最近一条报道的时间计算事件应处的状态
+// lastNewsAt 为零值表示没有关联新闻
+func computeLifecycleStatus(startTime, endTime, lastNewsAt, now time.Time, opts lifecycleOptions) string {
+	if now.Before(startTime) {
+		return models.EventStatusUpcoming
+	}
+
+	activeUntil := endTime
+	if !lastNewsAt.IsZero() {
+		if t := lastNewsAt.Add(opts.activeWindow); t.After(activeUntil) {
+			activeUntil = t
+		}
+	}
+
+	switch {
+	case !now.After(activeUntil):
+		return models.EventStatusOngoing
+	case now.Before(activeUntil.Add(opts.cooling)):
+		return models.EventStatusCooling
+	case now.Before(activeUntil.Add(opts.archiveAfter)):
+		return models.EventStatusEnded
+	default:
+		return models.EventStatusArchived
+	}
+}
+
+// initialEventStatus 新建事件的初始状态
+func initialEventStatus(startTime, endTime time.Time) string {
+	status := computeLifecycleStatus(startTime, endTime, time.Time{}, time.Now(), loadLifecycleOptions())
+	// 新建的事件不会直接归档
+	if status == models.EventStatusArchived {
+		return models.EventStatusEnded
+	}
+	return status
+}
+
+// eventStatusRank 状态在生命周期中的位置
+func eventStatusRank(status string) int {
+	for i, code := range models.EventStatuses {
+		if code == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// transitionEventStatus 将事件转换到目标状态，沿允许的转换路径逐步记录历史，调用方负责保存事件
+func transitionEventStatus(tx *gorm.DB, event *models.Event, to, trigger, reason string, operatorID *uint) error {
+	from := event.Status
+	path := models.EventStatusPath(from, to)
+	if path == nil {
+		return fmt.Errorf("invalid status transition from %s to %s", from, to)
+	}
+
+	for _, next := range path {
+		history := models.EventStatusHistory{
+			EventID:    event.ID,
+			FromStatus: from,
+			ToStatus:   next,
+			Trigger:    trigger,
+			Reason:     reason,
+			OperatorID: operatorID,
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+		from = next
+	}
+	event.Status = to
+	return nil
+}
+
+// recordEventCreated 记录新建事件的初始状态
+func recordEventCreated(tx *gorm.DB, event *models.Event, reason string, operatorID *uint) error {
+	trigger := models.EventTransitionAuto
+	if operatorID != nil {
+		trigger = models.EventTransitionManual
+	}
+	return tx.Create(&models.EventStatusHistory{
+		EventID:    event.ID,
+		ToStatus:   event.Status,
+		Trigger:    trigger,
+		Reason:     reason,
+		OperatorID: operatorID,
+	}).Error
+}
+
+// latestNewsTime 事件关联新闻中最新的发布时间，没有关联新闻时返回零值
+func latestNewsTime(tx *gorm.DB, eventID uint) (time.Time, error) {
+	var latest sql.NullTime
+	if err := tx.Model(&models.News{}).
+		Select("MAX(published_at)").
+		Where("belonged_event_id = ?", eventID).
+		Scan(&latest).Error; err != nil {
+		return time.Time{}, err
+	}
+	return latest.Time, nil
+}
+
+// syncEventStatus 事件的新闻或时间跨度变化后重新计算状态
+// 与定时任务不同，有新报道时允许降温中和已结束的事件重新进入进行中；已归档的事件保持不变
+func syncEventStatus(tx *gorm.DB, event *models.Event, reason string) error {
+	if event.Status == models.EventStatusArchived {
+		return nil
+	}
+	if code, ok := models.NormalizeEventStatus(event.Status); ok {
+		event.Status = code
+	}
+
+	lastNewsAt, err := latestNewsTime(tx, event.ID)
+	if err != nil {
+		return err
+	}
+	target := computeLifecycleStatus(event.StartTime, event.EndTime, lastNewsAt, time.Now(), loadLifecycleOptions())
+	if target == event.Status || target == models.EventStatusArchived {
+		return nil
+	}
+	return transitionEventStatus(tx, event, target, models.EventTransitionAuto, reason, nil)
+}
+
+// ChangeEventStatus 管理员手动修改事件状态，只允许直接转换
+func (s *EventService) ChangeEventStatus(id uint, req *models.TransitionEventStatusRequest, operatorID *uint) (*models.EventResponse, error) {
+	to, ok := models.NormalizeEventStatus(req.Status)
+	if !ok {
+		return nil, errors.New("invalid event status")
+	}
+
+	var event models.Event
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&event, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("event not found")
+			}
+			return err
+		}
+		if code, ok := models.NormalizeEventStatus(event.Status); ok {
+			event.Status = code
+		}
+		if event.Status == to {
+			return nil
+		}
+		if !models.CanTransitionEventStatus(event.Status, to) {
+			return fmt.Errorf("invalid status transition from %s to %s", event.Status, to)
+		}
+
+		reason := req.Reason
+		if reason == "" {
+			reason = "manual change"
+		}
+		if err := transitionEventStatus(tx, &event, to, models.EventTransitionManual, reason, operatorID); err != nil {
+			return err
+		}
+		return tx.Model(&event).Update("status", event.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := convertToEventResponse(&event)
+	return &response, nil
+}
+
+// GetEventStatusHistory 获取事件的状态转换记录，按时间倒序
+func (s *EventService) GetEventStatusHistory(id uint) ([]models.EventStatusHistory, error) {
+	var count int64
+	if err := s.db.Model(&models.Event{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("event not found")
+	}
+
+	history := make([]models.EventStatusHistory, 0)
+	if err := s.db.Where("event_id = ?", id).
+		Order("created_at DESC, id DESC").
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// RunLifecycleTransitions 按时间和新闻活跃度推进事件状态
+// 定时任务只向生命周期后段推进，不会撤销管理员提前结束或归档的操作
+func (s *EventService) RunLifecycleTransitions() (*models.EventLifecycleResult, error) {
+	start := time.Now()
+	opts := loadLifecycleOptions()
+	result := &models.EventLifecycleResult{Transitions: make(map[string]int)}
+
+	var events []models.Event
+	err := s.db.Where("status <> ?", models.EventStatusArchived).
+		FindInBatches(&events, 200, func(batchTx *gorm.DB, batch int) error {
+			ids := make([]uint, len(events))
+			for i := range events {
+				ids[i] = events[i].ID
+			}
+
+			var latest []struct {
+				BelongedEventID uint
+				LastPublished   time.Time
+			}
+			if err := s.db.Model(&models.News{}).
+				Select("belonged_event_id, MAX(published_at) AS last_published").
+				Where("belonged_event_id IN ?", ids).
+				Group("belonged_event_id").
+				Scan(&latest).Error; err != nil {
+				return err
+			}
+			lastNewsAt := make(map[uint]time.Time, len(latest))
+			for _, row := range latest {
+				lastNewsAt[row.BelongedEventID] = row.LastPublished
+			}
+
+			now := time.Now()
+			for i := range events {
+				event := &events[i]
+				result.Checked++
+
+				from, ok := models.NormalizeEventStatus(event.Status)
+				if !ok {
+					log.Printf("[LIFECYCLE WARNING] event %d has unknown status %q", event.ID, event.Status)
+					continue
+				}
+				event.Status = from
+
+				target := computeLifecycleStatus(event.StartTime, event.EndTime, lastNewsAt[event.ID], now, opts)
+				if eventStatusRank(target) <= eventStatusRank(from) {
+					continue
+				}
+
+				err := s.db.Transaction(func(tx *gorm.DB) error {
+					if err := transitionEventStatus(tx, event, target, models.EventTransitionAuto, "scheduled", nil); err != nil {
+						return err
+					}
+					return tx.Model(event).Update("status", event.Status).Error
+				})
+				if err != nil {
+					return fmt.Errorf("failed to transition event %d: %w", event.ID, err)
+				}
+				result.Transitions[from+"->"+target]++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result.Duration = time.Since(start).String()
+	return result, nil
+}
+
+// MigrateLegacyEventStatuses 将旧数据中的中文事件状态统一为状态代码
+func (s *EventService) MigrateLegacyEventStatuses() error {
+	for _, code := range models.EventStatuses {
+		label := models.EventStatusLabel(code, "zh")
+		result := s.db.Model(&models.Event{}).Where("status = ?", label).Update("status", code)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("[LIFECYCLE] Migrated %d events from status %s to %s", result.RowsAffected, label, code)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/geo"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultEventLocation  = "全国" // 没有抽取到明确地点时的事件位置
+	locationTitleWeight   = 3.0  // 标题中的地名权重
+	locationContentWeight = 1.0  // 正文中的地名权重
+	locationContentChars  = 500  // 每篇新闻参与地名抽取的正文长度
+	minLocationScore      = 2.0  // 至少一次标题命中或两次正文命中才认为地点明确
+	defaultNearRadiusKm   = 50.0
+	maxNearRadiusKm       = 20000.0
+)
+
+// extractNewsLocation 从一组新闻的标题和正文中抽取主要地点
+func extractNewsLocation(newsList []models.News) (*geo.Place, bool) {
+	extractor := geo.Default().NewExtractor()
+	for _, news := range newsList {
+		extractor.Add(news.Title, locationTitleWeight)
+		body := news.Summary
+		if news.Content != "" {
+			body = news.Content
+		} else if news.Description != "" {
+			body = news.Description
+		}
+		extractor.Add(truncateRunes(nlp.StripHTML(body), locationContentChars), locationContentWeight)
+	}
+	return extractor.Dominant(minLocationScore)
+}
+
+// applyEventLocation 按事件的 location 在地名库中解析编码和坐标，无法解析时清空
+func applyEventLocation(event *models.Event) bool {
+	place, ok := geo.Default().Lookup(event.Location)
+	if !ok {
+		event.LocationCode = ""
+		event.Latitude = nil
+		event.Longitude = nil
+		return false
+	}
+	lat, lon := place.Latitude, place.Longitude
+	event.LocationCode = place.Code
+	event.Latitude = &lat
+	event.Longitude = &lon
+	return true
+}
+
+// geoFilter 解析后的地理筛选条件
+type geoFilter struct {
+	near     bool
+	lat, lon float64
+	radiusKm float64
+
+	bbox                           bool
+	minLon, minLat, maxLon, maxLat float64
+}
+
+// parseGeoFilter 解析 near/radius/bbox 查询参数
+func parseGeoFilter(query *models.EventQueryRequest) (*geoFilter, error) {
+	filter := &geoFilter{}
+
+	if query.Near != "" {
+		values, ok := parseFloats(query.Near, 2)
+		if !ok || math.Abs(values[0]) > 90 || math.Abs(values[1]) > 180 {
+			return nil, errors.New("invalid near parameter")
+		}
+		radius := query.Radius
+		if radius == 0 {
+			radius = defaultNearRadiusKm
+		}
+		if radius < 0 || radius > maxNearRadiusKm {
+			return nil, errors.New("invalid radius parameter")
+		}
+		filter.near = true
+		filter.lat, filter.lon, filter.radiusKm = values[0], values[1], radius
+	} else if query.SortBy == "distance" {
+		return nil, errors.New("sort by distance requires near parameter")
+	}
+
+	if query.BBox != "" {
+		values, ok := parseFloats(query.BBox, 4)
+		if !ok || math.Abs(values[0]) > 180 || math.Abs(values[2]) > 180 ||
+			math.Abs(values[1]) > 90 || math.Abs(values[3]) > 90 || values[1] > values[3] {
+			return nil, errors.New("invalid bbox parameter")
+		}
+		filter.bbox = true
+		filter.minLon, filter.minLat, filter.maxLon, filter.maxLat = values[0], values[1], values[2], values[3]
+	}
+
+	return filter, nil
+}
+
+func parseFloats(value string, count int) ([]float64, bool) {
+	parts := strings.Split(value, ",")
+	if len(parts) != count {
+		return nil, false
+	}
+	values := make([]float64, count)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
+
+// distanceSQL 计算事件与查询点球面距离（公里）的 haversine 表达式
+const distanceSQL = "6371 * 2 * ASIN(LEAST(1, SQRT(POWER(SIN(RADIANS(latitude - ?) / 2), 2) + " +
+	"COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2))))"
+
+// apply 将地理筛选加到查询上：先用经纬度范围走索引预筛，再按 haversine 距离精确过滤
+func (f *geoFilter) apply(db *gorm.DB) *gorm.DB {
+	if !f.near && !f.bbox {
+		return db
+	}
+	db = db.Where("latitude IS NOT NULL AND longitude IS NOT NULL")
+
+	if f.bbox {
+		db = whereLatLonBox(db, f.minLat, f.minLon, f.maxLat, f.maxLon)
+	}
+	if f.near {
+		minLat, minLon, maxLat, maxLon := geo.BoundingBox(f.lat, f.lon, f.radiusKm)
+		db = whereLatLonBox(db, minLat, minLon, maxLat, maxLon)
+		db = db.Where(distanceSQL+" <= ?", f.lat, f.lat, f.lon, f.radiusKm)
+	}
+	return db
+}
+
+// orderByDistance 按与查询点的距离由近到远排序
+func (f *geoFilter) orderByDistance(db *gorm.DB) *gorm.DB {
+	return db.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                distanceSQL + " ASC, created_at DESC",
+		Vars:               []interface{}{f.lat, f.lat, f.lon},
+		WithoutParentheses: true,
+	}})
+}
+
+// fillDistance 为按 near 查询的结果填充距离
+func (f *geoFilter) fillDistance(response *models.EventResponse) {
+	if !f.near || response.Latitude == nil || response.Longitude == nil {
+		return
+	}
+	distance := math.Round(geo.DistanceKm(f.lat, f.lon, *response.Latitude, *response.Longitude)*10) / 10
+	response.DistanceKm = &distance
+}
+
+func whereLatLonBox(db *gorm.DB, minLat, minLon, maxLat, maxLon float64) *gorm.DB {
+	db = db.Where("latitude BETWEEN ? AND ?", minLat, maxLat)
+	if minLon <= maxLon {
+		return db.Where("longitude BETWEEN ? AND ?", minLon, maxLon)
+	}
+	// 跨越日期变更线
+	return db.Where("(longitude >= ? OR longitude <= ?)", minLon, maxLon)
+}
+
+// GeocodeEvents 重新解析事件的地理位置，reextract 时先从关联新闻重新抽取地点
+func (s *EventService) GeocodeEvents(req *models.GeocodeEventsRequest) (*models.GeocodeEventsResult, error) {
+	result := &models.GeocodeEventsResult{}
+
+	db := s.db.Model(&models.Event{})
+	if len(req.IDs) > 0 {
+		db = db.Where("id IN ?", req.IDs)
+	}
+
+	var events []models.Event
+	err := db.FindInBatches(&events, 100, func(tx *gorm.DB, batch int) error {
+		for i := range events {
+			event := &events[i]
+			result.Processed++
+
+			if req.Reextract {
+				var newsList []models.News
+				if err := s.db.Where("belonged_event_id = ?", event.ID).
+					Order("published_at ASC").Find(&newsList).Error; err != nil {
+					return err
+				}
+				location := defaultEventLocation
+				if place, ok := extractNewsLocation(newsList); ok {
+					location = place.Name
+				}
+				if len(newsList) > 0 && location != event.Location {
+					event.Location = location
+					result.Relocated++
+				}
+			}
+
+			if applyEventLocation(event) {
+				result.Geocoded++
+			} else {
+				result.Unresolved++
+			}
+
+			if err := s.db.Model(event).UpdateColumns(map[string]interface{}{
+				"location":      event.Location,
+				"location_code": event.LocationCode,
+				"latitude":      event.Latitude,
+				"longitude":     event.Longitude,
+				"updated_at":    time.Now(),
+			}).Error; err != nil {
+				return fmt.Errorf("failed to update event %d: %w", event.ID, err)
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,792 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxRedirectHops 解析事件重定向时最多跟随的次数
+const maxRedirectHops = 5
+
+// eventOperationSnapshot 合并/拆分前的状态快照
+type eventOperationSnapshot struct {
+	Events          []models.Event         `json:"events"`           // 操作前的事件，第一个为目标事件（合并）或原事件（拆分）
+	NewsEvents      map[uint]uint          `json:"news_events"`      // 新闻ID -> 操作前所属事件ID
+	CommentEvents   map[uint]uint          `json:"comment_events"`   // 评论ID -> 操作前所属事件ID
+	MilestoneEvents map[uint]uint          `json:"milestone_events"` // 里程碑ID -> 操作前所属事件ID
+	Redirects       []models.EventRedirect `json:"redirects"`        // 被改为指向目标事件的已有重定向
+	Relations       []models.EventRelation `json:"relations"`        // 合并时改为指向目标事件或被去掉的事件关系，改动的在前、去掉的在后
+}
+
+// MergeEvents 将多个事件合并到目标事件
+// 新闻、评论和里程碑移到目标事件，计数累加，标签和相关链接取并集，被合并的事件软删除并保留到目标事件的重定向；
+// 被合并事件的关系改为指向目标事件，变成自身关联或与已有关系重复的关系去掉
+func (s *EventService) MergeEvents(req *models.MergeEventsRequest, operatorID uint) (*models.EventOperationResult, error) {
+	sourceIDs := uniqueIDs(req.SourceIDs)
+	if len(sourceIDs) == 0 {
+		return nil, errors.New("no source events")
+	}
+	for _, id := range sourceIDs {
+		if id == req.TargetID {
+			return nil, errors.New("cannot merge an event into itself")
+		}
+	}
+
+	var operation models.EventOperation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var target models.Event
+		if err := tx.First(&target, req.TargetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("event not found")
+			}
+			return err
+		}
+
+		var sources []models.Event
+		if err := tx.Where("id IN ?", sourceIDs).Order("id ASC").Find(&sources).Error; err != nil {
+			return err
+		}
+		if len(sources) != len(sourceIDs) {
+			return errors.New("event not found")
+		}
+
+		snapshot := eventOperationSnapshot{
+			Events:          append([]models.Event{target}, sources...),
+			NewsEvents:      make(map[uint]uint),
+			CommentEvents:   make(map[uint]uint),
+			MilestoneEvents: make(map[uint]uint),
+		}
+
+		var newsList []models.News
+		if err := tx.Where("belonged_event_id IN ?", sourceIDs).Order("published_at ASC").Find(&newsList).Error; err != nil {
+			return err
+		}
+		newsIDs := make([]uint, 0, len(newsList))
+		for _, news := range newsList {
+			snapshot.NewsEvents[news.ID] = *news.BelongedEventID
+			newsIDs = append(newsIDs, news.ID)
+		}
+
+		var comments []models.Comment
+		if err := tx.Select("id", "target_id").
+			Where("target_type = ? AND target_id IN ?", models.CommentTargetEvent, sourceIDs).
+			Find(&comments).Error; err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			snapshot.CommentEvents[comment.ID] = comment.TargetID
+		}
+
+		var milestones []models.EventMilestone
+		if err := tx.Select("id", "event_id").Where("event_id IN ?", sourceIDs).Find(&milestones).Error; err != nil {
+			return err
+		}
+		for _, milestone := range milestones {
+			snapshot.MilestoneEvents[milestone.ID] = milestone.EventID
+		}
+
+		if err := tx.Where("to_event_id IN ?", sourceIDs).Find(&snapshot.Redirects).Error; err != nil {
+			return err
+		}
+
+		// 包括已删除的关系：它们阻止再次自动建立同一对事件的关系，也需要跟着迁移
+		involved := append([]uint{target.ID}, sourceIDs...)
+		var relations []models.EventRelation
+		if err := tx.Unscoped().Where("from_event_id IN ? OR to_event_id IN ?", involved, involved).
+			Order("id ASC").Find(&relations).Error; err != nil {
+			return err
+		}
+		updatedRelations, removedRelations := mergeRelations(relations, target.ID, sourceIDs)
+		originals := make(map[uint]models.EventRelation, len(relations))
+		for _, relation := range relations {
+			originals[relation.ID] = relation
+		}
+		for _, relation := range updatedRelations {
+			snapshot.Relations = append(snapshot.Relations, originals[relation.ID])
+		}
+		for _, id := range removedRelations {
+			snapshot.Relations = append(snapshot.Relations, originals[id])
+		}
+
+		// 合并计数、标签和相关链接
+		tags := jsonToSlice(target.Tags)
+		links := jsonToSlice(target.RelatedLinks)
+		for _, source := range sources {
+			target.ViewCount += source.ViewCount
+			target.LikeCount += source.LikeCount
+			target.CommentCount += source.CommentCount
+			target.ShareCount += source.ShareCount
+			tags = unionStrings(tags, jsonToSlice(source.Tags))
+			links = unionStrings(links, jsonToSlice(source.RelatedLinks))
+		}
+		target.Tags = sliceToJSON(tags)
+		target.RelatedLinks = sliceToJSON(links)
+		for _, news := range newsList {
+			target.Content += formatNewsSection(news)
+		}
+
+		// 移动新闻、评论和里程碑
+		if err := tx.Model(&models.News{}).Where("belonged_event_id IN ?", sourceIDs).
+			Update("belonged_event_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Comment{}).
+			Where("target_type = ? AND target_id IN ?", models.CommentTargetEvent, sourceIDs).
+			Update("target_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.EventMilestone{}).Where("event_id IN ?", sourceIDs).
+			Update("event_id", target.ID).Error; err != nil {
+			return err
+		}
+
+		// 先去掉重复的关系再迁移，避免与唯一索引冲突
+		if len(removedRelations) > 0 {
+			if err := tx.Unscoped().Where("id IN ?", removedRelations).Delete(&models.EventRelation{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, relation := range updatedRelations {
+			if err := tx.Unscoped().Model(&models.EventRelation{}).Where("id = ?", relation.ID).Updates(map[string]interface{}{
+				"from_event_id": relation.FromEventID,
+				"to_event_id":   relation.ToEventID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := s.recomputeEventSpan(tx, &target, "events merged"); err != nil {
+			return err
+		}
+		if err := syncEventEntities(tx, involved...); err != nil {
+			return err
+		}
+		if err := tx.Save(&target).Error; err != nil {
+			return err
+		}
+
+		operation = models.EventOperation{
+			Type:           models.EventOperationMerge,
+			TargetEventID:  target.ID,
+			SourceEventIDs: idsToJSON(sourceIDs),
+			NewsIDs:        idsToJSON(newsIDs),
+			Snapshot:       snapshotJSON(snapshot),
+			PerformedBy:    operatorID,
+		}
+		if err := tx.Create(&operation).Error; err != nil {
+			return err
+		}
+
+		// 指向被合并事件的旧重定向改为指向目标事件，再为被合并事件建立重定向
+		if err := tx.Model(&models.EventRedirect{}).Where("to_event_id IN ?", sourceIDs).
+			Update("to_event_id", target.ID).Error; err != nil {
+			return err
+		}
+		for _, id := range sourceIDs {
+			redirect := models.EventRedirect{FromEventID: id, ToEventID: target.ID, OperationID: operation.ID}
+			if err := tx.Create(&redirect).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Delete(&sources).Error; err != nil {
+			return err
+		}
+		if err := reindexEvents(tx, target.ID); err != nil {
+			return err
+		}
+		return syncEventTags(tx, involved...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.operationResult(&operation, req.TargetID)
+}
+
+// SplitEvent 将事件中的部分新闻拆分为新事件
+func (s *EventService) SplitEvent(id uint, req *models.SplitEventRequest, operatorID uint) (*models.EventOperationResult, error) {
+	newsIDs := uniqueIDs(req.NewsIDs)
+	if len(newsIDs) == 0 {
+		return nil, errors.New("no news to split")
+	}
+
+	var operation models.EventOperation
+	var created models.Event
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var original models.Event
+		if err := tx.First(&original, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("event not found")
+			}
+			return err
+		}
+
+		var moved []models.News
+		if err := tx.Where("id IN ?", newsIDs).Order("published_at ASC").Find(&moved).Error; err != nil {
+			return err
+		}
+		if len(moved) != len(newsIDs) {
+			return errors.New("news not found")
+		}
+		for _, news := range moved {
+			if news.BelongedEventID == nil || *news.BelongedEventID != id {
+				return errors.New("news does not belong to this event")
+			}
+		}
+
+		var remaining int64
+		if err := tx.Model(&models.News{}).
+			Where("belonged_event_id = ? AND id NOT IN ?", id, newsIDs).
+			Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining == 0 {
+			return errors.New("cannot move all news out of the event")
+		}
+
+		snapshot := eventOperationSnapshot{
+			Events:     []models.Event{original},
+			NewsEvents: make(map[uint]uint, len(moved)),
+		}
+		for _, news := range moved {
+			snapshot.NewsEvents[news.ID] = id
+		}
+
+		// 以拆出的新闻生成新事件
+		first := moved[0]
+		title := req.Title
+		if title == "" {
+			title = first.Title
+		}
+		description := req.Description
+		if description == "" {
+			description = s.summarizer.SummarizeEvent(moved)
+		}
+		if description == "" {
+			description = first.Summary
+		}
+
+		tags := make([]string, 0)
+		links := make([]string, 0)
+		content := fmt.Sprintf("# %s\n\n## 事件概述\n\n%s\n\n## 相关新闻\n\n", title, description)
+		for _, news := range moved {
+			tags = unionStrings(tags, s.extractTags(news))
+			if news.Link != "" {
+				links = unionStrings(links, []string{news.Link})
+			}
+			content += formatNewsSection(news)
+		}
+
+		source := first.Source
+		if source == "" {
+			source = original.Source
+		}
+
+		created = models.Event{
+			Title:        title,
+			Description:  description,
+			Content:      content,
+			StartTime:    first.PublishedAt,
+			EndTime:      first.PublishedAt.Add(defaultEventDuration),
+			Location:     original.Location,
+			Status:       initialEventStatus(first.PublishedAt, first.PublishedAt.Add(defaultEventDuration)),
+			CreatedBy:    operatorID,
+			Category:     original.Category,
+			Tags:         sliceToJSON(tags),
+			Source:       source,
+			Author:       original.Author,
+			RelatedLinks: sliceToJSON(links),
+		}
+		// 拆出的新闻可能发生在其他地点
+		if place, ok := extractNewsLocation(moved); ok {
+			created.Location = place.Name
+		}
+		applyEventLocation(&created)
+		if err := tx.Create(&created).Error; err != nil {
+			return err
+		}
+		if err := recordEventCreated(tx, &created, fmt.Sprintf("split from event %d", id), &operatorID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.News{}).Where("id IN ?", newsIDs).
+			Update("belonged_event_id", created.ID).Error; err != nil {
+			return err
+		}
+
+		// 原事件去掉拆出新闻的链接，两个事件都按剩余新闻重新计算时间跨度
+		movedLinks := make(map[string]bool, len(links))
+		for _, link := range links {
+			movedLinks[link] = true
+		}
+		remainingLinks := make([]string, 0)
+		for _, link := range jsonToSlice(original.RelatedLinks) {
+			if !movedLinks[link] {
+				remainingLinks = append(remainingLinks, link)
+			}
+		}
+		original.RelatedLinks = sliceToJSON(remainingLinks)
+
+		if err := syncEventEntities(tx, original.ID, created.ID); err != nil {
+			return err
+		}
+		if err := syncEventCategories(tx, created.ID); err != nil {
+			return err
+		}
+		for _, event := range []*models.Event{&original, &created} {
+			if err := s.recomputeEventSpan(tx, event, "event split"); err != nil {
+				return err
+			}
+			if err := tx.Save(event).Error; err != nil {
+				return err
+			}
+		}
+		if err := linkEventTags(tx, &created); err != nil {
+			return err
+		}
+		if err := reindexEvents(tx, original.ID, created.ID); err != nil {
+			return err
+		}
+
+		operation = models.EventOperation{
+			Type:           models.EventOperationSplit,
+			TargetEventID:  created.ID,
+			SourceEventIDs: idsToJSON([]uint{id}),
+			NewsIDs:        idsToJSON(newsIDs),
+			Snapshot:       snapshotJSON(snapshot),
+			PerformedBy:    operatorID,
+		}
+		return tx.Create(&operation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.operationResult(&operation, id, created.ID)
+}
+
+// UndoEventOperation 撤销一次合并或拆分
+// 涉及的事件在之后又被合并或拆分时，需要先撤销后面的操作
+func (s *EventService) UndoEventOperation(operationID uint, operatorID uint) (*models.EventOperationResult, error) {
+	var operation models.EventOperation
+	var affected []uint
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&operation, operationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("event operation not found")
+			}
+			return err
+		}
+		if operation.UndoneAt != nil {
+			return errors.New("event operation already undone")
+		}
+
+		sourceIDs := jsonToIDs(operation.SourceEventIDs)
+		involved := append([]uint{operation.TargetEventID}, sourceIDs...)
+		if err := s.checkLaterOperations(tx, &operation, involved); err != nil {
+			return err
+		}
+
+		var snapshot eventOperationSnapshot
+		if err := json.Unmarshal([]byte(operation.Snapshot), &snapshot); err != nil {
+			return fmt.Errorf("invalid operation snapshot: %w", err)
+		}
+		if len(snapshot.Events) == 0 {
+			return errors.New("invalid operation snapshot")
+		}
+
+		var err error
+		switch operation.Type {
+		case models.EventOperationMerge:
+			err = s.undoMerge(tx, &operation, &snapshot)
+		case models.EventOperationSplit:
+			err = s.undoSplit(tx, &operation, &snapshot)
+		default:
+			err = errors.New("invalid event operation type")
+		}
+		if err != nil {
+			return err
+		}
+		if err := syncEventEntities(tx, involved...); err != nil {
+			return err
+		}
+		if err := syncEventCategories(tx, involved...); err != nil {
+			return err
+		}
+		if err := syncEventTags(tx, involved...); err != nil {
+			return err
+		}
+		if err := reindexEvents(tx, involved...); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		operation.UndoneAt = &now
+		operation.UndoneBy = &operatorID
+		affected = involved
+		return tx.Save(&operation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.operationResult(&operation, affected...)
+}
+
+// GetEventOperations 获取事件合并/拆分记录
+func (s *EventService) GetEventOperations(query *models.EventOperationQueryRequest) ([]models.EventOperation, int64, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	db := s.db.Model(&models.EventOperation{})
+	if query.Type != "" {
+		db = db.Where("type = ?", query.Type)
+	}
+	if query.EventID > 0 {
+		// source_event_ids 为 JSON 数组字符串，按元素匹配
+		db = db.Where("target_event_id = ? OR source_event_ids::jsonb @> ?::jsonb", query.EventID, fmt.Sprintf("[%d]", query.EventID))
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var operations []models.EventOperation
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Order("created_at DESC").Offset(offset).Limit(query.Limit).Find(&operations).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return operations, total, nil
+}
+
+// undoMerge 恢复被合并的事件、新闻和评论的归属以及重定向和事件关系
+// 合并之后目标事件新增的计数保留在目标事件上
+func (s *EventService) undoMerge(tx *gorm.DB, operation *models.EventOperation, snapshot *eventOperationSnapshot) error {
+	var current models.Event
+	if err := tx.First(&current, operation.TargetEventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("event not found")
+		}
+		return err
+	}
+
+	restored := snapshot.Events[0]
+	var merged models.Event
+	for _, event := range snapshot.Events {
+		merged.ViewCount += event.ViewCount
+		merged.LikeCount += event.LikeCount
+		merged.ShareCount += event.ShareCount
+	}
+	restored.ViewCount += max(current.ViewCount-merged.ViewCount, 0)
+	restored.LikeCount += max(current.LikeCount-merged.LikeCount, 0)
+	restored.ShareCount += max(current.ShareCount-merged.ShareCount, 0)
+	if err := tx.Save(&restored).Error; err != nil {
+		return err
+	}
+
+	for _, source := range snapshot.Events[1:] {
+		source.DeletedAt = gorm.DeletedAt{}
+		if err := tx.Unscoped().Save(&source).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := restoreOwnership(tx, &models.News{}, "belonged_event_id", snapshot.NewsEvents); err != nil {
+		return err
+	}
+	if err := restoreOwnership(tx, &models.Comment{}, "target_id", snapshot.CommentEvents); err != nil {
+		return err
+	}
+	if err := restoreOwnership(tx, &models.EventMilestone{}, "event_id", snapshot.MilestoneEvents); err != nil {
+		return err
+	}
+
+	if err := tx.Where("operation_id = ?", operation.ID).Delete(&models.EventRedirect{}).Error; err != nil {
+		return err
+	}
+	for _, redirect := range snapshot.Redirects {
+		if err := tx.Model(&models.EventRedirect{}).Where("id = ?", redirect.ID).
+			Update("to_event_id", redirect.ToEventID).Error; err != nil {
+			return err
+		}
+	}
+	return restoreRelations(tx, snapshot.Relations)
+}
+
+// mergeRelations 计算合并后的事件关系：涉及被合并事件的关系改为指向目标事件，
+// 变成自身关联或与其他关系重复（同一对事件、同一类型，有方向的关系反向也算重复）的去掉；
+// 重复时优先保留未删除的、原本就属于目标事件的、较早建立的关系。返回改为新事件对的关系和需要去掉的关系ID
+func mergeRelations(relations []models.EventRelation, targetID uint, sourceIDs []uint) ([]models.EventRelation, []uint) {
+	merged := make(map[uint]bool, len(sourceIDs))
+	for _, id := range sourceIDs {
+		merged[id] = true
+	}
+	remap := func(id uint) uint {
+		if merged[id] {
+			return targetID
+		}
+		return id
+	}
+	moved := func(relation models.EventRelation) bool {
+		return merged[relation.FromEventID] || merged[relation.ToEventID]
+	}
+
+	ordered := append([]models.EventRelation(nil), relations...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.DeletedAt.Valid != b.DeletedAt.Valid {
+			return !a.DeletedAt.Valid
+		}
+		if moved(a) != moved(b) {
+			return !moved(a)
+		}
+		return a.ID < b.ID
+	})
+
+	type relationKey struct {
+		low, high    uint
+		relationType string
+	}
+	kept := make(map[relationKey]bool, len(ordered))
+	updated := make([]models.EventRelation, 0)
+	removed := make([]uint, 0)
+	for _, relation := range ordered {
+		from, to := relationPair(remap(relation.FromEventID), remap(relation.ToEventID), relation.Type)
+		key := relationKey{low: min(from, to), high: max(from, to), relationType: relation.Type}
+		if from == to || kept[key] {
+			removed = append(removed, relation.ID)
+			continue
+		}
+		kept[key] = true
+		if from != relation.FromEventID || to != relation.ToEventID {
+			relation.FromEventID, relation.ToEventID = from, to
+			updated = append(updated, relation)
+		}
+	}
+	return updated, removed
+}
+
+// restoreRelations 按快照恢复合并时改动或去掉的事件关系，原来的事件对已被合并之后建立的关系占用时跳过
+func restoreRelations(tx *gorm.DB, relations []models.EventRelation) error {
+	for _, relation := range relations {
+		var taken int64
+		if err := tx.Unscoped().Model(&models.EventRelation{}).
+			Where("from_event_id = ? AND to_event_id = ? AND type = ? AND id <> ?",
+				relation.FromEventID, relation.ToEventID, relation.Type, relation.ID).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			continue
+		}
+		// 被去掉的关系按原ID重新插入
+		if err := tx.Unscoped().Save(&relation).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// undoSplit 新闻、评论和里程碑移回原事件，拆分出的事件软删除并重定向到原事件
+func (s *EventService) undoSplit(tx *gorm.DB, operation *models.EventOperation, snapshot *eventOperationSnapshot) error {
+	var original, created models.Event
+	if err := tx.First(&original, snapshot.Events[0].ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("event not found")
+		}
+		return err
+	}
+	if err := tx.First(&created, operation.TargetEventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("event not found")
+		}
+		return err
+	}
+
+	// 拆分之后两个事件上产生的计数都保留
+	restored := snapshot.Events[0]
+	restored.ViewCount = original.ViewCount + created.ViewCount
+	restored.LikeCount = original.LikeCount + created.LikeCount
+	restored.ShareCount = original.ShareCount + created.ShareCount
+	if err := tx.Save(&restored).Error; err != nil {
+		return err
+	}
+
+	if err := restoreOwnership(tx, &models.News{}, "belonged_event_id", snapshot.NewsEvents); err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Comment{}).
+		Where("target_type = ? AND target_id = ?", models.CommentTargetEvent, created.ID).
+		Update("target_id", original.ID).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.EventMilestone{}).Where("event_id = ?", created.ID).
+		Update("event_id", original.ID).Error; err != nil {
+		return err
+	}
+
+	redirect := models.EventRedirect{FromEventID: created.ID, ToEventID: original.ID, OperationID: operation.ID}
+	if err := tx.Create(&redirect).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&created).Error
+}
+
+// checkLaterOperations 检查涉及相同事件的后续操作是否都已撤销
+func (s *EventService) checkLaterOperations(tx *gorm.DB, operation *models.EventOperation, involved []uint) error {
+	var later []models.EventOperation
+	if err := tx.Where("id > ? AND undone_at IS NULL", operation.ID).Find(&later).Error; err != nil {
+		return err
+	}
+
+	related := make(map[uint]bool, len(involved))
+	for _, id := range involved {
+		related[id] = true
+	}
+	for _, op := range later {
+		ids := append([]uint{op.TargetEventID}, jsonToIDs(op.SourceEventIDs)...)
+		for _, id := range ids {
+			if related[id] {
+				return fmt.Errorf("event %d was changed by later operation %d, undo it first", id, op.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// recomputeEventSpan 按关联新闻的发布时间重新计算事件时间跨度和状态，没有关联新闻时保持不变
+func (s *EventService) recomputeEventSpan(tx *gorm.DB, event *models.Event, reason string) error {
+	var span struct {
+		FirstPublished sql.NullTime
+		LastPublished  sql.NullTime
+	}
+	if err := tx.Model(&models.News{}).
+		Select("MIN(published_at) AS first_published, MAX(published_at) AS last_published").
+		Where("belonged_event_id = ?", event.ID).
+		Scan(&span).Error; err != nil {
+		return err
+	}
+
+	if span.FirstPublished.Valid {
+		event.StartTime = span.FirstPublished.Time
+		event.EndTime = span.FirstPublished.Time.Add(defaultEventDuration)
+		if span.LastPublished.Time.After(event.EndTime) {
+			event.EndTime = span.LastPublished.Time
+		}
+	}
+	return syncEventStatus(tx, event, reason)
+}
+
+// operationResult 重新计算受影响事件的评论数和热度，返回操作记录和事件的最新状态
+func (s *EventService) operationResult(operation *models.EventOperation, eventIDs ...uint) (*models.EventOperationResult, error) {
+	commentService := NewCommentService()
+	result := &models.EventOperationResult{
+		Operation: *operation,
+		Events:    make([]models.EventResponse, 0, len(eventIDs)),
+	}
+
+	for _, id := range eventIDs {
+		var event models.Event
+		if err := s.db.First(&event, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue // 已被合并或撤销拆分而删除
+			}
+			return nil, err
+		}
+
+		// 同步评论数的同时会重新计算热度
+		if err := commentService.SyncCommentCount(models.CommentTargetEvent, id); err != nil {
+			log.Printf("[EVENT WARNING] failed to refresh event %d after %s: %v", id, operation.Type, err)
+		}
+		if err := s.db.First(&event, id).Error; err != nil {
+			return nil, err
+		}
+		result.Events = append(result.Events, convertToEventResponse(&event))
+	}
+
+	return result, nil
+}
+
+// resolveEventRedirect 跟随合并留下的重定向，返回当前有效的事件ID
+func (s *EventService) resolveEventRedirect(id uint) (uint, bool) {
+	current := id
+	for i := 0; i < maxRedirectHops; i++ {
+		var redirect models.EventRedirect
+		if err := s.db.Where("from_event_id = ?", current).First(&redirect).Error; err != nil {
+			break
+		}
+		current = redirect.ToEventID
+	}
+	return current, current != id
+}
+
+// restoreOwnership 按快照把记录恢复到原来的归属
+func restoreOwnership(tx *gorm.DB, model interface{}, column string, owners map[uint]uint) error {
+	grouped := make(map[uint][]uint)
+	for id, owner := range owners {
+		grouped[owner] = append(grouped[owner], id)
+	}
+	for owner, ids := range grouped {
+		if err := tx.Model(model).Where("id IN ?", ids).Update(column, owner).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uint) []uint {
+	result := make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+func unionStrings(base []string, items []string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range base {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			base = append(base, item)
+		}
+	}
+	return base
+}
+
+func idsToJSON(ids []uint) string {
+	data, _ := json.Marshal(ids)
+	return string(data)
+}
+
+func jsonToIDs(data string) []uint {
+	var ids []uint
+	if data != "" {
+		json.Unmarshal([]byte(data), &ids)
+	}
+	return ids
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestMergeRelations(t *testing.T) {
+	deleted := gorm.DeletedAt{Time: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	relation := func(id, from, to uint, relationType string) models.EventRelation {
+		return models.EventRelation{ID: id, FromEventID: from, ToEventID: to, Type: relationType}
+	}
+
+	type pair struct{ id, from, to uint }
+	tests := []struct {
+		name        string
+		relations   []models.EventRelation
+		wantUpdated []pair
+		wantRemoved []uint
+	}{
+		{
+			name:        "relation to a merged event moves to the target",
+			relations:   []models.EventRelation{relation(1, 5, 2, models.EventRelationFollowUpOf)},
+			wantUpdated: []pair{{1, 5, 1}},
+			wantRemoved: []uint{},
+		},
+		{
+			name:        "undirected pair keeps the smaller id first",
+			relations:   []models.EventRelation{relation(1, 4, 2, models.EventRelationRelatedTo)},
+			wantUpdated: []pair{{1, 1, 4}},
+			wantRemoved: []uint{},
+		},
+		{
+			name:        "relation between target and merged event becomes a self link",
+			relations:   []models.EventRelation{relation(1, 1, 2, models.EventRelationRelatedTo)},
+			wantUpdated: []pair{},
+			wantRemoved: []uint{1},
+		},
+		{
+			name: "duplicate of a target relation is dropped",
+			relations: []models.EventRelation{
+				relation(1, 2, 5, models.EventRelationRelatedTo),
+				relation(2, 1, 5, models.EventRelationRelatedTo),
+			},
+			wantUpdated: []pair{},
+			wantRemoved: []uint{1},
+		},
+		{
+			name: "two merged events related to the same event",
+			relations: []models.EventRelation{
+				relation(1, 2, 5, models.EventRelationCausedBy),
+				relation(2, 3, 5, models.EventRelationCausedBy),
+				relation(3, 3, 5, models.EventRelationRelatedTo),
+			},
+			wantUpdated: []pair{{1, 1, 5}, {3, 1, 5}},
+			wantRemoved: []uint{2},
+		},
+		{
+			name: "reverse of a directed relation is a duplicate",
+			relations: []models.EventRelation{
+				relation(1, 5, 1, models.EventRelationFollowUpOf),
+				relation(2, 2, 5, models.EventRelationFollowUpOf),
+			},
+			wantUpdated: []pair{},
+			wantRemoved: []uint{2},
+		},
+		{
+			name: "live relation wins over a deleted one",
+			relations: []models.EventRelation{
+				{ID: 1, FromEventID: 1, ToEventID: 5, Type: models.EventRelationRelatedTo, DeletedAt: deleted},
+				relation(2, 2, 5, models.EventRelationRelatedTo),
+			},
+			wantUpdated: []pair{{2, 1, 5}},
+			wantRemoved: []uint{1},
+		},
+		{
+			name:        "unrelated relations are left alone",
+			relations:   []models.EventRelation{relation(1, 1, 5, models.EventRelationRelatedTo)},
+			wantUpdated: []pair{},
+			wantRemoved: []uint{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, removed := mergeRelations(tt.relations, 1, []uint{2, 3})
+			gotUpdated := make([]pair, len(updated))
+			for i, relation := range updated {
+				gotUpdated[i] = pair{relation.ID, relation.FromEventID, relation.ToEventID}
+			}
+			if !reflect.DeepEqual(gotUpdated, tt.wantUpdated) {
+				t.Errorf("updated = %v, want %v", gotUpdated, tt.wantUpdated)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
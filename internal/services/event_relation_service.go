@@ -0,0 +1,571 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/geo"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+)
+
+const (
+	// relationCandidateWindow 推荐关系时只比较开始时间在前后此范围内的事件
+	relationCandidateWindow = 90 * 24 * time.Hour
+	// relationCandidateLimit 推荐关系时最多比较的事件数
+	relationCandidateLimit = 300
+	// followUpWindow 后一个事件在前一个事件结束后此范围内开始时视为后续进展
+	followUpWindow = 30 * 24 * time.Hour
+	// followUpMinSimilarity 判定为后续进展的最低文本相似度
+	followUpMinSimilarity = 0.2
+	// relationSuggestThreshold 推荐关系的最低置信度
+	relationSuggestThreshold = 0.25
+	// autoRelationThreshold 自动建立关系的最低置信度
+	autoRelationThreshold = 0.45
+	// autoLinkRecentWindow 未指定事件时，自动建立关系处理最近开始的事件
+	autoLinkRecentWindow = 30 * 24 * time.Hour
+)
+
+// CreateEventRelation 管理员添加事件关系
+// 没有方向的关系按事件ID排序保存；已存在的同类关系（包括被删除的自动关系）改为管理员添加
+func (s *EventService) CreateEventRelation(fromID uint, req *models.CreateEventRelationRequest, operatorID uint) (*models.EventRelation, error) {
+	if !models.ValidEventRelationType(req.Type) {
+		return nil, errors.New("invalid relation type")
+	}
+	if fromID == req.ToEventID {
+		return nil, errors.New("cannot relate an event to itself")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Event{}).Where("id IN ?", []uint{fromID, req.ToEventID}).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count != 2 {
+		return nil, errors.New("event not found")
+	}
+
+	from, to := relationPair(fromID, req.ToEventID, req.Type)
+	confidence := 1.0
+	if req.Confidence != nil {
+		confidence = *req.Confidence
+	}
+
+	var relation models.EventRelation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 有方向的关系不能同时存在两个方向
+		if models.EventRelationDirected(req.Type) {
+			var reverse int64
+			if err := tx.Model(&models.EventRelation{}).
+				Where("from_event_id = ? AND to_event_id = ? AND type = ?", to, from, req.Type).
+				Count(&reverse).Error; err != nil {
+				return err
+			}
+			if reverse > 0 {
+				return errors.New("reverse relation already exists")
+			}
+		}
+
+		err := tx.Unscoped().Where("from_event_id = ? AND to_event_id = ? AND type = ?", from, to, req.Type).First(&relation).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			relation = models.EventRelation{
+				FromEventID: from,
+				ToEventID:   to,
+				Type:        req.Type,
+				Confidence:  confidence,
+				Origin:      models.EventRelationManual,
+				Reason:      req.Reason,
+				CreatedBy:   &operatorID,
+			}
+			return tx.Create(&relation).Error
+		case err != nil:
+			return err
+		}
+
+		relation.Confidence = confidence
+		relation.Origin = models.EventRelationManual
+		relation.Reason = req.Reason
+		relation.CreatedBy = &operatorID
+		relation.DeletedAt = gorm.DeletedAt{}
+		return tx.Unscoped().Save(&relation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// DeleteEventRelation 删除事件关系，记录保留为已删除，之后不会再自动建立同一对事件的关系
+func (s *EventService) DeleteEventRelation(id uint) error {
+	result := s.db.Delete(&models.EventRelation{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("event relation not found")
+	}
+	return nil
+}
+
+// GetRelatedEvents 获取与事件有关系的事件，按置信度乘以关系类型权重从高到低排列
+func (s *EventService) GetRelatedEvents(id uint, query *models.RelatedEventQueryRequest) ([]models.RelatedEventResponse, error) {
+	if query.Type != "" && !models.ValidEventRelationType(query.Type) {
+		return nil, errors.New("invalid relation type")
+	}
+	if query.Limit <= 0 || query.Limit > 50 {
+		query.Limit = 10
+	}
+
+	id, _ = s.resolveEventRedirect(id)
+	var count int64
+	if err := s.db.Model(&models.Event{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("event not found")
+	}
+
+	db := s.db.Where("(from_event_id = ? OR to_event_id = ?)", id, id)
+	if query.Type != "" {
+		db = db.Where("type = ?", query.Type)
+	}
+	if query.MinConfidence > 0 {
+		db = db.Where("confidence >= ?", query.MinConfidence)
+	}
+	var relations []models.EventRelation
+	if err := db.Find(&relations).Error; err != nil {
+		return nil, err
+	}
+
+	// 关系另一端的事件被合并时跟随重定向
+	neighborOf := make(map[uint]uint, len(relations))
+	neighborIDs := make([]uint, 0, len(relations))
+	for _, relation := range relations {
+		neighbor := relation.ToEventID
+		if neighbor == id {
+			neighbor = relation.FromEventID
+		}
+		neighbor, _ = s.resolveEventRedirect(neighbor)
+		neighborOf[relation.ID] = neighbor
+		neighborIDs = append(neighborIDs, neighbor)
+	}
+
+	var events []models.Event
+	if len(neighborIDs) > 0 {
+		if err := s.db.Where("id IN ?", neighborIDs).Find(&events).Error; err != nil {
+			return nil, err
+		}
+	}
+	eventByID := make(map[uint]*models.Event, len(events))
+	for i := range events {
+		eventByID[events[i].ID] = &events[i]
+	}
+
+	// 同一个相关事件只保留最强的关系
+	best := make(map[uint]models.RelatedEventResponse)
+	for _, relation := range relations {
+		neighbor := neighborOf[relation.ID]
+		event, ok := eventByID[neighbor]
+		if !ok || neighbor == id {
+			continue
+		}
+
+		incoming := relation.ToEventID == id
+		direction := "outgoing"
+		if incoming {
+			direction = "incoming"
+		}
+		item := models.RelatedEventResponse{
+			RelationID: relation.ID,
+			Type:       relation.Type,
+			Direction:  direction,
+			Label:      models.EventRelationLabel(relation.Type, incoming),
+			Confidence: relation.Confidence,
+			Origin:     relation.Origin,
+			Strength:   math.Round(relation.Confidence*models.EventRelationWeight(relation.Type)*1000) / 1000,
+			Reason:     relation.Reason,
+			Event:      convertToEventResponse(event),
+		}
+		item.Event.Content = ""
+
+		if current, ok := best[neighbor]; !ok || relatedEventLess(current, item) {
+			best[neighbor] = item
+		}
+	}
+
+	related := make([]models.RelatedEventResponse, 0, len(best))
+	for _, item := range best {
+		related = append(related, item)
+	}
+	sort.Slice(related, func(i, j int) bool {
+		return relatedEventLess(related[j], related[i])
+	})
+	if len(related) > query.Limit {
+		related = related[:query.Limit]
+	}
+	return related, nil
+}
+
+// relatedEventLess 相关事件的排序：关系强度优先，其次管理员添加的关系，最后是较新的事件
+func relatedEventLess(a, b models.RelatedEventResponse) bool {
+	if a.Strength != b.Strength {
+		return a.Strength < b.Strength
+	}
+	if a.Origin != b.Origin {
+		return a.Origin == models.EventRelationAuto
+	}
+	return a.Event.StartTime.Before(b.Event.StartTime)
+}
+
+// SuggestEventRelations 按共同标签、地点和文本相似度推荐可能相关的事件，不包括已有关系或被删除过关系的事件
+func (s *EventService) SuggestEventRelations(id uint, limit int) ([]models.EventRelationSuggestion, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	var event models.Event
+	if err := s.db.First(&event, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+
+	suggestions, err := s.relationSuggestions(event, relationSuggestThreshold, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// AutoLinkEvents 为指定事件自动建立高置信度的关系，未指定时处理最近开始的事件，每处理完一个事件汇报一次进度
+// 管理员添加或删除过的事件对不会被改动，已有的自动关系按最新的计算结果更新；ctx 取消时停止并返回已完成部分的结果
+func (s *EventService) AutoLinkEvents(ctx context.Context, eventIDs []uint, progress func(done, total int, partial *models.AutoLinkEventsResult)) (*models.AutoLinkEventsResult, error) {
+	start := time.Now()
+	result := &models.AutoLinkEventsResult{}
+
+	var events []models.Event
+	db := s.db
+	if len(eventIDs) > 0 {
+		db = db.Where("id IN ?", eventIDs)
+	} else {
+		db = db.Where("start_time >= ?", start.Add(-autoLinkRecentWindow))
+	}
+	if err := db.Order("start_time ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			result.Duration = time.Since(start).String()
+			return result, err
+		}
+		result.CheckedEvents++
+
+		suggestions, err := s.relationSuggestions(event, autoRelationThreshold, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, suggestion := range suggestions {
+			created, err := s.saveAutoRelation(suggestion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to link event %d: %w", event.ID, err)
+			}
+			if created {
+				result.Created++
+			}
+		}
+		updated, err := s.refreshAutoRelations(event, suggestions)
+		if err != nil {
+			return nil, err
+		}
+		result.Updated += updated
+		if progress != nil {
+			progress(result.CheckedEvents, len(events), result)
+		}
+	}
+
+	result.Duration = time.Since(start).String()
+	return result, nil
+}
+
+// saveAutoRelation 保存自动关系，事件对之间已有任何关系（包括被删除的）时不新建
+func (s *EventService) saveAutoRelation(suggestion models.EventRelationSuggestion) (bool, error) {
+	var existing int64
+	if err := s.db.Unscoped().Model(&models.EventRelation{}).
+		Where("(from_event_id = ? AND to_event_id = ?) OR (from_event_id = ? AND to_event_id = ?)",
+			suggestion.FromEventID, suggestion.ToEventID, suggestion.ToEventID, suggestion.FromEventID).
+		Count(&existing).Error; err != nil {
+		return false, err
+	}
+	if existing > 0 {
+		return false, nil
+	}
+
+	relation := models.EventRelation{
+		FromEventID: suggestion.FromEventID,
+		ToEventID:   suggestion.ToEventID,
+		Type:        suggestion.Type,
+		Confidence:  suggestion.Confidence,
+		Origin:      models.EventRelationAuto,
+		Reason:      truncateRunes(suggestion.Reason, 500),
+	}
+	if err := s.db.Create(&relation).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// refreshAutoRelations 按最新的计算结果更新事件已有自动关系的置信度和依据
+func (s *EventService) refreshAutoRelations(event models.Event, suggestions []models.EventRelationSuggestion) (int, error) {
+	var relations []models.EventRelation
+	if err := s.db.Where("origin = ? AND (from_event_id = ? OR to_event_id = ?)", models.EventRelationAuto, event.ID, event.ID).
+		Find(&relations).Error; err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, relation := range relations {
+		for _, suggestion := range suggestions {
+			if suggestion.FromEventID != relation.FromEventID || suggestion.ToEventID != relation.ToEventID ||
+				suggestion.Type != relation.Type || suggestion.Confidence == relation.Confidence {
+				continue
+			}
+			if err := s.db.Model(&relation).Updates(map[string]interface{}{
+				"confidence": suggestion.Confidence,
+				"reason":     truncateRunes(suggestion.Reason, 500),
+			}).Error; err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// relationSuggestions 计算事件与时间相近的其他事件之间的关系，按置信度从高到低排列
+// 管理员添加或删除过关系的事件对被排除；includeAuto 为 true 时保留已有自动关系的事件对以便更新
+func (s *EventService) relationSuggestions(event models.Event, threshold float64, includeAuto bool) ([]models.EventRelationSuggestion, error) {
+	var candidates []models.Event
+	if err := s.db.Where("id <> ? AND start_time BETWEEN ? AND ?", event.ID,
+		event.StartTime.Add(-relationCandidateWindow), event.EndTime.Add(relationCandidateWindow)).
+		Order("hotness_score DESC").
+		Limit(relationCandidateLimit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return []models.EventRelationSuggestion{}, nil
+	}
+
+	var existing []models.EventRelation
+	if err := s.db.Unscoped().Where("from_event_id = ? OR to_event_id = ?", event.ID, event.ID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	excluded := make(map[uint]bool)
+	for _, relation := range existing {
+		if includeAuto && relation.Origin == models.EventRelationAuto && !relation.DeletedAt.Valid {
+			continue
+		}
+		excluded[relation.FromEventID+relation.ToEventID-event.ID] = true
+	}
+
+	events := append([]models.Event{event}, candidates...)
+	eventIDs := make([]uint, len(events))
+	for i := range events {
+		eventIDs[i] = events[i].ID
+	}
+	entities, err := eventEntityNames(s.db, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	scorer := newRelationScorer(events, entities)
+	suggestions := make([]models.EventRelationSuggestion, 0)
+	for i, candidate := range candidates {
+		if excluded[candidate.ID] {
+			continue
+		}
+		suggestion, ok := scorer.score(0, i+1)
+		if !ok || suggestion.Confidence < threshold {
+			continue
+		}
+		suggestion.Event = convertToEventResponse(&candidates[i])
+		suggestion.Event.Content = ""
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+	return suggestions, nil
+}
+
+// relationScorer 在一组事件的标题和描述上建立 TF-IDF 模型，计算两两之间的关系
+type relationScorer struct {
+	events   []models.Event
+	vectors  []nlp.Vector
+	regions  []map[string]bool
+	tags     [][]string
+	entities [][]string
+}
+
+// entities 为各事件提到的人物和机构，按事件ID索引
+func newRelationScorer(events []models.Event, entities map[uint][]string) *relationScorer {
+	pipeline := newTextPipeline(loadClusteringOptions())
+
+	tokens := make([][]string, len(events))
+	for i, event := range events {
+		tokens[i] = pipeline.tokens(event.Title, event.Description)
+	}
+	model := nlp.FitTFIDF(tokens)
+
+	scorer := &relationScorer{events: events}
+	for i, event := range events {
+		scorer.vectors = append(scorer.vectors, model.Transform(tokens[i]))
+		scorer.regions = append(scorer.regions, pipeline.regions(tokens[i]))
+		scorer.tags = append(scorer.tags, relationTags(event))
+		scorer.entities = append(scorer.entities, entities[event.ID])
+	}
+	return scorer
+}
+
+// score 计算第 i 个和第 j 个事件之间的关系
+// 置信度由文本相似度、标签和实体的重合度以及地点组成；提到不同地域的事件不推荐
+func (r *relationScorer) score(i, j int) (models.EventRelationSuggestion, bool) {
+	a, b := r.events[i], r.events[j]
+	if regionConflict(r.regions[i], r.regions[j]) {
+		return models.EventRelationSuggestion{}, false
+	}
+
+	similarity := nlp.Cosine(r.vectors[i], r.vectors[j])
+	shared := sharedStrings(r.tags[i], r.tags[j])
+	sharedEntities := sharedStrings(r.entities[i], r.entities[j])
+	if similarity < 0.1 && len(shared) == 0 && len(sharedEntities) == 0 {
+		return models.EventRelationSuggestion{}, false
+	}
+
+	sameArea := sameEventArea(a.LocationCode, b.LocationCode)
+	confidence := 0.5*similarity + 0.2*jaccard(r.tags[i], r.tags[j], shared) +
+		0.2*jaccard(r.entities[i], r.entities[j], sharedEntities)
+	if sameArea {
+		confidence += 0.1
+	}
+	confidence = math.Round(math.Min(confidence, 1)*1000) / 1000
+
+	// 时间上先后衔接且内容接近的视为后续进展，其余视为相关
+	earlier, later := a, b
+	if later.StartTime.Before(earlier.StartTime) {
+		earlier, later = later, earlier
+	}
+	relationType := models.EventRelationRelatedTo
+	if gap := later.StartTime.Sub(earlier.EndTime); gap > 0 && gap <= followUpWindow && similarity >= followUpMinSimilarity {
+		relationType = models.EventRelationFollowUpOf
+	}
+	from, to := relationPair(later.ID, earlier.ID, relationType)
+
+	reasons := make([]string, 0, 4)
+	if len(shared) > 0 {
+		reasons = append(reasons, "共同标签: "+strings.Join(shared, "、"))
+	}
+	if len(sharedEntities) > 0 {
+		reasons = append(reasons, "共同实体: "+strings.Join(sharedEntities, "、"))
+	}
+	if sameArea {
+		reasons = append(reasons, "地点相同")
+	}
+	reasons = append(reasons, fmt.Sprintf("文本相似度 %.2f", similarity))
+
+	return models.EventRelationSuggestion{
+		FromEventID:    from,
+		ToEventID:      to,
+		Type:           relationType,
+		Confidence:     confidence,
+		SharedTags:     shared,
+		SharedEntities: sharedEntities,
+		SameArea:       sameArea,
+		Similarity:     math.Round(similarity*1000) / 1000,
+		Reason:         strings.Join(reasons, "；"),
+	}, true
+}
+
+// relationPair 没有方向的关系按事件ID排序
+func relationPair(from, to uint, relationType string) (uint, uint) {
+	if !models.EventRelationDirected(relationType) && from > to {
+		return to, from
+	}
+	return from, to
+}
+
+// relationTags 参与关系计算的事件标签，去掉分类和来源这类几乎所有事件都有的标签
+func relationTags(event models.Event) []string {
+	tags := make([]string, 0)
+	for _, tag := range jsonToSlice(event.Tags) {
+		if tag == "" || tag == event.Category || tag == event.Source {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// jaccard 根据两个集合和它们的交集计算 Jaccard 系数
+func jaccard(a, b, shared []string) float64 {
+	if union := len(a) + len(b) - len(shared); union > 0 {
+		return float64(len(shared)) / float64(union)
+	}
+	return 0
+}
+
+func sharedStrings(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, v := range b {
+		set[v] = true
+	}
+	shared := make([]string, 0)
+	for _, v := range a {
+		if set[v] {
+			shared = append(shared, v)
+			delete(set, v)
+		}
+	}
+	return shared
+}
+
+// sameEventArea 两个地点相同，或一个是另一个的上级省份或城市；同属一个国家不算
+func sameEventArea(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+
+	gazetteer := geo.Default()
+	placeA, okA := gazetteer.Get(a)
+	placeB, okB := gazetteer.Get(b)
+	if !okA || !okB {
+		return false
+	}
+	contains := func(child, parent *geo.Place) bool {
+		if parent.Level == geo.LevelCountry {
+			return false
+		}
+		for _, ancestor := range gazetteer.Ancestors(child) {
+			if ancestor.Code == parent.Code {
+				return true
+			}
+		}
+		return false
+	}
+	return contains(placeA, placeB) || contains(placeB, placeA)
+}
@@ -1,12 +1,11 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
-	"sort"
-	"strings"
 	"time"
 
 	"github.com/EasyPeek/EasyPeek-backend/internal/database"
@@ -34,12 +33,18 @@ func jsonToSlice(jsonStr string) []string {
 }
 
 type EventService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	viewCounter   *ViewCounter
+	summarizer    *SummaryService
+	searchOptions searchOptions
 }
 
 func NewEventService() *EventService {
 	return &EventService{
-		db: database.GetDB(),
+		db:            database.GetDB(),
+		viewCounter:   NewViewCounter(),
+		summarizer:    NewSummaryService(),
+		searchOptions: loadSearchOptions(),
 	}
 }
 
@@ -48,28 +53,52 @@ func (s *EventService) GetEvents(query *models.EventQueryRequest) (*models.Event
 	var events []models.Event
 	var total int64
 
+	geoFilter, err := parseGeoFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
 	db := s.db.Model(&models.Event{})
 
 	// 添加状态筛选
 	if query.Status != "" {
-		db = db.Where("status = ?", query.Status)
+		db = db.Where("status = ?", normalizeStatusFilter(query.Status))
 	}
 
-	// 添加分类筛选
+	// 添加分类筛选，包含子分类
 	if query.Category != "" {
-		db = db.Where("category = ?", query.Category)
+		db = categoryFilter(s.db, db, "event_categories", "event_id", "id", "category", query.Category)
 	}
 
-	// 添加搜索条件
-	if query.Search != "" {
-		searchTerm := "%" + query.Search + "%"
-		db = db.Where("title LIKE ? OR description LIKE ? OR content LIKE ? OR location LIKE ?", searchTerm, searchTerm, searchTerm, searchTerm)
+	// 全文搜索，分词后匹配标题、描述、内容和地点，支持与统一搜索相同的语法
+	search := parseSearchQuery(query.Search)
+	if search.raw != "" {
+		db = search.filter(db, s.db, eventSearchTarget)
+	}
+
+	// 添加地理筛选
+	db = geoFilter.apply(db)
+
+	// 按实体筛选
+	if query.EntityID > 0 {
+		db = db.Where("id IN (?)", entityFilter(s.db, "event_entities", "event_id", query.EntityID))
+	}
+
+	// 实体分面统计在排序和分页之前的全部筛选结果上进行
+	var facets models.EntityFacets
+	if query.Facets {
+		if facets, err = entityFacets(s.db, "event_entities", "event_id", db.Session(&gorm.Session{}).Select("id")); err != nil {
+			return nil, err
+		}
 	}
 
 	// 获取总数
 	if err := db.Count(&total).Error; err != nil {
 		return nil, err
 	}
+	if search.raw != "" && query.Page == 1 {
+		recordSearchQuery(s.db, models.SearchEndpointEvents, query.Search, total)
+	}
 
 	// 排序
 	orderBy := "created_at desc"
@@ -84,9 +113,18 @@ func (s *EventService) GetEvents(query *models.EventQueryRequest) (*models.Event
 		orderBy = "created_at desc"
 	}
 
+	if query.SortBy == "distance" {
+		db = geoFilter.orderByDistance(db)
+	} else if search.raw != "" && query.SortBy == "" {
+		// 搜索且未指定排序方式时按相关度
+		db = search.order(db, eventSearchTarget, s.searchOptions)
+	} else {
+		db = db.Order(orderBy)
+	}
+
 	// 分页
 	offset := (query.Page - 1) * query.Limit
-	err := db.Order(orderBy).Offset(offset).Limit(query.Limit).Find(&events).Error
+	err = db.Offset(offset).Limit(query.Limit).Find(&events).Error
 	if err != nil {
 		return nil, err
 	}
@@ -94,63 +132,76 @@ func (s *EventService) GetEvents(query *models.EventQueryRequest) (*models.Event
 	// 构建响应
 	var eventResponses []models.EventResponse
 	for _, event := range events {
-		eventResponses = append(eventResponses, convertToEventResponse(&event))
+		response := convertToEventResponse(&event)
+		geoFilter.fillDistance(&response)
+		if search.highlighted() {
+			response.Highlight = search.highlightEvent(&event, s.searchOptions.snippetLength)
+		}
+		eventResponses = append(eventResponses, response)
 	}
 
 	return &models.EventListResponse{
 		Total:  total,
 		Events: eventResponses,
+		Facets: facets,
 	}, nil
 }
 
 // GetEventByID 根据ID获取事件
 func (s *EventService) GetEventByID(id uint) (*models.EventResponse, error) {
-	var event models.Event
-	if err := s.db.First(&event, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("event not found")
-		}
+	event, redirectedFrom, err := s.findEventFollowingRedirects(id)
+	if err != nil {
 		return nil, err
 	}
 
-	response := convertToEventResponse(&event)
+	response := convertToEventResponse(event)
+	response.RedirectedFrom = redirectedFrom
 	return &response, nil
 }
 
-// ViewEvent 浏览事件（增加浏览量并重新计算热度）
-func (s *EventService) ViewEvent(id uint) (*models.EventResponse, error) {
-	var event models.Event
-	if err := s.db.First(&event, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("event not found")
-		}
-		return nil, err
-	}
-
-	// 增加浏览量
-	err := s.db.Model(&models.Event{}).
-		Where("id = ?", id).
-		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
-
+// ViewEvent 浏览事件（记录去重后的浏览量，热度由浏览量回写任务统一重算）
+func (s *EventService) ViewEvent(id uint, viewerKey string) (*models.EventResponse, error) {
+	event, redirectedFrom, err := s.findEventFollowingRedirects(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// 自动重新计算热度值
-	_, err = s.CalculateHotness(id, nil)
-	if err != nil {
+	if _, err := s.viewCounter.RecordView(ViewTargetEvent, event.ID, viewerKey); err != nil {
 		return nil, err
 	}
 
-	// 重新获取更新后的事件数据
-	if err := s.db.First(&event, id).Error; err != nil {
-		return nil, err
-	}
+	// 返回的浏览量包含尚未回写数据库的部分
+	event.ViewCount += s.viewCounter.PendingViews(ViewTargetEvent, event.ID)
 
-	response := convertToEventResponse(&event)
+	response := convertToEventResponse(event)
+	response.RedirectedFrom = redirectedFrom
 	return &response, nil
 }
 
+// findEventFollowingRedirects 按ID获取事件，事件已被合并时返回合并后的目标事件和原ID
+func (s *EventService) findEventFollowingRedirects(id uint) (*models.Event, *uint, error) {
+	var event models.Event
+	err := s.db.First(&event, id).Error
+	if err == nil {
+		return &event, nil, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	targetID, redirected := s.resolveEventRedirect(id)
+	if !redirected {
+		return nil, nil, errors.New("event not found")
+	}
+	if err := s.db.First(&event, targetID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("event not found")
+		}
+		return nil, nil, err
+	}
+	return &event, &id, nil
+}
+
 // CreateEvent 创建事件
 func (s *EventService) CreateEvent(req *models.CreateEventRequest) (*models.EventResponse, error) {
 	// 检查时间
@@ -165,7 +216,7 @@ func (s *EventService) CreateEvent(req *models.CreateEventRequest) (*models.Even
 		StartTime:    req.StartTime,
 		EndTime:      req.EndTime,
 		Location:     req.Location,
-		Status:       "进行中",
+		Status:       initialEventStatus(req.StartTime, req.EndTime),
 		CreatedBy:    1, // 这里应该从当前用户上下文中获取，示例代码使用固定值
 		Image:        req.Image,
 		Category:     req.Category,
@@ -176,9 +227,25 @@ func (s *EventService) CreateEvent(req *models.CreateEventRequest) (*models.Even
 		ViewCount:    0,
 		HotnessScore: 0.0,
 	}
+	applyEventLocation(&event)
 
 	// 保存到数据库
-	if err := s.db.Create(&event).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		if err := syncEventCategories(tx, event.ID); err != nil {
+			return err
+		}
+		if err := linkEventTags(tx, &event); err != nil {
+			return err
+		}
+		if err := indexEvent(tx, &event); err != nil {
+			return err
+		}
+		return recordEventCreated(tx, &event, "created", nil)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -214,9 +281,15 @@ func (s *EventService) UpdateEvent(id uint, req *models.UpdateEventRequest) (*mo
 	}
 	if req.Location != "" {
 		event.Location = req.Location
+		applyEventLocation(&event)
 	}
+	status := ""
 	if req.Status != "" {
-		event.Status = req.Status
+		code, ok := models.NormalizeEventStatus(req.Status)
+		if !ok {
+			return nil, errors.New("invalid event status")
+		}
+		status = code
 	}
 	if req.Category != "" {
 		event.Category = req.Category
@@ -242,8 +315,31 @@ func (s *EventService) UpdateEvent(id uint, req *models.UpdateEventRequest) (*mo
 		return nil, errors.New("end time must be after start time")
 	}
 
-	// 更新数据库
-	if err := s.db.Save(&event).Error; err != nil {
+	// 更新数据库，状态变化需要符合生命周期并记录历史
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if code, ok := models.NormalizeEventStatus(event.Status); ok {
+			event.Status = code
+		}
+		if status != "" && status != event.Status {
+			if !models.CanTransitionEventStatus(event.Status, status) {
+				return fmt.Errorf("invalid status transition from %s to %s", event.Status, status)
+			}
+			if err := transitionEventStatus(tx, &event, status, models.EventTransitionManual, "updated", nil); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&event).Error; err != nil {
+			return err
+		}
+		if err := syncEventCategories(tx, event.ID); err != nil {
+			return err
+		}
+		if err := linkEventTags(tx, &event); err != nil {
+			return err
+		}
+		return indexEvent(tx, &event)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -261,18 +357,19 @@ func (s *EventService) DeleteEvent(id uint) error {
 		return err
 	}
 
-	// 使用软删除
-	if err := s.db.Delete(&event).Error; err != nil {
-		return err
-	}
-
-	return nil
+	// 使用软删除，同时去掉标签关联
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&event).Error; err != nil {
+			return err
+		}
+		return syncEventTags(tx, event.ID)
+	})
 }
 
 // GetEventsByStatus 根据状态获取事件
 func (s *EventService) GetEventsByStatus(status string) ([]models.EventResponse, error) {
 	var events []models.Event
-	if err := s.db.Where("status = ?", status).Find(&events).Error; err != nil {
+	if err := s.db.Where("status = ?", normalizeStatusFilter(status)).Find(&events).Error; err != nil {
 		return nil, err
 	}
 
@@ -285,18 +382,18 @@ func (s *EventService) GetEventsByStatus(status string) ([]models.EventResponse,
 	return eventResponses, nil
 }
 
-// 检查并更新事件状态
+// UpdateEventStatus 按生命周期推进事件状态，见 RunLifecycleTransitions
 func (s *EventService) UpdateEventStatus() error {
-	now := time.Now()
+	_, err := s.RunLifecycleTransitions()
+	return err
+}
 
-	// 找出所有已结束但状态仍为"进行中"的事件
-	if err := s.db.Model(&models.Event{}).
-		Where("end_time < ? AND status = ?", now, "进行中").
-		Update("status", "已结束").Error; err != nil {
-		return err
+// normalizeStatusFilter 将状态筛选参数统一为状态代码，无法识别时原样使用
+func normalizeStatusFilter(status string) string {
+	if code, ok := models.NormalizeEventStatus(status); ok {
+		return code
 	}
-
-	return nil
+	return status
 }
 
 // 将Event转换为EventResponse
@@ -309,7 +406,11 @@ func convertToEventResponse(event *models.Event) models.EventResponse {
 		StartTime:    event.StartTime,
 		EndTime:      event.EndTime,
 		Location:     event.Location,
+		LocationCode: event.LocationCode,
+		Latitude:     event.Latitude,
+		Longitude:    event.Longitude,
 		Status:       event.Status,
+		StatusLabel:  models.EventStatusLabel(event.Status, "zh"),
 		CreatedBy:    event.CreatedBy,
 		Image:        event.Image,
 		Category:     event.Category,
@@ -334,7 +435,7 @@ func (s *EventService) GetHotEvents(limit int) ([]models.EventResponse, error) {
 	}
 
 	var events []models.Event
-	err := s.db.Where("status = ?", "进行中").
+	err := s.db.Where("status = ?", models.EventStatusOngoing).
 		Order("hotness_score desc, view_count desc, created_at desc").
 		Limit(limit).Find(&events).Error
 	if err != nil {
@@ -349,22 +450,31 @@ func (s *EventService) GetHotEvents(limit int) ([]models.EventResponse, error) {
 	return eventResponses, nil
 }
 
-// GetEventCategories 获取所有事件分类
+// GetEventCategories 获取有事件的分类名称，按分类的排序返回，停用的分类不返回
 func (s *EventService) GetEventCategories() ([]string, error) {
-	var categories []string
-	err := s.db.Model(&models.Event{}).
-		Select("DISTINCT category").
-		Where("category != ''").
-		Order("category").
-		Pluck("category", &categories).Error
+	categories := make([]string, 0)
+	err := s.db.Model(&models.Category{}).
+		Where("is_active = ?", true).
+		Where("id IN (?)", s.db.Table("event_categories AS ec").
+			Select("ec.category_id").
+			Joins("JOIN events e ON e.id = ec.event_id AND e.deleted_at IS NULL")).
+		Order("sort_order ASC, name ASC").
+		Pluck("name", &categories).Error
 	return categories, err
 }
 
-// IncrementViewCount 增加事件浏览次数
-func (s *EventService) IncrementViewCount(id uint) error {
-	return s.db.Model(&models.Event{}).
-		Where("id = ?", id).
-		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+// IncrementViewCount 增加事件浏览次数，同一访客在去重窗口内只计一次
+func (s *EventService) IncrementViewCount(id uint, viewerKey string) error {
+	var count int64
+	if err := s.db.Model(&models.Event{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("event not found")
+	}
+
+	_, err := s.viewCounter.RecordView(ViewTargetEvent, id, viewerKey)
+	return err
 }
 
 // UpdateHotnessScore 更新事件热度分值
@@ -379,11 +489,11 @@ func (s *EventService) GetEventsByCategory(category string, query *models.EventQ
 	var events []models.Event
 	var total int64
 
-	db := s.db.Model(&models.Event{}).Where("category = ?", category)
+	db := categoryFilter(s.db, s.db.Model(&models.Event{}), "event_categories", "event_id", "id", "category", category)
 
 	// 添加状态筛选
 	if query.Status != "" {
-		db = db.Where("status = ?", query.Status)
+		db = db.Where("status = ?", normalizeStatusFilter(query.Status))
 	}
 
 	// 计算总数
@@ -418,45 +528,48 @@ func (s *EventService) GetEventsByCategory(category string, query *models.EventQ
 	}, nil
 }
 
-// GetPopularTags 获取热门标签
+// GetPopularTags 获取热门标签，按关联的事件数排序，Category 为带有该标签的事件中最常见的分类
 func (s *EventService) GetPopularTags(limit int, minCount int) ([]models.TagResponse, error) {
-	type TagCount struct {
-		Tag      string `json:"tag"`
-		Count    int    `json:"count"`
-		Category string `json:"category"`
-	}
-
-	var results []TagCount
-	// 这里使用原生SQL查询，因为标签存储为JSON字符串
-	query := `
-		SELECT 
-			TRIM(BOTH '"' FROM JSON_EXTRACT(tags, CONCAT('$[', numbers.n, ']'))) as tag,
-			COUNT(*) as count,
-			category
-		FROM events
-		CROSS JOIN (
-			SELECT 0 as n UNION SELECT 1 UNION SELECT 2 UNION SELECT 3 UNION SELECT 4 
-			UNION SELECT 5 UNION SELECT 6 UNION SELECT 7 UNION SELECT 8 UNION SELECT 9
-		) numbers
-		WHERE JSON_LENGTH(tags) > numbers.n
-		AND tags != '[]' AND tags != ''
-		GROUP BY tag, category
-		HAVING count >= ?
-		ORDER BY count DESC, tag
-		LIMIT ?
-	`
-
-	err := s.db.Raw(query, minCount, limit).Scan(&results).Error
-	if err != nil {
+	var tags []models.Tag
+	if err := s.db.Where("event_count >= ? AND event_count > 0", minCount).
+		Order("event_count DESC, name").
+		Limit(limit).
+		Find(&tags).Error; err != nil {
 		return nil, err
 	}
+	if len(tags) == 0 {
+		return []models.TagResponse{}, nil
+	}
 
-	var tagResponses []models.TagResponse
-	for _, result := range results {
+	ids := make([]uint, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+	var rows []struct {
+		TagID    uint
+		Category string
+	}
+	if err := s.db.Raw(`SELECT DISTINCT ON (tag_id) tag_id, category FROM (
+			SELECT event_tags.tag_id, events.category, COUNT(*) AS count
+			FROM event_tags JOIN events ON events.id = event_tags.event_id
+			WHERE event_tags.tag_id IN ? AND events.deleted_at IS NULL
+			GROUP BY event_tags.tag_id, events.category
+		) AS categories
+		ORDER BY tag_id, count DESC, category`, ids).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	categories := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		categories[row.TagID] = row.Category
+	}
+
+	tagResponses := make([]models.TagResponse, 0, len(tags))
+	for _, tag := range tags {
 		tagResponses = append(tagResponses, models.TagResponse{
-			Tag:      result.Tag,
-			Count:    result.Count,
-			Category: result.Category,
+			Tag:      tag.Name,
+			Slug:     tag.Slug,
+			Count:    int(tag.EventCount),
+			Category: categories[tag.ID],
 		})
 	}
 
@@ -520,49 +633,57 @@ func (s *EventService) GetTrendingEvents(limit int, timeRange string) ([]models.
 }
 
 // UpdateEventTags 更新事件标签
+// 标签按名称和同义词识别：添加时已有的同义词不会重复，移除同义词会移除对应的标签
 func (s *EventService) UpdateEventTags(id uint, tags []string, operation string) (*models.Event, error) {
 	var event models.Event
-	if err := s.db.First(&event, id).Error; err != nil {
-		return nil, err
-	}
-
-	var newTags []string
-	currentTags := jsonToSlice(event.Tags)
-
-	switch operation {
-	case "replace":
-		newTags = tags
-	case "add":
-		// 添加新标签，去重
-		tagMap := make(map[string]bool)
-		for _, tag := range currentTags {
-			tagMap[tag] = true
-		}
-		for _, tag := range tags {
-			tagMap[tag] = true
-		}
-		for tag := range tagMap {
-			newTags = append(newTags, tag)
-		}
-	case "remove":
-		// 移除指定标签
-		removeMap := make(map[string]bool)
-		for _, tag := range tags {
-			removeMap[tag] = true
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&event, id).Error; err != nil {
+			return err
 		}
-		for _, tag := range currentTags {
-			if !removeMap[tag] {
-				newTags = append(newTags, tag)
+
+		var newTags []string
+		currentTags := parseTags(event.Tags)
+
+		switch operation {
+		case "replace":
+			newTags = tags
+		case "add":
+			// 添加新标签，保持原有顺序，去重在关联标签时完成
+			newTags = mergeTags(currentTags, tags)
+		case "remove":
+			// 移除指定标签
+			known, err := lookupTags(tx, append(append([]string{}, currentTags...), tags...))
+			if err != nil {
+				return err
+			}
+			removeMap := make(map[string]bool)
+			removeIDs := make(map[uint]bool)
+			for _, tag := range tags {
+				key := tagKey(tag)
+				removeMap[key] = true
+				if tagID, ok := known[key]; ok {
+					removeIDs[tagID] = true
+				}
 			}
+			for _, tag := range currentTags {
+				key := tagKey(tag)
+				if !removeMap[key] && !removeIDs[known[key]] {
+					newTags = append(newTags, tag)
+				}
+			}
+		default:
+			newTags = tags
 		}
-	default:
-		newTags = tags
-	}
 
-	event.Tags = sliceToJSON(newTags)
-	event.UpdatedAt = time.Now()
+		event.Tags = sliceToJSON(newTags)
+		event.UpdatedAt = time.Now()
 
-	if err := s.db.Save(&event).Error; err != nil {
+		if err := tx.Save(&event).Error; err != nil {
+			return err
+		}
+		return linkEventTags(tx, &event)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -741,21 +862,6 @@ func (s *EventService) UnlikeEvent(eventID uint, userID uint) error {
 	return err
 }
 
-// IncrementCommentCount 增加评论数
-func (s *EventService) IncrementCommentCount(eventID uint) error {
-	err := s.db.Model(&models.Event{}).
-		Where("id = ?", eventID).
-		UpdateColumn("comment_count", gorm.Expr("comment_count + 1")).Error
-
-	if err != nil {
-		return err
-	}
-
-	// 自动重新计算热度值
-	_, err = s.CalculateHotness(eventID, nil)
-	return err
-}
-
 // IncrementShareCount 增加分享数
 func (s *EventService) IncrementShareCount(eventID uint) error {
 	err := s.db.Model(&models.Event{}).
@@ -805,9 +911,12 @@ type EventCluster struct {
 
 // EventGenerationResult 事件生成结果
 type EventGenerationResult struct {
+	Mode              string                 `json:"mode"` // full 全量 / incremental 增量
 	GeneratedEvents   []models.EventResponse `json:"generated_events"`
+	UpdatedEvents     []models.EventResponse `json:"updated_events,omitempty"` // 增量模式下追加了新闻的已有事件
 	TotalEvents       int                    `json:"total_events"`
 	ProcessedNews     int                    `json:"processed_news"`
+	AttachedNews      int                    `json:"attached_news"` // 归入已有事件的新闻数
 	GenerationTime    time.Time              `json:"generation_time"`
 	ElapsedTime       string                 `json:"elapsed_time"`
 	CategoryBreakdown map[string]int         `json:"category_breakdown"`
@@ -815,48 +924,15 @@ type EventGenerationResult struct {
 
 // GenerateEventsFromNews 从新闻自动生成事件
 func (s *EventService) GenerateEventsFromNews() (*EventGenerationResult, error) {
-	startTime := time.Now()
-
-	// 1. 获取所有新闻
-	var allNews []models.News
-	if err := s.db.Find(&allNews).Error; err != nil {
-		return nil, fmt.Errorf("获取新闻列表失败: %w", err)
-	}
-
-	if len(allNews) == 0 {
-		return nil, errors.New("数据库中没有新闻，无法生成事件")
-	}
-
-	// 2. 根据分类将新闻分组
-	categoryNews := make(map[string][]models.News)
-	for _, news := range allNews {
-		if news.Category == "" {
-			news.Category = "未分类"
-		}
-		categoryNews[news.Category] = append(categoryNews[news.Category], news)
-	}
-
-	// 3. 为每个分类生成事件聚类
-	allEventClusters := make([]*EventCluster, 0)
-	categoryBreakdown := make(map[string]int)
-
-	for category, newsList := range categoryNews {
-		// 对每个分类内的新闻进行简单聚类
-		clusters := s.clusterNewsByTitle(newsList)
-		categoryBreakdown[category] = len(clusters)
-		allEventClusters = append(allEventClusters, clusters...)
-	}
-
-	// 4. 按热度对事件聚类排序
-	sort.Slice(allEventClusters, func(i, j int) bool {
-		return allEventClusters[i].HotnessScore > allEventClusters[j].HotnessScore
-	})
+	return s.GenerateEvents(context.Background(), "full", nil)
+}
 
-	// 5. 转换聚类为事件并保存到数据库
-	generatedEvents := make([]models.EventResponse, 0)
+// saveClusters 将聚类保存为事件并建立新闻关联
+func (s *EventService) saveClusters(clusters []*EventCluster) ([]models.EventResponse, error) {
+	generatedEvents := make([]models.EventResponse, 0, len(clusters))
 	newsService := NewNewsService() // 创建新闻服务实例
 
-	for _, cluster := range allEventClusters {
+	for _, cluster := range clusters {
 		event := s.convertClusterToEvent(cluster)
 
 		// 保存事件到数据库
@@ -867,327 +943,31 @@ func (s *EventService) GenerateEventsFromNews() (*EventGenerationResult, error)
 
 		// 更新关联新闻的事件ID
 		newsIDs := make([]uint, 0)
+		affectedEvents := []uint{savedEvent.ID} // 新事件和新闻原来所属的事件都需要重新汇总实体
 		for _, news := range cluster.NewsList {
 			newsIDs = append(newsIDs, news.ID)
+			if news.BelongedEventID != nil {
+				affectedEvents = append(affectedEvents, *news.BelongedEventID)
+			}
 		}
 
 		if err := newsService.UpdateNewsEventAssociation(newsIDs, savedEvent.ID); err != nil {
 			return nil, fmt.Errorf("更新新闻关联失败: %w", err)
 		}
-
-		generatedEvents = append(generatedEvents, *savedEvent)
-	}
-
-	elapsed := time.Since(startTime)
-	return &EventGenerationResult{
-		GeneratedEvents:   generatedEvents,
-		TotalEvents:       len(generatedEvents),
-		ProcessedNews:     len(allNews),
-		GenerationTime:    time.Now(),
-		ElapsedTime:       elapsed.String(),
-		CategoryBreakdown: categoryBreakdown,
-	}, nil
-}
-
-// clusterNewsByTitle 根据标题相似度简单聚类新闻
-func (s *EventService) clusterNewsByTitle(newsList []models.News) []*EventCluster {
-	// 按发布时间排序
-	sort.Slice(newsList, func(i, j int) bool {
-		return newsList[i].PublishedAt.Before(newsList[j].PublishedAt)
-	})
-
-	clusters := make([]*EventCluster, 0)
-	processed := make(map[uint]bool)
-
-	// 对每个未处理的新闻，尝试创建或加入聚类
-	for i, news := range newsList {
-		if processed[news.ID] {
-			continue
-		}
-
-		// 创建新聚类
-		cluster := &EventCluster{
-			Title:        news.Title,
-			Description:  news.Summary,
-			Category:     news.Category,
-			StartTime:    news.PublishedAt,
-			EndTime:      news.PublishedAt.Add(24 * time.Hour), // 默认事件持续一天
-			Location:     "全国",                                 // 默认位置
-			Status:       "进行中",
-			Tags:         s.extractTags(news),
-			Source:       news.Source,
-			NewsList:     []models.News{news},
-			HotnessScore: float64(news.ViewCount + news.LikeCount*2 + news.CommentCount*3 + news.ShareCount*5),
+		if err := syncEventEntities(s.db, affectedEvents...); err != nil {
+			return nil, fmt.Errorf("汇总事件实体失败: %w", err)
 		}
 
-		// 查找相似的新闻加入此聚类
-		for j := i + 1; j < len(newsList); j++ {
-			if processed[newsList[j].ID] {
-				continue
-			}
-
-			// 如果标题相似度高，加入聚类
-			if s.areTitlesSimilar(news.Title, newsList[j].Title) {
-				cluster.NewsList = append(cluster.NewsList, newsList[j])
-				processed[newsList[j].ID] = true
-
-				// 更新聚类信息
-				if newsList[j].PublishedAt.Before(cluster.StartTime) {
-					cluster.StartTime = newsList[j].PublishedAt
-				}
-				if newsList[j].PublishedAt.After(cluster.EndTime) {
-					cluster.EndTime = newsList[j].PublishedAt
-				}
-
-				// 合并标签
-				newsTags := s.extractTags(newsList[j])
-				for _, tag := range newsTags {
-					if !s.contains(cluster.Tags, tag) {
-						cluster.Tags = append(cluster.Tags, tag)
-					}
-				}
-
-				// 累加热度分数
-				cluster.HotnessScore += float64(newsList[j].ViewCount + newsList[j].LikeCount*2 +
-					newsList[j].CommentCount*3 + newsList[j].ShareCount*5)
-			}
-		}
-
-		// 完善聚类信息
-		if len(cluster.Description) == 0 && len(cluster.NewsList) > 0 {
-			// 如果没有描述，使用第一条新闻的内容开头作为描述
-			for _, n := range cluster.NewsList {
-				if len(n.Content) > 10 {
-					// 使用内容的前100个字符作为描述
-					endPos := int(math.Min(100, float64(len(n.Content))))
-					cluster.Description = n.Content[:endPos] + "..."
-					break
-				}
-			}
-		}
-
-		// 更新状态
-		now := time.Now()
-		if cluster.EndTime.Before(now) {
-			cluster.Status = "已结束"
-		} else if cluster.StartTime.After(now) {
-			cluster.Status = "未开始"
-		}
-
-		// 将此聚类加入结果
-		clusters = append(clusters, cluster)
-		processed[news.ID] = true
-	}
-
-	return clusters
-}
-
-// areTitlesSimilar 检查两个标题是否相似（放宽标准，提高聚合程度）
-func (s *EventService) areTitlesSimilar(title1, title2 string) bool {
-	title1 = strings.ToLower(title1)
-	title2 = strings.ToLower(title2)
-
-	// 1. 检查地域关键词匹配
-	if s.hasRegionalMatch(title1, title2) {
-		return true
-	}
-
-	// 2. 检查主题关键词匹配
-	if s.hasTopicMatch(title1, title2) {
-		return true
-	}
-
-	// 3. 通用关键词相似度检查（降低阈值）
-	keywords1 := s.extractKeywords(title1)
-	keywords2 := s.extractKeywords(title2)
-
-	var matches int
-	var totalKeywords int
-
-	// 计算关键词匹配数
-	for _, kw1 := range keywords1 {
-		if len(kw1) < 2 {
-			continue
-		}
-		totalKeywords++
-		for _, kw2 := range keywords2 {
-			if len(kw2) < 2 {
-				continue
-			}
-			// 包含关系或编辑距离相似
-			if strings.Contains(kw1, kw2) || strings.Contains(kw2, kw1) || s.isSimilarKeyword(kw1, kw2) {
-				matches++
-				break // 避免重复计算
-			}
-		}
-	}
-
-	// 降低相似度阈值：只需要20%的关键词匹配或至少1个关键词匹配
-	minKeywords := int(math.Max(1, float64(totalKeywords)*0.2))
-	return matches >= minKeywords
-}
-
-// hasRegionalMatch 检查是否有地域关键词匹配
-func (s *EventService) hasRegionalMatch(title1, title2 string) bool {
-	// 定义地域关键词组
-	regionalGroups := map[string][]string{
-		"中东":   {"中东", "以色列", "巴勒斯坦", "伊朗", "叙利亚", "伊拉克", "沙特", "阿联酋", "黎巴嫩", "约旦", "也门", "卡塔尔", "科威特", "巴林", "阿曼"},
-		"俄乌":   {"俄罗斯", "乌克兰", "俄乌", "普京", "泽连斯基", "基辅", "莫斯科", "顿巴斯", "克里米亚", "哈尔科夫", "马里乌波尔"},
-		"朝鲜半岛": {"朝鲜", "韩国", "金正恩", "文在寅", "尹锡悦", "平壤", "首尔", "三八线", "板门店"},
-		"南海":   {"南海", "台海", "台湾", "南沙", "西沙", "钓鱼岛", "尖阁诸岛"},
-		"欧洲":   {"欧盟", "英国", "法国", "德国", "意大利", "西班牙", "荷兰", "比利时", "瑞士", "奥地利", "波兰", "捷克"},
-		"美洲":   {"美国", "加拿大", "墨西哥", "巴西", "阿根廷", "智利", "哥伦比亚", "委内瑞拉"},
-		"非洲":   {"埃及", "南非", "尼日利亚", "肯尼亚", "摩洛哥", "阿尔及利亚", "利比亚", "苏丹", "埃塞俄比亚"},
-		"东南亚":  {"越南", "泰国", "新加坡", "马来西亚", "印尼", "菲律宾", "缅甸", "柬埔寨", "老挝"},
-		"南亚":   {"印度", "巴基斯坦", "孟加拉国", "斯里兰卡", "尼泊尔", "不丹", "马尔代夫"},
-	}
-
-	// 检查两个标题是否属于同一地域
-	for _, keywords := range regionalGroups {
-		found1, found2 := false, false
-		for _, keyword := range keywords {
-			if strings.Contains(title1, keyword) {
-				found1 = true
-			}
-			if strings.Contains(title2, keyword) {
-				found2 = true
-			}
-		}
-		if found1 && found2 {
-			return true
-		}
-	}
-
-	return false
-}
-
-// hasTopicMatch 检查是否有主题关键词匹配
-func (s *EventService) hasTopicMatch(title1, title2 string) bool {
-	// 定义主题关键词组
-	topicGroups := map[string][]string{
-		"经济": {"经济", "GDP", "通胀", "利率", "股市", "汇率", "贸易", "投资", "金融", "央行", "货币", "市场", "企业", "公司"},
-		"科技": {"科技", "AI", "人工智能", "5G", "芯片", "半导体", "互联网", "数字", "智能", "技术", "创新", "研发"},
-		"政治": {"政治", "选举", "总统", "首相", "政府", "议会", "国会", "外交", "会谈", "峰会", "访问", "制裁"},
-		"军事": {"军事", "军队", "武器", "导弹", "战机", "军演", "防务", "安全", "冲突", "战争", "和平", "停火"},
-		"能源": {"能源", "石油", "天然气", "电力", "核能", "煤炭", "新能源", "太阳能", "风能", "电池"},
-		"环境": {"环境", "气候", "碳排放", "全球变暖", "污染", "环保", "绿色", "可持续", "减排"},
-		"健康": {"健康", "医疗", "疫苗", "病毒", "疫情", "医院", "药物", "治疗", "医生", "患者"},
-		"教育": {"教育", "学校", "大学", "学生", "老师", "考试", "学习", "培训", "课程"},
-		"体育": {"体育", "奥运", "世界杯", "足球", "篮球", "网球", "游泳", "田径", "运动员", "比赛"},
-		"文化": {"文化", "艺术", "电影", "音乐", "文学", "博物馆", "遗产", "传统", "节庆"},
-	}
-
-	// 检查两个标题是否属于同一主题
-	for _, keywords := range topicGroups {
-		found1, found2 := false, false
-		for _, keyword := range keywords {
-			if strings.Contains(title1, keyword) {
-				found1 = true
-			}
-			if strings.Contains(title2, keyword) {
-				found2 = true
-			}
-		}
-		if found1 && found2 {
-			return true
-		}
-	}
-
-	return false
-}
-
-// isSimilarKeyword 检查两个关键词是否相似（编辑距离等）
-func (s *EventService) isSimilarKeyword(kw1, kw2 string) bool {
-	// 简单的相似度检查：长度相近且有公共子串
-	if math.Abs(float64(len(kw1)-len(kw2))) > 2 {
-		return false
-	}
-
-	// 检查是否有公共子串（长度>=2）
-	for i := 0; i <= len(kw1)-2; i++ {
-		substr := kw1[i : i+2]
-		if strings.Contains(kw2, substr) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// extractKeywords 从标题中提取关键词（增强版）
-func (s *EventService) extractKeywords(title string) []string {
-	title = strings.ToLower(title)
-
-	// 替换标点符号为空格
-	title = strings.ReplaceAll(title, "，", " ")
-	title = strings.ReplaceAll(title, "。", " ")
-	title = strings.ReplaceAll(title, "？", " ")
-	title = strings.ReplaceAll(title, "！", " ")
-	title = strings.ReplaceAll(title, "、", " ")
-	title = strings.ReplaceAll(title, "：", " ")
-	title = strings.ReplaceAll(title, "；", " ")
-	title = strings.ReplaceAll(title, ",", " ")
-	title = strings.ReplaceAll(title, ".", " ")
-	title = strings.ReplaceAll(title, "?", " ")
-	title = strings.ReplaceAll(title, "!", " ")
-	title = strings.ReplaceAll(title, ":", " ")
-	title = strings.ReplaceAll(title, ";", " ")
-	title = strings.ReplaceAll(title, "'", " ")
-	title = strings.ReplaceAll(title, "\"", " ")
-	title = strings.ReplaceAll(title, "(", " ")
-	title = strings.ReplaceAll(title, ")", " ")
-	title = strings.ReplaceAll(title, "（", " ")
-	title = strings.ReplaceAll(title, "）", " ")
-	title = strings.ReplaceAll(title, "[", " ")
-	title = strings.ReplaceAll(title, "]", " ")
-	title = strings.ReplaceAll(title, "【", " ")
-	title = strings.ReplaceAll(title, "】", " ")
-	title = strings.ReplaceAll(title, "《", " ")
-	title = strings.ReplaceAll(title, "》", " ")
-
-	words := strings.Split(title, " ")
-	result := make([]string, 0)
-
-	// 扩展停用词列表
-	stopWords := map[string]bool{
-		"的": true, "了": true, "是": true, "在": true, "有": true, "和": true, "与": true, "为": true, "将": true, "被": true, "把": true, "对": true, "向": true, "从": true, "到": true, "于": true, "以": true, "及": true, "或": true, "而": true, "且": true, "但": true, "不": true, "没": true, "无": true, "非": true,
-		"a": true, "an": true, "the": true, "to": true, "of": true, "for": true, "and": true, "or": true, "in": true, "on": true, "at": true, "by": true, "with": true, "from": true, "up": true, "about": true, "into": true, "through": true, "during": true, "before": true, "after": true, "above": true, "below": true, "between": true, "among": true, "this": true, "that": true, "these": true, "those": true,
-		"新闻": true, "报道": true, "消息": true, "最新": true, "今日": true, "昨日": true, "今天": true, "昨天": true, "明天": true, "本周": true, "上周": true, "下周": true, "本月": true, "上月": true, "下月": true, "今年": true, "去年": true, "明年": true,
-	}
-
-	for _, word := range words {
-		word = strings.TrimSpace(word)
-		// 保留长度>=2且不是停用词的词语
-		if len(word) >= 2 && !stopWords[word] {
-			result = append(result, word)
-		}
+		generatedEvents = append(generatedEvents, *savedEvent)
 	}
 
-	return result
+	return generatedEvents, nil
 }
 
 // extractTags 从新闻中提取标签
 func (s *EventService) extractTags(news models.News) []string {
-	tags := make([]string, 0)
-
-	// 1. 从新闻的tags字段提取
-	if news.Tags != "" {
-		// 如果是JSON格式，解析
-		if strings.HasPrefix(news.Tags, "[") && strings.HasSuffix(news.Tags, "]") {
-			var parsedTags []string
-			if err := json.Unmarshal([]byte(news.Tags), &parsedTags); err == nil {
-				tags = append(tags, parsedTags...)
-			}
-		} else {
-			// 否则按逗号分割
-			for _, tag := range strings.Split(news.Tags, ",") {
-				tag = strings.TrimSpace(tag)
-				if tag != "" {
-					tags = append(tags, tag)
-				}
-			}
-		}
-	}
+	// 1. 从新闻的tags字段提取，JSON 数组或以逗号分隔
+	tags := parseTags(news.Tags)
 
 	// 2. 添加分类作为标签
 	if news.Category != "" && !s.contains(tags, news.Category) {
@@ -1226,28 +1006,7 @@ func (s *EventService) convertClusterToEvent(cluster *EventCluster) models.Creat
 		}
 
 		// 添加新闻到内容
-		content += fmt.Sprintf("### %s\n\n", news.Title)
-		if news.Source != "" {
-			content += fmt.Sprintf("来源: %s   ", news.Source)
-		}
-		if !news.PublishedAt.IsZero() {
-			content += fmt.Sprintf("发布时间: %s\n\n", news.PublishedAt.Format("2006-01-02 15:04:05"))
-		} else {
-			content += "\n\n"
-		}
-
-		// 添加摘要或内容片段
-		if news.Summary != "" {
-			content += news.Summary + "\n\n"
-		} else if news.Content != "" {
-			// 使用内容的前200个字符作为摘要
-			endPos := int(math.Min(200, float64(len(news.Content))))
-			content += news.Content[:endPos]
-			if len(news.Content) > 200 {
-				content += "..."
-			}
-			content += "\n\n"
-		}
+		content += formatNewsSection(news)
 	}
 
 	return models.CreateEventRequest{
@@ -1264,3 +1023,31 @@ func (s *EventService) convertClusterToEvent(cluster *EventCluster) models.Creat
 		RelatedLinks: links,
 	}
 }
+
+// formatNewsSection 生成事件内容中单条相关新闻的段落
+func formatNewsSection(news models.News) string {
+	section := fmt.Sprintf("### %s\n\n", news.Title)
+	if news.Source != "" {
+		section += fmt.Sprintf("来源: %s   ", news.Source)
+	}
+	if !news.PublishedAt.IsZero() {
+		section += fmt.Sprintf("发布时间: %s\n\n", news.PublishedAt.Format("2006-01-02 15:04:05"))
+	} else {
+		section += "\n\n"
+	}
+
+	// 添加摘要或内容片段
+	if news.Summary != "" {
+		section += news.Summary + "\n\n"
+	} else if news.Content != "" {
+		// 使用内容的前200个字符作为摘要
+		endPos := int(math.Min(200, float64(len(news.Content))))
+		section += news.Content[:endPos]
+		if len(news.Content) > 200 {
+			section += "..."
+		}
+		section += "\n\n"
+	}
+
+	return section
+}
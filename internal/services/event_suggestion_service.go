@@ -0,0 +1,404 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// suggestionCandidateWindow 候选事件的时间跨度与新闻发布时间相距不超过此范围
+	suggestionCandidateWindow = 7 * 24 * time.Hour
+	// suggestionCandidateLimit 推荐候选事件时最多比较的事件数
+	suggestionCandidateLimit = 300
+	// maxMatchedKeywords 每个候选事件返回的共同关键词数
+	maxMatchedKeywords = 8
+	// suggestionFeedbackMinSamples 推荐归入阈值所需的最少审核记录数
+	suggestionFeedbackMinSamples = 20
+)
+
+// newsEventScore 新闻与一个事件的相似度
+type newsEventScore struct {
+	event      *models.Event
+	similarity float64
+	conflict   bool // 新闻和事件提到不同地域
+	keywords   []string
+}
+
+// SuggestEventsForNews 为新闻推荐可能所属的事件，按相似度从高到低返回前 limit 个
+// 与增量生成使用相同的文本处理，提到不同地域的事件和编辑拒绝过的事件不推荐
+func (s *EventService) SuggestEventsForNews(newsID uint, query *models.EventSuggestionQueryRequest) (*models.NewsEventSuggestionsResponse, error) {
+	if query.Limit <= 0 || query.Limit > 20 {
+		query.Limit = 5
+	}
+
+	var news models.News
+	if err := s.db.First(&news, newsID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("news not found")
+		}
+		return nil, err
+	}
+
+	candidates, err := s.suggestionCandidates(news, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rejected []uint
+	if err := s.db.Model(&models.EventSuggestionFeedback{}).
+		Where("news_id = ? AND decision = ?", news.ID, models.SuggestionRejected).
+		Pluck("event_id", &rejected).Error; err != nil {
+		return nil, err
+	}
+	excluded := make(map[uint]bool, len(rejected)+1)
+	for _, id := range rejected {
+		excluded[id] = true
+	}
+	if news.BelongedEventID != nil {
+		excluded[*news.BelongedEventID] = true
+	}
+
+	threshold := loadClusteringOptions().attachThreshold
+	response := &models.NewsEventSuggestionsResponse{
+		NewsID:          news.ID,
+		BelongedEventID: news.BelongedEventID,
+		AttachThreshold: threshold,
+		Suggestions:     []models.NewsEventSuggestion{},
+	}
+	for _, score := range scoreNewsAgainstEvents(news, candidates) {
+		if len(response.Suggestions) >= query.Limit {
+			break
+		}
+		if score.conflict || score.similarity <= 0 || excluded[score.event.ID] {
+			continue
+		}
+
+		suggestion := models.NewsEventSuggestion{
+			Rank:            len(response.Suggestions) + 1,
+			Similarity:      math.Round(score.similarity*1000) / 1000,
+			AboveThreshold:  score.similarity >= threshold,
+			SameCategory:    newsEventSameCategory(news, *score.event),
+			MatchedKeywords: score.keywords,
+			Event:           convertToEventResponse(score.event),
+		}
+		suggestion.Event.Content = ""
+		response.Suggestions = append(response.Suggestions, suggestion)
+	}
+	return response, nil
+}
+
+// ReviewEventSuggestions 批量审核候选事件
+// 接受的新闻归入事件并更新事件的时间跨度、标签和实体，拒绝的事件之后不再推荐给该新闻；
+// 每条审核结果连同当时的相似度和名次保存下来，用于评估归入阈值
+func (s *EventService) ReviewEventSuggestions(req *models.ReviewEventSuggestionsRequest, operatorID uint) (*models.ReviewEventSuggestionsResult, error) {
+	result := &models.ReviewEventSuggestionsResult{
+		Skipped:       []models.SkippedSuggestion{},
+		UpdatedEvents: []models.EventResponse{},
+	}
+	skip := func(item models.ReviewEventSuggestionItem, reason string) {
+		result.Skipped = append(result.Skipped, models.SkippedSuggestion{NewsID: item.NewsID, EventID: item.EventID, Reason: reason})
+	}
+
+	// 按新闻分组，每条新闻只计算一次相似度
+	newsOrder := make([]uint, 0)
+	itemsByNews := make(map[uint][]models.ReviewEventSuggestionItem)
+	for _, item := range req.Items {
+		if _, ok := itemsByNews[item.NewsID]; !ok {
+			newsOrder = append(newsOrder, item.NewsID)
+		}
+		itemsByNews[item.NewsID] = append(itemsByNews[item.NewsID], item)
+	}
+
+	attachOrder := make([]uint, 0)
+	attachments := make(map[uint][]models.News)
+	for _, newsID := range newsOrder {
+		items := itemsByNews[newsID]
+
+		var news models.News
+		if err := s.db.First(&news, newsID).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			for _, item := range items {
+				skip(item, "news not found")
+			}
+			continue
+		}
+
+		// 合并过的事件跟随重定向
+		eventIDs := make([]uint, len(items))
+		for i, item := range items {
+			eventIDs[i], _ = s.resolveEventRedirect(item.EventID)
+		}
+		candidates, err := s.suggestionCandidates(news, eventIDs)
+		if err != nil {
+			return nil, err
+		}
+		scores := scoreNewsAgainstEvents(news, candidates)
+
+		var acceptedEvent uint
+		for i, item := range items {
+			score, rank, ok := findNewsEventScore(scores, eventIDs[i])
+			if !ok {
+				skip(item, "event not found")
+				continue
+			}
+
+			if item.Decision == models.SuggestionAccepted {
+				if news.BelongedEventID != nil && *news.BelongedEventID != eventIDs[i] {
+					skip(item, "news already linked to another event")
+					continue
+				}
+				if acceptedEvent != 0 && acceptedEvent != eventIDs[i] {
+					skip(item, "news accepted for another event in this request")
+					continue
+				}
+			}
+
+			feedback := models.EventSuggestionFeedback{
+				NewsID:          news.ID,
+				EventID:         eventIDs[i],
+				Decision:        item.Decision,
+				Similarity:      math.Round(score.similarity*1000) / 1000,
+				Rank:            rank,
+				MatchedKeywords: sliceToJSON(score.keywords),
+				SameCategory:    newsEventSameCategory(news, *score.event),
+				OperatorID:      operatorID,
+			}
+			if err := s.db.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "news_id"}, {Name: "event_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"decision", "similarity", "rank", "matched_keywords", "same_category", "operator_id", "updated_at"}),
+			}).Create(&feedback).Error; err != nil {
+				return nil, err
+			}
+
+			if item.Decision == models.SuggestionRejected {
+				result.Rejected++
+				continue
+			}
+			result.Accepted++
+			if acceptedEvent == 0 && news.BelongedEventID == nil {
+				if _, ok := attachments[eventIDs[i]]; !ok {
+					attachOrder = append(attachOrder, eventIDs[i])
+				}
+				attachments[eventIDs[i]] = append(attachments[eventIDs[i]], news)
+			}
+			acceptedEvent = eventIDs[i]
+		}
+	}
+
+	for _, eventID := range attachOrder {
+		updated, err := s.attachNewsToEvent(eventID, attachments[eventID])
+		if err != nil {
+			return nil, err
+		}
+		updated.Content = ""
+		result.UpdatedEvents = append(result.UpdatedEvents, *updated)
+	}
+	return result, nil
+}
+
+// GetSuggestionFeedbackStats 统计候选事件的审核记录，给出当前归入阈值的准确率和召回率
+// 审核记录足够时在 0.05 到 0.95 之间按 0.05 的步长寻找准确率最高的阈值
+func (s *EventService) GetSuggestionFeedbackStats() (*models.SuggestionFeedbackStats, error) {
+	var feedback []models.EventSuggestionFeedback
+	if err := s.db.Select("decision", "similarity").Find(&feedback).Error; err != nil {
+		return nil, err
+	}
+
+	threshold := loadClusteringOptions().attachThreshold
+	stats := &models.SuggestionFeedbackStats{
+		AttachThreshold: threshold,
+		Buckets:         make([]models.SimilarityBucket, 10),
+	}
+	for i := range stats.Buckets {
+		stats.Buckets[i].Min = float64(i) / 10
+		stats.Buckets[i].Max = float64(i+1) / 10
+	}
+
+	var truePositive, predicted int64
+	for _, record := range feedback {
+		accepted := record.Decision == models.SuggestionAccepted
+		bucket := &stats.Buckets[min(int(record.Similarity*10), 9)]
+		if accepted {
+			stats.Accepted++
+			bucket.Accepted++
+		} else {
+			stats.Rejected++
+			bucket.Rejected++
+		}
+		if record.Similarity >= threshold {
+			predicted++
+			if accepted {
+				truePositive++
+			}
+		}
+	}
+	if predicted > 0 {
+		stats.Precision = math.Round(float64(truePositive)/float64(predicted)*1000) / 1000
+	}
+	if stats.Accepted > 0 {
+		stats.Recall = math.Round(float64(truePositive)/float64(stats.Accepted)*1000) / 1000
+	}
+
+	if len(feedback) < suggestionFeedbackMinSamples || stats.Accepted == 0 || stats.Rejected == 0 {
+		return stats, nil
+	}
+	bestThreshold, bestCorrect := threshold, -1
+	for step := 1; step <= 19; step++ {
+		candidate := float64(step) / 20
+		correct := 0
+		for _, record := range feedback {
+			if (record.Similarity >= candidate) == (record.Decision == models.SuggestionAccepted) {
+				correct++
+			}
+		}
+		// 准确率相同时取离当前阈值最近的
+		if correct > bestCorrect || (correct == bestCorrect && math.Abs(candidate-threshold) < math.Abs(bestThreshold-threshold)) {
+			bestThreshold, bestCorrect = candidate, correct
+		}
+	}
+	stats.RecommendedThreshold = &bestThreshold
+	return stats, nil
+}
+
+// suggestionCandidates 时间跨度与新闻发布时间相近的事件，以及 extraIDs 指定的事件
+func (s *EventService) suggestionCandidates(news models.News, extraIDs []uint) ([]models.Event, error) {
+	published := news.PublishedAt
+	if published.IsZero() {
+		published = news.CreatedAt
+	}
+
+	var candidates []models.Event
+	if err := s.db.Where("start_time <= ? AND end_time >= ?",
+		published.Add(suggestionCandidateWindow), published.Add(-suggestionCandidateWindow)).
+		Order("hotness_score DESC").
+		Limit(suggestionCandidateLimit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]bool, len(candidates))
+	for _, event := range candidates {
+		found[event.ID] = true
+	}
+	missing := make([]uint, 0)
+	for _, id := range uniqueIDs(extraIDs) {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		var extra []models.Event
+		if err := s.db.Where("id IN ?", missing).Find(&extra).Error; err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, extra...)
+	}
+	return candidates, nil
+}
+
+// scoreNewsAgainstEvents 在新闻和事件的文本上建立 TF-IDF 模型，按相似度从高到低返回新闻与各事件的相似度
+func scoreNewsAgainstEvents(news models.News, events []models.Event) []newsEventScore {
+	pipeline := newTextPipeline(loadClusteringOptions())
+
+	tokens := make([][]string, 0, len(events)+1)
+	tokens = append(tokens, pipeline.newsTokens(news))
+	for _, event := range events {
+		tokens = append(tokens, pipeline.eventTokens(event))
+	}
+	model := nlp.FitTFIDF(tokens)
+
+	newsVector := model.Transform(tokens[0])
+	newsRegions := pipeline.regions(tokens[0])
+	scores := make([]newsEventScore, len(events))
+	for i := range events {
+		eventVector := model.Transform(tokens[i+1])
+		scores[i] = newsEventScore{
+			event:      &events[i],
+			similarity: nlp.Cosine(newsVector, eventVector),
+			conflict:   regionConflict(newsRegions, pipeline.regions(tokens[i+1])),
+			keywords: matchedKeywords(newsVector, eventVector, maxMatchedKeywords, func(word string) bool {
+				return pipeline.segmenter.HasWord(word) || titleContains(news.Title, word) || titleContains(events[i].Title, word)
+			}),
+		}
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].similarity > scores[j].similarity
+	})
+	return scores
+}
+
+// findNewsEventScore 查找新闻与指定事件的相似度，以及该事件在候选列表（不含地域冲突的事件）中的名次
+func findNewsEventScore(scores []newsEventScore, eventID uint) (newsEventScore, int, bool) {
+	rank := 0
+	for _, score := range scores {
+		if !score.conflict && score.similarity > 0 {
+			rank++
+		}
+		if score.event.ID != eventID {
+			continue
+		}
+		if score.conflict || score.similarity <= 0 {
+			rank = 0
+		}
+		return score, rank, true
+	}
+	return newsEventScore{}, 0, false
+}
+
+// matchedKeywords 两个向量共有的词，按对余弦相似度的贡献从大到小排列
+// 忽略单字；中文词只保留 isWord 认可的，去掉未登录词切分出的二元片段
+func matchedKeywords(a, b nlp.Vector, n int, isWord func(string) bool) []string {
+	type term struct {
+		word   string
+		weight float64
+	}
+	terms := make([]term, 0)
+	for word, weight := range a {
+		other, ok := b[word]
+		if !ok || utf8.RuneCountInString(word) < 2 {
+			continue
+		}
+		if strings.IndexFunc(word, func(r rune) bool { return unicode.Is(unicode.Han, r) }) >= 0 && !isWord(word) {
+			continue
+		}
+		terms = append(terms, term{word, weight * other})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].weight != terms[j].weight {
+			return terms[i].weight > terms[j].weight
+		}
+		return terms[i].word < terms[j].word
+	})
+
+	keywords := make([]string, 0, min(n, len(terms)))
+	for _, t := range terms[:min(n, len(terms))] {
+		keywords = append(keywords, t.word)
+	}
+	return keywords
+}
+
+// titleContains 标题中出现该词，忽略大小写和全半角
+func titleContains(title, word string) bool {
+	return strings.Contains(strings.ToLower(nlp.ToHalfWidth(title)), word)
+}
+
+// newsEventSameCategory 新闻与事件分类相同，没有分类的事件视为“未分类”
+func newsEventSameCategory(news models.News, event models.Event) bool {
+	category := event.Category
+	if category == "" {
+		category = "未分类"
+	}
+	return category == news.Category
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// unknownNewsSource 来源为空的新闻在时间线中的来源名
+const unknownNewsSource = "未知来源"
+
+// GetEventTimeline 按天或小时将事件当前关联的新闻和里程碑组织为时间线
+// 每次请求都从新闻关联实时构建，合并、拆分或重新聚类后立即生效
+func (s *EventService) GetEventTimeline(id uint, query *models.EventTimelineQueryRequest) (*models.EventTimelineResponse, error) {
+	event, redirectedFrom, err := s.findEventFollowingRedirects(id)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity := query.Granularity
+	if granularity != models.TimelineGranularityHour {
+		granularity = models.TimelineGranularityDay
+	}
+
+	var newsList []models.News
+	if err := s.db.Select("id", "title", "summary", "source", "link", "published_at").
+		Where("belonged_event_id = ? AND is_active = ?", event.ID, true).
+		Order("published_at ASC, id ASC").
+		Find(&newsList).Error; err != nil {
+		return nil, err
+	}
+
+	var milestones []models.EventMilestone
+	if err := s.db.Where("event_id = ?", event.ID).
+		Order("occurred_at ASC, id ASC").
+		Find(&milestones).Error; err != nil {
+		return nil, err
+	}
+
+	response := &models.EventTimelineResponse{
+		EventID:        event.ID,
+		Title:          event.Title,
+		Granularity:    granularity,
+		TotalNews:      len(newsList),
+		Entries:        make([]models.TimelineEntry, 0),
+		RedirectedFrom: redirectedFrom,
+	}
+
+	entries := make(map[time.Time]*models.TimelineEntry)
+	sourceCounts := make(map[time.Time]map[string]int)
+	entryFor := func(t time.Time) *models.TimelineEntry {
+		start, end, label := timelinePeriod(t, granularity)
+		entry, ok := entries[start]
+		if !ok {
+			entry = &models.TimelineEntry{
+				PeriodStart: start,
+				PeriodEnd:   end,
+				Label:       label,
+				Sources:     make([]models.TimelineSourceCount, 0),
+				News:        make([]models.TimelineNewsItem, 0),
+				Milestones:  make([]models.EventMilestone, 0),
+			}
+			entries[start] = entry
+			sourceCounts[start] = make(map[string]int)
+		}
+		return entry
+	}
+
+	allSources := make(map[string]bool)
+	for i, news := range newsList {
+		source := news.Source
+		if source == "" {
+			source = unknownNewsSource
+		}
+		allSources[source] = true
+
+		item := models.TimelineNewsItem{
+			ID:             news.ID,
+			Title:          news.Title,
+			Summary:        news.Summary,
+			Source:         source,
+			Link:           news.Link,
+			PublishedAt:    news.PublishedAt,
+			IsFirstReport:  i == 0,
+			IsLatestUpdate: i == len(newsList)-1,
+		}
+
+		entry := entryFor(news.PublishedAt)
+		entry.News = append(entry.News, item)
+		entry.NewsCount++
+		entry.IsFirstReport = entry.IsFirstReport || item.IsFirstReport
+		entry.IsLatestUpdate = entry.IsLatestUpdate || item.IsLatestUpdate
+		sourceCounts[entry.PeriodStart][source]++
+	}
+	response.TotalSources = len(allSources)
+	if len(newsList) > 0 {
+		first := newsList[0].PublishedAt
+		latest := newsList[len(newsList)-1].PublishedAt
+		response.FirstReportAt = &first
+		response.LatestUpdateAt = &latest
+	}
+
+	for _, milestone := range milestones {
+		entry := entryFor(milestone.OccurredAt)
+		entry.Milestones = append(entry.Milestones, milestone)
+	}
+
+	for start, entry := range entries {
+		for source, count := range sourceCounts[start] {
+			entry.Sources = append(entry.Sources, models.TimelineSourceCount{Source: source, Count: count})
+		}
+		sort.Slice(entry.Sources, func(i, j int) bool {
+			if entry.Sources[i].Count != entry.Sources[j].Count {
+				return entry.Sources[i].Count > entry.Sources[j].Count
+			}
+			return entry.Sources[i].Source < entry.Sources[j].Source
+		})
+		entry.SourceCount = len(entry.Sources)
+		response.Entries = append(response.Entries, *entry)
+	}
+
+	sort.Slice(response.Entries, func(i, j int) bool {
+		if query.Order == "desc" {
+			return response.Entries[i].PeriodStart.After(response.Entries[j].PeriodStart)
+		}
+		return response.Entries[i].PeriodStart.Before(response.Entries[j].PeriodStart)
+	})
+
+	return response, nil
+}
+
+// CreateMilestone 为事件添加里程碑
+func (s *EventService) CreateMilestone(eventID uint, req *models.CreateMilestoneRequest, operatorID uint) (*models.EventMilestone, error) {
+	var event models.Event
+	if err := s.db.Select("id").First(&event, eventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+
+	milestone := models.EventMilestone{
+		EventID:     eventID,
+		OccurredAt:  req.OccurredAt,
+		Title:       req.Title,
+		Description: req.Description,
+		Link:        req.Link,
+		CreatedBy:   operatorID,
+	}
+	if err := s.db.Create(&milestone).Error; err != nil {
+		return nil, err
+	}
+
+	return &milestone, nil
+}
+
+// UpdateMilestone 更新里程碑
+func (s *EventService) UpdateMilestone(id uint, req *models.UpdateMilestoneRequest) (*models.EventMilestone, error) {
+	var milestone models.EventMilestone
+	if err := s.db.First(&milestone, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("milestone not found")
+		}
+		return nil, err
+	}
+
+	if req.OccurredAt != nil {
+		milestone.OccurredAt = *req.OccurredAt
+	}
+	if req.Title != "" {
+		milestone.Title = req.Title
+	}
+	if req.Description != nil {
+		milestone.Description = *req.Description
+	}
+	if req.Link != nil {
+		milestone.Link = *req.Link
+	}
+
+	if err := s.db.Save(&milestone).Error; err != nil {
+		return nil, err
+	}
+
+	return &milestone, nil
+}
+
+// DeleteMilestone 删除里程碑
+func (s *EventService) DeleteMilestone(id uint) error {
+	result := s.db.Delete(&models.EventMilestone{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("milestone not found")
+	}
+	return nil
+}
+
+// timelinePeriod 返回时间所在时段的起止时间和显示标签，按服务器本地时区划分
+func timelinePeriod(t time.Time, granularity string) (time.Time, time.Time, string) {
+	t = t.In(time.Local)
+	if granularity == models.TimelineGranularityHour {
+		start := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+		return start, start.Add(time.Hour), start.Format("2006-01-02 15:00")
+	}
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	return start, start.AddDate(0, 0, 1), start.Format("2006-01-02")
+}
@@ -0,0 +1,646 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// jobProgressInterval 执行中的任务写入进度的最小间隔
+	jobProgressInterval = time.Second
+	// jobHeartbeatInterval 执行中的任务刷新心跳并检查取消标记的间隔
+	jobHeartbeatInterval = 15 * time.Second
+	// jobHeartbeatTimeout 心跳超过该时长没有刷新的任务视为执行它的实例已退出
+	jobHeartbeatTimeout = 4 * jobHeartbeatInterval
+)
+
+// activeJobStatuses 未结束的任务状态
+var activeJobStatuses = []string{models.JobStatusQueued, models.JobStatusRunning}
+
+// jobInstanceID 本进程的实例标识，写入所执行任务的 owner
+var jobInstanceID = newJobInstanceID()
+
+func newJobInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	// 容器重启后主机名和进程号可能不变，加上启动时间区分
+	return fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano())
+}
+
+// ErrJobTypeRunning 同类任务已在执行
+var ErrJobTypeRunning = errors.New("a job of this type is already running")
+
+// jobRunner 后台任务的执行函数，ctx 在任务被取消或服务关闭时取消
+type jobRunner func(ctx context.Context, reporter *jobReporter) (interface{}, error)
+
+// runningJob 本进程中正在执行的任务
+type runningJob struct {
+	cancel   context.CancelFunc
+	canceled bool // 由管理员取消，区别于服务关闭
+}
+
+// jobRegistry 本进程中正在执行的任务，用于取消和关闭服务时等待
+var jobRegistry = struct {
+	sync.Mutex
+	running  map[uint]*runningJob
+	wg       sync.WaitGroup
+	stopping bool
+}{running: make(map[uint]*runningJob)}
+
+type JobService struct {
+	db *gorm.DB
+}
+
+func NewJobService() *JobService {
+	return &JobService{
+		db: database.GetDB(),
+	}
+}
+
+// SubmitJob 提交后台任务，任务在后台立即开始执行，返回任务记录
+func (s *JobService) SubmitJob(req *models.SubmitJobRequest, operatorID uint) (*models.JobResponse, error) {
+	var (
+		runner jobRunner
+		params interface{}
+	)
+	switch req.Type {
+	case models.JobTypeEventGeneration:
+		mode := req.Mode
+		if mode == "" {
+			mode = "full"
+		}
+		if mode != "full" && mode != "incremental" {
+			return nil, errors.New("invalid generation mode")
+		}
+		params = map[string]interface{}{"mode": mode, "dry_run": req.DryRun}
+		runner = eventGenerationJob(mode, req.DryRun, operatorID)
+	case models.JobTypeRSSFetchAll:
+		runner = rssFetchAllJob()
+	case models.JobTypeEntityExtraction:
+		runner = entityExtractionJob()
+	case models.JobTypeNewsTagging:
+		runner = newsTaggingJob()
+	case models.JobTypeNewsClassification:
+		runner = newsClassificationJob()
+	case models.JobTypeSearchIndex:
+		runner = searchIndexJob()
+	case models.JobTypeSummaryRegeneration:
+		summaries := req.Summaries
+		if summaries == nil {
+			summaries = &models.RegenerateSummariesRequest{}
+		}
+		if summaries.Target != "" && summaries.Target != models.SummaryTargetNews &&
+			summaries.Target != models.SummaryTargetEvents && summaries.Target != models.SummaryTargetAll {
+			return nil, errors.New("invalid summary target")
+		}
+		params = summaries
+		runner = summaryRegenerationJob(summaries)
+	case models.JobTypeEventRelationLink:
+		runner = eventRelationLinkJob()
+	case models.JobTypeClassifierTraining:
+		runner = classifierTrainingJob(operatorID)
+	default:
+		return nil, errors.New("invalid job type")
+	}
+
+	// 先结束执行实例已退出的任务，避免它们一直占用同类任务的名额
+	if err := s.RecoverInterruptedJobs(); err != nil {
+		log.Printf("[JOB WARNING] failed to recover interrupted jobs: %v", err)
+	}
+
+	now := time.Now()
+	job := models.Job{
+		Type:        req.Type,
+		Status:      models.JobStatusQueued,
+		Params:      snapshotJSON(params),
+		Message:     "queued",
+		Errors:      "[]",
+		Owner:       jobInstanceID,
+		HeartbeatAt: &now,
+		CreatedBy:   operatorID,
+	}
+	// 同类任务同时只执行一个，由 type 上只包含未结束任务的唯一索引保证，并发提交时只有一个能写入
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&job)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrJobTypeRunning
+	}
+
+	s.start(&job, runner)
+
+	response := convertToJobResponse(&job)
+	return &response, nil
+}
+
+// start 在后台执行任务
+func (s *JobService) start(job *models.Job, runner jobRunner) {
+	ctx, cancel := context.WithCancel(context.Background())
+	entry := &runningJob{cancel: cancel}
+
+	jobRegistry.Lock()
+	if jobRegistry.stopping {
+		jobRegistry.Unlock()
+		cancel()
+		s.finishJob(job.ID, models.JobStatusFailed, "interrupted by server shutdown")
+		return
+	}
+	jobRegistry.running[job.ID] = entry
+	jobRegistry.wg.Add(1)
+	jobRegistry.Unlock()
+
+	go s.run(ctx, job.ID, entry, runner)
+}
+
+func (s *JobService) run(ctx context.Context, jobID uint, entry *runningJob, runner jobRunner) {
+	reporter := &jobReporter{db: s.db, jobID: jobID, cancel: func() {
+		jobRegistry.Lock()
+		entry.canceled = true
+		jobRegistry.Unlock()
+		entry.cancel()
+	}}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[JOB ERROR] job %d panicked: %v", jobID, r)
+			reporter.AddError(fmt.Sprint(r))
+			reporter.finish(models.JobStatusFailed, "job panicked", nil)
+		}
+
+		jobRegistry.Lock()
+		delete(jobRegistry.running, jobID)
+		jobRegistry.Unlock()
+		entry.cancel()
+		jobRegistry.wg.Done()
+	}()
+
+	now := time.Now()
+	if err := s.db.Model(&models.Job{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":       models.JobStatusRunning,
+		"message":      "running",
+		"started_at":   now,
+		"heartbeat_at": now,
+	}).Error; err != nil {
+		log.Printf("[JOB ERROR] failed to start job %d: %v", jobID, err)
+	}
+
+	go s.heartbeat(ctx, jobID, reporter)
+	result, err := runner(ctx, reporter)
+
+	switch {
+	case err == nil:
+		reporter.finish(models.JobStatusSucceeded, "completed", result)
+	case errors.Is(err, context.Canceled):
+		jobRegistry.Lock()
+		canceled := entry.canceled
+		jobRegistry.Unlock()
+		if canceled {
+			reporter.finish(models.JobStatusCanceled, "canceled", result)
+		} else {
+			reporter.finish(models.JobStatusFailed, "interrupted by server shutdown", result)
+		}
+	default:
+		log.Printf("[JOB ERROR] job %d failed: %v", jobID, err)
+		reporter.AddError(err.Error())
+		reporter.finish(models.JobStatusFailed, truncateRunes(err.Error(), 255), result)
+	}
+}
+
+// heartbeat 在任务执行期间定期刷新心跳，并检查在其他实例上写入的取消标记，ctx 取消时停止
+func (s *JobService) heartbeat(ctx context.Context, jobID uint, reporter *jobReporter) {
+	ticker := time.NewTicker(jobHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.db.Model(&models.Job{}).
+				Where("id = ? AND status IN ?", jobID, activeJobStatuses).
+				Update("heartbeat_at", time.Now()).Error; err != nil {
+				log.Printf("[JOB ERROR] failed to refresh heartbeat of job %d: %v", jobID, err)
+			}
+			if reporter.cancelRequested() {
+				reporter.cancel()
+			}
+		}
+	}
+}
+
+// jobHeartbeatStale 任务的心跳是否已超时，即执行它的实例已经退出
+func jobHeartbeatStale(job *models.Job, now time.Time) bool {
+	return job.HeartbeatAt == nil || now.Sub(*job.HeartbeatAt) > jobHeartbeatTimeout
+}
+
+// finishJob 直接将没有实例在执行的任务标记为结束
+func (s *JobService) finishJob(id uint, status, message string) error {
+	return s.db.Model(&models.Job{}).
+		Where("id = ? AND status IN ?", id, activeJobStatuses).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"message":     message,
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// GetJob 获取任务的状态、进度、部分结果和错误
+func (s *JobService) GetJob(id uint) (*models.JobResponse, error) {
+	var job models.Job
+	if err := s.db.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("job not found")
+		}
+		return nil, err
+	}
+
+	response := convertToJobResponse(&job)
+	return &response, nil
+}
+
+// GetJobs 分页获取任务列表，不包含结果
+func (s *JobService) GetJobs(query *models.JobQueryRequest) ([]models.JobResponse, int64, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	db := s.db.Model(&models.Job{})
+	if query.Type != "" {
+		db = db.Where("type = ?", query.Type)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []models.Job
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Omit("result").Order("created_at DESC").Offset(offset).Limit(query.Limit).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]models.JobResponse, len(jobs))
+	for i := range jobs {
+		responses[i] = convertToJobResponse(&jobs[i])
+	}
+	return responses, total, nil
+}
+
+// CancelJob 取消任务
+// 正在执行的任务在完成当前步骤后停止，保留已完成部分的结果；在其他实例上执行的任务只写入取消标记，
+// 由该实例在汇报进度或刷新心跳时读到后停止并结束任务；心跳已超时的任务没有实例在执行，直接标记为已取消
+func (s *JobService) CancelJob(id uint) (*models.JobResponse, error) {
+	var job models.Job
+	if err := s.db.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("job not found")
+		}
+		return nil, err
+	}
+	if job.IsFinished() {
+		return nil, errors.New("job already finished")
+	}
+
+	if err := s.db.Model(&job).Update("cancel_requested", true).Error; err != nil {
+		return nil, err
+	}
+
+	jobRegistry.Lock()
+	entry, ok := jobRegistry.running[id]
+	if ok {
+		entry.canceled = true
+		entry.cancel()
+	}
+	jobRegistry.Unlock()
+
+	if !ok && jobHeartbeatStale(&job, time.Now()) {
+		if err := s.finishJob(id, models.JobStatusCanceled, "canceled"); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetJob(id)
+}
+
+// RecoverInterruptedJobs 将心跳超时的未结束任务标记为失败，执行它们的实例已经重启或退出
+// 其他实例上心跳正常的任务不受影响；服务启动、提交任务时和定时任务中调用
+func (s *JobService) RecoverInterruptedJobs() error {
+	result := s.db.Model(&models.Job{}).
+		Where("status IN ?", activeJobStatuses).
+		Where("heartbeat_at IS NULL OR heartbeat_at < ?", time.Now().Add(-jobHeartbeatTimeout)).
+		Updates(map[string]interface{}{
+			"status":      models.JobStatusFailed,
+			"message":     "interrupted by server restart",
+			"finished_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[JOB] Marked %d interrupted jobs as failed", result.RowsAffected)
+	}
+	return nil
+}
+
+// StopJobs 取消本进程中正在执行的任务并等待其保存结果，服务关闭时调用
+func StopJobs(timeout time.Duration) {
+	jobRegistry.Lock()
+	jobRegistry.stopping = true
+	for _, entry := range jobRegistry.running {
+		entry.cancel()
+	}
+	jobRegistry.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		jobRegistry.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("[JOB WARNING] jobs did not stop within %s", timeout)
+	}
+}
+
+func convertToJobResponse(job *models.Job) models.JobResponse {
+	response := models.JobResponse{
+		ID:              job.ID,
+		Type:            job.Type,
+		Status:          job.Status,
+		Processed:       job.Processed,
+		Total:           job.Total,
+		Message:         job.Message,
+		Errors:          jsonToSlice(job.Errors),
+		CancelRequested: job.CancelRequested,
+		Owner:           job.Owner,
+		HeartbeatAt:     job.HeartbeatAt,
+		CreatedBy:       job.CreatedBy,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+		CreatedAt:       job.CreatedAt,
+		UpdatedAt:       job.UpdatedAt,
+	}
+	if response.Errors == nil {
+		response.Errors = []string{}
+	}
+	if job.Params != "" {
+		response.Params = json.RawMessage(job.Params)
+	}
+	if job.Result != "" {
+		response.Result = json.RawMessage(job.Result)
+	}
+
+	if job.Total > 0 {
+		response.Progress = float64(job.Processed) * 100 / float64(job.Total)
+	}
+	if job.Status == models.JobStatusSucceeded {
+		response.Progress = 100
+	}
+	if job.StartedAt != nil {
+		end := time.Now()
+		if job.FinishedAt != nil {
+			end = *job.FinishedAt
+		}
+		response.Duration = end.Sub(*job.StartedAt).String()
+	}
+	return response
+}
+
+// jobReporter 在任务执行过程中写入进度、部分结果和错误
+type jobReporter struct {
+	db        *gorm.DB
+	jobID     uint
+	cancel    func() // 读到数据库中的取消标记时调用
+	mu        sync.Mutex
+	processed int
+	total     int
+	message   string
+	result    interface{}
+	errors    []string
+	lastFlush time.Time
+}
+
+// Progress 更新进度和部分结果，partial 为 nil 时保留之前的结果
+// 为避免频繁写库，距上次写入不足 jobProgressInterval 时只在完成全部步骤时写入；
+// 写入时同时检查取消标记，任务在其他实例上被取消时也能停止
+func (r *jobReporter) Progress(processed, total int, message string, partial interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.processed, r.total, r.message = processed, total, message
+	if partial != nil {
+		r.result = partial
+	}
+	if time.Since(r.lastFlush) >= jobProgressInterval || (total > 0 && processed >= total) {
+		r.flush(nil)
+		if r.cancel != nil && r.cancelRequested() {
+			r.cancel()
+		}
+	}
+}
+
+// cancelRequested 任务是否已被标记为取消
+func (r *jobReporter) cancelRequested() bool {
+	var requested []bool
+	if err := r.db.Model(&models.Job{}).Where("id = ?", r.jobID).Pluck("cancel_requested", &requested).Error; err != nil {
+		log.Printf("[JOB ERROR] failed to check cancellation of job %d: %v", r.jobID, err)
+		return false
+	}
+	return len(requested) > 0 && requested[0]
+}
+
+// AddError 记录一条不中断任务的错误
+func (r *jobReporter) AddError(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, message)
+}
+
+// finish 写入任务的最终状态，result 为 nil 时保留最近一次汇报的部分结果
+func (r *jobReporter) finish(status, message string, result interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if result != nil {
+		r.result = result
+	}
+	if status == models.JobStatusSucceeded && r.total > 0 {
+		r.processed = r.total
+	}
+	r.message = message
+	r.flush(map[string]interface{}{
+		"status":      status,
+		"finished_at": time.Now(),
+	})
+}
+
+func (r *jobReporter) flush(extra map[string]interface{}) {
+	updates := map[string]interface{}{
+		"processed": r.processed,
+		"total":     r.total,
+		"message":   truncateRunes(r.message, 255),
+		"result":    snapshotJSON(r.result),
+		"errors":    sliceToJSON(r.errors),
+		// 写入进度同时刷新心跳
+		"heartbeat_at": time.Now(),
+	}
+	for key, value := range extra {
+		updates[key] = value
+	}
+	if err := r.db.Model(&models.Job{}).Where("id = ?", r.jobID).Updates(updates).Error; err != nil {
+		log.Printf("[JOB ERROR] failed to save progress of job %d: %v", r.jobID, err)
+	}
+	r.lastFlush = time.Now()
+}
+
+// progressJob 把逐步汇报进度的服务方法包装为任务，每次汇报时以 message 写入进度和部分结果
+// 服务返回 nil 结果时任务结果为无类型的 nil，保留最近一次汇报的部分结果
+func progressJob[T any](message string, run func(ctx context.Context, progress func(done, total int, partial *T)) (*T, error)) jobRunner {
+	return func(ctx context.Context, reporter *jobReporter) (interface{}, error) {
+		result, err := run(ctx, func(done, total int, partial *T) {
+			if partial == nil {
+				reporter.Progress(done, total, message, nil)
+				return
+			}
+			reporter.Progress(done, total, message, partial)
+		})
+		if result == nil {
+			return nil, err
+		}
+		return result, err
+	}
+}
+
+// forEachBatch 按ID升序分批读取 db 范围内的记录交给 fn 处理，每批最多 size 条
+// 下一批从上一批最后一条记录的ID之后开始，处理过程中修改或新增记录不会导致重复或遗漏；
+// ctx 取消时在当前批次结束后停止并返回 ctx 的错误。批量任务在取消或出错时返回已处理部分的结果，已处理的记录保留新的结果
+func forEachBatch[T any](ctx context.Context, db *gorm.DB, size int, idOf func(*T) uint, fn func(batch []T) error) error {
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var batch []T
+		if err := db.Session(&gorm.Session{}).Where("id > ?", lastID).Order("id ASC").Limit(size).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		lastID = idOf(&batch[len(batch)-1])
+	}
+}
+
+// forEachNewsBatch 分批处理 db 范围内的新闻，见 forEachBatch
+func forEachNewsBatch(ctx context.Context, db *gorm.DB, size int, fn func(batch []models.News) error) error {
+	return forEachBatch(ctx, db.Model(&models.News{}), size, func(news *models.News) uint { return news.ID }, fn)
+}
+
+// forEachIDBatch 分批处理 db 范围内记录的ID，db 需要指定 Model，见 forEachBatch
+func forEachIDBatch(ctx context.Context, db *gorm.DB, size int, fn func(ids []uint) error) error {
+	return forEachBatch(ctx, db.Select("id"), size, func(id *uint) uint { return *id }, fn)
+}
+
+// eventGenerationJob 事件生成任务，每新建或更新一个事件汇报一次进度
+func eventGenerationJob(mode string, dryRun bool, operatorID uint) jobRunner {
+	save := progressJob("saving events", func(ctx context.Context, progress func(done, total int, partial *EventGenerationResult)) (*EventGenerationResult, error) {
+		return NewEventService().GenerateEvents(ctx, mode, progress)
+	})
+	return func(ctx context.Context, reporter *jobReporter) (interface{}, error) {
+		if dryRun {
+			reporter.Progress(0, 1, "clustering news", nil)
+			preview, err := NewEventService().PreviewEventGeneration(mode, operatorID)
+			if err != nil {
+				return nil, err
+			}
+			reporter.Progress(1, 1, "preview ready", preview)
+			return preview, nil
+		}
+
+		reporter.Progress(0, 0, "clustering news", nil)
+		return save(ctx, reporter)
+	}
+}
+
+// rssFetchAllJob 抓取所有活跃RSS源的任务，每抓取完一个源汇报一次进度，抓取失败的源记为错误
+func rssFetchAllJob() jobRunner {
+	return func(ctx context.Context, reporter *jobReporter) (interface{}, error) {
+		reported := 0
+		return progressJob("fetching RSS sources", func(ctx context.Context, progress func(done, total int, partial *models.RSSFetchResult)) (*models.RSSFetchResult, error) {
+			return NewRSSService().FetchAllRSSFeedsWithProgress(ctx, func(done, total int, partial *models.RSSFetchResult) {
+				for _, stats := range partial.Stats[reported:] {
+					if stats.Error != "" {
+						reporter.AddError(fmt.Sprintf("%s: %s", stats.SourceName, stats.Error))
+					}
+				}
+				reported = len(partial.Stats)
+				progress(done, total, partial)
+			})
+		})(ctx, reporter)
+	}
+}
+
+// entityExtractionJob 重新识别全部新闻实体的任务，每处理完一批新闻汇报一次进度
+func entityExtractionJob() jobRunner {
+	return progressJob("extracting entities", NewEntityService().ExtractAllNewsEntities)
+}
+
+// newsTaggingJob 重新提取全部新闻关键词标签的任务，每处理完一批新闻汇报一次进度
+func newsTaggingJob() jobRunner {
+	return progressJob("tagging news", NewNewsTaggingService().RetagAllNews)
+}
+
+// newsClassificationJob 用最新的分类模型重新预测全部新闻分类的任务，每处理完一批新闻汇报一次进度
+func newsClassificationJob() jobRunner {
+	return progressJob("classifying news", NewCategoryClassifierService().ReclassifyAllNews)
+}
+
+// searchIndexJob 重建全部新闻和事件搜索索引的任务，每处理完一批汇报一次进度
+func searchIndexJob() jobRunner {
+	return progressJob("indexing news and events", NewSearchService().RebuildSearchIndex)
+}
+
+// summaryRegenerationJob 重新生成新闻摘要和事件描述的任务，每处理完一条新闻或一个事件汇报一次进度
+func summaryRegenerationJob(req *models.RegenerateSummariesRequest) jobRunner {
+	return progressJob("regenerating summaries", func(ctx context.Context, progress func(done, total int, partial *models.RegenerateSummariesResult)) (*models.RegenerateSummariesResult, error) {
+		return NewSummaryService().RegenerateSummaries(ctx, req, progress)
+	})
+}
+
+// eventRelationLinkJob 为最近开始的事件自动建立关系的任务，每处理完一个事件汇报一次进度
+func eventRelationLinkJob() jobRunner {
+	return progressJob("linking events", func(ctx context.Context, progress func(done, total int, partial *models.AutoLinkEventsResult)) (*models.AutoLinkEventsResult, error) {
+		return NewEventService().AutoLinkEvents(ctx, nil, progress)
+	})
+}
+
+// classifierTrainingJob 重新训练分类模型的任务，分词时每处理一批新闻汇报一次进度，结果为新模型的评估结果
+func classifierTrainingJob(operatorID uint) jobRunner {
+	return progressJob("training classifier", func(ctx context.Context, progress func(done, total int, partial *models.CategoryModelResponse)) (*models.CategoryModelResponse, error) {
+		return NewCategoryClassifierService().Train(ctx, operatorID, progress)
+	})
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+)
+
+func TestJobHeartbeatStale(t *testing.T) {
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) *time.Time {
+		heartbeat := now.Add(-ago)
+		return &heartbeat
+	}
+
+	tests := []struct {
+		name      string
+		heartbeat *time.Time
+		want      bool
+	}{
+		{name: "no heartbeat recorded", heartbeat: nil, want: true},
+		{name: "recent heartbeat", heartbeat: at(jobHeartbeatInterval), want: false},
+		{name: "at the timeout", heartbeat: at(jobHeartbeatTimeout), want: false},
+		{name: "past the timeout", heartbeat: at(jobHeartbeatTimeout + time.Second), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &models.Job{Status: models.JobStatusRunning, HeartbeatAt: tt.heartbeat}
+			if got := jobHeartbeatStale(job, now); got != tt.want {
+				t.Errorf("jobHeartbeatStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,481 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+)
+
+// ErrContentBlocked 内容命中严重敏感词被拦截
+var ErrContentBlocked = errors.New("content contains prohibited words")
+
+// sensitiveFilter 进程内缓存的敏感词自动机，词库变更后重建
+type sensitiveFilter struct {
+	mu         sync.RWMutex
+	loaded     bool
+	generation uint64 // 每次失效时加一，用于发现加载期间的词库变更
+	matcher    *nlp.Matcher
+	patterns   []sensitivePattern // 模式编号 -> 敏感词
+}
+
+// sensitivePattern 自动机中的一个模式
+type sensitivePattern struct {
+	word      models.SensitiveWord
+	wholeWord bool // 英文或拼音模式只在词边界处命中，避免去掉空白后跨词误中
+}
+
+var filterCache = &sensitiveFilter{}
+
+type ModerationService struct {
+	db *gorm.DB
+}
+
+func NewModerationService() *ModerationService {
+	return &ModerationService{
+		db: database.GetDB(),
+	}
+}
+
+// CheckText 检测文本并按命中的最高严重程度给出处理动作
+func (s *ModerationService) CheckText(text string) (*models.ModerationResult, error) {
+	if err := s.ensureFilter(); err != nil {
+		return nil, err
+	}
+
+	filterCache.mu.RLock()
+	matcher := filterCache.matcher
+	patterns := filterCache.patterns
+	filterCache.mu.RUnlock()
+
+	result := &models.ModerationResult{
+		Action:       models.ModerationActionAllow,
+		MatchedWords: []string{},
+	}
+
+	for _, word := range findSensitiveWords(matcher, patterns, text) {
+		result.MatchedWords = append(result.MatchedWords, word.Word)
+		if severityRank(word.Severity) > severityRank(result.Severity) {
+			result.Severity = word.Severity
+		}
+	}
+	sort.Strings(result.MatchedWords)
+
+	switch result.Severity {
+	case models.SeverityHigh:
+		result.Action = models.ModerationActionBlock
+	case models.SeverityMedium:
+		result.Action = models.ModerationActionReview
+	}
+
+	return result, nil
+}
+
+// Submit 审核用户提交的内容，需人工审核时写入审核队列
+// 返回的动作为 block 时调用方应拒绝保存，review 时应将内容置为待审核
+// 同一内容之前待审核的记录标记为已被代替，避免审核旧的快照时发布了未经审核的新内容
+func (s *ModerationService) Submit(tx *gorm.DB, targetType string, targetID uint, authorID uint, content string, result *models.ModerationResult) error {
+	if result.Action != models.ModerationActionReview {
+		return nil
+	}
+
+	if err := tx.Model(&models.ModerationRecord{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.ModerationStatusPending).
+		Updates(map[string]interface{}{
+			"status": models.ModerationStatusSuperseded,
+			"reason": "content changed and resubmitted for review",
+		}).Error; err != nil {
+		return err
+	}
+
+	record := models.ModerationRecord{
+		TargetType:   targetType,
+		TargetID:     targetID,
+		AuthorID:     authorID,
+		Content:      content,
+		MatchedWords: sliceToJSON(result.MatchedWords),
+		Severity:     result.Severity,
+		Action:       result.Action,
+		Status:       models.ModerationStatusPending,
+	}
+	return tx.Create(&record).Error
+}
+
+// GetQueue 获取审核队列
+func (s *ModerationService) GetQueue(query *models.ModerationQueueQueryRequest) ([]models.ModerationRecord, int64, error) {
+	if s.db == nil {
+		return nil, 0, errors.New("database connection not initialized")
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	db := s.db.Model(&models.ModerationRecord{})
+	status := query.Status
+	if status == "" {
+		status = models.ModerationStatusPending
+	}
+	if status != "all" {
+		db = db.Where("status = ?", status)
+	}
+	if query.TargetType != "" {
+		db = db.Where("target_type = ?", query.TargetType)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count moderation records: %w", err)
+	}
+
+	var records []models.ModerationRecord
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Order("created_at ASC").Offset(offset).Limit(query.Limit).Find(&records).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+
+	return records, total, nil
+}
+
+// Approve 审核通过，内容恢复为公开状态
+func (s *ModerationService) Approve(recordID uint, reviewerID uint, reason string) (*models.ModerationRecord, error) {
+	return s.review(recordID, reviewerID, reason, true)
+}
+
+// Reject 审核拒绝，必须给出理由
+func (s *ModerationService) Reject(recordID uint, reviewerID uint, reason string) (*models.ModerationRecord, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.New("reject reason is required")
+	}
+	return s.review(recordID, reviewerID, reason, false)
+}
+
+func (s *ModerationService) review(recordID uint, reviewerID uint, reason string, approved bool) (*models.ModerationRecord, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	var record models.ModerationRecord
+	if err := s.db.First(&record, recordID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("moderation record not found")
+		}
+		return nil, err
+	}
+
+	if record.Status != models.ModerationStatusPending {
+		return nil, errors.New("moderation record already reviewed")
+	}
+
+	now := time.Now()
+	record.Status = models.ModerationStatusRejected
+	if approved {
+		record.Status = models.ModerationStatusApproved
+	}
+	record.Reason = strings.TrimSpace(reason)
+	record.ReviewedBy = &reviewerID
+	record.ReviewedAt = &now
+
+	var commentTarget *models.Comment
+	errAlreadyReviewed := errors.New("moderation record already reviewed")
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 只更新仍待审核的记录，读取之后被审核或被新的提交代替时放弃
+		result := tx.Model(&models.ModerationRecord{}).
+			Where("id = ? AND status = ?", record.ID, models.ModerationStatusPending).
+			Updates(map[string]interface{}{
+				"status":      record.Status,
+				"reason":      record.Reason,
+				"reviewed_by": record.ReviewedBy,
+				"reviewed_at": record.ReviewedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyReviewed
+		}
+
+		switch record.TargetType {
+		case models.ModerationTargetComment:
+			status := models.CommentStatusRejected
+			if approved {
+				status = models.CommentStatusPublished
+			}
+			var comment models.Comment
+			if err := tx.First(&comment, record.TargetID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil // 评论已被删除，只更新审核记录
+				}
+				return err
+			}
+			if err := tx.Model(&comment).Update("status", status).Error; err != nil {
+				return err
+			}
+			if comment.ParentID != nil {
+				if err := syncReplyCount(tx, *comment.ParentID); err != nil {
+					return err
+				}
+			}
+			commentTarget = &comment
+		case models.ModerationTargetNews:
+			updates := map[string]interface{}{"status": "rejected", "is_active": false}
+			if approved {
+				updates = map[string]interface{}{"status": "published", "is_active": true}
+			}
+			if err := tx.Model(&models.News{}).Where("id = ?", record.TargetID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errAlreadyReviewed) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to review content: %w", err)
+	}
+
+	if commentTarget != nil {
+		if err := NewCommentService().SyncCommentCount(commentTarget.TargetType, commentTarget.TargetID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &record, nil
+}
+
+// GetWords 获取敏感词列表
+func (s *ModerationService) GetWords(query *models.SensitiveWordQueryRequest) ([]models.SensitiveWord, int64, error) {
+	if s.db == nil {
+		return nil, 0, errors.New("database connection not initialized")
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	db := s.db.Model(&models.SensitiveWord{})
+	if query.Category != "" {
+		db = db.Where("category = ?", query.Category)
+	}
+	if query.Severity != "" {
+		db = db.Where("severity = ?", query.Severity)
+	}
+	if query.Search != "" {
+		db = db.Where("word ILIKE ? OR pinyin ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%")
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var words []models.SensitiveWord
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Order("created_at DESC").Offset(offset).Limit(query.Limit).Find(&words).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return words, total, nil
+}
+
+// CreateWord 添加敏感词
+func (s *ModerationService) CreateWord(req *models.CreateSensitiveWordRequest, createdBy uint) (*models.SensitiveWord, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	word := strings.TrimSpace(req.Word)
+	if nlp.NormalizeForMatch(word) == "" {
+		return nil, errors.New("sensitive word cannot be empty")
+	}
+
+	var count int64
+	s.db.Model(&models.SensitiveWord{}).Where("word = ?", word).Count(&count)
+	if count > 0 {
+		return nil, errors.New("sensitive word already exists")
+	}
+
+	sensitiveWord := models.SensitiveWord{
+		Word:      word,
+		Pinyin:    strings.TrimSpace(req.Pinyin),
+		Category:  req.Category,
+		Severity:  req.Severity,
+		IsActive:  true,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.Create(&sensitiveWord).Error; err != nil {
+		return nil, err
+	}
+
+	invalidateFilter()
+	return &sensitiveWord, nil
+}
+
+// UpdateWord 更新敏感词
+func (s *ModerationService) UpdateWord(id uint, req *models.UpdateSensitiveWordRequest) (*models.SensitiveWord, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	var word models.SensitiveWord
+	if err := s.db.First(&word, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sensitive word not found")
+		}
+		return nil, err
+	}
+
+	if req.Pinyin != nil {
+		word.Pinyin = strings.TrimSpace(*req.Pinyin)
+	}
+	if req.Category != "" {
+		word.Category = req.Category
+	}
+	if req.Severity != "" {
+		word.Severity = req.Severity
+	}
+	if req.IsActive != nil {
+		word.IsActive = *req.IsActive
+	}
+
+	if err := s.db.Save(&word).Error; err != nil {
+		return nil, err
+	}
+
+	invalidateFilter()
+	return &word, nil
+}
+
+// DeleteWord 删除敏感词
+func (s *ModerationService) DeleteWord(id uint) error {
+	if s.db == nil {
+		return errors.New("database connection not initialized")
+	}
+
+	result := s.db.Delete(&models.SensitiveWord{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("sensitive word not found")
+	}
+
+	invalidateFilter()
+	return nil
+}
+
+// findSensitiveWords 返回文本命中的敏感词，同一个词只返回一次
+func findSensitiveWords(matcher *nlp.Matcher, patterns []sensitivePattern, text string) []models.SensitiveWord {
+	normalized, boundaries := nlp.NormalizeForMatchWithBoundaries(text)
+	words := make([]models.SensitiveWord, 0)
+	seen := make(map[string]bool)
+	for _, match := range matcher.FindAll(normalized) {
+		pattern := patterns[match.Pattern]
+		if pattern.wholeWord && !(boundaries[match.Start] && boundaries[match.End]) {
+			continue
+		}
+		if seen[pattern.word.Word] {
+			continue
+		}
+		seen[pattern.word.Word] = true
+		words = append(words, pattern.word)
+	}
+	return words
+}
+
+// ensureFilter 首次使用或词库变更后从数据库加载敏感词并构建自动机
+// 加载期间词库又发生变更时丢弃本次结果重新加载，避免用旧词库覆盖失效标记
+func (s *ModerationService) ensureFilter() error {
+	for {
+		filterCache.mu.RLock()
+		loaded := filterCache.loaded
+		generation := filterCache.generation
+		filterCache.mu.RUnlock()
+		if loaded {
+			return nil
+		}
+
+		matcher, patterns, err := s.loadFilter()
+		if err != nil {
+			return err
+		}
+
+		filterCache.mu.Lock()
+		if filterCache.generation == generation {
+			filterCache.matcher = matcher
+			filterCache.patterns = patterns
+			filterCache.loaded = true
+			filterCache.mu.Unlock()
+			return nil
+		}
+		filterCache.mu.Unlock()
+	}
+}
+
+// loadFilter 从数据库加载启用的敏感词并构建自动机
+func (s *ModerationService) loadFilter() (*nlp.Matcher, []sensitivePattern, error) {
+	if s.db == nil {
+		return nil, nil, errors.New("database connection not initialized")
+	}
+
+	var words []models.SensitiveWord
+	if err := s.db.Where("is_active = ?", true).Find(&words).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load sensitive words: %w", err)
+	}
+
+	matcher, patterns := buildSensitiveMatcher(words)
+	return matcher, patterns, nil
+}
+
+// buildSensitiveMatcher 用敏感词的原词和拼音写法构建自动机
+func buildSensitiveMatcher(words []models.SensitiveWord) (*nlp.Matcher, []sensitivePattern) {
+	builder := nlp.NewMatcherBuilder()
+	patterns := make([]sensitivePattern, 0, len(words)*2)
+	add := func(word models.SensitiveWord, pattern string) {
+		// 编号与 patterns 下标一一对应
+		if builder.AddPattern(pattern) >= 0 {
+			patterns = append(patterns, sensitivePattern{word: word, wholeWord: nlp.IsASCIIWord(pattern)})
+		}
+	}
+	for _, word := range words {
+		add(word, nlp.NormalizeForMatch(word.Word))
+		if word.Pinyin != "" {
+			add(word, nlp.NormalizePinyin(word.Pinyin))
+		}
+	}
+	return builder.Build(), patterns
+}
+
+// invalidateFilter 使缓存的自动机失效，下次检测时重新加载
+func invalidateFilter() {
+	filterCache.mu.Lock()
+	filterCache.loaded = false
+	filterCache.generation++
+	filterCache.mu.Unlock()
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case models.SeverityHigh:
+		return 3
+	case models.SeverityMedium:
+		return 2
+	case models.SeverityLow:
+		return 1
+	}
+	return 0
+}
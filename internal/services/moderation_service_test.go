@@ -0,0 +1,43 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+)
+
+func TestFindSensitiveWords(t *testing.T) {
+	matcher, patterns := buildSensitiveMatcher([]models.SensitiveWord{
+		{Word: "赌博", Pinyin: "dǔ bó"},
+		{Word: "SB"},
+		{Word: "绿色通道", Pinyin: "lǜ sè tōng dào"},
+	})
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "chinese across separators", text: "网上赌*博", want: []string{"赌博"}},
+		{name: "chinese inside a sentence", text: "严禁参与赌博活动", want: []string{"赌博"}},
+		{name: "pinyin as a word", text: "来 du bo 吧", want: []string{"赌博"}},
+		{name: "pinyin next to chinese", text: "一起dubo", want: []string{"赌博"}},
+		{name: "pinyin inside a longer word", text: "redubox", want: []string{}},
+		{name: "latin across word boundary", text: "this is best", want: []string{}},
+		{name: "latin as a word", text: "you are S.B!", want: []string{"SB"}},
+		{name: "u with diaeresis", text: "开通 lüsètōngdào", want: []string{"绿色通道"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, word := range findSensitiveWords(matcher, patterns, tt.text) {
+				got = append(got, word.Word)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findSensitiveWords(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt" // 导入 fmt 包用于错误信息拼接
+	"log"
 	"time"
 
 	"github.com/EasyPeek/EasyPeek-backend/internal/database" // 假设你的数据库连接在此处提供
@@ -12,13 +13,23 @@ import (
 
 // NewsService 结构体，用于封装与新闻相关的数据库操作和业务逻辑
 type NewsService struct {
-	db *gorm.DB
+	db                *gorm.DB
+	moderationService *ModerationService         // 用户提交内容的敏感词审核
+	entityService     *EntityService             // 识别新闻中的人物、机构和地点
+	tagger            *NewsTaggingService        // 提取关键词标签
+	classifier        *CategoryClassifierService // 预测新闻分类
+	searchService     *SearchService             // 维护全文搜索索引并执行搜索
 }
 
 // NewNewsService 创建并返回一个新的 NewsService 实例
 func NewNewsService() *NewsService {
 	return &NewsService{
-		db: database.GetDB(), // 从 internal/database 包获取 GORM 数据库实例
+		db:                database.GetDB(), // 从 internal/database 包获取 GORM 数据库实例
+		moderationService: NewModerationService(),
+		entityService:     NewEntityService(),
+		tagger:            NewNewsTaggingService(),
+		classifier:        NewCategoryClassifierService(),
+		searchService:     NewSearchService(),
 	}
 }
 
@@ -51,11 +62,49 @@ func (s *NewsService) CreateNews(req *models.NewsCreateRequest, createdByUserID
 		news.IsActive = *req.IsActive
 	}
 
+	// 手动创建的新闻没有分类标签，标签全部来自关键词提取
+	s.tagger.TagNews(news)
+	// 没有填写分类时使用置信度足够高的预测分类
+	news.SourceCategory = req.Category
+	s.classifier.ClassifyNews(news, req.Category == "")
+
+	// 敏感词审核：严重违规直接拒绝，中等风险转入人工审核并暂不展示
+	moderation, err := s.moderationService.CheckText(moderationText(news))
+	if err != nil {
+		return nil, err
+	}
+	if moderation.Action == models.ModerationActionBlock {
+		return nil, ErrContentBlocked
+	}
+	if moderation.Action == models.ModerationActionReview {
+		news.Status = "pending"
+		news.IsActive = false
+	}
+
 	// 将新闻保存到数据库
-	if err := s.db.Create(news).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(news).Error; err != nil {
+			return err
+		}
+		return s.moderationService.Submit(tx, models.ModerationTargetNews, news.ID, createdByUserID, moderationText(news), moderation)
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to create news: %w", err)
 	}
 
+	if _, err := s.entityService.ExtractNewsEntities(news); err != nil {
+		log.Printf("[NEWS WARNING] failed to extract entities for news %d: %v", news.ID, err)
+	}
+	if err := syncNewsCategories(s.db, news); err != nil {
+		log.Printf("[NEWS WARNING] failed to link categories for news %d: %v", news.ID, err)
+	}
+	if err := linkNewsTags(s.db, news); err != nil {
+		log.Printf("[NEWS WARNING] failed to link tags for news %d: %v", news.ID, err)
+	}
+	if err := indexNews(s.db, news); err != nil {
+		log.Printf("[NEWS WARNING] failed to index news %d for search: %v", news.ID, err)
+	}
+
 	return news, nil
 }
 
@@ -77,6 +126,31 @@ func (s *NewsService) GetNewsByID(id uint) (*models.News, error) {
 	return &news, nil
 }
 
+// GetVisibleNewsByID 获取对访问者可见的单条新闻，审核中或被驳回的新闻只有发布者和管理员可以查看
+func (s *NewsService) GetVisibleNewsByID(id uint, viewerID uint, isAdmin bool) (*models.News, error) {
+	news, err := s.GetNewsByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !newsVisibleTo(news, viewerID, isAdmin) {
+		return nil, errors.New("news not found")
+	}
+	return news, nil
+}
+
+// newsVisibleTo 判断新闻是否对访问者可见
+func newsVisibleTo(news *models.News, viewerID uint, isAdmin bool) bool {
+	if news.IsActive && news.Status == "published" {
+		return true
+	}
+	return isAdmin || (viewerID != 0 && news.CreatedBy != nil && *news.CreatedBy == viewerID)
+}
+
+// publishedNews 只保留已发布且正在展示的新闻，用于对外公开的查询
+func publishedNews(db *gorm.DB) *gorm.DB {
+	return db.Where("is_active = ? AND status = ?", true, "published")
+}
+
 // GetNewsByTitle 根据标题获取新闻列表（标题可能不唯一，所以返回切片）
 func (s *NewsService) GetNewsByTitle(title string) ([]models.News, error) {
 	// 检查数据库连接是否已初始化
@@ -86,7 +160,7 @@ func (s *NewsService) GetNewsByTitle(title string) ([]models.News, error) {
 
 	var newsList []models.News
 	// 使用 Where 方法根据标题查找新闻
-	if err := s.db.Where("title = ?", title).Find(&newsList).Error; err != nil {
+	if err := s.db.Scopes(publishedNews).Where("title = ?", title).Find(&newsList).Error; err != nil {
 		return nil, fmt.Errorf("failed to get news by title: %w", err)
 	}
 	return newsList, nil
@@ -114,6 +188,7 @@ func (s *NewsService) UpdateNews(news *models.News, req *models.NewsUpdateReques
 	}
 	if req.Category != "" {
 		news.Category = req.Category
+		news.SourceCategory = req.Category
 	}
 	if req.PublishedAt != nil {
 		news.PublishedAt = *req.PublishedAt
@@ -122,13 +197,63 @@ func (s *NewsService) UpdateNews(news *models.News, req *models.NewsUpdateReques
 		news.IsActive = *req.IsActive
 	}
 
+	// 手动新闻修改后按新内容重新提取标签并重新审核，RSS抓取的新闻不经过用户审核
+	var moderation *models.ModerationResult
+	if news.SourceType == models.NewsTypeManual {
+		if s.tagger.Enabled() {
+			news.Tags = sliceToJSON(s.tagger.ExtractKeywords(news))
+		}
+		backfillSourceCategory(news)
+		s.classifier.ClassifyNews(news, isFallbackCategory(news, nil))
+
+		var err error
+		moderation, err = s.moderationService.CheckText(moderationText(news))
+		if err != nil {
+			return err
+		}
+		if moderation.Action == models.ModerationActionBlock {
+			return ErrContentBlocked
+		}
+		if moderation.Action == models.ModerationActionReview {
+			news.Status = "pending"
+			news.IsActive = false
+		}
+	}
+
 	// 使用 Save 方法保存更新，GORM 会根据主键自动判断是插入还是更新
-	if err := s.db.Save(news).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(news).Error; err != nil {
+			return err
+		}
+		if moderation == nil || news.CreatedBy == nil {
+			return nil
+		}
+		return s.moderationService.Submit(tx, models.ModerationTargetNews, news.ID, *news.CreatedBy, moderationText(news), moderation)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update news: %w", err)
 	}
+
+	if _, err := s.entityService.ExtractNewsEntities(news); err != nil {
+		log.Printf("[NEWS WARNING] failed to extract entities for news %d: %v", news.ID, err)
+	}
+	if err := syncNewsCategories(s.db, news); err != nil {
+		log.Printf("[NEWS WARNING] failed to link categories for news %d: %v", news.ID, err)
+	}
+	if err := linkNewsTags(s.db, news); err != nil {
+		log.Printf("[NEWS WARNING] failed to link tags for news %d: %v", news.ID, err)
+	}
+	if err := indexNews(s.db, news); err != nil {
+		log.Printf("[NEWS WARNING] failed to index news %d for search: %v", news.ID, err)
+	}
 	return nil
 }
 
+// moderationText 拼接新闻中需要审核的文本
+func moderationText(news *models.News) string {
+	return news.Title + "\n" + news.Summary + "\n" + news.Content
+}
+
 // DeleteNews 根据ID软删除新闻
 func (s *NewsService) DeleteNews(id uint) error {
 	// 检查数据库连接是否已初始化
@@ -136,15 +261,21 @@ func (s *NewsService) DeleteNews(id uint) error {
 		return errors.New("database connection not initialized")
 	}
 
-	// 使用 GORM 的 Delete 方法进行软删除
-	result := s.db.Delete(&models.News{}, id)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete news: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return errors.New("news not found or already deleted") // 如果没有行受影响，说明新闻不存在或已被软删除
-	}
-	return nil
+	// 软删除和去掉标签关联在同一事务中完成，标签计数只统计未删除的新闻
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// 使用 GORM 的 Delete 方法进行软删除
+		result := tx.Delete(&models.News{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete news: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("news not found or already deleted") // 如果没有行受影响，说明新闻不存在或已被软删除
+		}
+		if err := syncNewsTags(tx, id); err != nil {
+			return fmt.Errorf("failed to unlink tags: %w", err)
+		}
+		return nil
+	})
 }
 
 // GetAllNews 获取所有新闻，支持分页
@@ -158,7 +289,7 @@ func (s *NewsService) GetAllNews(page, pageSize int) ([]models.News, int64, erro
 	var total int64
 
 	// 计算总记录数
-	if err := s.db.Model(&models.News{}).Count(&total).Error; err != nil {
+	if err := s.db.Model(&models.News{}).Scopes(publishedNews).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count total news: %w", err)
 	}
 
@@ -172,49 +303,32 @@ func (s *NewsService) GetAllNews(page, pageSize int) ([]models.News, int64, erro
 	}
 
 	// 查询带分页的新闻数据
-	if err := s.db.Offset(offset).Limit(pageSize).Find(&newsList).Error; err != nil {
+	if err := s.db.Scopes(publishedNews).Offset(offset).Limit(pageSize).Find(&newsList).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to get all news with pagination: %w", err)
 	}
 
 	return newsList, total, nil
 }
 
-// SearchNews 根据查询字符串在标题或内容中搜索新闻，支持分页
-func (s *NewsService) SearchNews(query string, page, pageSize int) ([]models.News, int64, error) {
+// SearchNews 全文搜索新闻标题、摘要和正文，按相关度排序并返回高亮片段，支持分页
+func (s *NewsService) SearchNews(query string, page, pageSize int) ([]models.NewsSearchResult, int64, error) {
 	// 检查数据库连接是否已初始化
 	if s.db == nil {
 		return nil, 0, errors.New("database connection not initialized")
 	}
 
-	var newsList []models.News
-	var total int64
-
-	// 构建搜索查询
-	// % 是 SQL 中的通配符，用于模糊匹配
-	searchQuery := "%" + query + "%"
-	dbQuery := s.db.Model(&models.News{}).
-		Where("title ILIKE ? OR content ILIKE ? OR summary ILIKE ?", searchQuery, searchQuery, searchQuery) // ILIKE 用于不区分大小写的模糊匹配，如果是 MySQL 请用 LIKE
-
-	// 计算符合条件的记录总数
-	if err := dbQuery.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
-	}
-
-	// 计算分页偏移量
-	offset := (page - 1) * pageSize
-	if offset < 0 {
-		offset = 0
+	if page < 1 {
+		page = 1
 	}
 	if pageSize <= 0 {
 		pageSize = 10
 	}
 
-	// 执行带分页的搜索查询
-	if err := dbQuery.Offset(offset).Limit(pageSize).Find(&newsList).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to search news with pagination: %w", err)
+	results, total, err := s.searchService.SearchNews(query, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search news: %w", err)
 	}
-
-	return newsList, total, nil
+	return results, total, nil
 }
 
 // UpdateNewsEventAssociation 批量更新新闻的关联事件ID
@@ -252,7 +366,7 @@ func (s *NewsService) GetNewsByEventID(eventID uint) ([]models.News, error) {
 	}
 
 	var newsList []models.News
-	if err := s.db.Where("belonged_event_id = ?", eventID).Find(&newsList).Error; err != nil {
+	if err := s.db.Scopes(publishedNews).Where("belonged_event_id = ?", eventID).Find(&newsList).Error; err != nil {
 		return nil, fmt.Errorf("获取事件关联新闻失败: %w", err)
 	}
 
@@ -297,7 +411,7 @@ func (s *NewsService) GetUnlinkedNews(page, pageSize int) ([]models.News, int64,
 	var total int64
 
 	// 计算未关联事件的新闻总数
-	if err := s.db.Model(&models.News{}).Where("belonged_event_id IS NULL").Count(&total).Error; err != nil {
+	if err := s.db.Model(&models.News{}).Scopes(publishedNews).Where("belonged_event_id IS NULL").Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count unlinked news: %w", err)
 	}
 
@@ -311,7 +425,7 @@ func (s *NewsService) GetUnlinkedNews(page, pageSize int) ([]models.News, int64,
 	}
 
 	// 查询未关联事件的新闻
-	if err := s.db.Where("belonged_event_id IS NULL").
+	if err := s.db.Scopes(publishedNews).Where("belonged_event_id IS NULL").
 		Order("created_at desc").
 		Offset(offset).Limit(pageSize).
 		Find(&newsList).Error; err != nil {
@@ -331,8 +445,8 @@ func (s *NewsService) GetNewsByCategory(category string, page, pageSize int) ([]
 	var newsList []models.News
 	var total int64
 
-	// 构建分类查询
-	dbQuery := s.db.Model(&models.News{}).Where("category = ?", category)
+	// 构建分类查询，包含子分类
+	dbQuery := categoryFilter(s.db, s.db.Model(&models.News{}).Scopes(publishedNews), "news_categories", "news_id", "id", "category", category)
 
 	// 计算符合条件的记录总数
 	if err := dbQuery.Count(&total).Error; err != nil {
@@ -358,6 +472,58 @@ func (s *NewsService) GetNewsByCategory(category string, page, pageSize int) ([]
 	return newsList, total, nil
 }
 
+// GetPopularTags 统计新闻中使用最多的标签，只统计展示中的新闻
+func (s *NewsService) GetPopularTags(query *models.NewsTagQueryRequest) ([]models.TagResponse, error) {
+	// 检查数据库连接是否已初始化
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	if query.Limit <= 0 || query.Limit > 200 {
+		query.Limit = 50
+	}
+	if query.MinCount <= 0 {
+		query.MinCount = 1
+	}
+
+	// 按标签关联统计，同义词计入同一个标签
+	db := s.db.Table("news_tags").
+		Select("tags.name AS tag, tags.slug, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = news_tags.tag_id").
+		Joins("JOIN news ON news.id = news_tags.news_id").
+		Where("news.deleted_at IS NULL AND news.is_active = ?", true)
+	if query.Days > 0 {
+		db = db.Where("news.published_at >= ?", time.Now().AddDate(0, 0, -query.Days))
+	}
+	if query.Category != "" {
+		db = categoryFilter(s.db, db, "news_categories", "news_id", "news.id", "news.category", query.Category)
+	}
+
+	var results []struct {
+		Tag   string
+		Slug  string
+		Count int
+	}
+	if err := db.Group("tags.id, tags.name, tags.slug").
+		Having("COUNT(*) >= ?", query.MinCount).
+		Order("count DESC, tag").
+		Limit(query.Limit).
+		Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to get popular news tags: %w", err)
+	}
+
+	tags := make([]models.TagResponse, 0, len(results))
+	for _, result := range results {
+		tags = append(tags, models.TagResponse{
+			Tag:      result.Tag,
+			Slug:     result.Slug,
+			Count:    result.Count,
+			Category: query.Category,
+		})
+	}
+	return tags, nil
+}
+
 // GetHotNews 获取热门新闻，按热度分数排序
 func (s *NewsService) GetHotNews(limit int) ([]models.News, error) {
 	// 检查数据库连接是否已初始化
@@ -373,7 +539,7 @@ func (s *NewsService) GetHotNews(limit int) ([]models.News, error) {
 	var newsList []models.News
 
 	// 按热度分数降序排列获取热门新闻
-	if err := s.db.Where("is_active = ?", true).
+	if err := s.db.Scopes(publishedNews).
 		Order("hotness_score desc, view_count desc, like_count desc, created_at desc").
 		Limit(limit).
 		Find(&newsList).Error; err != nil {
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+)
+
+func TestNewsVisibleTo(t *testing.T) {
+	ownerID := uint(3)
+	published := models.News{Status: "published", IsActive: true, CreatedBy: &ownerID}
+	pending := models.News{Status: "pending", IsActive: false, CreatedBy: &ownerID}
+	hidden := models.News{Status: "published", IsActive: false}
+
+	tests := []struct {
+		name     string
+		news     models.News
+		viewerID uint
+		isAdmin  bool
+		want     bool
+	}{
+		{name: "published for anonymous", news: published, want: true},
+		{name: "pending for anonymous", news: pending, want: false},
+		{name: "pending for other user", news: pending, viewerID: 4, want: false},
+		{name: "pending for owner", news: pending, viewerID: ownerID, want: true},
+		{name: "pending for admin", news: pending, isAdmin: true, want: true},
+		{name: "inactive rss news without owner", news: hidden, viewerID: 4, want: false},
+		{name: "inactive rss news for admin", news: hidden, isAdmin: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newsVisibleTo(&tt.news, tt.viewerID, tt.isAdmin); got != tt.want {
+				t.Errorf("newsVisibleTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
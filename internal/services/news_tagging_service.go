@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+)
+
+const (
+	// retagBatchSize 重新提取标签时每批处理的新闻数
+	retagBatchSize = 200
+	// maxUnknownKeywordRunes 标题中未登录词典的汉字串作为关键词时的最大长度，更长的多半是几个词连在一起
+	maxUnknownKeywordRunes = 4
+)
+
+// taggingOptions 关键词标签的参数，来自配置文件，未配置的项使用默认值
+type taggingOptions struct {
+	disabled        bool
+	maxTags         int
+	minScore        float64
+	corpusSize      int
+	refreshInterval time.Duration
+}
+
+func loadTaggingOptions() taggingOptions {
+	opts := taggingOptions{
+		maxTags:         5,
+		minScore:        0.2,
+		corpusSize:      2000,
+		refreshInterval: time.Hour,
+	}
+
+	if config.AppConfig == nil {
+		return opts
+	}
+
+	cfg := config.AppConfig.Tagging
+	opts.disabled = cfg.Disabled
+	if cfg.MaxTags > 0 {
+		opts.maxTags = cfg.MaxTags
+	}
+	if cfg.MinScore > 0 {
+		opts.minScore = cfg.MinScore
+	}
+	if cfg.CorpusSize > 0 {
+		opts.corpusSize = cfg.CorpusSize
+	}
+	if cfg.RefreshMinutes > 0 {
+		opts.refreshInterval = time.Duration(cfg.RefreshMinutes) * time.Minute
+	}
+	return opts
+}
+
+// taggingCorpus 在最近新闻上统计的文档频率，按间隔和词库版本重建
+var taggingCorpus struct {
+	mu       sync.Mutex
+	pipeline *textPipeline
+	model    *nlp.TFIDF
+	version  uint
+	builtAt  time.Time
+}
+
+// NewsTaggingService 新闻关键词标签服务
+// 用最近新闻统计文档频率，按 TF-IDF 权重从新闻标题和正文中选出关键词作为标签，标题中的词权重更高
+type NewsTaggingService struct {
+	db   *gorm.DB
+	opts taggingOptions
+}
+
+func NewNewsTaggingService() *NewsTaggingService {
+	return &NewsTaggingService{
+		db:   database.GetDB(),
+		opts: loadTaggingOptions(),
+	}
+}
+
+// Enabled 是否自动提取关键词标签
+func (s *NewsTaggingService) Enabled() bool {
+	return !s.opts.disabled
+}
+
+// ExtractKeywords 提取新闻的关键词，按权重从高到低排列，只保留权重不低于阈值的词
+func (s *NewsTaggingService) ExtractKeywords(news *models.News) []string {
+	pipeline, model := s.corpus()
+
+	body := news.Summary
+	if body == "" {
+		body = news.Description
+	}
+	title := nlp.StripHTML(news.Title)
+	vector := model.Transform(pipeline.tokens(title, nlp.StripHTML(body+" "+news.Content)))
+
+	scores := make(map[string]float64)
+	for term, weight := range vector {
+		if isKeyword(term, pipeline.segmenter) {
+			scores[term] = weight
+		}
+	}
+	// 标题中未登录词典的词（如新出现的人名、公司名）在分词时被切成二元组，按二元组的平均权重整体作为候选
+	for _, run := range pipeline.segmenter.UnknownRuns(title) {
+		runes := []rune(run)
+		if len(runes) > maxUnknownKeywordRunes || pipeline.stopWords[run] {
+			continue
+		}
+		var sum float64
+		for i := 0; i+2 <= len(runes); i++ {
+			sum += vector[string(runes[i:i+2])]
+		}
+		scores[run] = max(scores[run], sum/float64(len(runes)-1))
+	}
+
+	terms := make([]string, 0, len(scores))
+	for term, score := range scores {
+		if score >= s.opts.minScore {
+			terms = append(terms, term)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if scores[terms[i]] != scores[terms[j]] {
+			return scores[terms[i]] > scores[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > s.opts.maxTags {
+		terms = terms[:s.opts.maxTags]
+	}
+	return terms
+}
+
+// TagNews 将关键词追加到新闻已有的标签之后，忽略大小写去重；关闭自动标签时保持原样
+func (s *NewsTaggingService) TagNews(news *models.News) {
+	if !s.Enabled() {
+		return
+	}
+	news.Tags = sliceToJSON(mergeTags(jsonToSlice(news.Tags), s.ExtractKeywords(news)))
+}
+
+// RetagAllNews 重新提取全部新闻的关键词标签，RSS 新闻保留条目自带的分类，跳过关闭了自动标签的源
+func (s *NewsTaggingService) RetagAllNews(ctx context.Context, progress func(done, total int, partial *models.RetagNewsResult)) (*models.RetagNewsResult, error) {
+	start := time.Now()
+	result := &models.RetagNewsResult{}
+
+	var sources []models.RSSSource
+	if err := s.db.Select("id", "category", "skip_tagging").Find(&sources).Error; err != nil {
+		return nil, err
+	}
+	sourceCategory := make(map[uint]string, len(sources))
+	skipped := make([]uint, 0)
+	for _, source := range sources {
+		sourceCategory[source.ID] = source.Category
+		if source.SkipTagging {
+			skipped = append(skipped, source.ID)
+		}
+	}
+	db := s.db.Model(&models.News{})
+	if len(skipped) > 0 {
+		db = db.Where("rss_source_id IS NULL OR rss_source_id NOT IN ?", skipped)
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	err := forEachNewsBatch(ctx, db, retagBatchSize, func(batch []models.News) error {
+		for i := range batch {
+			news := &batch[i]
+
+			// 手动新闻的标签全部来自自动提取，RSS 新闻保留条目自带的分类
+			var tags []string
+			if news.SourceType == models.NewsTypeRSS && news.RSSSourceID != nil {
+				tags = rssItemTags(news, sourceCategory[*news.RSSSourceID])
+			}
+			tags = mergeTags(tags, s.ExtractKeywords(news))
+			if encoded := sliceToJSON(tags); encoded != news.Tags {
+				// 关联标签后标签字符串改为规范名称，只有同义词不同时不算作变化
+				previous := news.Tags
+				err := s.db.Transaction(func(tx *gorm.DB) error {
+					if err := tx.Model(news).UpdateColumn("tags", encoded).Error; err != nil {
+						return err
+					}
+					news.Tags = encoded
+					return linkNewsTags(tx, news)
+				})
+				if err != nil {
+					return err
+				}
+				if news.Tags != previous {
+					result.UpdatedNews++
+				}
+			}
+			result.ProcessedNews++
+		}
+
+		if progress != nil {
+			result.Duration = time.Since(start).String()
+			progress(result.ProcessedNews, int(total), result)
+		}
+		return nil
+	})
+
+	result.Duration = time.Since(start).String()
+	return result, err
+}
+
+// corpus 返回当前的分词器和文档频率模型，超过刷新间隔或词库变更时重新统计
+func (s *NewsTaggingService) corpus() (*textPipeline, *nlp.TFIDF) {
+	var version uint
+	if taxonomy, err := NewTaxonomyService().CurrentTaxonomy(); err == nil {
+		version = taxonomy.Version
+	}
+
+	taggingCorpus.mu.Lock()
+	defer taggingCorpus.mu.Unlock()
+	if taggingCorpus.model != nil && taggingCorpus.version == version &&
+		time.Since(taggingCorpus.builtAt) < s.opts.refreshInterval {
+		return taggingCorpus.pipeline, taggingCorpus.model
+	}
+
+	pipeline := newTextPipeline(loadClusteringOptions())
+	docs := make([][]string, 0)
+	if s.db != nil {
+		var recent []models.News
+		if err := s.db.Select("id", "title", "summary", "description", "content").
+			Order("id DESC").
+			Limit(s.opts.corpusSize).
+			Find(&recent).Error; err != nil {
+			log.Printf("[TAGGING WARNING] failed to load news corpus: %v", err)
+		}
+		for _, news := range recent {
+			docs = append(docs, pipeline.newsTokens(news))
+		}
+	}
+
+	taggingCorpus.pipeline = pipeline
+	taggingCorpus.model = nlp.FitTFIDF(docs)
+	taggingCorpus.version = version
+	taggingCorpus.builtAt = time.Now()
+	return pipeline, taggingCorpus.model
+}
+
+// isKeyword 可以作为标签的词：至少两个字符且不是纯数字；中文词需要在词典中，排除未登录词切分出的二元片段
+func isKeyword(term string, segmenter *nlp.Segmenter) bool {
+	if utf8.RuneCountInString(term) < 2 {
+		return false
+	}
+	if strings.IndexFunc(term, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' }) < 0 {
+		return false
+	}
+	if strings.IndexFunc(term, func(r rune) bool { return unicode.Is(unicode.Han, r) }) >= 0 {
+		return segmenter.HasWord(term)
+	}
+	return true
+}
+
+// rssItemTags RSS 新闻条目自带的分类，抓取时以逗号分隔保存在 Category 中；条目没有分类时 Category 为源的分类，不作为标签
+func rssItemTags(news *models.News, sourceCategory string) []string {
+	tags := make([]string, 0)
+	if news.Category == sourceCategory {
+		return tags
+	}
+	for _, tag := range strings.Split(news.Category, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// mergeTags 合并两组标签，保持顺序，忽略大小写去重
+func mergeTags(tags []string, extra []string) []string {
+	merged := make([]string, 0, len(tags)+len(extra))
+	seen := make(map[string]bool, len(tags)+len(extra))
+	for _, tag := range append(append([]string{}, tags...), extra...) {
+		key := strings.ToLower(strings.TrimSpace(tag))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
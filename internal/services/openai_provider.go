@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+)
+
+// textTaskPrompts 各任务的系统提示词，%d 为结果的最大字数
+var textTaskPrompts = map[string]string{
+	TextTaskNewsSummary:      "你是新闻编辑。请用简体中文为用户给出的新闻写一段不超过%d字的客观摘要，只陈述事实，不加评论，不要重复标题，直接输出摘要正文。",
+	TextTaskEventTitle:       "你是新闻编辑。用户会给出同一事件的多篇报道，请用简体中文为该事件拟一个不超过%d字的中性标题，直接输出标题，不加引号和标点结尾。",
+	TextTaskEventDescription: "你是新闻编辑。用户会给出同一事件的多篇报道，请用简体中文写一段不超过%d字的事件概述，交代事件的起因、经过和最新进展，只陈述事实，直接输出概述正文。",
+}
+
+// ProviderError 外部模型返回的错误
+type ProviderError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("text provider returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable 限流和服务端错误可以稍后重试
+func (e *ProviderError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// requestError 网络错误，总是可以重试
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string   { return e.err.Error() }
+func (e *requestError) Unwrap() error   { return e.err }
+func (e *requestError) Retryable() bool { return true }
+
+// OpenAIProvider 调用 OpenAI 兼容的 /chat/completions 接口生成文本
+// 通过信号量限制并发，通过最小请求间隔限制速率
+type OpenAIProvider struct {
+	baseURL       string
+	apiKey        string
+	model         string
+	maxInputChars int
+	client        *http.Client
+	slots         chan struct{}
+	limiter       *rateLimiter
+}
+
+func NewOpenAIProvider(cfg config.LLMConfig) *OpenAIProvider {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 2
+	}
+	maxInputChars := cfg.MaxInputChars
+	if maxInputChars <= 0 {
+		maxInputChars = 2000
+	}
+
+	return &OpenAIProvider{
+		baseURL:       strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:        cfg.APIKey,
+		model:         cfg.Model,
+		maxInputChars: maxInputChars,
+		client:        &http.Client{Timeout: timeout},
+		slots:         make(chan struct{}, concurrency),
+		limiter:       newRateLimiter(cfg.RequestsPerMinute),
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return TextProviderOpenAI + ":" + p.baseURL + ":" + p.model
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Generate 生成文本，结果超过最大字数时截断
+func (p *OpenAIProvider) Generate(ctx context.Context, req TextRequest) (string, error) {
+	prompt, ok := textTaskPrompts[req.Task]
+	if !ok {
+		return "", fmt.Errorf("unsupported text task: %s", req.Task)
+	}
+	maxChars := req.MaxChars
+	if maxChars <= 0 {
+		maxChars = 200
+	}
+
+	user := truncateRunes(req.Text, p.maxInputChars)
+	if req.Title != "" {
+		user = "标题：" + req.Title + "\n\n" + user
+	}
+	body, err := json.Marshal(chatCompletionRequest{
+		Model: p.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: fmt.Sprintf(prompt, maxChars)},
+			{Role: "user", Content: user},
+		},
+		Temperature: 0.2,
+		MaxTokens:   maxChars * 2, // 中文约每字一到两个 token
+	})
+	if err != nil {
+		return "", err
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		defer func() { <-p.slots }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	if err := p.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+			return "", &requestError{err: err}
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", &requestError{err: err}
+	}
+
+	var result chatCompletionResponse
+	if resp.StatusCode != http.StatusOK {
+		message := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &result) == nil && result.Error != nil {
+			message = result.Error.Message
+		}
+		return "", &ProviderError{StatusCode: resp.StatusCode, Message: truncateRunes(message, 200)}
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("invalid text provider response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", errors.New("text provider returned no choices")
+	}
+
+	text := strings.TrimSpace(result.Choices[0].Message.Content)
+	if text == "" {
+		return "", errors.New("text provider returned empty content")
+	}
+	if len([]rune(text)) > maxChars {
+		text = truncateRunes(text, maxChars) + "…"
+	}
+	return text, nil
+}
+
+// rateLimiter 按固定最小间隔放行请求
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter 每分钟最多放行 perMinute 个请求，perMinute 不大于 0 时不限制
+func newRateLimiter(perMinute int) *rateLimiter {
+	limiter := &rateLimiter{}
+	if perMinute > 0 {
+		limiter.interval = time.Minute / time.Duration(perMinute)
+	}
+	return limiter
+}
+
+// Wait 等待直到可以发出下一个请求
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
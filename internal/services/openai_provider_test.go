@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+)
+
+// newFakeLLMServer 启动一个本地的 OpenAI 兼容接口，收到的请求交给 handler 处理
+func newFakeLLMServer(t *testing.T, handler func(w http.ResponseWriter, req chatCompletionRequest)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		handler(w, req)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeChoice(w http.ResponseWriter, content string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"message": map[string]string{"role": "assistant", "content": content}},
+		},
+	})
+}
+
+func TestOpenAIProviderGenerate(t *testing.T) {
+	var gotAuth string
+	var gotReq chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		writeChoice(w, "  央行宣布下调存款准备金率。 \n")
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(config.LLMConfig{BaseURL: server.URL + "/", APIKey: "test-key", Model: "test-model", MaxInputChars: 5})
+	text, err := provider.Generate(context.Background(), TextRequest{
+		Task:     TextTaskNewsSummary,
+		Title:    "降准",
+		Text:     "一二三四五六七八",
+		MaxChars: 50,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if text != "央行宣布下调存款准备金率。" {
+		t.Errorf("Generate() = %q", text)
+	}
+	if gotAuth != "Bearer test-key" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if gotReq.Model != "test-model" || gotReq.MaxTokens != 100 || len(gotReq.Messages) != 2 {
+		t.Fatalf("unexpected request %+v", gotReq)
+	}
+	if !strings.Contains(gotReq.Messages[0].Content, "50字") {
+		t.Errorf("system prompt = %q, want max chars filled in", gotReq.Messages[0].Content)
+	}
+	if want := "标题：降准\n\n一二三四五"; gotReq.Messages[1].Content != want {
+		t.Errorf("user message = %q, want %q", gotReq.Messages[1].Content, want)
+	}
+}
+
+func TestOpenAIProviderErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		retryable bool
+		message   string
+	}{
+		{"rate limited", http.StatusTooManyRequests, `{"error":{"message":"rate limit exceeded"}}`, true, "rate limit exceeded"},
+		{"server error", http.StatusInternalServerError, `internal error`, true, "internal error"},
+		{"bad gateway", http.StatusBadGateway, `{"error":{"message":"upstream unavailable"}}`, true, "upstream unavailable"},
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"message":"invalid api key"}}`, false, "invalid api key"},
+		{"bad request", http.StatusBadRequest, `{"error":{"message":"context length exceeded"}}`, false, "context length exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeLLMServer(t, func(w http.ResponseWriter, req chatCompletionRequest) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			provider := NewOpenAIProvider(config.LLMConfig{BaseURL: server.URL + "/v1", Model: "test-model"})
+
+			_, err := provider.Generate(context.Background(), TextRequest{Task: TextTaskNewsSummary, Text: "正文"})
+			var providerErr *ProviderError
+			if !errors.As(err, &providerErr) {
+				t.Fatalf("Generate() error = %v, want *ProviderError", err)
+			}
+			if providerErr.StatusCode != tt.status || providerErr.Message != tt.message {
+				t.Errorf("ProviderError = %+v, want status %d message %q", providerErr, tt.status, tt.message)
+			}
+			if got := isRetryable(err); got != tt.retryable {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+// TestOpenAIProviderRetry 按 Retryable 重试，限流和服务端错误之后成功，客户端错误不再重试
+func TestOpenAIProviderRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int // 依次返回的状态码，用完后返回成功
+		wantText string
+		wantCall int
+	}{
+		{"rate limited then ok", []int{http.StatusTooManyRequests}, "摘要", 2},
+		{"server errors then ok", []int{http.StatusServiceUnavailable, http.StatusInternalServerError}, "摘要", 3},
+		{"client error is final", []int{http.StatusForbidden}, "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := newFakeLLMServer(t, func(w http.ResponseWriter, req chatCompletionRequest) {
+				calls++
+				if calls <= len(tt.statuses) {
+					http.Error(w, "failed", tt.statuses[calls-1])
+					return
+				}
+				writeChoice(w, "摘要")
+			})
+			provider := NewOpenAIProvider(config.LLMConfig{BaseURL: server.URL + "/v1", Model: "test-model"})
+
+			var text string
+			var err error
+			for attempt := 0; attempt < 5; attempt++ {
+				text, err = provider.Generate(context.Background(), TextRequest{Task: TextTaskNewsSummary, Text: "正文"})
+				if err == nil || !isRetryable(err) {
+					break
+				}
+			}
+			if tt.wantText == "" && err == nil {
+				t.Fatalf("Generate() succeeded, want error")
+			}
+			if tt.wantText != "" && err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if text != tt.wantText || calls != tt.wantCall {
+				t.Errorf("got text %q after %d calls, want %q after %d", text, calls, tt.wantText, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestOpenAIProviderTruncatesOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		maxChars int
+		want     string
+	}{
+		{"within limit", "一二三四五", 5, "一二三四五"},
+		{"over limit", "一二三四五六七", 5, "一二三四五…"},
+		{"mixed text", "OpenAI发布新模型", 8, "OpenAI发布…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeLLMServer(t, func(w http.ResponseWriter, req chatCompletionRequest) {
+				writeChoice(w, tt.content)
+			})
+			provider := NewOpenAIProvider(config.LLMConfig{BaseURL: server.URL + "/v1", Model: "test-model"})
+
+			got, err := provider.Generate(context.Background(), TextRequest{Task: TextTaskEventTitle, Text: "正文", MaxChars: tt.maxChars})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Generate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIProviderInvalidResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no choices", `{"choices":[]}`},
+		{"empty content", `{"choices":[{"message":{"role":"assistant","content":"  "}}]}`},
+		{"not json", `<html>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeLLMServer(t, func(w http.ResponseWriter, req chatCompletionRequest) {
+				w.Write([]byte(tt.body))
+			})
+			provider := NewOpenAIProvider(config.LLMConfig{BaseURL: server.URL + "/v1", Model: "test-model"})
+
+			_, err := provider.Generate(context.Background(), TextRequest{Task: TextTaskNewsSummary, Text: "正文"})
+			if err == nil {
+				t.Fatal("Generate() succeeded, want error")
+			}
+			if isRetryable(err) {
+				t.Errorf("isRetryable(%v) = true, want false", err)
+			}
+		})
+	}
+}
+
+func TestOpenAIProviderUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	provider := NewOpenAIProvider(config.LLMConfig{BaseURL: url, Model: "test-model"})
+	_, err := provider.Generate(context.Background(), TextRequest{Task: TextTaskNewsSummary, Text: "正文"})
+	if err == nil {
+		t.Fatal("Generate() succeeded, want error")
+	}
+	if !isRetryable(err) {
+		t.Errorf("isRetryable(%v) = false, want true", err)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -15,14 +16,26 @@ import (
 )
 
 type RSSService struct {
-	db     *gorm.DB
-	parser *gofeed.Parser
+	db            *gorm.DB
+	parser        *gofeed.Parser
+	viewCounter   *ViewCounter
+	summarizer    *SummaryService
+	entityService *EntityService
+	tagger        *NewsTaggingService
+	classifier    *CategoryClassifierService
+	searchOptions searchOptions
 }
 
 func NewRSSService() *RSSService {
 	return &RSSService{
-		db:     database.GetDB(),
-		parser: gofeed.NewParser(),
+		db:            database.GetDB(),
+		parser:        gofeed.NewParser(),
+		viewCounter:   NewViewCounter(),
+		summarizer:    NewSummaryService(),
+		entityService: NewEntityService(),
+		tagger:        NewNewsTaggingService(),
+		classifier:    NewCategoryClassifierService(),
+		searchOptions: loadSearchOptions(),
 	}
 }
 
@@ -51,6 +64,7 @@ func (s *RSSService) CreateRSSSource(req *models.CreateRSSSourceRequest) (*model
 		Priority:    req.Priority,
 		UpdateFreq:  req.UpdateFreq,
 		IsActive:    true,
+		SkipTagging: req.SkipTagging,
 	}
 
 	// 设置默认值
@@ -169,6 +183,9 @@ func (s *RSSService) UpdateRSSSource(id uint, req *models.UpdateRSSSourceRequest
 	if req.UpdateFreq > 0 {
 		source.UpdateFreq = req.UpdateFreq
 	}
+	if req.SkipTagging != nil {
+		source.SkipTagging = *req.SkipTagging
+	}
 
 	if err := s.db.Save(&source).Error; err != nil {
 		return nil, err
@@ -266,6 +283,12 @@ func (s *RSSService) FetchRSSFeed(sourceID uint) (*models.RSSFetchStats, error)
 
 // FetchAllRSSFeeds 抓取所有活跃RSS源的内容
 func (s *RSSService) FetchAllRSSFeeds() (*models.RSSFetchResult, error) {
+	return s.FetchAllRSSFeedsWithProgress(context.Background(), nil)
+}
+
+// FetchAllRSSFeedsWithProgress 逐个抓取活跃RSS源，每抓取完一个源后汇报进度和目前的结果
+// ctx 取消时不再抓取后续的源，返回已抓取部分的结果和 ctx 的错误
+func (s *RSSService) FetchAllRSSFeedsWithProgress(ctx context.Context, progress func(done, total int, partial *models.RSSFetchResult)) (*models.RSSFetchResult, error) {
 	var sources []models.RSSSource
 	if err := s.db.Where("is_active = ?", true).Find(&sources).Error; err != nil {
 		return nil, err
@@ -275,9 +298,30 @@ func (s *RSSService) FetchAllRSSFeeds() (*models.RSSFetchResult, error) {
 		Success: true,
 		Stats:   make([]models.RSSFetchStats, 0),
 	}
+	if progress != nil {
+		progress(0, len(sources), result)
+	}
 
 	successCount := 0
-	for _, source := range sources {
+	summarize := func() {
+		if successCount == 0 {
+			result.Success = false
+			result.Message = "All RSS feeds failed to fetch"
+		} else if successCount < len(sources) {
+			result.Success = true
+			result.Message = fmt.Sprintf("Partially successful: %d/%d sources fetched", successCount, len(sources))
+		} else {
+			result.Success = true
+			result.Message = fmt.Sprintf("Successfully fetched %d RSS sources", successCount)
+		}
+	}
+
+	for i, source := range sources {
+		if err := ctx.Err(); err != nil {
+			summarize()
+			return result, err
+		}
+
 		stats, err := s.FetchRSSFeed(source.ID)
 		if err != nil {
 			log.Printf("Failed to fetch RSS feed for source %s: %v", source.Name, err)
@@ -286,23 +330,20 @@ func (s *RSSService) FetchAllRSSFeeds() (*models.RSSFetchResult, error) {
 				SourceName: source.Name,
 				ErrorItems: 1,
 				FetchTime:  time.Now(),
+				Error:      err.Error(),
 			})
-			continue
+		} else {
+			result.Stats = append(result.Stats, *stats)
+			successCount++
 		}
 
-		result.Stats = append(result.Stats, *stats)
-		successCount++
-	}
-
-	if successCount == 0 {
-		result.Success = false
-		result.Message = "All RSS feeds failed to fetch"
-	} else if successCount < len(sources) {
-		result.Message = fmt.Sprintf("Partially successful: %d/%d sources fetched", successCount, len(sources))
-	} else {
-		result.Message = fmt.Sprintf("Successfully fetched %d RSS sources", successCount)
+		if progress != nil {
+			summarize()
+			progress(i+1, len(sources), result)
+		}
 	}
 
+	summarize()
 	return result, nil
 }
 
@@ -386,6 +427,20 @@ func (s *RSSService) processNewsItem(source *models.RSSSource, item *gofeed.Item
 		Language:    source.Language,
 		IsActive:    true,
 	}
+	// 标题和正文没有变化时沿用已有摘要和处理状态，避免模型生成的摘要被抽取式摘要覆盖后重新排队
+	if !isNew && sameNewsText(&existingItem, &newsItem) {
+		newsItem.Summary = existingItem.Summary
+		newsItem.IsProcessed = existingItem.IsProcessed
+	} else {
+		newsItem.Summary = s.summarizer.SummarizeNews(&newsItem)
+	}
+	// 记录来源给出的分类，条目没有自带分类时置信度足够高的预测分类替换源的默认分类
+	newsItem.SourceCategory = categoryStr
+	s.classifier.ClassifyNews(&newsItem, len(categories) == 0)
+	// 条目自带的分类之后追加从标题和正文中提取的关键词
+	if !source.SkipTagging {
+		s.tagger.TagNews(&newsItem)
+	}
 
 	if isNew {
 		newsItem.ID = 0 // 确保是新记录
@@ -397,13 +452,7 @@ func (s *RSSService) processNewsItem(source *models.RSSSource, item *gofeed.Item
 		log.Printf("[RSS DEBUG] Successfully created news item with ID: %d", newsItem.ID)
 	} else {
 		// 更新现有记录
-		newsItem.ID = existingItem.ID
-		newsItem.ViewCount = existingItem.ViewCount
-		newsItem.LikeCount = existingItem.LikeCount
-		newsItem.CommentCount = existingItem.CommentCount
-		newsItem.ShareCount = existingItem.ShareCount
-		newsItem.HotnessScore = existingItem.HotnessScore
-		newsItem.CreatedBy = existingItem.CreatedBy
+		keepExistingNewsState(&newsItem, &existingItem)
 
 		log.Printf("[RSS DEBUG] Updating existing news item ID: %d", newsItem.ID)
 		if err := s.db.Save(&newsItem).Error; err != nil {
@@ -413,29 +462,64 @@ func (s *RSSService) processNewsItem(source *models.RSSSource, item *gofeed.Item
 		log.Printf("[RSS DEBUG] Successfully updated news item ID: %d", newsItem.ID)
 	}
 
+	// 识别新闻中的人物、机构和地点，失败不影响抓取
+	if _, err := s.entityService.ExtractNewsEntities(&newsItem); err != nil {
+		log.Printf("[RSS WARNING] failed to extract entities for news %d: %v", newsItem.ID, err)
+	}
+	// 关联规范化的分类，失败不影响抓取
+	if err := syncNewsCategories(s.db, &newsItem); err != nil {
+		log.Printf("[RSS WARNING] failed to link categories for news %d: %v", newsItem.ID, err)
+	}
+	// 关联标签并将标签字符串改为规范名称，失败不影响抓取
+	if err := linkNewsTags(s.db, &newsItem); err != nil {
+		log.Printf("[RSS WARNING] failed to link tags for news %d: %v", newsItem.ID, err)
+	}
+	// 更新搜索索引，失败不影响抓取
+	if err := indexNews(s.db, &newsItem); err != nil {
+		log.Printf("[RSS WARNING] failed to index news %d for search: %v", newsItem.ID, err)
+	}
+
 	return &newsItem, isNew, nil
 }
 
-// GetNews 获取新闻列表
+// keepExistingNewsState 重新抓取已有新闻时保留统计数据、创建者和所属事件
+func keepExistingNewsState(newsItem, existing *models.News) {
+	newsItem.ID = existing.ID
+	newsItem.ViewCount = existing.ViewCount
+	newsItem.LikeCount = existing.LikeCount
+	newsItem.CommentCount = existing.CommentCount
+	newsItem.ShareCount = existing.ShareCount
+	newsItem.HotnessScore = existing.HotnessScore
+	newsItem.CreatedBy = existing.CreatedBy
+	newsItem.BelongedEventID = existing.BelongedEventID
+}
+
+// sameNewsText 判断两条新闻用于生成摘要的标题、描述和正文是否一致
+func sameNewsText(a, b *models.News) bool {
+	return a.Title == b.Title && a.Description == b.Description && a.Content == b.Content
+}
+
+// GetNews 获取新闻列表，只包含已发布且正在展示的新闻
 func (s *RSSService) GetNews(query *models.NewsQueryRequest) (*models.NewsListResponse, error) {
 	var news []models.News
 	var total int64
 
-	db := s.db.Model(&models.News{}).Preload("RSSSource").Where("source_type = ?", models.NewsTypeRSS)
+	db := s.db.Model(&models.News{}).Scopes(publishedNews).Where("source_type = ?", models.NewsTypeRSS)
 
 	// 添加筛选条件
 	if query.RSSSourceID > 0 {
 		db = db.Where("rss_source_id = ?", query.RSSSourceID)
 	}
 	if query.Category != "" {
-		db = db.Where("category LIKE ?", "%"+query.Category+"%")
+		db = categoryFilter(s.db, db, "news_categories", "news_id", "id", "category", query.Category)
 	}
 	if query.Status != "" {
 		db = db.Where("status = ?", query.Status)
 	}
-	if query.Search != "" {
-		searchTerm := "%" + query.Search + "%"
-		db = db.Where("title LIKE ? OR description LIKE ? OR content LIKE ?", searchTerm, searchTerm, searchTerm)
+	// 全文搜索，分词后匹配标题、摘要、描述和正文，支持与统一搜索相同的语法
+	search := parseSearchQuery(query.Search)
+	if search.raw != "" {
+		db = search.filter(db, s.db, newsSearchTarget)
 	}
 
 	// 日期范围筛选
@@ -450,12 +534,29 @@ func (s *RSSService) GetNews(query *models.NewsQueryRequest) (*models.NewsListRe
 		}
 	}
 
+	// 按实体筛选
+	if query.EntityID > 0 {
+		db = db.Where("id IN (?)", entityFilter(s.db, "news_entities", "news_id", query.EntityID))
+	}
+
+	// 实体分面统计在排序和分页之前的全部筛选结果上进行
+	var facets models.EntityFacets
+	if query.Facets {
+		var err error
+		if facets, err = entityFacets(s.db, "news_entities", "news_id", db.Session(&gorm.Session{}).Select("id")); err != nil {
+			return nil, err
+		}
+	}
+
 	// 获取总数
 	if err := db.Count(&total).Error; err != nil {
 		return nil, err
 	}
+	if search.raw != "" && query.Page == 1 {
+		recordSearchQuery(s.db, models.SearchEndpointRSS, query.Search, total)
+	}
 
-	// 排序
+	// 排序，搜索且未指定排序方式时按相关度
 	orderBy := "published_at DESC"
 	switch query.SortBy {
 	case "hotness":
@@ -465,10 +566,15 @@ func (s *RSSService) GetNews(query *models.NewsQueryRequest) (*models.NewsListRe
 	case "published_at":
 		orderBy = "published_at DESC"
 	}
+	if search.raw != "" && query.SortBy == "" {
+		db = search.order(db, newsSearchTarget, s.searchOptions)
+	} else {
+		db = db.Order(orderBy)
+	}
 
 	// 分页
 	offset := (query.Page - 1) * query.Limit
-	if err := db.Order(orderBy).Offset(offset).Limit(query.Limit).Find(&news).Error; err != nil {
+	if err := db.Preload("RSSSource").Offset(offset).Limit(query.Limit).Find(&news).Error; err != nil {
 		return nil, err
 	}
 
@@ -501,31 +607,35 @@ func (s *RSSService) GetNews(query *models.NewsQueryRequest) (*models.NewsListRe
 			UpdatedAt:    newsResp.UpdatedAt,
 			RSSSource:    newsResp.RSSSource,
 		}
+		if search.highlighted() {
+			newsItemResp.Highlight = search.highlightNews(&item, s.searchOptions.snippetLength)
+		}
 		newsResponses = append(newsResponses, newsItemResp)
 	}
 
 	return &models.NewsListResponse{
-		Total: total,
-		News:  newsResponses,
+		Total:  total,
+		News:   newsResponses,
+		Facets: facets,
 	}, nil
 }
 
 // GetNewsItem 获取单个新闻详情
-func (s *RSSService) GetNewsItem(id uint) (*models.NewsItemResponse, error) {
+// viewerKey 用于浏览去重，热度由浏览量回写任务统一重算
+func (s *RSSService) GetNewsItem(id uint, viewerKey string) (*models.NewsItemResponse, error) {
 	var newsItem models.News
-	if err := s.db.Preload("RSSSource").Where("source_type = ?", models.NewsTypeRSS).First(&newsItem, id).Error; err != nil {
+	if err := s.db.Preload("RSSSource").Scopes(publishedNews).Where("source_type = ?", models.NewsTypeRSS).First(&newsItem, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("news item not found")
 		}
 		return nil, err
 	}
 
-	// 增加浏览量
-	s.db.Model(&newsItem).UpdateColumn("view_count", gorm.Expr("view_count + 1"))
-	newsItem.ViewCount++
-
-	// 重新计算热度
-	s.calculateNewsHotness(newsItem.ID)
+	// 记录浏览，返回的浏览量包含尚未回写数据库的部分
+	if _, err := s.viewCounter.RecordView(ViewTargetNews, newsItem.ID, viewerKey); err != nil {
+		log.Printf("[RSS WARNING] failed to record view for news %d: %v", newsItem.ID, err)
+	}
+	newsItem.ViewCount += s.viewCounter.PendingViews(ViewTargetNews, newsItem.ID)
 
 	// 转换为NewsItemResponse格式
 	newsResp := newsItem.ToResponse()
@@ -629,6 +739,7 @@ func (s *RSSService) convertToRSSSourceResponse(source *models.RSSSource) *model
 		Tags:        source.Tags,
 		Priority:    source.Priority,
 		UpdateFreq:  source.UpdateFreq,
+		SkipTagging: source.SkipTagging,
 		CreatedAt:   source.CreatedAt,
 		UpdatedAt:   source.UpdatedAt,
 	}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+)
+
+func TestSameNewsText(t *testing.T) {
+	base := models.News{Title: "芯片出口管制升级", Description: "商务部发布公告", Content: "<p>正文</p>"}
+	tests := []struct {
+		name   string
+		mutate func(n *models.News)
+		want   bool
+	}{
+		{name: "unchanged", mutate: func(n *models.News) {}, want: true},
+		{name: "counters and summary ignored", mutate: func(n *models.News) {
+			n.ViewCount = 10
+			n.Summary = "新摘要"
+		}, want: true},
+		{name: "title changed", mutate: func(n *models.News) { n.Title += "（更新）" }, want: false},
+		{name: "description changed", mutate: func(n *models.News) { n.Description = "" }, want: false},
+		{name: "content changed", mutate: func(n *models.News) { n.Content = "<p>更正后的正文</p>" }, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refetched := base
+			tt.mutate(&refetched)
+			if got := sameNewsText(&base, &refetched); got != tt.want {
+				t.Errorf("sameNewsText() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
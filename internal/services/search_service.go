@@ -0,0 +1,1178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// searchIndexBatchSize 重建搜索索引时每批处理的新闻或事件数
+	searchIndexBatchSize = 500
+	// maxIndexedContentRunes 正文参与索引的最大字数，避免超长正文撑大索引
+	maxIndexedContentRunes = 20000
+	// searchFacetLimit 分类和来源分面最多返回的项数
+	searchFacetLimit = 20
+)
+
+// searchOptions 全文搜索的排序、高亮和搜索建议参数，来自配置文件，未配置的项使用默认值
+type searchOptions struct {
+	recencyWeight    float64
+	halfLifeDays     float64
+	hotnessWeight    float64
+	snippetLength    int
+	suggestRefresh   time.Duration
+	popularDays      int
+	logRetentionDays int
+}
+
+func loadSearchOptions() searchOptions {
+	opts := searchOptions{
+		recencyWeight:    0.5,
+		halfLifeDays:     7,
+		hotnessWeight:    0.1,
+		snippetLength:    120,
+		suggestRefresh:   10 * time.Minute,
+		popularDays:      30,
+		logRetentionDays: 90,
+	}
+
+	if config.AppConfig == nil {
+		return opts
+	}
+
+	cfg := config.AppConfig.Search
+	if cfg.RecencyWeight > 0 {
+		opts.recencyWeight = min(cfg.RecencyWeight, 1)
+	}
+	if cfg.HalfLifeDays > 0 {
+		opts.halfLifeDays = cfg.HalfLifeDays
+	}
+	if cfg.HotnessWeight > 0 {
+		opts.hotnessWeight = cfg.HotnessWeight
+	}
+	if cfg.SnippetLength > 0 {
+		opts.snippetLength = cfg.SnippetLength
+	}
+	if cfg.SuggestRefreshMinutes > 0 {
+		opts.suggestRefresh = time.Duration(cfg.SuggestRefreshMinutes) * time.Minute
+	}
+	if cfg.PopularQueryDays > 0 {
+		opts.popularDays = cfg.PopularQueryDays
+	}
+	if cfg.QueryLogRetentionDays > 0 {
+		opts.logRetentionDays = cfg.QueryLogRetentionDays
+	}
+	return opts
+}
+
+// searchDateRanges 日期分段对应的时长
+var searchDateRanges = map[string]time.Duration{
+	models.SearchDateDay:   24 * time.Hour,
+	models.SearchDateWeek:  7 * 24 * time.Hour,
+	models.SearchDateMonth: 30 * 24 * time.Hour,
+	models.SearchDateYear:  365 * 24 * time.Hour,
+}
+
+// searchDateLabels 日期分段的显示名称
+var searchDateLabels = map[string]string{
+	models.SearchDateDay:   "最近24小时",
+	models.SearchDateWeek:  "最近一周",
+	models.SearchDateMonth: "最近一个月",
+	models.SearchDateYear:  "最近一年",
+}
+
+// searchTarget 参与搜索的表及其列
+type searchTarget struct {
+	kind        string
+	label       string
+	model       interface{}
+	textColumns []string // 查询词分不出词时做子串匹配的列
+	startColumn string   // 日期范围按 [startColumn, endColumn] 与查询范围是否重叠判断
+	endColumn   string   // 同时用于时间衰减、日期分段和按时间排序
+	linkTable   string   // 分类关联表
+	ownerColumn string
+}
+
+var (
+	newsSearchTarget = searchTarget{
+		kind:        models.SearchTypeNews,
+		label:       "新闻",
+		model:       &models.News{},
+		textColumns: []string{"title", "summary", "description", "content"},
+		startColumn: "published_at",
+		endColumn:   "published_at",
+		linkTable:   "news_categories",
+		ownerColumn: "news_id",
+	}
+	eventSearchTarget = searchTarget{
+		kind:        models.SearchTypeEvent,
+		label:       "事件",
+		model:       &models.Event{},
+		textColumns: []string{"title", "description", "content", "location"},
+		startColumn: "start_time",
+		endColumn:   "end_time",
+		linkTable:   "event_categories",
+		ownerColumn: "event_id",
+	}
+	searchTargets = []searchTarget{newsSearchTarget, eventSearchTarget}
+)
+
+// SearchService 基于 PostgreSQL 全文检索的新闻和事件搜索
+// 数据库的分词器不支持中文，索引和查询都先在应用中分词，再以空格分隔的词交给 simple 配置建立 tsvector
+type SearchService struct {
+	db   *gorm.DB
+	opts searchOptions
+}
+
+func NewSearchService() *SearchService {
+	return &SearchService{
+		db:   database.GetDB(),
+		opts: loadSearchOptions(),
+	}
+}
+
+var (
+	searchStopWords     map[string]bool
+	searchStopWordsOnce sync.Once
+)
+
+// searchTokens 对索引和查询文本分词，两边使用同一个只含内置词典的分词器，词库调整不会让已有索引失配
+func searchTokens(text string) []string {
+	searchStopWordsOnce.Do(func() {
+		searchStopWords = make(map[string]bool, len(nlp.DefaultStopWords))
+		for _, word := range normalizeStopWords(nlp.DefaultStopWords) {
+			searchStopWords[word] = true
+		}
+	})
+	return nlp.DefaultSegmenter().Tokens(text, searchStopWords)
+}
+
+// searchTerm 查询中的一个词或引号内的短语
+type searchTerm struct {
+	text   string   // 小写半角的原文，分不出词（如单个汉字）时做子串匹配
+	tokens []string // 分词结果
+	phrase bool     // 短语要求各个词按顺序相邻
+}
+
+// tsquery 词之间的连接：短语和排除的词要求相邻，其余只要求都出现
+func (t searchTerm) tsquery(adjacent bool) string {
+	if len(t.tokens) == 1 {
+		return t.tokens[0]
+	}
+	separator := " & "
+	if adjacent || t.phrase {
+		separator = " <-> "
+	}
+	return "(" + strings.Join(t.tokens, separator) + ")"
+}
+
+// searchQuery 解析后的搜索条件
+type searchQuery struct {
+	raw               string
+	include           []searchTerm
+	exclude           []searchTerm
+	sources           []string // source:，满足任意一个即可
+	excludeSources    []string // -source:
+	categories        []string // category:，满足任意一个即可，包含子分类
+	excludeCategories []string // -category:
+	types             []string // type:
+	statuses          []string // status:，只搜索事件
+	start             *time.Time
+	end               *time.Time // 不包含
+}
+
+// searchQueryPart 查询字符串按空白切分后的一段
+type searchQueryPart struct {
+	field   string
+	value   string
+	quoted  bool
+	negated bool
+}
+
+// searchFields 高级语法支持的字段
+var searchFields = map[string]bool{"source": true, "category": true, "type": true, "status": true, "date": true}
+
+// parseSearchQuery 解析搜索语法："短语"、-排除词、-"排除短语"、source:、category:（两者可以用 - 排除）、
+// type:、status: 和 date:；字段值可以加引号，无法识别的字段和取值按普通词处理
+func parseSearchQuery(raw string) searchQuery {
+	q := searchQuery{raw: strings.TrimSpace(raw)}
+	for _, part := range splitSearchQuery(q.raw) {
+		if part.field != "" && q.applyField(part) {
+			continue
+		}
+
+		value := part.value
+		if part.field != "" {
+			value = part.field + ":" + value
+		}
+		term := searchTerm{
+			text:   strings.ToLower(value),
+			tokens: searchTokens(value),
+			phrase: part.quoted,
+		}
+		if strings.TrimSpace(term.text) == "" {
+			continue
+		}
+		if part.negated {
+			q.exclude = append(q.exclude, term)
+		} else {
+			q.include = append(q.include, term)
+		}
+	}
+	return q
+}
+
+// applyField 应用字段条件，取值无法识别时返回 false
+func (q *searchQuery) applyField(part searchQueryPart) bool {
+	value := strings.TrimSpace(part.value)
+	if value == "" {
+		return false
+	}
+	switch part.field {
+	case "source":
+		if part.negated {
+			q.excludeSources = append(q.excludeSources, value)
+		} else {
+			q.sources = append(q.sources, value)
+		}
+	case "category":
+		if part.negated {
+			q.excludeCategories = append(q.excludeCategories, value)
+		} else {
+			q.categories = append(q.categories, value)
+		}
+	case "type":
+		kind := strings.ToLower(value)
+		if part.negated || (kind != models.SearchTypeNews && kind != models.SearchTypeEvent) {
+			return false
+		}
+		q.types = append(q.types, kind)
+	case "status":
+		if part.negated {
+			return false
+		}
+		q.statuses = append(q.statuses, normalizeStatusFilter(value))
+	case "date":
+		start, end, ok := parseSearchDate(value, time.Now())
+		if part.negated || !ok {
+			return false
+		}
+		q.restrictDates(start, end)
+	default:
+		return false
+	}
+	return true
+}
+
+// restrictDates 与已有的日期范围取交集
+func (q *searchQuery) restrictDates(start, end *time.Time) {
+	if start != nil && (q.start == nil || start.After(*q.start)) {
+		q.start = start
+	}
+	if end != nil && (q.end == nil || end.Before(*q.end)) {
+		q.end = end
+	}
+}
+
+// splitSearchQuery 按空白切分查询字符串，引号（包括中文引号）内的空白不切分
+func splitSearchQuery(raw string) []searchQueryPart {
+	runes := []rune(nlp.ToHalfWidth(raw))
+	parts := make([]searchQueryPart, 0)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		var part searchQueryPart
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			part.negated = true
+			i++
+		}
+
+		// 字段名后紧跟冒号
+		j := i
+		for j < len(runes) && ((runes[j] >= 'a' && runes[j] <= 'z') || (runes[j] >= 'A' && runes[j] <= 'Z')) {
+			j++
+		}
+		if field := strings.ToLower(string(runes[i:j])); j < len(runes) && runes[j] == ':' && searchFields[field] {
+			part.field = field
+			i = j + 1
+		}
+
+		if i < len(runes) && isQuote(runes[i]) {
+			j = i + 1
+			for j < len(runes) && !isQuote(runes[j]) {
+				j++
+			}
+			part.value = string(runes[i+1 : j])
+			part.quoted = true
+			i = min(j+1, len(runes))
+		} else {
+			j = i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) {
+				j++
+			}
+			part.value = string(runes[i:j])
+			i = j
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func isQuote(r rune) bool {
+	return r == '"' || r == '“' || r == '”'
+}
+
+// parseSearchDate 解析日期条件：日期分段（如 week），单个日期、月份或年份，或用 .. 分隔的范围（任一端可省略）
+// 返回的结束时间不包含在范围内
+func parseSearchDate(value string, now time.Time) (*time.Time, *time.Time, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if duration, ok := searchDateRanges[value]; ok {
+		start := now.Add(-duration)
+		return &start, nil, true
+	}
+
+	if from, to, ok := strings.Cut(value, ".."); ok {
+		var start, end *time.Time
+		if from != "" {
+			begin, _, ok := parseDateBound(from)
+			if !ok {
+				return nil, nil, false
+			}
+			start = &begin
+		}
+		if to != "" {
+			_, next, ok := parseDateBound(to)
+			if !ok {
+				return nil, nil, false
+			}
+			end = &next
+		}
+		return start, end, start != nil || end != nil
+	}
+
+	begin, next, ok := parseDateBound(value)
+	if !ok {
+		return nil, nil, false
+	}
+	return &begin, &next, true
+}
+
+// parseDateBound 解析 YYYY-MM-DD、YYYY-MM 或 YYYY，返回该时间段的开始和下一个时间段的开始
+func parseDateBound(value string) (time.Time, time.Time, bool) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, t.AddDate(0, 0, 1), true
+	}
+	if t, err := time.Parse("2006-01", value); err == nil {
+		return t, t.AddDate(0, 1, 0), true
+	}
+	if t, err := time.Parse("2006", value); err == nil {
+		return t, t.AddDate(1, 0, 0), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// empty 没有任何搜索条件
+func (q searchQuery) empty() bool {
+	return len(q.include) == 0 && len(q.exclude) == 0 && len(q.sources) == 0 && len(q.excludeSources) == 0 &&
+		len(q.categories) == 0 && len(q.excludeCategories) == 0 && len(q.types) == 0 && len(q.statuses) == 0 &&
+		q.start == nil && q.end == nil
+}
+
+// searches 是否搜索该类型，指定了事件状态时只搜索事件
+func (q searchQuery) searches(kind string) bool {
+	if len(q.types) > 0 && !containsString(q.types, kind) {
+		return false
+	}
+	return len(q.statuses) == 0 || kind == models.SearchTypeEvent
+}
+
+// targets 参与搜索的类型
+func (q searchQuery) targets() []searchTarget {
+	targets := make([]searchTarget, 0, len(searchTargets))
+	for _, target := range searchTargets {
+		if q.searches(target.kind) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// tsquery 全文检索条件：包含的词都要命中，排除的词都不能命中；分词结果只含字母数字和汉字，不需要转义
+func (q searchQuery) tsquery() string {
+	parts := make([]string, 0, len(q.include)+len(q.exclude))
+	if rankQuery := q.rankQuery(); rankQuery != "" {
+		parts = append(parts, rankQuery)
+	}
+	for _, term := range q.exclude {
+		if len(term.tokens) > 0 {
+			parts = append(parts, "!"+term.tsquery(true))
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+// rankQuery 计算相关度使用的条件，只包含要命中的词
+func (q searchQuery) rankQuery() string {
+	parts := make([]string, 0, len(q.include))
+	for _, term := range q.include {
+		if len(term.tokens) > 0 {
+			parts = append(parts, term.tsquery(false))
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+// terms 高亮使用的词，分不出词的查询词按原文高亮
+func (q searchQuery) terms() []string {
+	terms := make([]string, 0)
+	for _, term := range q.include {
+		if len(term.tokens) > 0 {
+			terms = unionStrings(terms, term.tokens)
+		} else {
+			terms = unionStrings(terms, []string{term.text})
+		}
+	}
+	return terms
+}
+
+// highlighted 是否有需要高亮的词
+func (q searchQuery) highlighted() bool {
+	return len(q.include) > 0
+}
+
+// filter 在查询上加入搜索条件，db 为查询的根连接，用于构建分类子查询
+func (q searchQuery) filter(query, db *gorm.DB, target searchTarget) *gorm.DB {
+	if tsquery := q.tsquery(); tsquery != "" {
+		query = query.Where("search_vector @@ to_tsquery('simple', ?)", tsquery)
+	}
+	// 分不出词的查询词退回到子串匹配
+	for _, term := range q.include {
+		if len(term.tokens) == 0 {
+			condition, args := likeCondition(target.textColumns, term.text)
+			query = query.Where(condition, args...)
+		}
+	}
+	for _, term := range q.exclude {
+		if len(term.tokens) == 0 {
+			condition, args := likeCondition(target.textColumns, term.text)
+			query = query.Where("NOT ("+condition+")", args...)
+		}
+	}
+
+	if len(q.sources) > 0 {
+		query = query.Where("LOWER(source) IN ?", lowerStrings(q.sources))
+	}
+	if len(q.excludeSources) > 0 {
+		query = query.Where("LOWER(COALESCE(source, '')) NOT IN ?", lowerStrings(q.excludeSources))
+	}
+	if len(q.categories) > 0 {
+		query = query.Where(categoriesFilter(db, target.linkTable, target.ownerColumn, "id", "category", q.categories))
+	}
+	if len(q.excludeCategories) > 0 {
+		query = query.Not(categoriesFilter(db, target.linkTable, target.ownerColumn, "id", "category", q.excludeCategories))
+	}
+	if q.start != nil {
+		query = query.Where(target.endColumn+" >= ?", *q.start)
+	}
+	if q.end != nil {
+		query = query.Where(target.startColumn+" < ?", *q.end)
+	}
+	if target.kind == models.SearchTypeEvent && len(q.statuses) > 0 {
+		query = query.Where("status IN ?", q.statuses)
+	}
+	return query
+}
+
+// score 综合得分：ts_rank 乘以随时间衰减的系数和热度加成；没有要命中的词时为 0
+func (q searchQuery) score(target searchTarget, opts searchOptions) clause.Expr {
+	rankQuery := q.rankQuery()
+	if rankQuery == "" {
+		return clause.Expr{SQL: "0"}
+	}
+	return clause.Expr{
+		SQL: "ts_rank(search_vector, to_tsquery('simple', ?))" +
+			" * (1 - ? + ? * POWER(0.5, GREATEST(EXTRACT(EPOCH FROM (NOW() - " + target.endColumn + ")), 0) / 86400.0 / ?))" +
+			" * (1 + ? * LN(1 + GREATEST(hotness_score, 0)))",
+		Vars: []interface{}{rankQuery, opts.recencyWeight, opts.recencyWeight, opts.halfLifeDays, opts.hotnessWeight},
+	}
+}
+
+// order 按综合得分排序，没有要命中的词时按时间
+func (q searchQuery) order(query *gorm.DB, target searchTarget, opts searchOptions) *gorm.DB {
+	score := q.score(target, opts)
+	if len(score.Vars) == 0 {
+		return query.Order(target.endColumn + " DESC, id DESC")
+	}
+	return query.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                score.SQL + " DESC, " + target.endColumn + " DESC, id DESC",
+		Vars:               score.Vars,
+		WithoutParentheses: true,
+	}})
+}
+
+// likeCondition 任意一列包含该文本，不区分大小写
+func likeCondition(columns []string, text string) (string, []interface{}) {
+	pattern := "%" + escapeLike(text) + "%"
+	conditions := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		conditions[i] = "COALESCE(" + column + ", '') ILIKE ?"
+		args[i] = pattern
+	}
+	return strings.Join(conditions, " OR "), args
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func lowerStrings(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// withRequest 合并请求参数中的筛选条件
+func (q searchQuery) withRequest(req *models.SearchRequest) (searchQuery, error) {
+	if req.Type != "" {
+		if req.Type != models.SearchTypeNews && req.Type != models.SearchTypeEvent {
+			return q, errors.New("invalid search type")
+		}
+		q.types = append(q.types, req.Type)
+	}
+	if req.Category != "" {
+		q.categories = append(q.categories, req.Category)
+	}
+	if req.Source != "" {
+		q.sources = append(q.sources, req.Source)
+	}
+	if req.Status != "" {
+		q.statuses = append(q.statuses, normalizeStatusFilter(req.Status))
+	}
+	if req.Date != "" {
+		duration, ok := searchDateRanges[req.Date]
+		if !ok {
+			return q, errors.New("invalid date bucket")
+		}
+		start := time.Now().Add(-duration)
+		q.restrictDates(&start, nil)
+	}
+	if req.StartDate != "" {
+		start, _, ok := parseDateBound(req.StartDate)
+		if !ok {
+			return q, errors.New("invalid start_date")
+		}
+		q.restrictDates(&start, nil)
+	}
+	if req.EndDate != "" {
+		_, end, ok := parseDateBound(req.EndDate)
+		if !ok {
+			return q, errors.New("invalid end_date")
+		}
+		q.restrictDates(nil, &end)
+	}
+	return q, nil
+}
+
+// without 去掉一个分面对应的筛选条件，分面统计时使用
+func (q searchQuery) without(facet string) searchQuery {
+	switch facet {
+	case "type":
+		q.types = nil
+	case "category":
+		q.categories, q.excludeCategories = nil, nil
+	case "source":
+		q.sources, q.excludeSources = nil, nil
+	case "status":
+		q.statuses = nil
+	case "date":
+		q.start, q.end = nil, nil
+	}
+	return q
+}
+
+// matches 某个类型中满足搜索条件的记录，新闻只包含已发布且正在展示的
+func (s *SearchService) matches(q searchQuery, target searchTarget) *gorm.DB {
+	query := s.db.Model(target.model)
+	if target.kind == models.SearchTypeNews {
+		query = query.Scopes(publishedNews)
+	}
+	return q.filter(query, s.db, target)
+}
+
+// Search 同时搜索新闻和事件，按综合得分（或时间、热度）统一排序分页，并返回分面统计
+func (s *SearchService) Search(req *models.SearchRequest) (*models.SearchResponse, error) {
+	q, err := parseSearchQuery(req.Query).withRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if q.empty() {
+		return nil, errors.New("search query cannot be empty")
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+
+	var orderBy string
+	switch req.SortBy {
+	case "", "relevance":
+		orderBy = "score DESC, date DESC, type ASC, id DESC"
+	case "time":
+		orderBy = "date DESC, type ASC, id DESC"
+	case "hotness":
+		orderBy = "hotness_score DESC, date DESC, type ASC, id DESC"
+	default:
+		return nil, errors.New("invalid sort_by")
+	}
+
+	response := &models.SearchResponse{Results: make([]models.SearchResult, 0), Page: req.Page, Limit: req.Limit}
+	if targets := q.targets(); len(targets) > 0 {
+		selects := make([]string, len(targets))
+		vars := make([]interface{}, 0, len(targets)+2)
+		for i, target := range targets {
+			var count int64
+			if err := s.matches(q, target).Count(&count).Error; err != nil {
+				return nil, err
+			}
+			response.Total += count
+
+			score := q.score(target, s.opts)
+			selects[i] = "(?)"
+			vars = append(vars, s.matches(q, target).Select(
+				"? AS type, id, "+score.SQL+" AS score, "+target.endColumn+" AS date, hotness_score",
+				append([]interface{}{target.kind}, score.Vars...)...))
+		}
+
+		var hits []struct {
+			Type  string
+			ID    uint
+			Score float64
+		}
+		offset := (req.Page - 1) * req.Limit
+		if err := s.db.Raw("SELECT type, id, score FROM ("+strings.Join(selects, " UNION ALL ")+") AS results ORDER BY "+orderBy+" LIMIT ? OFFSET ?",
+			append(vars, req.Limit, offset)...).Scan(&hits).Error; err != nil {
+			return nil, err
+		}
+
+		newsIDs := make([]uint, 0)
+		eventIDs := make([]uint, 0)
+		for _, hit := range hits {
+			if hit.Type == models.SearchTypeNews {
+				newsIDs = append(newsIDs, hit.ID)
+			} else {
+				eventIDs = append(eventIDs, hit.ID)
+			}
+		}
+		newsByID, eventsByID, err := s.loadHits(newsIDs, eventIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hit := range hits {
+			result := models.SearchResult{Type: hit.Type, ID: hit.ID, Score: hit.Score}
+			switch hit.Type {
+			case models.SearchTypeNews:
+				news, ok := newsByID[hit.ID]
+				if !ok {
+					continue
+				}
+				newsResponse := news.ToResponse()
+				result.News = &newsResponse
+				if q.highlighted() {
+					result.Highlight = q.highlightNews(news, s.opts.snippetLength)
+				}
+			case models.SearchTypeEvent:
+				event, ok := eventsByID[hit.ID]
+				if !ok {
+					continue
+				}
+				eventResponse := convertToEventResponse(event)
+				result.Event = &eventResponse
+				if q.highlighted() {
+					result.Highlight = q.highlightEvent(event, s.opts.snippetLength)
+				}
+			}
+			response.Results = append(response.Results, result)
+		}
+	}
+
+	if response.Facets, err = s.facets(q); err != nil {
+		return nil, err
+	}
+	if req.Page == 1 {
+		recordSearchQuery(s.db, models.SearchEndpointUnified, req.Query, response.Total)
+	}
+	return response, nil
+}
+
+// loadHits 读取一页结果中的新闻和事件
+func (s *SearchService) loadHits(newsIDs, eventIDs []uint) (map[uint]*models.News, map[uint]*models.Event, error) {
+	newsByID := make(map[uint]*models.News, len(newsIDs))
+	if len(newsIDs) > 0 {
+		var newsList []models.News
+		if err := s.db.Where("id IN ?", newsIDs).Find(&newsList).Error; err != nil {
+			return nil, nil, err
+		}
+		for i := range newsList {
+			newsByID[newsList[i].ID] = &newsList[i]
+		}
+	}
+
+	eventsByID := make(map[uint]*models.Event, len(eventIDs))
+	if len(eventIDs) > 0 {
+		var events []models.Event
+		if err := s.db.Where("id IN ?", eventIDs).Find(&events).Error; err != nil {
+			return nil, nil, err
+		}
+		for i := range events {
+			eventsByID[events[i].ID] = &events[i]
+		}
+	}
+	return newsByID, eventsByID, nil
+}
+
+// facets 分面统计，每个分面去掉自身的筛选条件，在其余条件的结果上统计
+func (s *SearchService) facets(q searchQuery) (models.SearchFacets, error) {
+	facets := models.SearchFacets{
+		Type:     make([]models.SearchFacet, 0, len(searchTargets)),
+		Category: make([]models.SearchFacet, 0),
+		Source:   make([]models.SearchFacet, 0),
+		Date:     make([]models.SearchFacet, 0, len(models.SearchDateBuckets)),
+		Status:   make([]models.SearchFacet, 0, len(models.EventStatuses)),
+	}
+
+	// 类型
+	typeQuery := q.without("type")
+	for _, target := range searchTargets {
+		var count int64
+		if typeQuery.searches(target.kind) {
+			if err := s.matches(typeQuery, target).Count(&count).Error; err != nil {
+				return facets, err
+			}
+		}
+		facets.Type = append(facets.Type, models.SearchFacet{Value: target.kind, Label: target.label, Count: count})
+	}
+
+	// 分类，按关联的规范化分类统计
+	categoryQuery := q.without("category")
+	categoryCounts := make(map[uint]int64)
+	for _, target := range categoryQuery.targets() {
+		var rows []struct {
+			CategoryID uint
+			Count      int64
+		}
+		if err := s.db.Table(target.linkTable).
+			Select("category_id, COUNT(*) AS count").
+			Where(target.ownerColumn+" IN (?)", s.matches(categoryQuery, target).Select("id")).
+			Group("category_id").
+			Scan(&rows).Error; err != nil {
+			return facets, err
+		}
+		for _, row := range rows {
+			categoryCounts[row.CategoryID] += row.Count
+		}
+	}
+	if len(categoryCounts) > 0 {
+		ids := make([]uint, 0, len(categoryCounts))
+		for id := range categoryCounts {
+			ids = append(ids, id)
+		}
+		var categories []models.Category
+		if err := s.db.Select("id", "slug", "name").Where("id IN ?", ids).Find(&categories).Error; err != nil {
+			return facets, err
+		}
+		for _, category := range categories {
+			facets.Category = append(facets.Category, models.SearchFacet{Value: category.Slug, Label: category.Name, Count: categoryCounts[category.ID]})
+		}
+		facets.Category = topFacets(facets.Category)
+	}
+
+	// 来源
+	sourceQuery := q.without("source")
+	sourceCounts := make(map[string]int64)
+	for _, target := range sourceQuery.targets() {
+		var rows []struct {
+			Source string
+			Count  int64
+		}
+		if err := s.matches(sourceQuery, target).
+			Select("source, COUNT(*) AS count").
+			Where("source <> ''").
+			Group("source").
+			Scan(&rows).Error; err != nil {
+			return facets, err
+		}
+		for _, row := range rows {
+			sourceCounts[row.Source] += row.Count
+		}
+	}
+	for source, count := range sourceCounts {
+		facets.Source = append(facets.Source, models.SearchFacet{Value: source, Label: source, Count: count})
+	}
+	facets.Source = topFacets(facets.Source)
+
+	// 日期分段
+	dateQuery := q.without("date")
+	now := time.Now()
+	dateCounts := make(map[string]int64, len(models.SearchDateBuckets))
+	for _, target := range dateQuery.targets() {
+		columns := make([]string, len(models.SearchDateBuckets))
+		vars := make([]interface{}, len(models.SearchDateBuckets))
+		for i, bucket := range models.SearchDateBuckets {
+			columns[i] = "COUNT(*) FILTER (WHERE " + target.endColumn + " >= ?) AS " + bucket
+			vars[i] = now.Add(-searchDateRanges[bucket])
+		}
+		row := make(map[string]interface{})
+		if err := s.matches(dateQuery, target).Select(strings.Join(columns, ", "), vars...).Take(&row).Error; err != nil {
+			return facets, err
+		}
+		for _, bucket := range models.SearchDateBuckets {
+			if count, ok := row[bucket].(int64); ok {
+				dateCounts[bucket] += count
+			}
+		}
+	}
+	for _, bucket := range models.SearchDateBuckets {
+		facets.Date = append(facets.Date, models.SearchFacet{Value: bucket, Label: searchDateLabels[bucket], Count: dateCounts[bucket]})
+	}
+
+	// 事件状态
+	statusQuery := q.without("status")
+	if statusQuery.searches(models.SearchTypeEvent) {
+		var rows []struct {
+			Status string
+			Count  int64
+		}
+		if err := s.matches(statusQuery, eventSearchTarget).
+			Select("status, COUNT(*) AS count").
+			Group("status").
+			Scan(&rows).Error; err != nil {
+			return facets, err
+		}
+		counts := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			counts[row.Status] = row.Count
+		}
+		for _, status := range models.EventStatuses {
+			facets.Status = append(facets.Status, models.SearchFacet{Value: status, Label: models.EventStatusLabel(status, "zh"), Count: counts[status]})
+		}
+	}
+
+	return facets, nil
+}
+
+// topFacets 按数量从多到少排列，只保留前 searchFacetLimit 项
+func topFacets(items []models.SearchFacet) []models.SearchFacet {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Value < items[j].Value
+	})
+	return items[:min(len(items), searchFacetLimit)]
+}
+
+// SearchNews 全文搜索新闻，按相关度、发布时间和热度排序，返回带高亮的结果；支持与统一搜索相同的语法
+func (s *SearchService) SearchNews(query string, page, pageSize int) ([]models.NewsSearchResult, int64, error) {
+	q := parseSearchQuery(query)
+	db := q.filter(s.db.Model(&models.News{}).Scopes(publishedNews), s.db, newsSearchTarget)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if page == 1 {
+		recordSearchQuery(s.db, models.SearchEndpointNews, query, total)
+	}
+
+	var newsList []models.News
+	offset := (page - 1) * pageSize
+	if err := q.order(db, newsSearchTarget, s.opts).Offset(offset).Limit(pageSize).Find(&newsList).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]models.NewsSearchResult, len(newsList))
+	for i := range newsList {
+		results[i] = models.NewsSearchResult{NewsResponse: newsList[i].ToResponse()}
+		if q.highlighted() {
+			results[i].Highlight = q.highlightNews(&newsList[i], s.opts.snippetLength)
+		}
+	}
+	return results, total, nil
+}
+
+// RebuildSearchIndex 重新建立全部新闻和事件的搜索索引，用于分词器更新后重建
+func (s *SearchService) RebuildSearchIndex(ctx context.Context, progress func(done, total int, partial *models.RebuildSearchIndexResult)) (*models.RebuildSearchIndexResult, error) {
+	return s.rebuildSearchIndex(ctx, false, progress)
+}
+
+// BackfillSearchIndex 为还没有搜索索引的新闻和事件建立索引，服务启动时在后台调用
+// 升级前已有的记录在此之前只能通过子串匹配搜到
+func (s *SearchService) BackfillSearchIndex() {
+	result, err := s.rebuildSearchIndex(context.Background(), true, nil)
+	if err != nil {
+		log.Printf("[SEARCH WARNING] failed to backfill search index: %v", err)
+		return
+	}
+	if result.ProcessedNews+result.ProcessedEvents > 0 {
+		log.Printf("[SEARCH] Backfilled search index for %d news and %d events in %s",
+			result.ProcessedNews, result.ProcessedEvents, result.Duration)
+	}
+}
+
+// rebuildSearchIndex 分批重建新闻和事件的搜索索引，missingOnly 为 true 时只处理 search_vector 为空的记录
+func (s *SearchService) rebuildSearchIndex(ctx context.Context, missingOnly bool, progress func(done, total int, partial *models.RebuildSearchIndexResult)) (*models.RebuildSearchIndexResult, error) {
+	start := time.Now()
+	result := &models.RebuildSearchIndexResult{}
+	scope := func(db *gorm.DB) *gorm.DB {
+		if missingOnly {
+			return db.Where("search_vector IS NULL")
+		}
+		return db
+	}
+
+	var newsTotal, eventTotal int64
+	if err := s.db.Model(&models.News{}).Scopes(scope).Count(&newsTotal).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&models.Event{}).Scopes(scope).Count(&eventTotal).Error; err != nil {
+		return nil, err
+	}
+	total := int(newsTotal + eventTotal)
+
+	tables := []struct {
+		model     interface{}
+		reindex   func(*gorm.DB, ...uint) error
+		processed *int
+	}{
+		{&models.News{}, reindexNews, &result.ProcessedNews},
+		{&models.Event{}, reindexEvents, &result.ProcessedEvents},
+	}
+	for _, table := range tables {
+		err := forEachIDBatch(ctx, s.db.Model(table.model).Scopes(scope), searchIndexBatchSize, func(ids []uint) error {
+			if err := s.db.Transaction(func(tx *gorm.DB) error {
+				return table.reindex(tx, ids...)
+			}); err != nil {
+				return err
+			}
+			*table.processed += len(ids)
+
+			if progress != nil {
+				result.Duration = time.Since(start).String()
+				progress(result.ProcessedNews+result.ProcessedEvents, total, result)
+			}
+			return nil
+		})
+		if err != nil {
+			result.Duration = time.Since(start).String()
+			return result, err
+		}
+	}
+
+	result.Duration = time.Since(start).String()
+	return result, nil
+}
+
+// indexNews 更新新闻的搜索索引：标题权重最高，其次是摘要和描述，最后是正文
+func indexNews(tx *gorm.DB, news *models.News) error {
+	return updateSearchVector(tx, "news", news.ID, news.Title,
+		news.Summary+"\n"+nlp.StripHTML(news.Description), nlp.StripHTML(news.Content))
+}
+
+// indexEvent 更新事件的搜索索引：标题权重最高，其次是描述和地点，最后是内容
+func indexEvent(tx *gorm.DB, event *models.Event) error {
+	return updateSearchVector(tx, "events", event.ID, event.Title,
+		nlp.StripHTML(event.Description)+"\n"+event.Location, nlp.StripHTML(event.Content))
+}
+
+func updateSearchVector(tx *gorm.DB, table string, id uint, title, abstract, body string) error {
+	return tx.Exec("UPDATE "+table+" SET search_vector = setweight(to_tsvector('simple', ?), 'A') || "+
+		"setweight(to_tsvector('simple', ?), 'B') || setweight(to_tsvector('simple', ?), 'C') WHERE id = ?",
+		strings.Join(searchTokens(title), " "),
+		strings.Join(searchTokens(abstract), " "),
+		strings.Join(searchTokens(truncateRunes(body, maxIndexedContentRunes)), " "),
+		id).Error
+}
+
+// reindexNews 重新读取新闻的文本并更新搜索索引，用于只改了部分列的更新
+func reindexNews(tx *gorm.DB, ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var newsList []models.News
+	if err := tx.Select("id", "title", "summary", "description", "content").
+		Where("id IN ?", ids).Find(&newsList).Error; err != nil {
+		return err
+	}
+	for i := range newsList {
+		if err := indexNews(tx, &newsList[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexEvents 重新读取事件的文本并更新搜索索引
+func reindexEvents(tx *gorm.DB, ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var events []models.Event
+	if err := tx.Select("id", "title", "description", "content", "location").
+		Where("id IN ?", ids).Find(&events).Error; err != nil {
+		return err
+	}
+	for i := range events {
+		if err := indexEvent(tx, &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// highlightNews 高亮新闻标题，并从摘要、描述或正文中截取命中位置附近的片段
+func (q searchQuery) highlightNews(news *models.News, snippetLength int) *models.SearchHighlight {
+	return highlightDocument(q.terms(), news.Title, snippetLength,
+		news.Summary, nlp.StripHTML(news.Description), nlp.StripHTML(news.Content))
+}
+
+// highlightEvent 高亮事件标题，并从描述或内容中截取命中位置附近的片段
+func (q searchQuery) highlightEvent(event *models.Event, snippetLength int) *models.SearchHighlight {
+	return highlightDocument(q.terms(), event.Title, snippetLength,
+		nlp.StripHTML(event.Description), nlp.StripHTML(event.Content))
+}
+
+// highlightDocument 高亮标题；摘要取第一段有命中的候选文本，都没有命中时取第一段非空文本的开头
+func highlightDocument(terms []string, title string, snippetLength int, candidates ...string) *models.SearchHighlight {
+	highlight := &models.SearchHighlight{Title: highlightText(title, terms, 0)}
+	for _, text := range candidates {
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" {
+			continue
+		}
+		if highlight.Snippet == "" {
+			highlight.Snippet = highlightText(text, terms, snippetLength)
+		}
+		if len(matchRanges(text, terms)) > 0 {
+			highlight.Snippet = highlightText(text, terms, snippetLength)
+			break
+		}
+	}
+	return highlight
+}
+
+// highlightText 转义文本并用 <em> 包裹命中的词；limit 大于 0 时只保留第一个命中位置附近的 limit 个字，截断处加省略号
+func highlightText(text string, terms []string, limit int) string {
+	runes := []rune(text)
+	ranges := matchRanges(text, terms)
+
+	start, end := 0, len(runes)
+	if limit > 0 && len(runes) > limit {
+		if len(ranges) > 0 {
+			// 命中位置之前保留四分之一的上下文
+			start = max(0, min(ranges[0][0]-limit/4, len(runes)-limit))
+		}
+		end = start + limit
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, r := range ranges {
+		from, to := max(r[0], pos), min(r[1], end)
+		if from >= to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:from])))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(string(runes[from:to])))
+		b.WriteString("</em>")
+		pos = to
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// matchRanges 查找文本中各个词出现的位置（按字计），忽略大小写和全半角，重叠或相邻的位置合并
+func matchRanges(text string, terms []string) [][2]int {
+	normalized := []rune(nlp.ToHalfWidth(text))
+	for i, r := range normalized {
+		normalized[i] = unicode.ToLower(r)
+	}
+
+	var ranges [][2]int
+	for _, term := range terms {
+		pattern := []rune(term)
+		if len(pattern) == 0 {
+			continue
+		}
+		// 字母数字词只匹配完整的词，避免在更长的单词中间高亮
+		alnum := isAlnum(pattern[0])
+		for i := 0; i+len(pattern) <= len(normalized); i++ {
+			if string(normalized[i:i+len(pattern)]) != term {
+				continue
+			}
+			end := i + len(pattern)
+			if alnum && ((i > 0 && isAlnum(normalized[i-1])) || (end < len(normalized) && isAlnum(normalized[end]))) {
+				continue
+			}
+			ranges = append(ranges, [2]int{i, end})
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func isAlnum(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
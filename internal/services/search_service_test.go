@@ -0,0 +1,220 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+)
+
+func TestSplitSearchQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []searchQueryPart
+	}{
+		{
+			name: "words phrases and exclusions",
+			raw:  `芯片  "人工 智能" -关税 -"贸易 战"`,
+			want: []searchQueryPart{
+				{value: "芯片"},
+				{value: "人工 智能", quoted: true},
+				{value: "关税", negated: true},
+				{value: "贸易 战", quoted: true, negated: true},
+			},
+		},
+		{
+			name: "fields",
+			raw:  `SOURCE:新华社 -category:"国际 新闻" date:2025-06`,
+			want: []searchQueryPart{
+				{field: "source", value: "新华社"},
+				{field: "category", value: "国际 新闻", quoted: true, negated: true},
+				{field: "date", value: "2025-06"},
+			},
+		},
+		{
+			name: "unknown field is a plain word",
+			raw:  `foo:bar`,
+			want: []searchQueryPart{{value: "foo:bar"}},
+		},
+		{
+			name: "chinese quotes and full width",
+			raw:  `“人工 智能”　ｓｏｕｒｃｅ：新华社`,
+			want: []searchQueryPart{
+				{value: "人工 智能", quoted: true},
+				{field: "source", value: "新华社"},
+			},
+		},
+		{
+			name: "lone minus and unterminated quote",
+			raw:  `- "芯片 出口`,
+			want: []searchQueryPart{
+				{value: "-"},
+				{value: "芯片 出口", quoted: true},
+			},
+		},
+		{
+			name: "empty",
+			raw:  "   ",
+			want: []searchQueryPart{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitSearchQuery(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitSearchQuery(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	format := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	}
+
+	tests := []struct {
+		name              string
+		raw               string
+		include           []string
+		phrases           []string
+		exclude           []string
+		sources           []string
+		excludeSources    []string
+		categories        []string
+		excludeCategories []string
+		types             []string
+		statuses          []string
+		start, end        string
+	}{
+		{
+			name:    "terms",
+			raw:     `芯片出口 "人工智能 芯片" -关税`,
+			include: []string{"芯片出口", "人工智能 芯片"},
+			phrases: []string{"人工智能 芯片"},
+			exclude: []string{"关税"},
+		},
+		{
+			name:              "source and category filters",
+			raw:               `source:新华社 source:"央视 新闻" -source:环球 category:科技 -category:娱乐`,
+			sources:           []string{"新华社", "央视 新闻"},
+			excludeSources:    []string{"环球"},
+			categories:        []string{"科技"},
+			excludeCategories: []string{"娱乐"},
+		},
+		{
+			name:     "type and status",
+			raw:      `type:NEWS type:video status:进行中 -status:已结束`,
+			include:  []string{"type:video"},
+			exclude:  []string{"status:已结束"},
+			types:    []string{models.SearchTypeNews},
+			statuses: []string{models.EventStatusOngoing},
+		},
+		{
+			name:  "date ranges intersect",
+			raw:   `date:2025-01..2025-03 date:2025-02`,
+			start: "2025-02-01",
+			end:   "2025-03-01",
+		},
+		{
+			name: "open ended date range",
+			raw:  `date:..2025`,
+			end:  "2026-01-01",
+		},
+		{
+			name:    "invalid values fall back to words",
+			raw:     `date:yesterday -date:2025 source:""`,
+			include: []string{"date:yesterday", "source:"},
+			phrases: []string{"source:"},
+			exclude: []string{"date:2025"},
+		},
+		{
+			name: "empty phrase is dropped",
+			raw:  `"" -""`,
+		},
+	}
+
+	texts := func(terms []searchTerm, phrasesOnly bool) []string {
+		var result []string
+		for _, term := range terms {
+			if !phrasesOnly || term.phrase {
+				result = append(result, term.text)
+			}
+		}
+		return result
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := parseSearchQuery(tt.raw)
+			checks := []struct {
+				field     string
+				got, want []string
+			}{
+				{"include", texts(q.include, false), tt.include},
+				{"phrases", texts(q.include, true), tt.phrases},
+				{"exclude", texts(q.exclude, false), tt.exclude},
+				{"sources", q.sources, tt.sources},
+				{"excludeSources", q.excludeSources, tt.excludeSources},
+				{"categories", q.categories, tt.categories},
+				{"excludeCategories", q.excludeCategories, tt.excludeCategories},
+				{"types", q.types, tt.types},
+				{"statuses", q.statuses, tt.statuses},
+			}
+			for _, c := range checks {
+				if len(c.got) != 0 || len(c.want) != 0 {
+					if !reflect.DeepEqual(c.got, c.want) {
+						t.Errorf("%s = %q, want %q", c.field, c.got, c.want)
+					}
+				}
+			}
+			if format(q.start) != tt.start || format(q.end) != tt.end {
+				t.Errorf("date range = [%s, %s), want [%s, %s)", format(q.start), format(q.end), tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryTokens(t *testing.T) {
+	q := parseSearchQuery(`ＡＩ芯片的出口`)
+	if len(q.include) != 1 {
+		t.Fatalf("include = %+v, want one term", q.include)
+	}
+	if want := []string{"ai", "芯片", "出口"}; !reflect.DeepEqual(q.include[0].tokens, want) {
+		t.Errorf("tokens = %q, want %q", q.include[0].tokens, want)
+	}
+}
+
+func TestHighlightText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		limit int
+		want  string
+	}{
+		{"plain", "国产芯片出口增长", []string{"芯片"}, 0, "国产<em>芯片</em>出口增长"},
+		{"escapes html", "<b>AI</b> 芯片", []string{"ai"}, 0, "&lt;b&gt;<em>AI</em>&lt;/b&gt; 芯片"},
+		{"whole alphanumeric words only", "OpenAI 发布 AI 模型", []string{"ai"}, 0, "OpenAI 发布 <em>AI</em> 模型"},
+		{"full width keeps original text", "ＡＩ芯片", []string{"ai"}, 0, "<em>ＡＩ</em>芯片"},
+		{"overlapping terms merged", "人工智能芯片", []string{"人工智能", "智能芯片"}, 0, "<em>人工智能芯片</em>"},
+		{"no match", "国产芯片", []string{"关税"}, 0, "国产芯片"},
+		{"snippet around match", "一二三四五六七八九十芯片一二三四五六七八九十", []string{"芯片"}, 8, "…九十<em>芯片</em>一二三四…"},
+		{"snippet without match", "一二三四五六七八九十", []string{"芯片"}, 8, "一二三四五六七八…"},
+		{"snippet near end", "一二三四五六七八九十芯片", []string{"芯片"}, 8, "…五六七八九十<em>芯片</em>"},
+		{"match cut by snippet", "一二三四五六七八九十人工智能", []string{"人工智能"}, 4, "…十<em>人工智</em>…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightText(tt.text, tt.terms, tt.limit); got != tt.want {
+				t.Errorf("highlightText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
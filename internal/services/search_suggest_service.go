@@ -0,0 +1,448 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxLoggedQueryRunes 搜索记录保存的最大字数，与表字段长度一致
+	maxLoggedQueryRunes = 200
+	// suggestSourceLimit 每种来源参与搜索建议的最大条数
+	suggestSourceLimit = 5000
+	// suggestVocabularyNews 统计纠错词汇时使用的最近新闻数
+	suggestVocabularyNews = 5000
+	// suggestMaxScan 一次补全最多检查的前缀匹配项，避免过短的前缀扫描整个索引
+	suggestMaxScan = 2000
+	// minPopularQueryCount 搜索词至少被搜索过的次数才作为建议，避免展示个别用户的搜索
+	minPopularQueryCount = 2
+	// minCorrectionWordCount 纠错候选词在语料中至少出现的次数
+	minCorrectionWordCount = 2
+)
+
+// suggestionWeights 各来源建议的权重，乘以 ln(2 + 热度、计数或提及次数)
+var suggestionWeights = map[string]float64{
+	models.SuggestionTypeQuery:  3,
+	models.SuggestionTypeTag:    2,
+	models.SuggestionTypeEntity: 2,
+	models.SuggestionTypeEvent:  1,
+}
+
+// normalizeSearchText 规范化搜索语句：小写半角，连续空白合并为一个空格
+func normalizeSearchText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(nlp.ToHalfWidth(text)), " "))
+}
+
+// recordSearchQuery 记录一次搜索，写入失败只记录日志，不影响搜索结果
+func recordSearchQuery(db *gorm.DB, endpoint, query string, total int64) {
+	query = truncateRunes(normalizeSearchText(query), maxLoggedQueryRunes)
+	if query == "" {
+		return
+	}
+	entry := models.SearchQueryLog{Query: query, Endpoint: endpoint, ResultCount: total}
+	if err := db.Create(&entry).Error; err != nil {
+		log.Printf("[SEARCH WARNING] failed to record search query: %v", err)
+	}
+}
+
+// suggestEntry 可以补全的一项，按规范化的文本做前缀匹配
+type suggestEntry struct {
+	key        string
+	score      float64
+	suggestion models.SearchSuggestion
+}
+
+// suggestWord 纠错使用的词
+type suggestWord struct {
+	text  string
+	runes []rune
+	count int
+}
+
+// suggestIndex 内存中的搜索建议索引
+type suggestIndex struct {
+	entries       []suggestEntry        // 按 key 排序
+	vocabulary    map[string]int        // 语料中的词及出现次数
+	wordsByLength map[int][]suggestWord // 出现次数足够的词按字数分组，纠错时只比较长度接近的词
+	builtAt       time.Time
+}
+
+// suggestIndexCache 当前的搜索建议索引，超过刷新间隔后在后台重建，重建期间继续使用旧索引
+var suggestIndexCache struct {
+	mu       sync.Mutex
+	index    *suggestIndex
+	building bool
+}
+
+// currentSuggestIndex 返回当前的搜索建议索引，还没有索引时同步建立
+func (s *SearchService) currentSuggestIndex() *suggestIndex {
+	suggestIndexCache.mu.Lock()
+	defer suggestIndexCache.mu.Unlock()
+
+	if suggestIndexCache.index == nil {
+		suggestIndexCache.index = s.buildSuggestIndex()
+		return suggestIndexCache.index
+	}
+	if time.Since(suggestIndexCache.index.builtAt) >= s.opts.suggestRefresh && !suggestIndexCache.building {
+		suggestIndexCache.building = true
+		go func() {
+			index := s.buildSuggestIndex()
+			suggestIndexCache.mu.Lock()
+			suggestIndexCache.index = index
+			suggestIndexCache.building = false
+			suggestIndexCache.mu.Unlock()
+		}()
+	}
+	return suggestIndexCache.index
+}
+
+// WarmSuggestIndex 预先建立搜索建议索引，避免第一次请求时等待
+func (s *SearchService) WarmSuggestIndex() {
+	s.currentSuggestIndex()
+}
+
+// buildSuggestIndex 从事件标题、标签、实体名称和热门搜索词建立补全项，从这些名称和最近新闻标题统计纠错词汇；
+// 读取失败的来源跳过，只记录日志
+func (s *SearchService) buildSuggestIndex() *suggestIndex {
+	start := time.Now()
+	index := &suggestIndex{
+		entries:       make([]suggestEntry, 0),
+		vocabulary:    make(map[string]int),
+		wordsByLength: make(map[int][]suggestWord),
+	}
+	add := func(text string, weight float64, suggestion models.SearchSuggestion) {
+		key := normalizeSearchText(text)
+		if key == "" {
+			return
+		}
+		suggestion.Text = strings.TrimSpace(text)
+		index.entries = append(index.entries, suggestEntry{
+			key:        key,
+			score:      suggestionWeights[suggestion.Type] * math.Log(2+math.Max(weight, 0)),
+			suggestion: suggestion,
+		})
+	}
+	// 名称整体也作为词，未登录词典的人名、机构名可以按整体纠正
+	addWords := func(text string, whole bool) {
+		for _, token := range searchTokens(text) {
+			index.vocabulary[token]++
+		}
+		if key := normalizeSearchText(text); whole && key != "" && !strings.Contains(key, " ") {
+			index.vocabulary[key]++
+		}
+	}
+
+	var events []models.Event
+	if err := s.db.Select("id", "title", "hotness_score").
+		Order("hotness_score DESC, id DESC").
+		Limit(suggestSourceLimit).
+		Find(&events).Error; err != nil {
+		log.Printf("[SEARCH WARNING] failed to load events for suggestions: %v", err)
+	}
+	for _, event := range events {
+		add(event.Title, event.HotnessScore, models.SearchSuggestion{Type: models.SuggestionTypeEvent, ID: event.ID})
+		addWords(event.Title, false)
+	}
+
+	var tags []models.Tag
+	if err := s.db.Select("id", "slug", "name", "news_count", "event_count").
+		Where("news_count + event_count > 0").
+		Order("news_count + event_count DESC, id ASC").
+		Limit(suggestSourceLimit).
+		Find(&tags).Error; err != nil {
+		log.Printf("[SEARCH WARNING] failed to load tags for suggestions: %v", err)
+	}
+	for _, tag := range tags {
+		add(tag.Name, float64(tag.NewsCount+tag.EventCount), models.SearchSuggestion{Type: models.SuggestionTypeTag, ID: tag.ID, Slug: tag.Slug})
+		addWords(tag.Name, true)
+	}
+
+	// 实体按提到它的新闻数排序
+	var mentions []struct {
+		EntityID uint
+		Count    int64
+	}
+	if err := s.db.Table("news_entities").
+		Select("entity_id, COUNT(*) AS count").
+		Group("entity_id").
+		Order("count DESC").
+		Limit(suggestSourceLimit).
+		Scan(&mentions).Error; err != nil {
+		log.Printf("[SEARCH WARNING] failed to count entity mentions for suggestions: %v", err)
+	}
+	if len(mentions) > 0 {
+		counts := make(map[uint]int64, len(mentions))
+		ids := make([]uint, len(mentions))
+		for i, mention := range mentions {
+			counts[mention.EntityID] = mention.Count
+			ids[i] = mention.EntityID
+		}
+		var entities []models.Entity
+		if err := s.db.Select("id", "type", "name").Where("id IN ?", ids).Find(&entities).Error; err != nil {
+			log.Printf("[SEARCH WARNING] failed to load entities for suggestions: %v", err)
+		}
+		for _, entity := range entities {
+			add(entity.Name, float64(counts[entity.ID]), models.SearchSuggestion{Type: models.SuggestionTypeEntity, ID: entity.ID, EntityType: entity.Type})
+			addWords(entity.Name, true)
+		}
+	}
+
+	// 最近一段时间内有结果的热门搜索词
+	var queries []struct {
+		Query string
+		Count int64
+	}
+	if err := s.db.Model(&models.SearchQueryLog{}).
+		Select("query, COUNT(*) AS count").
+		Where("created_at >= ? AND result_count > 0", time.Now().AddDate(0, 0, -s.opts.popularDays)).
+		Group("query").
+		Having("COUNT(*) >= ?", minPopularQueryCount).
+		Order("count DESC").
+		Limit(suggestSourceLimit).
+		Scan(&queries).Error; err != nil {
+		log.Printf("[SEARCH WARNING] failed to load popular queries for suggestions: %v", err)
+	}
+	for _, query := range queries {
+		add(query.Query, float64(query.Count), models.SearchSuggestion{Type: models.SuggestionTypeQuery})
+	}
+
+	var newsList []models.News
+	if err := s.db.Select("id", "title").
+		Scopes(publishedNews).
+		Order("id DESC").
+		Limit(suggestVocabularyNews).
+		Find(&newsList).Error; err != nil {
+		log.Printf("[SEARCH WARNING] failed to load news titles for suggestions: %v", err)
+	}
+	for _, news := range newsList {
+		addWords(news.Title, false)
+	}
+
+	sort.Slice(index.entries, func(i, j int) bool {
+		if index.entries[i].key != index.entries[j].key {
+			return index.entries[i].key < index.entries[j].key
+		}
+		return index.entries[i].score > index.entries[j].score
+	})
+	for word, count := range index.vocabulary {
+		if count < minCorrectionWordCount {
+			continue
+		}
+		runes := []rune(word)
+		index.wordsByLength[len(runes)] = append(index.wordsByLength[len(runes)], suggestWord{text: word, runes: runes, count: count})
+	}
+
+	// 过期的搜索记录在重建索引时清理
+	if err := s.db.Where("created_at < ?", time.Now().AddDate(0, 0, -s.opts.logRetentionDays)).
+		Delete(&models.SearchQueryLog{}).Error; err != nil {
+		log.Printf("[SEARCH WARNING] failed to prune search query logs: %v", err)
+	}
+
+	index.builtAt = time.Now()
+	log.Printf("[SEARCH] built suggestion index: %d entries, %d words in %s",
+		len(index.entries), len(index.vocabulary), time.Since(start))
+	return index
+}
+
+// complete 前缀匹配的补全，按得分从高到低排列，相同文本只保留得分最高的一项
+func (idx *suggestIndex) complete(prefix string, limit int) []models.SearchSuggestion {
+	suggestions := make([]models.SearchSuggestion, 0, limit)
+	if prefix == "" {
+		return suggestions
+	}
+
+	// 只保留得分最高的 limit 项，按得分从高到低插入；相同文本的项在索引中相邻，得分最高的排在最前
+	first := sort.Search(len(idx.entries), func(i int) bool { return idx.entries[i].key >= prefix })
+	top := make([]int, 0, limit+1)
+	for i := first; i < len(idx.entries) && i-first < suggestMaxScan && strings.HasPrefix(idx.entries[i].key, prefix); i++ {
+		if i > first && idx.entries[i-1].key == idx.entries[i].key {
+			continue
+		}
+		pos := len(top)
+		for pos > 0 && suggestBefore(idx.entries[i], idx.entries[top[pos-1]]) {
+			pos--
+		}
+		if pos >= limit {
+			continue
+		}
+		top = append(top, 0)
+		copy(top[pos+1:], top[pos:])
+		top[pos] = i
+		if len(top) > limit {
+			top = top[:limit]
+		}
+	}
+
+	for _, i := range top {
+		suggestions = append(suggestions, idx.entries[i].suggestion)
+	}
+	return suggestions
+}
+
+// suggestBefore 得分高的排在前面，得分相同时较短的文本排在前面
+func suggestBefore(a, b suggestEntry) bool {
+	if a.score != b.score {
+		return a.score > b.score
+	}
+	return len(a.key) < len(b.key)
+}
+
+// correct 逐个纠正搜索语句中不在语料中的词，返回纠正后的语句；字段条件和引号内的短语保持不变，没有需要纠正的词时返回空字符串
+func (idx *suggestIndex) correct(query string) string {
+	fields := strings.Fields(nlp.ToHalfWidth(query))
+	changed := false
+	for i, field := range fields {
+		negation, word := "", field
+		if strings.HasPrefix(word, "-") {
+			negation, word = "-", word[1:]
+		}
+		if word == "" || strings.ContainsAny(word, `:"“”`) {
+			continue
+		}
+		if corrected, ok := idx.correctWord(strings.ToLower(word)); ok {
+			fields[i] = negation + corrected
+			changed = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+	return strings.Join(fields, " ")
+}
+
+// correctWord 在长度接近的词中找编辑距离最小的词，距离相同时取出现次数最多的
+func (idx *suggestIndex) correctWord(word string) (string, bool) {
+	if idx.vocabulary[word] > 0 {
+		return "", false
+	}
+	tokens := searchTokens(word)
+	if len(tokens) == 0 {
+		return "", false
+	}
+	known := true
+	for _, token := range tokens {
+		if idx.vocabulary[token] == 0 {
+			known = false
+			break
+		}
+	}
+	if known {
+		return "", false
+	}
+
+	runes := []rune(word)
+	maxDistance := correctionDistance(runes)
+	if maxDistance == 0 {
+		return "", false
+	}
+
+	var best suggestWord
+	bestDistance := maxDistance + 1
+	for length := len(runes) - maxDistance; length <= len(runes)+maxDistance; length++ {
+		for _, candidate := range idx.wordsByLength[length] {
+			distance := editDistance(runes, candidate.runes, maxDistance+1)
+			if distance < bestDistance || (distance == bestDistance && distance <= maxDistance && candidate.count > best.count) {
+				best, bestDistance = candidate, distance
+			}
+		}
+	}
+	if bestDistance > maxDistance {
+		return "", false
+	}
+	return best.text, true
+}
+
+// correctionDistance 允许纠正的最大编辑距离：字母数字词 3-4 个字符允许 1 处，更长的允许 2 处；
+// 中文词至少 3 个字才纠正 1 处，两个字的词改一个字往往是另一个意思
+func correctionDistance(runes []rune) int {
+	han := false
+	for _, r := range runes {
+		if unicode.Is(unicode.Han, r) {
+			han = true
+			break
+		}
+	}
+	switch {
+	case han && len(runes) >= 3:
+		return 1
+	case han || len(runes) < 3:
+		return 0
+	case len(runes) <= 4:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance 编辑距离，相邻两个字符互换算一次编辑；超过 limit 时提前返回 limit
+func editDistance(a, b []rune, limit int) int {
+	if abs(len(a)-len(b)) >= limit {
+		return limit
+	}
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin >= limit {
+			return limit
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return min(prev[len(b)], limit)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Suggest 搜索建议：按前缀补全事件标题、标签、实体名称和热门搜索词，并给出拼写纠正；
+// 没有补全结果时按纠正后的语句补全
+func (s *SearchService) Suggest(req *models.SuggestRequest) (*models.SuggestResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, errors.New("search query cannot be empty")
+	}
+	if req.Limit <= 0 || req.Limit > 20 {
+		req.Limit = 10
+	}
+
+	index := s.currentSuggestIndex()
+	response := &models.SuggestResponse{
+		Query:       query,
+		Suggestions: index.complete(normalizeSearchText(query), req.Limit),
+		DidYouMean:  index.correct(query),
+	}
+	if len(response.Suggestions) == 0 && response.DidYouMean != "" {
+		response.Suggestions = index.complete(normalizeSearchText(response.DidYouMean), req.Limit)
+	}
+	return response, nil
+}
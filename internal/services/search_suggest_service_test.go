@@ -0,0 +1,33 @@
+package services
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"kitten", "sitting", 10, 3},
+		{"abc", "abc", 3, 0},
+		{"", "abc", 5, 3},
+		{"ab", "ba", 5, 1},
+		{"芯片", "片芯", 5, 1},
+		{"人工智能", "人工只能", 2, 1},
+		{"abcd", "acbd", 2, 1},
+		// 只允许相邻互换一次，不能再在互换后的字符之间插入
+		{"ca", "abc", 5, 3},
+		{"abcdef", "abc", 3, 3},
+		{"abcd", "wxyz", 2, 2},
+		{"发布会", "发部会", 1, 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b), tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+		if got := editDistance([]rune(tt.b), []rune(tt.a), tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.b, tt.a, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -53,6 +53,42 @@ type NewsJSONData struct {
 	IsProcessed  bool    `json:"is_processed"`
 }
 
+// toNews 将JSON中的新闻数据转换为新闻模型
+func (d NewsJSONData) toNews(publishedAt time.Time) models.News {
+	// 转换SourceType
+	var sourceType models.NewsType = models.NewsTypeManual
+	if d.SourceType == "rss" {
+		sourceType = models.NewsTypeRSS
+	}
+
+	return models.News{
+		Title:        d.Title,
+		Content:      d.Content,
+		Summary:      d.Summary,
+		Description:  d.Description,
+		Source:       d.Source,
+		Category:     d.Category,
+		PublishedAt:  publishedAt,
+		CreatedBy:    d.CreatedBy,
+		IsActive:     d.IsActive,
+		SourceType:   sourceType,
+		RSSSourceID:  d.RSSSourceID,
+		Link:         d.Link,
+		GUID:         d.GUID,
+		Author:       d.Author,
+		ImageURL:     d.ImageURL,
+		Tags:         d.Tags,
+		Language:     d.Language,
+		ViewCount:    d.ViewCount,
+		LikeCount:    d.LikeCount,
+		CommentCount: d.CommentCount,
+		ShareCount:   d.ShareCount,
+		HotnessScore: d.HotnessScore,
+		Status:       d.Status,
+		IsProcessed:  d.IsProcessed,
+	}
+}
+
 // SeedNewsFromJSON 从JSON文件导入新闻数据
 func (s *SeedService) SeedNewsFromJSON(jsonFilePath string) error {
 	log.Printf("开始从文件 %s 导入新闻数据...", jsonFilePath)
@@ -115,39 +151,8 @@ func (s *SeedService) SeedNewsFromJSON(jsonFilePath string) error {
 			continue
 		}
 
-		// 转换SourceType
-		var sourceType models.NewsType = models.NewsTypeManual
-		if newsData.SourceType == "rss" {
-			sourceType = models.NewsTypeRSS
-		}
-
 		// 创建新闻记录
-		news := models.News{
-			Title:        newsData.Title,
-			Content:      newsData.Content,
-			Summary:      newsData.Summary,
-			Description:  newsData.Description,
-			Source:       newsData.Source,
-			Category:     newsData.Category,
-			PublishedAt:  publishedAt,
-			CreatedBy:    newsData.CreatedBy,
-			IsActive:     newsData.IsActive,
-			SourceType:   sourceType,
-			RSSSourceID:  newsData.RSSSourceID,
-			Link:         newsData.Link,
-			GUID:         newsData.GUID,
-			Author:       newsData.Author,
-			ImageURL:     newsData.ImageURL,
-			Tags:         newsData.Tags,
-			Language:     newsData.Language,
-			ViewCount:    newsData.ViewCount,
-			LikeCount:    newsData.LikeCount,
-			CommentCount: newsData.CommentCount,
-			ShareCount:   newsData.ShareCount,
-			HotnessScore: newsData.HotnessScore,
-			Status:       newsData.Status,
-			IsProcessed:  newsData.IsProcessed,
-		}
+		news := newsData.toNews(publishedAt)
 
 		newsList = append(newsList, news)
 		importedCount++
@@ -184,6 +189,15 @@ func (s *SeedService) batchInsertNews(newsList []models.News) error {
 		if err := tx.CreateInBatches(newsList, 50).Error; err != nil {
 			return err
 		}
+		// 关联导入新闻的标签并建立搜索索引
+		for i := range newsList {
+			if err := linkNewsTags(tx, &newsList[i]); err != nil {
+				return err
+			}
+			if err := indexNews(tx, &newsList[i]); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 }
@@ -291,6 +305,26 @@ func (s *SeedService) SeedDefaultData() error {
 		return err
 	}
 
+	// 初始化事件聚类词库
+	if err := NewTaxonomyService().SeedDefaults(); err != nil {
+		return err
+	}
+
+	// 初始化分类并从已有的分类字符串迁移
+	if err := NewCategoryService().SeedDefaults(); err != nil {
+		return err
+	}
+
+	// 将旧数据中的中文事件状态迁移为状态代码
+	if err := NewEventService().MigrateLegacyEventStatuses(); err != nil {
+		return err
+	}
+
+	// 从新闻和事件已有的标签字符串迁移标签
+	if err := NewTagService().SeedFromLegacy(); err != nil {
+		return err
+	}
+
 	// 可以在这里添加其他默认数据的初始化
 	// 例如：默认分类、默认RSS源等
 
@@ -0,0 +1,492 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxSentencesPerNews 生成事件描述时每篇新闻最多取的句子数，限制句子图的规模
+	maxSentencesPerNews = 20
+	// maxEventSentences 生成事件描述时参与排序的句子总数上限
+	maxEventSentences = 300
+	// summaryBatchSize 重新生成摘要时每批处理的记录数
+	summaryBatchSize = 200
+)
+
+// summaryOptions 新闻摘要和事件描述的参数，来自配置文件，未配置的项使用默认值
+type summaryOptions struct {
+	news  nlp.SummaryOptions
+	event nlp.SummaryOptions
+}
+
+func loadSummaryOptions() summaryOptions {
+	opts := summaryOptions{
+		news:  nlp.SummaryOptions{MaxSentences: 2, MaxChars: 150},
+		event: nlp.SummaryOptions{MaxSentences: 3, MaxChars: 300},
+	}
+
+	if config.AppConfig == nil {
+		return opts
+	}
+
+	cfg := config.AppConfig.Summary
+	if cfg.NewsMaxSentences > 0 {
+		opts.news.MaxSentences = cfg.NewsMaxSentences
+	}
+	if cfg.NewsMaxChars > 0 {
+		opts.news.MaxChars = cfg.NewsMaxChars
+	}
+	if cfg.EventMaxSentences > 0 {
+		opts.event.MaxSentences = cfg.EventMaxSentences
+	}
+	if cfg.EventMaxChars > 0 {
+		opts.event.MaxChars = cfg.EventMaxChars
+	}
+	if cfg.MinSentenceChars > 0 {
+		opts.news.MinSentenceChars = cfg.MinSentenceChars
+		opts.event.MinSentenceChars = cfg.MinSentenceChars
+	}
+	return opts
+}
+
+// summaryPipeline 摘要使用的分词器，按词库版本缓存，避免每条新闻都重建词典
+var summaryPipeline struct {
+	mu       sync.Mutex
+	pipeline *textPipeline
+	version  uint
+}
+
+// SummaryService 摘要服务
+// 内置抽取式摘要对句子做 TextRank 排序，选出最核心的句子；配置了外部模型时优先使用模型生成，失败时退回抽取式摘要
+type SummaryService struct {
+	db       *gorm.DB
+	opts     summaryOptions
+	provider TextProvider
+}
+
+func NewSummaryService() *SummaryService {
+	return NewSummaryServiceWithProvider(GetTextProvider())
+}
+
+// NewSummaryServiceWithProvider 使用指定的文本生成服务创建摘要服务
+func NewSummaryServiceWithProvider(provider TextProvider) *SummaryService {
+	return &SummaryService{
+		db:       database.GetDB(),
+		opts:     loadSummaryOptions(),
+		provider: provider,
+	}
+}
+
+// SummarizeNews 从新闻正文或描述中抽取摘要，正文过短时返回空字符串
+func (s *SummaryService) SummarizeNews(news *models.News) string {
+	sentences := newsSentences(news, 0)
+	selected := nlp.Summarize(sentences, s.tokenizer(), s.opts.news)
+	return joinSummary(selected, s.opts.news.MaxChars)
+}
+
+// SummarizeEvent 从事件关联的多篇新闻中抽取最核心的句子作为事件描述
+func (s *SummaryService) SummarizeEvent(newsList []models.News) string {
+	sentences := make([]string, 0)
+	for i := range newsList {
+		sentences = append(sentences, newsSentences(&newsList[i], maxSentencesPerNews)...)
+		if len(sentences) >= maxEventSentences {
+			sentences = sentences[:maxEventSentences]
+			break
+		}
+	}
+
+	selected := nlp.Summarize(sentences, s.tokenizer(), s.opts.event)
+	return joinSummary(selected, s.opts.event.MaxChars)
+}
+
+// GenerateNewsSummary 使用外部模型生成新闻摘要
+// 未配置模型或模型返回不可重试的错误时使用抽取式摘要；可重试的错误原样返回，由调用方稍后重试
+func (s *SummaryService) GenerateNewsSummary(ctx context.Context, news *models.News) (string, error) {
+	text := strings.Join(newsSentences(news, 0), "")
+	if text != "" {
+		summary, err := s.provider.Generate(ctx, TextRequest{
+			Task:     TextTaskNewsSummary,
+			Title:    news.Title,
+			Text:     text,
+			MaxChars: s.opts.news.MaxChars,
+		})
+		if err == nil {
+			return summary, nil
+		}
+		if isRetryable(err) {
+			return "", err
+		}
+		if !errors.Is(err, ErrTextProviderDisabled) {
+			log.Printf("[SUMMARY WARNING] text provider failed for news %d, using extractive summary: %v", news.ID, err)
+		}
+	}
+
+	return s.SummarizeNews(news), nil
+}
+
+// GenerateEventText 使用外部模型生成事件标题和描述
+// 未配置模型时标题为空、描述使用抽取式摘要；模型失败时同样退回
+func (s *SummaryService) GenerateEventText(ctx context.Context, newsList []models.News) (string, string) {
+	titles := make([]string, 0, len(newsList))
+	var body strings.Builder
+	for i := range newsList {
+		titles = append(titles, newsList[i].Title)
+		body.WriteString(newsList[i].Title)
+		body.WriteString("\n")
+		body.WriteString(strings.Join(newsSentences(&newsList[i], 5), ""))
+		body.WriteString("\n\n")
+	}
+	if len(titles) == 0 {
+		return "", ""
+	}
+	text := body.String()
+
+	var title string
+	generated, err := s.provider.Generate(ctx, TextRequest{
+		Task:     TextTaskEventTitle,
+		Text:     strings.Join(titles, "\n"),
+		MaxChars: 30,
+	})
+	if err == nil {
+		title = generated
+	} else if !errors.Is(err, ErrTextProviderDisabled) {
+		log.Printf("[SUMMARY WARNING] text provider failed to generate event title: %v", err)
+	}
+
+	description, err := s.provider.Generate(ctx, TextRequest{
+		Task:     TextTaskEventDescription,
+		Text:     text,
+		MaxChars: s.opts.event.MaxChars,
+	})
+	if err != nil {
+		if !errors.Is(err, ErrTextProviderDisabled) {
+			log.Printf("[SUMMARY WARNING] text provider failed to generate event description: %v", err)
+		}
+		description = s.SummarizeEvent(newsList)
+	}
+
+	return title, description
+}
+
+// ProcessPendingNews 为未处理的新闻生成摘要并标记为已处理，由后台任务定期调用
+// 手动创建且已填写摘要的新闻直接标记为已处理；外部模型暂时不可用的新闻留待下一轮
+func (s *SummaryService) ProcessPendingNews(ctx context.Context, limit int) (*models.PendingNewsResult, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var pending []models.News
+	if err := s.db.Select("id", "title", "content", "description", "summary", "source_type").
+		Where("is_processed = ?", false).
+		Order("id ASC").
+		Limit(limit).
+		Find(&pending).Error; err != nil {
+		return nil, err
+	}
+
+	result := &models.PendingNewsResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, pendingNewsWorkers())
+	for i := range pending {
+		news := &pending[i]
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			updates := map[string]interface{}{"is_processed": true}
+			if news.SourceType != models.NewsTypeManual || news.Summary == "" {
+				summary, err := s.GenerateNewsSummary(ctx, news)
+				if err != nil {
+					log.Printf("[SUMMARY WARNING] deferring news %d: %v", news.ID, err)
+					mu.Lock()
+					result.Deferred++
+					mu.Unlock()
+					return
+				}
+				if summary != "" {
+					updates["summary"] = summary
+				}
+			}
+
+			if err := s.db.Model(&models.News{}).Where("id = ?", news.ID).UpdateColumns(updates).Error; err != nil {
+				log.Printf("[SUMMARY ERROR] failed to save summary for news %d: %v", news.ID, err)
+				mu.Lock()
+				result.Deferred++
+				mu.Unlock()
+				return
+			}
+			if _, ok := updates["summary"]; ok {
+				if err := reindexNews(s.db, news.ID); err != nil {
+					log.Printf("[SUMMARY WARNING] failed to index news %d for search: %v", news.ID, err)
+				}
+			}
+			mu.Lock()
+			result.Processed++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return result, nil
+}
+
+// RegenerateSummaries 重新生成新闻摘要和事件描述，每处理完一条新闻或一个事件汇报一次进度
+// 手动创建的新闻只在摘要为空时填充，不覆盖编辑填写的摘要；ctx 取消时停止并返回已完成部分的结果
+func (s *SummaryService) RegenerateSummaries(ctx context.Context, req *models.RegenerateSummariesRequest, progress func(done, total int, partial *models.RegenerateSummariesResult)) (*models.RegenerateSummariesResult, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	target := req.Target
+	if target == "" {
+		target = models.SummaryTargetAll
+	}
+	ids := req.IDs
+	if target == models.SummaryTargetAll {
+		ids = nil
+	}
+	withNews := target == models.SummaryTargetNews || target == models.SummaryTargetAll
+	withEvents := target == models.SummaryTargetEvents || target == models.SummaryTargetAll
+
+	startTime := time.Now()
+	result := &models.RegenerateSummariesResult{}
+
+	var newsTotal, eventTotal int64
+	if withNews {
+		if err := s.newsToSummarize(ids, req.OnlyEmpty).Count(&newsTotal).Error; err != nil {
+			return nil, err
+		}
+	}
+	if withEvents {
+		if err := s.eventsToDescribe(ids, req.OnlyEmpty).Count(&eventTotal).Error; err != nil {
+			return nil, err
+		}
+	}
+	done, total := 0, int(newsTotal+eventTotal)
+	step := func() error {
+		done++
+		if progress != nil {
+			progress(done, total, result)
+		}
+		return ctx.Err()
+	}
+
+	var err error
+	if withNews {
+		err = s.regenerateNewsSummaries(ctx, ids, req.OnlyEmpty, result, step)
+	}
+	if err == nil && withEvents {
+		err = s.regenerateEventDescriptions(ctx, ids, req.OnlyEmpty, req.Titles, result, step)
+	}
+	result.Duration = time.Since(startTime).String()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return result, err
+		}
+		return nil, err
+	}
+
+	log.Printf("[SUMMARY] Regenerated %d news summaries and %d event descriptions in %s",
+		result.NewsUpdated, result.EventsUpdated, result.Duration)
+	return result, nil
+}
+
+// newsToSummarize 需要重新生成摘要的新闻
+func (s *SummaryService) newsToSummarize(ids []uint, onlyEmpty bool) *gorm.DB {
+	db := s.db.Model(&models.News{})
+	if len(ids) > 0 {
+		db = db.Where("id IN ?", ids)
+	}
+	if onlyEmpty {
+		return db.Where("summary IS NULL OR summary = ''")
+	}
+	return db.Where("source_type = ? OR summary IS NULL OR summary = ''", models.NewsTypeRSS)
+}
+
+// eventsToDescribe 需要重新生成描述的事件
+func (s *SummaryService) eventsToDescribe(ids []uint, onlyEmpty bool) *gorm.DB {
+	db := s.db.Model(&models.Event{})
+	if len(ids) > 0 {
+		db = db.Where("id IN ?", ids)
+	}
+	if onlyEmpty {
+		db = db.Where("description IS NULL OR description = ''")
+	}
+	return db
+}
+
+func (s *SummaryService) regenerateNewsSummaries(ctx context.Context, ids []uint, onlyEmpty bool, result *models.RegenerateSummariesResult, step func() error) error {
+	var batch []models.News
+	return s.newsToSummarize(ids, onlyEmpty).Select("id", "title", "content", "description", "summary").
+		FindInBatches(&batch, summaryBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				summary, err := s.GenerateNewsSummary(ctx, &batch[i])
+				if err != nil {
+					if ctxErr := ctx.Err(); ctxErr != nil {
+						return ctxErr
+					}
+					log.Printf("[SUMMARY WARNING] text provider unavailable for news %d, using extractive summary: %v", batch[i].ID, err)
+					summary = s.SummarizeNews(&batch[i])
+				}
+				switch {
+				case summary == "":
+					result.NewsSkipped++
+				case summary != batch[i].Summary:
+					if err := s.db.Model(&models.News{}).Where("id = ?", batch[i].ID).
+						UpdateColumn("summary", summary).Error; err != nil {
+						return err
+					}
+					if err := reindexNews(s.db, batch[i].ID); err != nil {
+						return err
+					}
+					result.NewsUpdated++
+				}
+				if err := step(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+func (s *SummaryService) regenerateEventDescriptions(ctx context.Context, ids []uint, onlyEmpty, titles bool, result *models.RegenerateSummariesResult, step func() error) error {
+	var batch []models.Event
+	return s.eventsToDescribe(ids, onlyEmpty).Select("id", "title", "description").
+		FindInBatches(&batch, summaryBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, event := range batch {
+				if err := s.regenerateEventDescription(ctx, &event, titles, result); err != nil {
+					return err
+				}
+				if err := step(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// regenerateEventDescription 按事件当前展示的新闻重新生成一个事件的描述，titles 为 true 时同时更新标题
+func (s *SummaryService) regenerateEventDescription(ctx context.Context, event *models.Event, titles bool, result *models.RegenerateSummariesResult) error {
+	var newsList []models.News
+	if err := s.db.Select("id", "title", "content", "description", "summary").
+		Where("belonged_event_id = ? AND is_active = ?", event.ID, true).
+		Order("published_at ASC").
+		Find(&newsList).Error; err != nil {
+		return err
+	}
+
+	title, description := s.GenerateEventText(ctx, newsList)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if description == "" {
+		result.EventsSkipped++
+		return nil
+	}
+
+	updates := make(map[string]interface{})
+	if description != event.Description {
+		updates["description"] = description
+	}
+	if titles && title != "" && title != event.Title {
+		updates["title"] = title
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := s.db.Model(&models.Event{}).Where("id = ?", event.ID).
+		UpdateColumns(updates).Error; err != nil {
+		return err
+	}
+	if err := reindexEvents(s.db, event.ID); err != nil {
+		log.Printf("[SUMMARY WARNING] failed to index event %d for search: %v", event.ID, err)
+	}
+	result.EventsUpdated++
+	return nil
+}
+
+// pendingNewsWorkers 后台处理新闻的并发数，与外部模型的并发上限一致
+func pendingNewsWorkers() int {
+	if config.AppConfig != nil && config.AppConfig.LLM.Concurrency > 0 {
+		return config.AppConfig.LLM.Concurrency
+	}
+	return 2
+}
+
+// tokenizer 返回按当前词库分词并过滤停用词的函数，词库版本变化时重建分词器
+func (s *SummaryService) tokenizer() func(string) []string {
+	var version uint
+	if taxonomy, err := NewTaxonomyService().CurrentTaxonomy(); err == nil {
+		version = taxonomy.Version
+	}
+
+	summaryPipeline.mu.Lock()
+	if summaryPipeline.pipeline == nil || summaryPipeline.version != version {
+		summaryPipeline.pipeline = newTextPipeline(loadClusteringOptions())
+		summaryPipeline.version = version
+	}
+	pipeline := summaryPipeline.pipeline
+	summaryPipeline.mu.Unlock()
+
+	return func(sentence string) []string {
+		return pipeline.segmenter.Tokens(sentence, pipeline.stopWords)
+	}
+}
+
+// newsSentences 取正文和描述中较长的一段切分为句子，去掉与标题相同的句子；limit 为 0 时不限句数
+func newsSentences(news *models.News, limit int) []string {
+	content := nlp.StripHTML(news.Content)
+	description := nlp.StripHTML(news.Description)
+	text := content
+	if len([]rune(strings.TrimSpace(description))) > len([]rune(strings.TrimSpace(content))) {
+		text = description
+	}
+
+	title := strings.TrimSpace(news.Title)
+	sentences := make([]string, 0)
+	for _, sentence := range nlp.SplitSentences(text) {
+		if sentence == title {
+			continue
+		}
+		sentences = append(sentences, sentence)
+		if limit > 0 && len(sentences) >= limit {
+			break
+		}
+	}
+	return sentences
+}
+
+// joinSummary 拼接选出的句子，超过最大字数时截断
+func joinSummary(sentences []string, maxChars int) string {
+	var b strings.Builder
+	for i, sentence := range sentences {
+		// 英文句子之间保留空格
+		if i > 0 && sentences[i-1][len(sentences[i-1])-1] < utf8.RuneSelf {
+			b.WriteByte(' ')
+		}
+		b.WriteString(sentence)
+	}
+	summary := b.String()
+	if maxChars > 0 && len([]rune(summary)) > maxChars {
+		summary = truncateRunes(summary, maxChars) + "…"
+	}
+	return summary
+}
@@ -0,0 +1,847 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxTagRunes 标签名称的最大长度，更长的名称截断后保存
+	maxTagRunes = 100
+	// tagRewriteBatch 标签改名、合并或删除后每批改写标签字符串的新闻或事件数
+	tagRewriteBatch = 500
+)
+
+// tagLink 新闻或事件与标签的关联表
+type tagLink struct {
+	table       string // 关联表
+	ownerTable  string // 新闻或事件表
+	ownerColumn string // 关联表中的新闻或事件ID列
+	countColumn string // tags 表中对应的计数列
+}
+
+var (
+	newsTagLink  = tagLink{table: "news_tags", ownerTable: "news", ownerColumn: "news_id", countColumn: "news_count"}
+	eventTagLink = tagLink{table: "event_tags", ownerTable: "events", ownerColumn: "event_id", countColumn: "event_count"}
+)
+
+// TagService 标签管理服务
+// 标签按名称、slug 和同义词识别，新闻和事件保存标签时关联到同一个标签，标签字符串改为规范名称
+type TagService struct {
+	db *gorm.DB
+}
+
+func NewTagService() *TagService {
+	return &TagService{
+		db: database.GetDB(),
+	}
+}
+
+// SeedFromLegacy 标签表为空时从新闻和事件已有的标签字符串迁移
+func (s *TagService) SeedFromLegacy() error {
+	if s.db == nil {
+		return errors.New("database connection not initialized")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Tag{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	result, err := s.MigrateLegacyTags()
+	if err != nil {
+		return fmt.Errorf("failed to migrate tags: %w", err)
+	}
+	if result.CreatedTags > 0 {
+		log.Printf("Tags migrated, %d tags created, %d news and %d events linked", result.CreatedTags, result.LinkedNews, result.LinkedEvents)
+	}
+	return nil
+}
+
+// MigrateLegacyTags 为标签字符串中还没有对应标签的名称新建标签，然后重新关联全部新闻和事件并重新计数
+// 标签字符串可以是 JSON 数组或以逗号分隔，迁移时不改写标签字符串
+func (s *TagService) MigrateLegacyTags() (*models.MigrateTagsResult, error) {
+	start := time.Now()
+	result := &models.MigrateTagsResult{}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var names []string
+		if err := tx.Raw(`SELECT DISTINCT LEFT(TRIM(legacy.tag), ?) FROM (`+
+			legacyTagsSQL(newsTagLink)+` UNION ALL `+legacyTagsSQL(eventTagLink)+`) AS legacy
+			WHERE TRIM(legacy.tag) <> ''`, maxTagRunes).Scan(&names).Error; err != nil {
+			return err
+		}
+
+		known, err := lookupTags(tx, names)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			key := tagKey(name)
+			if _, ok := known[key]; ok || key == "" {
+				continue
+			}
+			id, err := createTag(tx, name)
+			if err != nil {
+				return err
+			}
+			known[key] = id
+			result.CreatedTags++
+		}
+
+		for _, link := range []tagLink{newsTagLink, eventTagLink} {
+			if err := tx.Exec("DELETE FROM " + link.table).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`INSERT INTO `+link.table+` (`+link.ownerColumn+`, tag_id, position)
+				SELECT legacy.owner_id, tag_aliases.tag_id, MIN(legacy.ord) - 1
+				FROM (`+legacyTagsSQL(link)+`) AS legacy
+				JOIN tag_aliases ON tag_aliases.key = LOWER(TRIM(LEFT(TRIM(legacy.tag), ?)))
+				GROUP BY legacy.owner_id, tag_aliases.tag_id`, maxTagRunes).Error; err != nil {
+				return err
+			}
+		}
+		if err := recountTags(tx); err != nil {
+			return err
+		}
+
+		if err := tx.Table(newsTagLink.table).Distinct(newsTagLink.ownerColumn).Count(&result.LinkedNews).Error; err != nil {
+			return err
+		}
+		return tx.Table(eventTagLink.table).Distinct(eventTagLink.ownerColumn).Count(&result.LinkedEvents).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Duration = time.Since(start).String()
+	return result, nil
+}
+
+// GetTags 获取标签列表，按关联的新闻和事件数从多到少排序
+func (s *TagService) GetTags(query *models.TagQueryRequest) ([]models.TagInfo, int64, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	db := s.db.Model(&models.Tag{})
+	if key := tagKey(query.Query); key != "" {
+		db = db.Where("id IN (?)", s.db.Table("tag_aliases").Select("tag_id").Where("key LIKE ?", "%"+key+"%"))
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tags []models.Tag
+	if err := db.Preload("Aliases").
+		Order("news_count + event_count DESC, id ASC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&tags).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.TagInfo, 0, len(tags))
+	for i := range tags {
+		items = append(items, convertToTagInfo(&tags[i]))
+	}
+	return items, total, nil
+}
+
+// GetTag 获取标签页：标签信息和分页的相关事件、相关新闻
+// slug 也可以是标签的名称或同义词，被合并的标签原来的地址仍然有效
+func (s *TagService) GetTag(slug string, query *models.TagDetailQueryRequest) (*models.TagDetailResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	var tag models.Tag
+	err := s.db.Preload("Aliases").Where("slug = ?", slug).First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = s.db.Preload("Aliases").
+			Where("id = (?)", s.db.Table("tag_aliases").Select("tag_id").Where("key = ?", tagKey(slug))).
+			First(&tag).Error
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+
+	response := &models.TagDetailResponse{
+		Tag:    convertToTagInfo(&tag),
+		Events: make([]models.EventResponse, 0),
+		News:   make([]models.NewsResponse, 0),
+		Page:   query.Page,
+		Limit:  query.Limit,
+	}
+	offset := (query.Page - 1) * query.Limit
+
+	var events []models.Event
+	if err := s.db.Joins("JOIN event_tags ON event_tags.event_id = events.id").
+		Where("event_tags.tag_id = ?", tag.ID).
+		Order("events.hotness_score DESC, events.id DESC").
+		Offset(offset).
+		Limit(query.Limit).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	for i := range events {
+		item := convertToEventResponse(&events[i])
+		item.Content = ""
+		response.Events = append(response.Events, item)
+	}
+
+	var newsList []models.News
+	if err := s.db.Joins("JOIN news_tags ON news_tags.news_id = news.id").
+		Where("news_tags.tag_id = ? AND news.is_active = ?", tag.ID, true).
+		Order("news.published_at DESC, news.id DESC").
+		Offset(offset).
+		Limit(query.Limit).
+		Find(&newsList).Error; err != nil {
+		return nil, err
+	}
+	for _, news := range newsList {
+		item := news.ToResponse()
+		item.Content = ""
+		response.News = append(response.News, item)
+	}
+	return response, nil
+}
+
+// UpdateTag 更新标签的名称、slug 和同义词
+// 改名后原名称作为同义词保留（同时传入 synonyms 时以传入的为准），并改写相关新闻和事件的标签字符串
+func (s *TagService) UpdateTag(id uint, req *models.UpdateTagRequest) (*models.TagInfo, error) {
+	var tag models.Tag
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Aliases").First(&tag, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("tag not found")
+			}
+			return err
+		}
+
+		synonyms := make([]string, 0)
+		slugs := make([]string, 0)
+		for _, alias := range tag.Aliases {
+			switch alias.Kind {
+			case models.TagAliasSynonym:
+				synonyms = append(synonyms, alias.Alias)
+			case models.TagAliasSlug:
+				slugs = append(slugs, alias.Alias)
+			}
+		}
+
+		renamed := false
+		if name := truncateRunes(strings.TrimSpace(req.Name), maxTagRunes); name != "" && name != tag.Name {
+			synonyms = append(synonyms, tag.Name)
+			tag.Name = name
+			renamed = true
+		}
+		if req.Slug != "" {
+			slug := slugify(req.Slug)
+			if slug == "" {
+				return errors.New("invalid tag slug")
+			}
+			if slug != tag.Slug {
+				var count int64
+				if err := tx.Model(&models.Tag{}).Where("slug = ? AND id <> ?", slug, id).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					return errors.New("tag slug already exists")
+				}
+				tag.Slug = slug
+			}
+		}
+		if req.Synonyms != nil {
+			synonyms = req.Synonyms
+		}
+
+		aliases := tagAliases(&tag, slugs, synonyms)
+		if err := checkTagAliases(tx, id, aliases); err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&models.TagAlias{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&aliases).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Aliases").Save(&tag).Error; err != nil {
+			return err
+		}
+		tag.Aliases = aliases
+
+		if !renamed {
+			return nil
+		}
+		for _, link := range []tagLink{newsTagLink, eventTagLink} {
+			owners, err := taggedOwners(tx, link, []uint{id})
+			if err != nil {
+				return err
+			}
+			if err := rewriteTagNames(tx, link, owners); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := convertToTagInfo(&tag)
+	return &info, nil
+}
+
+// MergeTags 将其他标签并入目标标签：关联、名称和同义词都转到目标标签，改写相关新闻和事件的标签字符串后删除被合并的标签
+func (s *TagService) MergeTags(id uint, req *models.MergeTagsRequest) (*models.TagInfo, error) {
+	sourceIDs := make([]uint, 0, len(req.SourceIDs))
+	for _, sourceID := range uniqueIDs(req.SourceIDs) {
+		if sourceID != id {
+			sourceIDs = append(sourceIDs, sourceID)
+		}
+	}
+	if len(sourceIDs) == 0 {
+		return nil, errors.New("no tags to merge")
+	}
+
+	var tag models.Tag
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&tag, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("tag not found")
+			}
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.Tag{}).Where("id IN ?", sourceIDs).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(sourceIDs) {
+			return errors.New("tag not found")
+		}
+
+		for _, link := range []tagLink{newsTagLink, eventTagLink} {
+			owners, err := taggedOwners(tx, link, sourceIDs)
+			if err != nil {
+				return err
+			}
+			// 同时带有两个标签的新闻或事件只保留一个关联，位置取靠前的
+			if err := tx.Exec(`INSERT INTO `+link.table+` (`+link.ownerColumn+`, tag_id, position)
+				SELECT `+link.ownerColumn+`, ?, MIN(position) FROM `+link.table+`
+				WHERE tag_id IN ? GROUP BY `+link.ownerColumn+`
+				ON CONFLICT DO NOTHING`, id, sourceIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM "+link.table+" WHERE tag_id IN ?", sourceIDs).Error; err != nil {
+				return err
+			}
+			if err := rewriteTagNames(tx, link, owners); err != nil {
+				return err
+			}
+		}
+
+		// 被合并标签的名称成为同义词，slug 保留以便旧地址跳转
+		if err := tx.Model(&models.TagAlias{}).Where("tag_id IN ?", sourceIDs).
+			Updates(map[string]interface{}{
+				"tag_id": id,
+				"kind":   gorm.Expr("CASE WHEN kind = ? THEN ? ELSE kind END", models.TagAliasName, models.TagAliasSynonym),
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Tag{}, sourceIDs).Error; err != nil {
+			return err
+		}
+		if err := recountTags(tx, id); err != nil {
+			return err
+		}
+		return tx.Preload("Aliases").First(&tag, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := convertToTagInfo(&tag)
+	return &info, nil
+}
+
+// DeleteTag 删除标签，从相关新闻和事件的标签字符串中去掉该标签
+func (s *TagService) DeleteTag(id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Tag{}, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("tag not found")
+			}
+			return err
+		}
+
+		for _, link := range []tagLink{newsTagLink, eventTagLink} {
+			owners, err := taggedOwners(tx, link, []uint{id})
+			if err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM "+link.table+" WHERE tag_id = ?", id).Error; err != nil {
+				return err
+			}
+			if err := rewriteTagNames(tx, link, owners); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&models.TagAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
+
+// linkNewsTags 按新闻的标签字符串关联标签，并把标签字符串改为标签的规范名称；已删除的新闻去掉全部关联
+func linkNewsTags(tx *gorm.DB, news *models.News) error {
+	if news.DeletedAt.Valid {
+		_, err := replaceTagLinks(tx, newsTagLink, news.ID, nil)
+		return err
+	}
+	names, err := replaceTagLinks(tx, newsTagLink, news.ID, parseTags(news.Tags))
+	if err != nil {
+		return err
+	}
+	if encoded := sliceToJSON(names); encoded != news.Tags && (news.Tags != "" || len(names) > 0) {
+		news.Tags = encoded
+		return tx.Model(news).UpdateColumn("tags", encoded).Error
+	}
+	return nil
+}
+
+// linkEventTags 按事件的标签字符串关联标签，并把标签字符串改为标签的规范名称；已删除的事件去掉全部关联
+func linkEventTags(tx *gorm.DB, event *models.Event) error {
+	if event.DeletedAt.Valid {
+		_, err := replaceTagLinks(tx, eventTagLink, event.ID, nil)
+		return err
+	}
+	names, err := replaceTagLinks(tx, eventTagLink, event.ID, parseTags(event.Tags))
+	if err != nil {
+		return err
+	}
+	if encoded := sliceToJSON(names); encoded != event.Tags && (event.Tags != "" || len(names) > 0) {
+		event.Tags = encoded
+		return tx.Model(event).UpdateColumn("tags", encoded).Error
+	}
+	return nil
+}
+
+// syncNewsTags 按数据库中新闻当前的标签字符串重新关联标签，包括已删除的新闻
+func syncNewsTags(tx *gorm.DB, newsIDs ...uint) error {
+	ids := uniqueIDs(newsIDs)
+	if len(ids) == 0 {
+		return nil
+	}
+	var newsList []models.News
+	if err := tx.Unscoped().Select("id", "tags", "deleted_at").Where("id IN ?", ids).Find(&newsList).Error; err != nil {
+		return err
+	}
+	for i := range newsList {
+		if err := linkNewsTags(tx, &newsList[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncEventTags 按数据库中事件当前的标签字符串重新关联标签，包括已删除的事件
+func syncEventTags(tx *gorm.DB, eventIDs ...uint) error {
+	ids := uniqueIDs(eventIDs)
+	if len(ids) == 0 {
+		return nil
+	}
+	var events []models.Event
+	if err := tx.Unscoped().Select("id", "tags", "deleted_at").Where("id IN ?", ids).Find(&events).Error; err != nil {
+		return err
+	}
+	for i := range events {
+		if err := linkEventTags(tx, &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceTagLinks 用 names 对应的标签替换新闻或事件原有的关联，增量更新标签的计数，返回去重后的规范名称
+// 还没有对应标签的名称新建标签，同义词对应到同一个标签
+func replaceTagLinks(tx *gorm.DB, link tagLink, ownerID uint, names []string) ([]string, error) {
+	tags, err := resolveTags(tx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var current []uint
+	if err := tx.Table(link.table).Where(link.ownerColumn+" = ?", ownerID).Pluck("tag_id", &current).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Exec("DELETE FROM "+link.table+" WHERE "+link.ownerColumn+" = ?", ownerID).Error; err != nil {
+		return nil, err
+	}
+
+	canonical := make([]string, 0, len(tags))
+	linked := make(map[uint]bool, len(tags))
+	rows := make([]map[string]interface{}, 0, len(tags))
+	for i, tag := range tags {
+		canonical = append(canonical, tag.Name)
+		linked[tag.ID] = true
+		rows = append(rows, map[string]interface{}{link.ownerColumn: ownerID, "tag_id": tag.ID, "position": i})
+	}
+	if len(rows) > 0 {
+		if err := tx.Table(link.table).Create(rows).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	previous := make(map[uint]bool, len(current))
+	removed := make([]uint, 0)
+	for _, id := range current {
+		previous[id] = true
+		if !linked[id] {
+			removed = append(removed, id)
+		}
+	}
+	added := make([]uint, 0)
+	for _, tag := range tags {
+		if !previous[tag.ID] {
+			added = append(added, tag.ID)
+		}
+	}
+	if len(added) > 0 {
+		if err := tx.Model(&models.Tag{}).Where("id IN ?", added).
+			UpdateColumn(link.countColumn, gorm.Expr(link.countColumn+" + 1")).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(removed) > 0 {
+		if err := tx.Model(&models.Tag{}).Where("id IN ?", removed).
+			UpdateColumn(link.countColumn, gorm.Expr("GREATEST("+link.countColumn+" - 1, 0)")).Error; err != nil {
+			return nil, err
+		}
+	}
+	return canonical, nil
+}
+
+// resolveTags 按名称和同义词找到标签，没有时新建；按 names 的顺序返回去重后的标签
+func resolveTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	known, err := lookupTags(tx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]uint, 0, len(names))
+	seen := make(map[uint]bool, len(names))
+	for _, name := range names {
+		name = truncateRunes(strings.TrimSpace(name), maxTagRunes)
+		key := tagKey(name)
+		if key == "" {
+			continue
+		}
+		id, ok := known[key]
+		if !ok {
+			if id, err = createTag(tx, name); err != nil {
+				return nil, err
+			}
+			known[key] = id
+		}
+		if !seen[id] {
+			seen[id] = true
+			order = append(order, id)
+		}
+	}
+	if len(order) == 0 {
+		return nil, nil
+	}
+
+	var tags []models.Tag
+	if err := tx.Where("id IN ?", order).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Tag, len(tags))
+	for _, tag := range tags {
+		byID[tag.ID] = tag
+	}
+	result := make([]models.Tag, 0, len(order))
+	for _, id := range order {
+		if tag, ok := byID[id]; ok {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// lookupTags 查询名称对应的标签ID，返回规范化名称到标签ID的映射，不新建标签
+func lookupTags(tx *gorm.DB, names []string) (map[string]uint, error) {
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		if key := tagKey(truncateRunes(strings.TrimSpace(name), maxTagRunes)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	known := make(map[string]uint, len(keys))
+	for i := 0; i < len(keys); i += tagRewriteBatch {
+		end := min(i+tagRewriteBatch, len(keys))
+		var aliases []models.TagAlias
+		if err := tx.Where("key IN ?", keys[i:end]).Find(&aliases).Error; err != nil {
+			return nil, err
+		}
+		for _, alias := range aliases {
+			known[alias.Key] = alias.TagID
+		}
+	}
+	return known, nil
+}
+
+// createTag 新建标签及其名称和 slug，返回标签ID；名称已被并发创建的标签使用时返回该标签
+// 同一名称的创建通过事务级 advisory lock 串行执行，slug 被并发占用时重新选择
+func createTag(tx *gorm.DB, name string) (uint, error) {
+	slug := slugify(name)
+	if slug == "" {
+		slug = "tag"
+	}
+
+	var id uint
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		key := tagKey(name)
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "tag:"+key).Error; err != nil {
+			return err
+		}
+		var existing []uint
+		if err := tx.Model(&models.TagAlias{}).Where("key = ?", key).Limit(1).Pluck("tag_id", &existing).Error; err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			id = existing[0]
+			return nil
+		}
+
+		tag := models.Tag{Name: name}
+		for {
+			candidate, err := uniqueTagSlug(tx, slug)
+			if err != nil {
+				return err
+			}
+			tag.Slug = candidate
+			result := tx.Omit("Aliases").
+				Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "slug"}}, DoNothing: true}).
+				Create(&tag)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				break
+			}
+		}
+
+		aliases := tagAliases(&tag, nil, nil)
+		if err := tx.Create(&aliases[0]).Error; err != nil {
+			return err
+		}
+		// slug 可能已是其他标签的同义词，此时只保留名称
+		if len(aliases) > 1 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(aliases[1:]).Error; err != nil {
+				return err
+			}
+		}
+		id = tag.ID
+		return nil
+	})
+	return id, err
+}
+
+// taggedOwners 带有指定标签的新闻或事件ID
+func taggedOwners(tx *gorm.DB, link tagLink, tagIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Table(link.table).Distinct(link.ownerColumn).Where("tag_id IN ?", tagIDs).Pluck(link.ownerColumn, &ids).Error
+	return ids, err
+}
+
+// rewriteTagNames 按当前的关联改写新闻或事件的标签字符串
+func rewriteTagNames(tx *gorm.DB, link tagLink, ownerIDs []uint) error {
+	for i := 0; i < len(ownerIDs); i += tagRewriteBatch {
+		batch := ownerIDs[i:min(i+tagRewriteBatch, len(ownerIDs))]
+
+		var rows []struct {
+			OwnerID uint
+			Name    string
+		}
+		if err := tx.Table(link.table).
+			Select(link.table+"."+link.ownerColumn+" AS owner_id, tags.name").
+			Joins("JOIN tags ON tags.id = "+link.table+".tag_id").
+			Where(link.table+"."+link.ownerColumn+" IN ?", batch).
+			Order(link.table + ".position, " + link.table + ".tag_id").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		names := make(map[uint][]string, len(batch))
+		for _, row := range rows {
+			names[row.OwnerID] = append(names[row.OwnerID], row.Name)
+		}
+
+		for _, id := range batch {
+			if err := tx.Table(link.ownerTable).Where("id = ?", id).
+				UpdateColumn("tags", sliceToJSON(names[id])).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// recountTags 按关联表重新统计标签的计数，不指定标签时统计全部标签
+func recountTags(tx *gorm.DB, ids ...uint) error {
+	db := tx.Model(&models.Tag{})
+	if len(ids) > 0 {
+		db = db.Where("id IN ?", ids)
+	} else {
+		db = db.Where("1 = 1")
+	}
+	return db.UpdateColumns(map[string]interface{}{
+		"news_count":  gorm.Expr("(SELECT COUNT(*) FROM news_tags WHERE news_tags.tag_id = tags.id)"),
+		"event_count": gorm.Expr("(SELECT COUNT(*) FROM event_tags WHERE event_tags.tag_id = tags.id)"),
+	}).Error
+}
+
+// legacyTagsSQL 展开未删除的新闻或事件的标签字符串，返回 owner_id、tag 和从 1 开始的顺序 ord
+// 以 [ 开头、] 结尾的按 JSON 数组解析，其余按逗号分隔
+func legacyTagsSQL(link tagLink) string {
+	return `SELECT owner.id AS owner_id, part.tag, part.ord FROM ` + link.ownerTable + ` AS owner
+		CROSS JOIN LATERAL (
+			SELECT value, ordinality FROM jsonb_array_elements_text(CASE WHEN owner.tags LIKE '[%]' THEN CAST(owner.tags AS jsonb) ELSE '[]' END) WITH ORDINALITY
+			UNION ALL
+			SELECT value, ordinality FROM unnest(string_to_array(CASE WHEN owner.tags LIKE '[%]' THEN '' ELSE owner.tags END, ',')) WITH ORDINALITY
+		) AS part(tag, ord)
+		WHERE owner.deleted_at IS NULL`
+}
+
+// checkTagAliases 标签的识别名称不能被其他标签使用，已有的标签需要通过合并变成同义词
+func checkTagAliases(tx *gorm.DB, id uint, aliases []models.TagAlias) error {
+	keys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		keys = append(keys, alias.Key)
+	}
+
+	var conflict models.TagAlias
+	err := tx.Where("key IN ? AND tag_id <> ?", keys, id).First(&conflict).Error
+	if err == nil {
+		return fmt.Errorf("tag name or synonym already in use: %s", conflict.Alias)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// uniqueTagSlug 生成不与其他标签重复的 slug，重复时追加序号
+func uniqueTagSlug(tx *gorm.DB, slug string) (string, error) {
+	slug = truncateRunes(slug, maxTagRunes-4)
+	candidate := slug
+	for i := 2; ; i++ {
+		var count int64
+		if err := tx.Model(&models.Tag{}).Where("slug = ?", candidate).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", slug, i)
+	}
+}
+
+// tagAliases 标签的全部识别名称：规范名称、当前和以前的 slug、同义词，按规范化形式去重，第一个为规范名称
+func tagAliases(tag *models.Tag, slugs []string, synonyms []string) []models.TagAlias {
+	rows := make([]models.TagAlias, 0, len(slugs)+len(synonyms)+2)
+	seen := make(map[string]bool)
+	add := func(alias, kind string) {
+		alias = truncateRunes(strings.TrimSpace(alias), maxTagRunes)
+		key := tagKey(alias)
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		rows = append(rows, models.TagAlias{TagID: tag.ID, Key: key, Alias: alias, Kind: kind})
+	}
+
+	add(tag.Name, models.TagAliasName)
+	add(tag.Slug, models.TagAliasSlug)
+	for _, slug := range slugs {
+		add(slug, models.TagAliasSlug)
+	}
+	for _, synonym := range synonyms {
+		add(synonym, models.TagAliasSynonym)
+	}
+	return rows
+}
+
+func convertToTagInfo(tag *models.Tag) models.TagInfo {
+	synonyms := make([]string, 0)
+	for _, alias := range tag.Aliases {
+		if alias.Kind == models.TagAliasSynonym {
+			synonyms = append(synonyms, alias.Alias)
+		}
+	}
+	return models.TagInfo{
+		ID:         tag.ID,
+		Slug:       tag.Slug,
+		Name:       tag.Name,
+		Synonyms:   synonyms,
+		NewsCount:  tag.NewsCount,
+		EventCount: tag.EventCount,
+		CreatedAt:  tag.CreatedAt,
+		UpdatedAt:  tag.UpdatedAt,
+	}
+}
+
+// parseTags 解析标签字符串：JSON 数组或以逗号分隔的名称，去掉空白和空名称
+func parseTags(value string) []string {
+	tags := make([]string, 0)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return tags
+	}
+
+	parts := strings.Split(value, ",")
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var parsed []string
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return tags
+		}
+		parts = parsed
+	}
+	for _, tag := range parts {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// tagKey 标签名称的规范化形式，与迁移时 SQL 中的 LOWER(TRIM(...)) 一致
+func tagKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
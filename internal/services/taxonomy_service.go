@@ -0,0 +1,600 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"github.com/EasyPeek/EasyPeek-backend/internal/nlp"
+	"gorm.io/gorm"
+)
+
+// defaultRegionalGroups 初始地域关键词组，首次启动时写入数据库
+var defaultRegionalGroups = map[string][]string{
+	"中东":   {"中东", "以色列", "巴勒斯坦", "伊朗", "叙利亚", "伊拉克", "沙特", "阿联酋", "黎巴嫩", "约旦", "也门", "卡塔尔", "科威特", "巴林", "阿曼"},
+	"俄乌":   {"俄罗斯", "乌克兰", "俄乌", "普京", "泽连斯基", "基辅", "莫斯科", "顿巴斯", "克里米亚", "哈尔科夫", "马里乌波尔"},
+	"朝鲜半岛": {"朝鲜", "韩国", "金正恩", "李在明", "尹锡悦", "平壤", "首尔", "三八线", "板门店"},
+	"南海":   {"南海", "台海", "台湾", "南沙", "西沙", "钓鱼岛", "尖阁诸岛"},
+	"欧洲":   {"欧盟", "英国", "法国", "德国", "意大利", "西班牙", "荷兰", "比利时", "瑞士", "奥地利", "波兰", "捷克"},
+	"美洲":   {"美国", "加拿大", "墨西哥", "巴西", "阿根廷", "智利", "哥伦比亚", "委内瑞拉"},
+	"非洲":   {"埃及", "南非", "尼日利亚", "肯尼亚", "摩洛哥", "阿尔及利亚", "利比亚", "苏丹", "埃塞俄比亚"},
+	"东南亚":  {"越南", "泰国", "新加坡", "马来西亚", "印尼", "菲律宾", "缅甸", "柬埔寨", "老挝"},
+	"南亚":   {"印度", "巴基斯坦", "孟加拉国", "斯里兰卡", "尼泊尔", "不丹", "马尔代夫"},
+}
+
+// defaultTopicGroups 初始主题关键词组，首次启动时写入数据库
+var defaultTopicGroups = map[string][]string{
+	"经济": {"经济", "GDP", "通胀", "利率", "股市", "汇率", "贸易", "投资", "金融", "央行", "货币", "市场", "企业", "公司"},
+	"科技": {"科技", "AI", "人工智能", "5G", "芯片", "半导体", "互联网", "数字", "智能", "技术", "创新", "研发"},
+	"政治": {"政治", "选举", "总统", "首相", "政府", "议会", "国会", "外交", "会谈", "峰会", "访问", "制裁"},
+	"军事": {"军事", "军队", "武器", "导弹", "战机", "军演", "防务", "安全", "冲突", "战争", "和平", "停火"},
+	"能源": {"能源", "石油", "天然气", "电力", "核能", "煤炭", "新能源", "太阳能", "风能", "电池"},
+	"环境": {"环境", "气候", "碳排放", "全球变暖", "污染", "环保", "绿色", "可持续", "减排"},
+	"健康": {"健康", "医疗", "疫苗", "病毒", "疫情", "医院", "药物", "治疗", "医生", "患者"},
+	"教育": {"教育", "学校", "大学", "学生", "老师", "考试", "学习", "培训", "课程"},
+	"体育": {"体育", "奥运", "世界杯", "足球", "篮球", "网球", "游泳", "田径", "运动员", "比赛"},
+	"文化": {"文化", "艺术", "电影", "音乐", "文学", "博物馆", "遗产", "传统", "节庆"},
+}
+
+// taxonomyCheckInterval 缓存的词库版本与数据库核对的间隔，保证多实例部署时其他实例的变更能被感知
+const taxonomyCheckInterval = time.Minute
+
+// taxonomyStore 进程内缓存的词库，本实例变更后立即失效，其他实例的变更按版本号定期核对
+type taxonomyStore struct {
+	mu        sync.RWMutex
+	snapshot  *models.TaxonomySnapshot
+	checkedAt time.Time
+}
+
+var taxonomyCache = &taxonomyStore{}
+
+type TaxonomyService struct {
+	db *gorm.DB
+}
+
+func NewTaxonomyService() *TaxonomyService {
+	return &TaxonomyService{
+		db: database.GetDB(),
+	}
+}
+
+// CurrentTaxonomy 获取当前生效的词库
+// 数据库不可用时返回内置的默认词库
+func (s *TaxonomyService) CurrentTaxonomy() (*models.TaxonomySnapshot, error) {
+	taxonomyCache.mu.RLock()
+	snapshot := taxonomyCache.snapshot
+	fresh := snapshot != nil && time.Since(taxonomyCache.checkedAt) < taxonomyCheckInterval
+	taxonomyCache.mu.RUnlock()
+	if fresh {
+		return snapshot, nil
+	}
+
+	if s.db == nil {
+		return defaultTaxonomy(), nil
+	}
+
+	version, err := s.latestVersion(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == nil || snapshot.Version != version {
+		if snapshot, err = s.loadTaxonomy(version); err != nil {
+			return nil, err
+		}
+	}
+
+	taxonomyCache.mu.Lock()
+	taxonomyCache.snapshot = snapshot
+	taxonomyCache.checkedAt = time.Now()
+	taxonomyCache.mu.Unlock()
+	return snapshot, nil
+}
+
+// SeedDefaults 词库为空时写入内置的默认关键词组和停用词
+func (s *TaxonomyService) SeedDefaults() error {
+	if s.db == nil {
+		return errors.New("database connection not initialized")
+	}
+
+	var changes int64
+	if err := s.db.Model(&models.TaxonomyChange{}).Count(&changes).Error; err != nil {
+		return err
+	}
+	if changes > 0 {
+		return nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for kind, groups := range map[string]map[string][]string{
+			models.KeywordGroupRegion: defaultRegionalGroups,
+			models.KeywordGroupTopic:  defaultTopicGroups,
+		} {
+			for _, name := range sortedKeys(groups) {
+				group := models.KeywordGroup{
+					Kind:     kind,
+					Name:     name,
+					Keywords: sliceToJSON(groups[name]),
+					IsActive: true,
+				}
+				if err := tx.Create(&group).Error; err != nil {
+					return err
+				}
+				if err := s.recordChange(tx, models.TaxonomyEntityGroup, group.ID, models.TaxonomyActionCreate, nil, group.ToResponse(), 0); err != nil {
+					return err
+				}
+			}
+		}
+
+		words := normalizeStopWords(nlp.DefaultStopWords)
+		stopWords := make([]models.StopWord, 0, len(words))
+		for _, word := range words {
+			stopWords = append(stopWords, models.StopWord{Word: word})
+		}
+		if err := tx.Create(&stopWords).Error; err != nil {
+			return err
+		}
+		return s.recordChange(tx, models.TaxonomyEntityStopWord, 0, models.TaxonomyActionCreate, nil, words, 0)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to seed taxonomy: %w", err)
+	}
+
+	invalidateTaxonomy()
+	log.Println("Default clustering taxonomy seeded")
+	return nil
+}
+
+// GetGroups 获取关键词组列表
+func (s *TaxonomyService) GetGroups(query *models.KeywordGroupQueryRequest) ([]models.KeywordGroupResponse, int64, error) {
+	if s.db == nil {
+		return nil, 0, errors.New("database connection not initialized")
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	db := s.db.Model(&models.KeywordGroup{})
+	if query.Kind != "" {
+		db = db.Where("kind = ?", query.Kind)
+	}
+	if query.Search != "" {
+		db = db.Where("name ILIKE ? OR keywords ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%")
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var groups []models.KeywordGroup
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Order("kind ASC, name ASC").Offset(offset).Limit(query.Limit).Find(&groups).Error; err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]models.KeywordGroupResponse, 0, len(groups))
+	for i := range groups {
+		responses = append(responses, groups[i].ToResponse())
+	}
+	return responses, total, nil
+}
+
+// CreateGroup 创建关键词组
+func (s *TaxonomyService) CreateGroup(req *models.CreateKeywordGroupRequest, changedBy uint) (*models.KeywordGroupResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	keywords := normalizeKeywords(req.Keywords)
+	if len(keywords) == 0 {
+		return nil, errors.New("keywords cannot be empty")
+	}
+
+	group := models.KeywordGroup{
+		Kind:        req.Kind,
+		Name:        strings.TrimSpace(req.Name),
+		Keywords:    sliceToJSON(keywords),
+		Description: req.Description,
+		IsActive:    true,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		tx.Model(&models.KeywordGroup{}).Where("kind = ? AND name = ?", group.Kind, group.Name).Count(&count)
+		if count > 0 {
+			return errors.New("keyword group already exists")
+		}
+		if err := s.checkRegionOverlap(tx, &group, keywords); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&group).Error; err != nil {
+			return err
+		}
+		return s.recordChange(tx, models.TaxonomyEntityGroup, group.ID, models.TaxonomyActionCreate, nil, group.ToResponse(), changedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateTaxonomy()
+	response := group.ToResponse()
+	return &response, nil
+}
+
+// UpdateGroup 更新关键词组
+func (s *TaxonomyService) UpdateGroup(id uint, req *models.UpdateKeywordGroupRequest, changedBy uint) (*models.KeywordGroupResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	var group models.KeywordGroup
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&group, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("keyword group not found")
+			}
+			return err
+		}
+		before := group.ToResponse()
+
+		if name := strings.TrimSpace(req.Name); name != "" && name != group.Name {
+			var count int64
+			tx.Model(&models.KeywordGroup{}).Where("kind = ? AND name = ? AND id <> ?", group.Kind, name, group.ID).Count(&count)
+			if count > 0 {
+				return errors.New("keyword group already exists")
+			}
+			group.Name = name
+		}
+		if req.Keywords != nil {
+			keywords := normalizeKeywords(req.Keywords)
+			if len(keywords) == 0 {
+				return errors.New("keywords cannot be empty")
+			}
+			group.Keywords = sliceToJSON(keywords)
+		}
+		if req.Description != nil {
+			group.Description = *req.Description
+		}
+		if req.IsActive != nil {
+			group.IsActive = *req.IsActive
+		}
+
+		if err := s.checkRegionOverlap(tx, &group, jsonToSlice(group.Keywords)); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&group).Error; err != nil {
+			return err
+		}
+		return s.recordChange(tx, models.TaxonomyEntityGroup, group.ID, models.TaxonomyActionUpdate, before, group.ToResponse(), changedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateTaxonomy()
+	response := group.ToResponse()
+	return &response, nil
+}
+
+// DeleteGroup 删除关键词组
+func (s *TaxonomyService) DeleteGroup(id uint, changedBy uint) error {
+	if s.db == nil {
+		return errors.New("database connection not initialized")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var group models.KeywordGroup
+		if err := tx.First(&group, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("keyword group not found")
+			}
+			return err
+		}
+
+		if err := tx.Delete(&group).Error; err != nil {
+			return err
+		}
+		return s.recordChange(tx, models.TaxonomyEntityGroup, group.ID, models.TaxonomyActionDelete, group.ToResponse(), nil, changedBy)
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidateTaxonomy()
+	return nil
+}
+
+// GetStopWords 获取停用词列表
+func (s *TaxonomyService) GetStopWords(query *models.StopWordQueryRequest) ([]models.StopWord, int64, error) {
+	if s.db == nil {
+		return nil, 0, errors.New("database connection not initialized")
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 200 {
+		query.Limit = 50
+	}
+
+	db := s.db.Model(&models.StopWord{})
+	if query.Search != "" {
+		db = db.Where("word ILIKE ?", "%"+query.Search+"%")
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var words []models.StopWord
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Order("word ASC").Offset(offset).Limit(query.Limit).Find(&words).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return words, total, nil
+}
+
+// CreateStopWords 批量添加停用词，已存在的词会被跳过
+func (s *TaxonomyService) CreateStopWords(req *models.CreateStopWordsRequest, changedBy uint) ([]models.StopWord, error) {
+	if s.db == nil {
+		return nil, errors.New("database connection not initialized")
+	}
+
+	words := normalizeStopWords(req.Words)
+	if len(words) == 0 {
+		return nil, errors.New("stop words cannot be empty")
+	}
+
+	created := make([]models.StopWord, 0, len(words))
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var existing []string
+		if err := tx.Model(&models.StopWord{}).Where("word IN ?", words).Pluck("word", &existing).Error; err != nil {
+			return err
+		}
+		exists := make(map[string]bool, len(existing))
+		for _, word := range existing {
+			exists[word] = true
+		}
+
+		added := make([]string, 0, len(words))
+		for _, word := range words {
+			if !exists[word] {
+				created = append(created, models.StopWord{Word: word})
+				added = append(added, word)
+			}
+		}
+		if len(created) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&created).Error; err != nil {
+			return err
+		}
+		return s.recordChange(tx, models.TaxonomyEntityStopWord, 0, models.TaxonomyActionCreate, nil, added, changedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(created) > 0 {
+		invalidateTaxonomy()
+	}
+	return created, nil
+}
+
+// DeleteStopWord 删除停用词
+func (s *TaxonomyService) DeleteStopWord(id uint, changedBy uint) error {
+	if s.db == nil {
+		return errors.New("database connection not initialized")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var word models.StopWord
+		if err := tx.First(&word, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("stop word not found")
+			}
+			return err
+		}
+
+		if err := tx.Delete(&word).Error; err != nil {
+			return err
+		}
+		return s.recordChange(tx, models.TaxonomyEntityStopWord, word.ID, models.TaxonomyActionDelete, []string{word.Word}, nil, changedBy)
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidateTaxonomy()
+	return nil
+}
+
+// GetChanges 获取词库变更记录，按版本倒序
+func (s *TaxonomyService) GetChanges(query *models.TaxonomyChangeQueryRequest) ([]models.TaxonomyChange, int64, error) {
+	if s.db == nil {
+		return nil, 0, errors.New("database connection not initialized")
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	db := s.db.Model(&models.TaxonomyChange{})
+	if query.EntityType != "" {
+		db = db.Where("entity_type = ?", query.EntityType)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var changes []models.TaxonomyChange
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Order("version DESC").Offset(offset).Limit(query.Limit).Find(&changes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return changes, total, nil
+}
+
+// recordChange 记录一次词库变更并分配新的版本号
+func (s *TaxonomyService) recordChange(tx *gorm.DB, entityType string, entityID uint, action string, before, after interface{}, changedBy uint) error {
+	// 串行化版本号分配，避免并发变更拿到相同的版本
+	if err := tx.Exec("LOCK TABLE taxonomy_changes IN EXCLUSIVE MODE").Error; err != nil {
+		return err
+	}
+
+	version, err := s.latestVersion(tx)
+	if err != nil {
+		return err
+	}
+
+	change := models.TaxonomyChange{
+		Version:    version + 1,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Before:     snapshotJSON(before),
+		After:      snapshotJSON(after),
+		ChangedBy:  changedBy,
+	}
+	return tx.Create(&change).Error
+}
+
+func (s *TaxonomyService) latestVersion(db *gorm.DB) (uint, error) {
+	var version uint
+	err := db.Model(&models.TaxonomyChange{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// loadTaxonomy 从数据库加载启用的关键词组和全部停用词
+func (s *TaxonomyService) loadTaxonomy(version uint) (*models.TaxonomySnapshot, error) {
+	var groups []models.KeywordGroup
+	if err := s.db.Where("is_active = ?", true).Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to load keyword groups: %w", err)
+	}
+
+	var stopWords []string
+	if err := s.db.Model(&models.StopWord{}).Order("word ASC").Pluck("word", &stopWords).Error; err != nil {
+		return nil, fmt.Errorf("failed to load stop words: %w", err)
+	}
+
+	snapshot := &models.TaxonomySnapshot{
+		Version:        version,
+		RegionalGroups: make(map[string][]string),
+		TopicGroups:    make(map[string][]string),
+		StopWords:      stopWords,
+	}
+	for _, group := range groups {
+		switch group.Kind {
+		case models.KeywordGroupRegion:
+			snapshot.RegionalGroups[group.Name] = jsonToSlice(group.Keywords)
+		case models.KeywordGroupTopic:
+			snapshot.TopicGroups[group.Name] = jsonToSlice(group.Keywords)
+		}
+	}
+	return snapshot, nil
+}
+
+// checkRegionOverlap 同一个关键词只能属于一个启用的地域组，否则地域冲突判断会产生歧义
+func (s *TaxonomyService) checkRegionOverlap(tx *gorm.DB, group *models.KeywordGroup, keywords []string) error {
+	if group.Kind != models.KeywordGroupRegion || !group.IsActive {
+		return nil
+	}
+
+	var others []models.KeywordGroup
+	if err := tx.Where("kind = ? AND is_active = ? AND id <> ?", models.KeywordGroupRegion, true, group.ID).Find(&others).Error; err != nil {
+		return err
+	}
+
+	owner := make(map[string]string)
+	for _, other := range others {
+		for _, keyword := range jsonToSlice(other.Keywords) {
+			owner[keyword] = other.Name
+		}
+	}
+	for _, keyword := range keywords {
+		if name, ok := owner[keyword]; ok {
+			return fmt.Errorf("keyword %s already belongs to region group %s", keyword, name)
+		}
+	}
+	return nil
+}
+
+// invalidateTaxonomy 使缓存的词库失效，下次使用时重新加载
+func invalidateTaxonomy() {
+	taxonomyCache.mu.Lock()
+	taxonomyCache.snapshot = nil
+	taxonomyCache.mu.Unlock()
+}
+
+// defaultTaxonomy 内置的默认词库
+func defaultTaxonomy() *models.TaxonomySnapshot {
+	return &models.TaxonomySnapshot{
+		RegionalGroups: defaultRegionalGroups,
+		TopicGroups:    defaultTopicGroups,
+		StopWords:      normalizeStopWords(nlp.DefaultStopWords),
+	}
+}
+
+// normalizeKeywords 去除空白和重复的关键词，保持原有顺序
+func normalizeKeywords(keywords []string) []string {
+	result := make([]string, 0, len(keywords))
+	seen := make(map[string]bool, len(keywords))
+	for _, keyword := range keywords {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" || seen[keyword] {
+			continue
+		}
+		seen[keyword] = true
+		result = append(result, keyword)
+	}
+	return result
+}
+
+// normalizeStopWords 停用词与分词结果一样统一为小写半角形式
+func normalizeStopWords(words []string) []string {
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		normalized = append(normalized, strings.ToLower(nlp.ToHalfWidth(word)))
+	}
+	return normalizeKeywords(normalized)
+}
+
+func snapshotJSON(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/cache"
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+)
+
+// 文本生成任务
+const (
+	TextTaskNewsSummary      = "news_summary"      // 新闻摘要
+	TextTaskEventTitle       = "event_title"       // 事件标题
+	TextTaskEventDescription = "event_description" // 事件描述
+)
+
+// 文本生成服务类型
+const (
+	TextProviderNone   = "none"
+	TextProviderOpenAI = "openai"
+)
+
+// ErrTextProviderDisabled 未配置外部模型，调用方应使用内置的抽取式摘要
+var ErrTextProviderDisabled = errors.New("text provider disabled")
+
+// TextRequest 文本生成请求
+type TextRequest struct {
+	Task     string // 任务类型
+	Title    string // 标题，事件任务中为各篇新闻标题
+	Text     string // 正文
+	MaxChars int    // 结果的最大字数
+}
+
+// TextProvider 为新闻摘要、事件标题和描述生成文本的外部模型
+// 实现需要可并发调用；返回的错误实现 Retryable() bool 且返回 true 时，调用方会在稍后重试
+type TextProvider interface {
+	Name() string
+	Generate(ctx context.Context, req TextRequest) (string, error)
+}
+
+// NoopTextProvider 未配置外部模型时使用，始终返回 ErrTextProviderDisabled
+type NoopTextProvider struct{}
+
+func (NoopTextProvider) Name() string {
+	return TextProviderNone
+}
+
+func (NoopTextProvider) Generate(ctx context.Context, req TextRequest) (string, error) {
+	return "", ErrTextProviderDisabled
+}
+
+// isRetryable 判断错误是否为暂时性错误
+func isRetryable(err error) bool {
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+const (
+	textCacheKeyPrefix  = "easypeek:text:"
+	maxMemoryTextCached = 1000
+)
+
+// CachedTextProvider 按内容哈希缓存生成结果，Redis 可用时写入 Redis，否则缓存在进程内
+type CachedTextProvider struct {
+	inner TextProvider
+	ttl   time.Duration
+
+	mu     sync.Mutex
+	memory map[string]string
+}
+
+func NewCachedTextProvider(inner TextProvider, ttl time.Duration) *CachedTextProvider {
+	return &CachedTextProvider{
+		inner:  inner,
+		ttl:    ttl,
+		memory: make(map[string]string),
+	}
+}
+
+func (p *CachedTextProvider) Name() string {
+	return p.inner.Name()
+}
+
+func (p *CachedTextProvider) Generate(ctx context.Context, req TextRequest) (string, error) {
+	key := p.cacheKey(req)
+	if text, ok := p.lookup(ctx, key); ok {
+		return text, nil
+	}
+
+	text, err := p.inner.Generate(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	p.store(ctx, key, text)
+	return text, nil
+}
+
+// cacheKey 以服务名、任务、字数限制和输入内容的哈希作为缓存键，更换模型后不会命中旧结果
+func (p *CachedTextProvider) cacheKey(req TextRequest) string {
+	h := sha256.New()
+	for _, part := range []string{p.inner.Name(), req.Task, strconv.Itoa(req.MaxChars), req.Title, req.Text} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return textCacheKeyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+func (p *CachedTextProvider) lookup(ctx context.Context, key string) (string, bool) {
+	if redisCache := cache.GetCache(); redisCache != nil {
+		text, ok, err := redisCache.Get(ctx, key)
+		if err == nil {
+			return text, ok
+		}
+		log.Printf("[TEXT WARNING] redis lookup failed, using memory cache: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	text, ok := p.memory[key]
+	return text, ok
+}
+
+func (p *CachedTextProvider) store(ctx context.Context, key, text string) {
+	if redisCache := cache.GetCache(); redisCache != nil {
+		err := redisCache.Set(ctx, key, text, p.ttl)
+		if err == nil {
+			return
+		}
+		log.Printf("[TEXT WARNING] redis store failed, using memory cache: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.memory) >= maxMemoryTextCached {
+		p.memory = make(map[string]string)
+	}
+	p.memory[key] = text
+}
+
+var (
+	textProvider     TextProvider
+	textProviderOnce sync.Once
+)
+
+// GetTextProvider 获取按配置创建的共享文本生成服务，未配置或配置不完整时返回 NoopTextProvider
+func GetTextProvider() TextProvider {
+	textProviderOnce.Do(func() {
+		textProvider = newTextProviderFromConfig()
+	})
+	return textProvider
+}
+
+func newTextProviderFromConfig() TextProvider {
+	if config.AppConfig == nil {
+		return NoopTextProvider{}
+	}
+
+	cfg := config.AppConfig.LLM
+	switch cfg.Provider {
+	case TextProviderOpenAI:
+		if cfg.BaseURL == "" || cfg.Model == "" {
+			log.Printf("[TEXT WARNING] llm.base_url or llm.model is empty, falling back to extractive summaries")
+			return NoopTextProvider{}
+		}
+		ttl := time.Duration(cfg.CacheTTLHours) * time.Hour
+		if ttl <= 0 {
+			ttl = 7 * 24 * time.Hour
+		}
+		log.Printf("[TEXT] Using OpenAI-compatible provider %s (model %s)", cfg.BaseURL, cfg.Model)
+		return NewCachedTextProvider(NewOpenAIProvider(cfg), ttl)
+	case "", TextProviderNone:
+		return NoopTextProvider{}
+	default:
+		log.Printf("[TEXT WARNING] unknown llm.provider %q, falling back to extractive summaries", cfg.Provider)
+		return NoopTextProvider{}
+	}
+}
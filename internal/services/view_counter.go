@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/EasyPeek/EasyPeek-backend/internal/cache"
+	"github.com/EasyPeek/EasyPeek-backend/internal/config"
+	"github.com/EasyPeek/EasyPeek-backend/internal/database"
+	"github.com/EasyPeek/EasyPeek-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// 浏览计数对象类型
+const (
+	ViewTargetEvent = "event"
+	ViewTargetNews  = "news"
+)
+
+const (
+	defaultViewDedupWindow = 30 * time.Minute
+	defaultViewFlushBatch  = 200
+	viewRedisTimeout       = 500 * time.Millisecond
+)
+
+// ViewCounter 浏览量计数器
+// 同一访客在去重窗口内的重复浏览只计一次，计数先累积在 Redis 中，由后台任务批量回写数据库；
+// Redis 不可用时退化为直接更新数据库
+type ViewCounter struct {
+	db          *gorm.DB
+	cache       *cache.RedisCache
+	dedupWindow time.Duration
+	flushBatch  int
+}
+
+// FlushStats 一次回写的统计
+type FlushStats struct {
+	Events int   `json:"events"`
+	News   int   `json:"news"`
+	Views  int64 `json:"views"`
+}
+
+func NewViewCounter() *ViewCounter {
+	counter := &ViewCounter{
+		db:          database.GetDB(),
+		cache:       cache.GetCache(),
+		dedupWindow: defaultViewDedupWindow,
+		flushBatch:  defaultViewFlushBatch,
+	}
+
+	if cfg := config.AppConfig; cfg != nil {
+		if cfg.Views.DedupWindowMinutes > 0 {
+			counter.dedupWindow = time.Duration(cfg.Views.DedupWindowMinutes) * time.Minute
+		}
+		if cfg.Views.FlushBatchSize > 0 {
+			counter.flushBatch = cfg.Views.FlushBatchSize
+		}
+	}
+
+	return counter
+}
+
+// RecordView 记录一次浏览，viewerKey 为用户ID或匿名访客指纹
+// 返回本次浏览是否被计数
+func (v *ViewCounter) RecordView(targetType string, id uint, viewerKey string) (bool, error) {
+	if v.cache == nil {
+		return true, v.incrementDirect(targetType, id, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), viewRedisTimeout)
+	defer cancel()
+
+	seenKey := fmt.Sprintf("views:seen:%s:%d:%s", targetType, id, viewerKey)
+	first, err := v.cache.SetNX(ctx, seenKey, 1, v.dedupWindow)
+	if err != nil {
+		log.Printf("[VIEWS WARNING] redis dedup failed, falling back to database: %v", err)
+		return true, v.incrementDirect(targetType, id, 1)
+	}
+	if !first {
+		return false, nil
+	}
+
+	if _, err := v.cache.HIncrBy(ctx, pendingViewsKey(targetType), strconv.FormatUint(uint64(id), 10), 1); err != nil {
+		log.Printf("[VIEWS WARNING] redis increment failed, falling back to database: %v", err)
+		return true, v.incrementDirect(targetType, id, 1)
+	}
+
+	return true, nil
+}
+
+// PendingViews 获取尚未回写数据库的浏览量
+func (v *ViewCounter) PendingViews(targetType string, id uint) int64 {
+	if v.cache == nil {
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), viewRedisTimeout)
+	defer cancel()
+
+	count, err := v.cache.HGetInt(ctx, pendingViewsKey(targetType), strconv.FormatUint(uint64(id), 10))
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// Flush 将 Redis 中累积的浏览量批量回写数据库，并重新计算受影响内容的热度
+func (v *ViewCounter) Flush() (*FlushStats, error) {
+	stats := &FlushStats{}
+	if v.cache == nil {
+		return stats, nil
+	}
+
+	for _, targetType := range []string{ViewTargetEvent, ViewTargetNews} {
+		// 回写失败时 ids 和 views 只包含已写入数据库的部分
+		ids, views, err := v.flushTarget(targetType)
+		stats.Views += views
+		if targetType == ViewTargetEvent {
+			stats.Events = len(ids)
+		} else {
+			stats.News = len(ids)
+		}
+
+		v.recalculateHotness(targetType, ids)
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// flushTarget 回写一类内容的浏览量，返回已写入数据库的ID和浏览量；写入失败的部分放回 Redis
+func (v *ViewCounter) flushTarget(targetType string) ([]uint, int64, error) {
+	ctx := context.Background()
+	key := pendingViewsKey(targetType)
+
+	values, err := v.cache.DrainHash(ctx, key)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to drain pending views: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, 0, nil
+	}
+
+	deltas := make(map[string]int64, len(values))
+	ids := make([]uint, 0, len(values))
+	for field, raw := range values {
+		id, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			continue
+		}
+		delta, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || delta <= 0 {
+			continue
+		}
+		deltas[field] = delta
+		ids = append(ids, uint(id))
+	}
+
+	table := "events"
+	if targetType == ViewTargetNews {
+		table = "news"
+	}
+
+	var persisted int64
+	for start := 0; start < len(ids); start += v.flushBatch {
+		end := start + v.flushBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := ids[start:end]
+
+		err := v.db.Transaction(func(tx *gorm.DB) error {
+			for _, id := range batch {
+				delta := deltas[strconv.FormatUint(uint64(id), 10)]
+				if err := tx.Table(table).
+					Where("id = ?", id).
+					UpdateColumn("view_count", gorm.Expr("view_count + ?", delta)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			// 回写失败的计数放回 Redis，等待下次回写
+			remaining := make(map[string]int64)
+			for _, id := range ids[start:] {
+				field := strconv.FormatUint(uint64(id), 10)
+				remaining[field] = deltas[field]
+			}
+			if restoreErr := v.cache.RestoreHash(ctx, key, remaining); restoreErr != nil {
+				log.Printf("[VIEWS ERROR] failed to restore %d pending view counters: %v", len(remaining), restoreErr)
+			}
+			return ids[:start], persisted, fmt.Errorf("failed to persist view counts: %w", err)
+		}
+		for _, id := range batch {
+			persisted += deltas[strconv.FormatUint(uint64(id), 10)]
+		}
+	}
+
+	return ids, persisted, nil
+}
+
+func (v *ViewCounter) recalculateHotness(targetType string, ids []uint) {
+	if len(ids) == 0 {
+		return
+	}
+
+	if targetType == ViewTargetEvent {
+		eventService := NewEventService()
+		for _, id := range ids {
+			if _, err := eventService.CalculateHotness(id, nil); err != nil {
+				log.Printf("[VIEWS WARNING] failed to recalculate hotness for event %d: %v", id, err)
+			}
+		}
+		return
+	}
+
+	rssService := NewRSSService()
+	for _, id := range ids {
+		if err := rssService.calculateNewsHotness(id); err != nil {
+			log.Printf("[VIEWS WARNING] failed to recalculate hotness for news %d: %v", id, err)
+		}
+	}
+}
+
+// incrementDirect 直接更新数据库中的浏览量（Redis 不可用时的降级路径）
+func (v *ViewCounter) incrementDirect(targetType string, id uint, delta int64) error {
+	var model interface{} = &models.Event{}
+	if targetType == ViewTargetNews {
+		model = &models.News{}
+	}
+
+	return v.db.Model(model).
+		Where("id = ?", id).
+		UpdateColumn("view_count", gorm.Expr("view_count + ?", delta)).Error
+}
+
+func pendingViewsKey(targetType string) string {
+	return "views:pending:" + targetType
+}